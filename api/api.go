@@ -26,6 +26,7 @@ import (
 
 	dxlibConfiguration "github.com/donnyhardyanto/dxlib/configuration"
 	"github.com/donnyhardyanto/dxlib/core"
+	"github.com/donnyhardyanto/dxlib/databases"
 	"github.com/donnyhardyanto/dxlib/log"
 	dxlibOtel "github.com/donnyhardyanto/dxlib/otel"
 	"github.com/donnyhardyanto/dxlib/utils"
@@ -102,6 +103,11 @@ type DXAPI struct {
 	ReadTimeoutSec               int
 	CORSAllowedOrigins           string // comma-separated allowed origins; empty or "*" = allow all
 	EnableBrowserSecurityHeaders bool   // when true, adds X-Content-Type-Options, HSTS, X-Frame-Options
+	IsMockMode                   bool   // when true, endpoints skip middlewares and OnExecute and serve synthesized responses (see api_mock.go)
+	MockLatencyMs                int    // default artificial latency in mock mode, overridable per request
+	JSONRPCUri                   string // when set, the JSON endpoints are also served as JSON-RPC 2.0 methods at this URI (see api_jsonrpc.go)
+	JSONRPCMethodNaming          string // JSONRPCMethodNamingURI (default) or JSONRPCMethodNamingTitle
+	JSONRPCDiscoverEnabled       bool   // when true, rpc.discover publishes the method catalog to any caller
 	EndPoints                    []DXAPIEndPoint
 	RawHandlers                  []struct {
 		Pattern string
@@ -247,6 +253,20 @@ func (a *DXAPI) ApplyConfigurations(configurationNameId string) (err error) {
 	}
 
 	a.EnableBrowserSecurityHeaders = utilsJSON.GetBoolWithDefault(c1, "enable-browser-security-headers", false)
+	a.IsMockMode = utilsJSON.GetBoolWithDefault(c1, "mock-mode", false)
+	a.MockLatencyMs = utilsJSON.GetNumberWithDefault(c1, "mock-latency-ms", 0)
+	if a.IsMockMode {
+		log.Log.Warnf("API %s is running in MOCK MODE: middlewares and OnExecute are skipped", a.NameId)
+	}
+	jsonRPCUri, err := utilsJSON.GetString(c1, "jsonrpc-uri")
+	if err == nil {
+		a.JSONRPCUri = jsonRPCUri
+	}
+	jsonRPCMethodNaming, err := utilsJSON.GetString(c1, "jsonrpc-method-naming")
+	if err == nil {
+		a.JSONRPCMethodNaming = jsonRPCMethodNaming
+	}
+	a.JSONRPCDiscoverEnabled = utilsJSON.GetBoolWithDefault(c1, "jsonrpc-discover-enabled", false)
 
 	return nil
 }
@@ -346,6 +366,8 @@ type dbContextCarrier interface {
 func (a *DXAPI) routeHandler(w http.ResponseWriter, r *http.Request, p *DXAPIEndPoint) {
 	requestContext, span := otel.Tracer(a.Log.Prefix).Start(r.Context(), "routeHandler|"+p.Uri)
 	defer span.End()
+	// Replica routing: once this request writes to a database, its reads go to the primary.
+	requestContext = databases.ContextWithReadYourWrites(requestContext)
 
 	if core.IsOtelEnabled {
 		span.SetAttributes(
@@ -533,6 +555,18 @@ func (a *DXAPI) routeHandler(w http.ResponseWriter, r *http.Request, p *DXAPIEnd
 		return
 	}
 
+	if a.IsMockMode {
+		mockStartTime := time.Now()
+		LogExecutionTrace(requestContext, "mock_start", aepr.Id, p.Uri, r.Method, mockStartTime, 0, "")
+		err = aepr.executeMock()
+		if err != nil {
+			LogExecutionTrace(requestContext, "mock_end", aepr.Id, p.Uri, r.Method, mockStartTime, aepr.ResponseStatusCode, err.Error())
+			return
+		}
+		LogExecutionTrace(requestContext, "mock_end", aepr.Id, p.Uri, r.Method, mockStartTime, aepr.ResponseStatusCode, "")
+		return
+	}
+
 	aepr.Log.Debugf("Middleware Start: %s", aepr.EndPoint.Uri)
 
 	// TRACE: middleware_start
@@ -755,6 +789,11 @@ func (a *DXAPI) StartAndWait(errorGroup *errgroup.Group) error {
 		mux.Handle(p.Uri, corsMiddleware(http.HandlerFunc(wrappedHandler)))
 	}
 
+	if a.JSONRPCUri != "" {
+		wrappedHandler := wrapHandler(a.NewJSONRPCHandler(a.JSONRPCMethodNaming).ServeHTTP, a.JSONRPCUri)
+		mux.Handle(a.JSONRPCUri, corsMiddleware(http.HandlerFunc(wrappedHandler)))
+	}
+
 	// Register raw handlers (static files, redirects, etc.)
 	for _, rh := range a.RawHandlers {
 		mux.Handle(rh.Pattern, corsMiddleware(rh.Handler))
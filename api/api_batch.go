@@ -0,0 +1,280 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/donnyhardyanto/dxlib/databases"
+	"github.com/donnyhardyanto/dxlib/errors"
+	dxlibTypes "github.com/donnyhardyanto/dxlib/types"
+	"github.com/donnyhardyanto/dxlib/utils"
+	utilsHttp "github.com/donnyhardyanto/dxlib/utils/http"
+)
+
+const DXAPIBatchDefaultMaxSubRequests = 20
+
+// DXAPIBatchSubResponse is one entry of the batch response, in request order.
+type DXAPIBatchSubResponse struct {
+	Uri        string `json:"uri"`
+	StatusCode int    `json:"status_code"`
+	Body       any    `json:"body"`
+}
+
+// inProcessResponseRecorder captures what the per-endpoint pipeline writes for a call
+// dispatched in-process (batch sub-requests, JSON-RPC calls).
+type inProcessResponseRecorder struct {
+	header     http.Header
+	statusCode int
+	body       bytes.Buffer
+}
+
+func (r *inProcessResponseRecorder) Header() http.Header {
+	return r.header
+}
+
+func (r *inProcessResponseRecorder) Write(b []byte) (int, error) {
+	if r.statusCode == 0 {
+		r.statusCode = http.StatusOK
+	}
+	return r.body.Write(b)
+}
+
+func (r *inProcessResponseRecorder) WriteHeader(statusCode int) {
+	if r.statusCode == 0 {
+		r.statusCode = statusCode
+	}
+}
+
+// StatusCode returns the written status code, 200 when the pipeline wrote nothing.
+func (r *inProcessResponseRecorder) StatusCode() int {
+	if r.statusCode == 0 {
+		return http.StatusOK
+	}
+	return r.statusCode
+}
+
+// BodyValue returns the body decoded as JSON when it was written as JSON, otherwise as a
+// string.
+func (r *inProcessResponseRecorder) BodyValue() any {
+	if strings.HasPrefix(r.header.Get("Content-Type"), "application/json") {
+		var v any
+		if json.Unmarshal(r.body.Bytes(), &v) == nil {
+			return v
+		}
+	}
+	return r.body.String()
+}
+
+// NewBatchEndPoint registers an endpoint that accepts
+//
+//	{"requests": [{"uri": "/v1/...", "parameters": {...}}, ...], "atomic": false}
+//
+// and runs every sub-request through the normal pipeline of its endpoint (validation,
+// middlewares, privileges, audit log), returning the results in order with their own
+// status codes. Sub-requests inherit the batch request headers, so the caller's
+// credentials apply to each of them.
+//
+// With "atomic": true the sub-requests run inside one transaction on
+// sharedTxDatabase: every DXDatabase.Tx on that database joins it, the first
+// sub-request answering >= 400 stops the batch, and nothing is committed. Reads made
+// outside DXDatabase.Tx use other connections and do not see uncommitted writes.
+// sharedTxDatabase may be nil when atomic mode is not offered.
+//
+// End-to-end encrypted endpoints can only be reached through a batch endpoint that is
+// itself end-to-end encrypted; their sub-requests are dispatched in-process as JSON.
+func (a *DXAPI) NewBatchEndPoint(uri string, endPointType DXAPIEndPointType, maxSubRequests int, sharedTxDatabase *databases.DXDatabase,
+	middlewares []DXAPIEndPointExecuteFunc, privileges []string) *DXAPIEndPoint {
+	if maxSubRequests <= 0 {
+		maxSubRequests = DXAPIBatchDefaultMaxSubRequests
+	}
+	parameters := []DXAPIEndPointParameter{
+		{NameId: "requests", Type: dxlibTypes.APIParameterTypeArray, Description: "Sub-requests: [{uri, parameters}]", IsMustExist: true,
+			Constraint: DXAPIEndPointParameterConstraint{MinItems: utils.Ptr(1), MaxItems: utils.Ptr(maxSubRequests)}},
+		{NameId: "atomic", Type: dxlibTypes.APIParameterTypeBoolean, Description: "Run all sub-requests in one all-or-nothing transaction", IsMustExist: false},
+	}
+	responsePossibilities := &DXAPIEndPointResponsePossibilities{
+		"success": {StatusCode: http.StatusOK, Description: "Success - 200", DataTemplate: []*DXAPIEndPointParameter{
+			{NameId: "committed", Type: dxlibTypes.APIParameterTypeBoolean, IsMustExist: true},
+			{NameId: "responses", Type: dxlibTypes.APIParameterTypeArray, IsMustExist: true},
+		}},
+		"invalid_request":      {StatusCode: http.StatusBadRequest, Description: "Invalid request - 400"},
+		"unprocessable_entity": {StatusCode: http.StatusUnprocessableEntity, Description: "Unprocessable entity - 422"},
+		"internal_error":       {StatusCode: http.StatusInternalServerError, Description: "Internal error - 500"},
+	}
+	onExecute := func(aepr *DXAPIEndPointRequest) (err error) {
+		return a.executeBatch(aepr, sharedTxDatabase)
+	}
+	return a.NewEndPoint("Batch", "Executes several API calls in one request", uri, http.MethodPost, endPointType,
+		utilsHttp.RequestContentTypeApplicationJSON, parameters, onExecute, nil, responsePossibilities, middlewares, privileges, 0, "")
+}
+
+func (a *DXAPI) executeBatch(aepr *DXAPIEndPointRequest, sharedTxDatabase *databases.DXDatabase) (err error) {
+	_, requests, err := aepr.GetParameterValueAsArrayOfAny("requests")
+	if err != nil {
+		return err
+	}
+	_, isAtomic, err := aepr.GetParameterValueAsBool("atomic", false)
+	if err != nil {
+		return err
+	}
+
+	subRequests := make([]utils.JSON, len(requests))
+	for i, v := range requests {
+		subRequest, ok := v.(map[string]any)
+		if !ok {
+			return aepr.WriteResponseAndNewErrorf(http.StatusUnprocessableEntity, "", "BATCH_INVALID_SUB_REQUEST:%d", i)
+		}
+		uri, ok := subRequest["uri"].(string)
+		if !ok || uri == "" {
+			return aepr.WriteResponseAndNewErrorf(http.StatusUnprocessableEntity, "", "BATCH_SUB_REQUEST_URI_MISSING:%d", i)
+		}
+		if uri == aepr.EndPoint.Uri {
+			return aepr.WriteResponseAndNewErrorf(http.StatusUnprocessableEntity, "", "BATCH_NESTED_BATCH_NOT_ALLOWED:%d", i)
+		}
+		if _, ok := subRequest["parameters"]; ok {
+			if _, ok := subRequest["parameters"].(map[string]any); !ok {
+				return aepr.WriteResponseAndNewErrorf(http.StatusUnprocessableEntity, "", "BATCH_SUB_REQUEST_PARAMETERS_NOT_OBJECT:%d", i)
+			}
+		}
+		subRequests[i] = subRequest
+	}
+
+	responses := make([]DXAPIBatchSubResponse, 0, len(subRequests))
+	if !isAtomic {
+		for _, subRequest := range subRequests {
+			responses = append(responses, a.dispatchBatchSubRequest(aepr.Context, aepr, subRequest))
+		}
+		aepr.WriteResponseAsJSON(http.StatusOK, nil, utils.JSON{"committed": true, "responses": responses})
+		return nil
+	}
+
+	if sharedTxDatabase == nil {
+		return aepr.WriteResponseAndNewErrorf(http.StatusUnprocessableEntity, "", "BATCH_ATOMIC_MODE_NOT_AVAILABLE")
+	}
+	errBatchAborted := errors.New("BATCH_ABORTED")
+	// the sub-requests are not retried: their handlers may have effects outside the database
+	err = sharedTxDatabase.TxWithRetryPolicy(aepr.Context, &aepr.Log, databases.LevelReadCommitted, databases.NoTxRetryPolicy, func(dtx *databases.DXDatabaseTx) error {
+		responses = responses[:0]
+		ctx := databases.ContextWithTx(aepr.Context, dtx)
+		for _, subRequest := range subRequests {
+			response := a.dispatchBatchSubRequest(ctx, aepr, subRequest)
+			responses = append(responses, response)
+			if response.StatusCode >= 400 {
+				return errors.Wrapf(errBatchAborted, "uri=%s status_code=%d", response.Uri, response.StatusCode)
+			}
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errBatchAborted) {
+		return err
+	}
+	aepr.WriteResponseAsJSON(http.StatusOK, nil, utils.JSON{"committed": err == nil, "responses": responses})
+	return nil
+}
+
+func (a *DXAPI) dispatchBatchSubRequest(ctx context.Context, aepr *DXAPIEndPointRequest, subRequest utils.JSON) DXAPIBatchSubResponse {
+	uri := subRequest["uri"].(string)
+	parameters, _ := subRequest["parameters"].(map[string]any)
+	response := DXAPIBatchSubResponse{Uri: uri}
+	fail := func(statusCode int, reason string) DXAPIBatchSubResponse {
+		response.StatusCode = statusCode
+		response.Body = utils.JSON{
+			"status":         http.StatusText(statusCode),
+			"status_code":    statusCode,
+			"reason":         reason,
+			"reason_message": reason,
+		}
+		return response
+	}
+
+	endPoint := a.FindEndPointByURI(uri)
+	if endPoint == nil {
+		return fail(http.StatusNotFound, "BATCH_ENDPOINT_NOT_FOUND")
+	}
+	subEndPoint := *endPoint
+	switch subEndPoint.EndPointType {
+	case EndPointTypeHTTPJSON:
+	case EndPointTypeHTTPEndToEndEncryptionV2, EndPointTypeHTTPEndToEndEncryptionV3, EndPointTypeHTTPEndToEndEncryptionV4:
+		switch aepr.EndPoint.EndPointType {
+		case EndPointTypeHTTPEndToEndEncryptionV2, EndPointTypeHTTPEndToEndEncryptionV3, EndPointTypeHTTPEndToEndEncryptionV4:
+			// Already protected by the batch envelope.
+			subEndPoint.EndPointType = EndPointTypeHTTPJSON
+		default:
+			return fail(http.StatusBadRequest, "BATCH_ENCRYPTED_ENDPOINT_REQUIRES_ENCRYPTED_BATCH")
+		}
+	default:
+		return fail(http.StatusBadRequest, "BATCH_ENDPOINT_TYPE_NOT_SUPPORTED")
+	}
+
+	header := aepr.Request.Header.Clone()
+	for k, v := range aepr.EffectiveRequestHeader {
+		header.Set(k, v)
+	}
+	header.Del("X-Var")
+	recorder, err := a.invokeEndPoint(ctx, aepr.Request, header, &subEndPoint, parameters)
+	if err != nil {
+		return fail(http.StatusBadRequest, "BATCH_SUB_REQUEST_INVALID:"+err.Error())
+	}
+
+	response.StatusCode = recorder.StatusCode()
+	response.Body = recorder.BodyValue()
+	return response
+}
+
+// invokeEndPoint runs one call through the pipeline of endPoint in-process, as if it had
+// arrived over HTTP with the given header, and returns what the pipeline wrote. GET and
+// DELETE endpoints receive parameters as a query string, so only strings, numbers and
+// booleans can be passed to them; the others receive a JSON body. Remote address and
+// host are taken from parent.
+func (a *DXAPI) invokeEndPoint(ctx context.Context, parent *http.Request, header http.Header, endPoint *DXAPIEndPoint, parameters utils.JSON) (*inProcessResponseRecorder, error) {
+	target := endPoint.Uri
+	var body []byte
+	switch endPoint.Method {
+	case http.MethodGet, http.MethodDelete:
+		query := url.Values{}
+		for k, v := range parameters {
+			switch tv := v.(type) {
+			case nil:
+			case string:
+				query.Set(k, tv)
+			case []any, map[string]any:
+				// SetRawValue reads a query string value as text, arrays and objects do not round-trip
+				return nil, errors.Errorf("INVOKE_ENDPOINT_QUERY_PARAMETER_NOT_SCALAR:%s", k)
+			default:
+				b, _ := json.Marshal(v)
+				query.Set(k, string(b))
+			}
+		}
+		if len(query) > 0 {
+			target += "?" + query.Encode()
+		}
+	default:
+		if parameters == nil {
+			parameters = utils.JSON{}
+		}
+		var err error
+		body, err = json.Marshal(parameters)
+		if err != nil {
+			return nil, errors.Wrap(err, "INVOKE_ENDPOINT_PARAMETERS_MARSHAL_ERROR")
+		}
+	}
+
+	r, err := http.NewRequestWithContext(ctx, endPoint.Method, target, bytes.NewReader(body))
+	if err != nil {
+		return nil, errors.Wrap(err, "INVOKE_ENDPOINT_NEW_REQUEST_ERROR")
+	}
+	r.Header = header
+	r.Header.Del("Content-Length")
+	r.Header.Set("Content-Type", "application/json")
+	r.ContentLength = int64(len(body))
+	r.RemoteAddr = parent.RemoteAddr
+	r.Host = parent.Host
+
+	recorder := &inProcessResponseRecorder{header: http.Header{}}
+	a.routeHandler(recorder, r, endPoint)
+	return recorder, nil
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/donnyhardyanto/dxlib/errors"
 	"github.com/donnyhardyanto/dxlib/log"
 	dxlibTypes "github.com/donnyhardyanto/dxlib/types"
+	"github.com/donnyhardyanto/dxlib/utils"
 	utilsHttp "github.com/donnyhardyanto/dxlib/utils/http"
 )
 
@@ -70,6 +71,7 @@ type DXAPIEndPointParameter struct {
 	IsNullable  bool
 	Children    []DXAPIEndPointParameter
 	Enum        []any
+	Constraint  DXAPIEndPointParameterConstraint
 }
 
 func (aep *DXAPIEndPointParameter) PrintSpec(leftIndent int64) (s string) {
@@ -95,13 +97,20 @@ func (aep *DXAPIEndPointParameter) PrintSpec(leftIndent int64) (s string) {
 			enumBuilder.WriteString("]")
 			s += fmt.Sprintf("%*s   Possible values: %s\n", leftIndent, "", enumBuilder.String())
 		}
+		if !aep.Constraint.IsEmpty() {
+			s += fmt.Sprintf("%*s   Constraints: %s\n", leftIndent, "", aep.Constraint.String())
+		}
 		if len(aep.Children) > 0 {
 			for _, c := range aep.Children {
 				s += c.PrintSpec(leftIndent + 2)
 			}
 		}
 	case "PostmanCollection":
-		return fmt.Sprintf("    - name: %s\n    - description: %s\n    - type: %s\n    - required: %t\n    - nullable: %t\n", aep.NameId, aep.Description, aep.Type, aep.IsMustExist, aep.IsNullable)
+		s = fmt.Sprintf("    - name: %s\n    - description: %s\n    - type: %s\n    - required: %t\n    - nullable: %t\n", aep.NameId, aep.Description, aep.Type, aep.IsMustExist, aep.IsNullable)
+		if !aep.Constraint.IsEmpty() {
+			s += fmt.Sprintf("    - constraints: %s\n", aep.Constraint.String())
+		}
+		return s
 	default:
 		return ""
 
@@ -116,6 +125,7 @@ type DXAPIEndPointResponsePossibility struct {
 	Description  string
 	Headers      map[string]string
 	DataTemplate []*DXAPIEndPointParameter
+	Example      utils.JSON // sample body served in mock mode instead of one generated from DataTemplate
 }
 
 type DXAPIEndPointExecuteFunc func(aepr *DXAPIEndPointRequest) (err error)
@@ -137,6 +147,7 @@ type DXAPIEndPoint struct {
 	Privileges              []string
 	RequestMaxContentLength int64
 	RateLimitGroupNameId    string
+	CrossFieldRules         []DXAPIEndPointCrossFieldRule
 }
 
 func (aep *DXAPIEndPoint) PrintSpec() (s string, err error) {
@@ -153,6 +164,12 @@ func (aep *DXAPIEndPoint) PrintSpec() (s string, err error) {
 		for _, p := range aep.Parameters {
 			s += p.PrintSpec(4)
 		}
+		if len(aep.CrossFieldRules) > 0 {
+			s += "####  Rules:\n"
+			for _, r := range aep.CrossFieldRules {
+				s += fmt.Sprintf("    - %s\n", r.String())
+			}
+		}
 		s += "####  Response Possibilities:\n"
 		keys := make([]string, 0, len(*aep.ResponsePossibilities))
 
@@ -0,0 +1,461 @@
+package api
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/donnyhardyanto/dxlib/errors"
+	"github.com/google/uuid"
+)
+
+// DXAPIEndPointParameterConstraint declares value constraints on a parameter on top of
+// its type. Nil/empty fields are not checked. Minimum/Maximum apply to numbers,
+// MinLength/MaxLength (in runes) and Pattern to strings, MinItems/MaxItems to arrays
+// and maps.
+type DXAPIEndPointParameterConstraint struct {
+	Minimum   *float64
+	Maximum   *float64
+	MinLength *int
+	MaxLength *int
+	Pattern   string
+	MinItems  *int
+	MaxItems  *int
+	Format    string // one of the ParameterFormat* values
+}
+
+const (
+	ParameterFormatEmail        = "email"
+	ParameterFormatPhoneNumber  = "phonenumber"
+	ParameterFormatNPWP         = "npwp"
+	ParameterFormatUUID         = "uuid"
+	ParameterFormatURI          = "uri"
+	ParameterFormatIP           = "ip"
+	ParameterFormatDate         = "date"
+	ParameterFormatTime         = "time"
+	ParameterFormatDateTime     = "date-time"
+	ParameterFormatAlphanumeric = "alphanumeric"
+)
+
+func (c *DXAPIEndPointParameterConstraint) IsEmpty() bool {
+	return c.Minimum == nil && c.Maximum == nil && c.MinLength == nil && c.MaxLength == nil &&
+		c.Pattern == "" && c.MinItems == nil && c.MaxItems == nil && c.Format == ""
+}
+
+// String renders the constraint for PrintSpec, e.g. "min=1, max=100, pattern=^[A-Z]{3}$".
+func (c *DXAPIEndPointParameterConstraint) String() string {
+	var parts []string
+	if c.Minimum != nil {
+		parts = append(parts, fmt.Sprintf("min=%v", *c.Minimum))
+	}
+	if c.Maximum != nil {
+		parts = append(parts, fmt.Sprintf("max=%v", *c.Maximum))
+	}
+	if c.MinLength != nil {
+		parts = append(parts, fmt.Sprintf("minLength=%d", *c.MinLength))
+	}
+	if c.MaxLength != nil {
+		parts = append(parts, fmt.Sprintf("maxLength=%d", *c.MaxLength))
+	}
+	if c.Pattern != "" {
+		parts = append(parts, fmt.Sprintf("pattern=%s", c.Pattern))
+	}
+	if c.MinItems != nil {
+		parts = append(parts, fmt.Sprintf("minItems=%d", *c.MinItems))
+	}
+	if c.MaxItems != nil {
+		parts = append(parts, fmt.Sprintf("maxItems=%d", *c.MaxItems))
+	}
+	if c.Format != "" {
+		parts = append(parts, fmt.Sprintf("format=%s", c.Format))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// DXAPIParameterViolation is one failed constraint, reported back to the client.
+type DXAPIParameterViolation struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// DXAPIParameterViolationError carries every constraint violation found on a request so
+// the client can fix all fields in one round trip.
+type DXAPIParameterViolationError struct {
+	Violations []DXAPIParameterViolation
+}
+
+func (e *DXAPIParameterViolationError) Error() string {
+	parts := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		parts[i] = v.Code + ":" + v.Field
+	}
+	return "PARAMETER_CONSTRAINT_VIOLATION:" + strings.Join(parts, ",")
+}
+
+var constraintPatternCache sync.Map
+
+func constraintPattern(pattern string) (*regexp.Regexp, error) {
+	if v, ok := constraintPatternCache.Load(pattern); ok {
+		return v.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, errors.Wrapf(err, "INVALID_CONSTRAINT_PATTERN:%s", pattern)
+	}
+	constraintPatternCache.Store(pattern, re)
+	return re, nil
+}
+
+func constraintToFloat64(v any) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case float32:
+		return float64(n), true
+	case float64:
+		return n, true
+	default:
+		return 0, false
+	}
+}
+
+func constraintItemCount(v any) (int, bool) {
+	switch a := v.(type) {
+	case []any:
+		return len(a), true
+	case []string:
+		return len(a), true
+	case []int64:
+		return len(a), true
+	case map[string]any:
+		return len(a), true
+	case map[string]string:
+		return len(a), true
+	default:
+		return 0, false
+	}
+}
+
+func constraintCheckFormat(format string, s string) (bool, error) {
+	switch format {
+	case ParameterFormatEmail:
+		return FormatEMailCheckValid(s), nil
+	case ParameterFormatPhoneNumber:
+		return FormatPhoneNumberCheckValid(s), nil
+	case ParameterFormatNPWP:
+		return FormatNPWPorNIKCheckValid(s), nil
+	case ParameterFormatUUID:
+		_, err := uuid.Parse(s)
+		return err == nil, nil
+	case ParameterFormatURI:
+		u, err := url.Parse(s)
+		return err == nil && u.Scheme != "" && u.Host != "", nil
+	case ParameterFormatIP:
+		return net.ParseIP(s) != nil, nil
+	case ParameterFormatDate:
+		_, err := time.Parse(time.DateOnly, s)
+		return err == nil, nil
+	case ParameterFormatTime:
+		_, err := time.Parse(time.TimeOnly, s)
+		return err == nil, nil
+	case ParameterFormatDateTime:
+		_, err := time.Parse(time.RFC3339Nano, s)
+		return err == nil, nil
+	case ParameterFormatAlphanumeric:
+		for _, r := range s {
+			if !((r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9')) {
+				return false, nil
+			}
+		}
+		return true, nil
+	default:
+		return false, errors.Errorf("UNKNOWN_CONSTRAINT_FORMAT:%s", format)
+	}
+}
+
+// Check returns the violations of value against the constraint. The error is reserved
+// for a broken declaration (bad pattern, unknown format), not for invalid input.
+func (c *DXAPIEndPointParameterConstraint) Check(field string, value any) (violations []DXAPIParameterViolation, err error) {
+	add := func(code string, format string, args ...any) {
+		violations = append(violations, DXAPIParameterViolation{Field: field, Code: code, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if n, ok := constraintToFloat64(value); ok {
+		if c.Minimum != nil && n < *c.Minimum {
+			add("MINIMUM", "%s must be >= %v", field, *c.Minimum)
+		}
+		if c.Maximum != nil && n > *c.Maximum {
+			add("MAXIMUM", "%s must be <= %v", field, *c.Maximum)
+		}
+	}
+
+	if s, ok := value.(string); ok {
+		length := utf8.RuneCountInString(s)
+		if c.MinLength != nil && length < *c.MinLength {
+			add("MIN_LENGTH", "%s must be at least %d characters", field, *c.MinLength)
+		}
+		if c.MaxLength != nil && length > *c.MaxLength {
+			add("MAX_LENGTH", "%s must be at most %d characters", field, *c.MaxLength)
+		}
+		if c.Pattern != "" {
+			re, err := constraintPattern(c.Pattern)
+			if err != nil {
+				return nil, err
+			}
+			if !re.MatchString(s) {
+				add("PATTERN", "%s must match %s", field, c.Pattern)
+			}
+		}
+		if c.Format != "" {
+			ok, err := constraintCheckFormat(c.Format, s)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				add("FORMAT", "%s must be a valid %s", field, c.Format)
+			}
+		}
+	}
+
+	if count, ok := constraintItemCount(value); ok {
+		if c.MinItems != nil && count < *c.MinItems {
+			add("MIN_ITEMS", "%s must have at least %d items", field, *c.MinItems)
+		}
+		if c.MaxItems != nil && count > *c.MaxItems {
+			add("MAX_ITEMS", "%s must have at most %d items", field, *c.MaxItems)
+		}
+	}
+	return violations, nil
+}
+
+type DXAPIEndPointCrossFieldRuleType string
+
+const (
+	CrossFieldRuleGreaterThan        DXAPIEndPointCrossFieldRuleType = "gt"  // Fields[0] > Fields[1]
+	CrossFieldRuleGreaterThanOrEqual DXAPIEndPointCrossFieldRuleType = "gte" // Fields[0] >= Fields[1]
+	CrossFieldRuleLessThan           DXAPIEndPointCrossFieldRuleType = "lt"  // Fields[0] < Fields[1]
+	CrossFieldRuleLessThanOrEqual    DXAPIEndPointCrossFieldRuleType = "lte" // Fields[0] <= Fields[1]
+	CrossFieldRuleExactlyOneOf       DXAPIEndPointCrossFieldRuleType = "exactly_one_of"
+	CrossFieldRuleAtLeastOneOf       DXAPIEndPointCrossFieldRuleType = "at_least_one_of"
+	CrossFieldRuleAtMostOneOf        DXAPIEndPointCrossFieldRuleType = "at_most_one_of"
+	CrossFieldRuleAllOrNone          DXAPIEndPointCrossFieldRuleType = "all_or_none"
+	CrossFieldRuleRequiredWith       DXAPIEndPointCrossFieldRuleType = "required_with" // Fields[1:] are mandatory when Fields[0] is present
+	CrossFieldRuleCustom             DXAPIEndPointCrossFieldRuleType = "custom"        // Check decides
+)
+
+// DXAPIEndPointCrossFieldRule is an endpoint-level rule over several top level
+// parameters. Comparison rules are skipped when either side is absent; combine them
+// with a presence rule when both sides are required.
+type DXAPIEndPointCrossFieldRule struct {
+	Type        DXAPIEndPointCrossFieldRuleType
+	Fields      []string
+	Description string
+	Check       func(values map[string]any) (ok bool, err error) // only for CrossFieldRuleCustom
+}
+
+func (r *DXAPIEndPointCrossFieldRule) String() string {
+	s := fmt.Sprintf("%s(%s)", r.Type, strings.Join(r.Fields, ", "))
+	if r.Description != "" {
+		s += " " + r.Description
+	}
+	return s
+}
+
+func crossFieldCompare(a any, b any) (int, bool) {
+	if an, ok := constraintToFloat64(a); ok {
+		if bn, ok := constraintToFloat64(b); ok {
+			switch {
+			case an < bn:
+				return -1, true
+			case an > bn:
+				return 1, true
+			default:
+				return 0, true
+			}
+		}
+		return 0, false
+	}
+	if at, ok := a.(time.Time); ok {
+		if bt, ok := b.(time.Time); ok {
+			return at.Compare(bt), true
+		}
+		return 0, false
+	}
+	if as, ok := a.(string); ok {
+		if bs, ok := b.(string); ok {
+			return strings.Compare(as, bs), true
+		}
+	}
+	return 0, false
+}
+
+// Evaluate checks the rule against the resolved parameter values. A value counts as
+// present when it is non-nil and not an empty string.
+func (r *DXAPIEndPointCrossFieldRule) Evaluate(values map[string]any) (violation *DXAPIParameterViolation, err error) {
+	field := strings.Join(r.Fields, ",")
+	fail := func(format string, args ...any) (*DXAPIParameterViolation, error) {
+		message := r.Description
+		if message == "" {
+			message = fmt.Sprintf(format, args...)
+		}
+		return &DXAPIParameterViolation{Field: field, Code: "RULE_" + strings.ToUpper(string(r.Type)), Message: message}, nil
+	}
+	isPresent := func(name string) bool {
+		v, ok := values[name]
+		if !ok || v == nil {
+			return false
+		}
+		if s, ok := v.(string); ok && s == "" {
+			return false
+		}
+		return true
+	}
+	presentCount := 0
+	for _, f := range r.Fields {
+		if isPresent(f) {
+			presentCount++
+		}
+	}
+
+	switch r.Type {
+	case CrossFieldRuleGreaterThan, CrossFieldRuleGreaterThanOrEqual, CrossFieldRuleLessThan, CrossFieldRuleLessThanOrEqual:
+		if len(r.Fields) != 2 {
+			return nil, errors.Errorf("INVALID_CROSS_FIELD_RULE:%s_NEEDS_2_FIELDS", r.Type)
+		}
+		if !isPresent(r.Fields[0]) || !isPresent(r.Fields[1]) {
+			return nil, nil
+		}
+		c, ok := crossFieldCompare(values[r.Fields[0]], values[r.Fields[1]])
+		if !ok {
+			return fail("%s and %s are not comparable", r.Fields[0], r.Fields[1])
+		}
+		var passed bool
+		var operator string
+		switch r.Type {
+		case CrossFieldRuleGreaterThan:
+			passed, operator = c > 0, ">"
+		case CrossFieldRuleGreaterThanOrEqual:
+			passed, operator = c >= 0, ">="
+		case CrossFieldRuleLessThan:
+			passed, operator = c < 0, "<"
+		default:
+			passed, operator = c <= 0, "<="
+		}
+		if !passed {
+			return fail("%s must be %s %s", r.Fields[0], operator, r.Fields[1])
+		}
+	case CrossFieldRuleExactlyOneOf:
+		if presentCount != 1 {
+			return fail("exactly one of %s must be set", strings.Join(r.Fields, ", "))
+		}
+	case CrossFieldRuleAtLeastOneOf:
+		if presentCount < 1 {
+			return fail("at least one of %s must be set", strings.Join(r.Fields, ", "))
+		}
+	case CrossFieldRuleAtMostOneOf:
+		if presentCount > 1 {
+			return fail("at most one of %s may be set", strings.Join(r.Fields, ", "))
+		}
+	case CrossFieldRuleAllOrNone:
+		if presentCount != 0 && presentCount != len(r.Fields) {
+			return fail("%s must be set together or not at all", strings.Join(r.Fields, ", "))
+		}
+	case CrossFieldRuleRequiredWith:
+		if len(r.Fields) < 2 {
+			return nil, errors.Errorf("INVALID_CROSS_FIELD_RULE:%s_NEEDS_2_FIELDS", r.Type)
+		}
+		if isPresent(r.Fields[0]) {
+			for _, f := range r.Fields[1:] {
+				if !isPresent(f) {
+					return fail("%s is required when %s is set", f, r.Fields[0])
+				}
+			}
+		}
+	case CrossFieldRuleCustom:
+		if r.Check == nil {
+			return nil, errors.Errorf("INVALID_CROSS_FIELD_RULE:%s_WITHOUT_CHECK", r.Type)
+		}
+		ok, err := r.Check(values)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return fail("%s failed a custom rule", field)
+		}
+	default:
+		return nil, errors.Errorf("UNKNOWN_CROSS_FIELD_RULE:%s", r.Type)
+	}
+	return nil, nil
+}
+
+// validateCrossFieldRules evaluates the endpoint rules and returns a
+// DXAPIParameterViolationError holding previously collected parameter violations plus
+// the rule violations, or nil when there are none.
+func (aepr *DXAPIEndPointRequest) validateCrossFieldRules(violations []DXAPIParameterViolation) (err error) {
+	if len(aepr.EndPoint.CrossFieldRules) > 0 {
+		values := map[string]any{}
+		for k, v := range aepr.ParameterValues {
+			if v != nil {
+				values[k] = v.Value
+			}
+		}
+		for i := range aepr.EndPoint.CrossFieldRules {
+			violation, err := aepr.EndPoint.CrossFieldRules[i].Evaluate(values)
+			if err != nil {
+				return err
+			}
+			if violation != nil {
+				violations = append(violations, *violation)
+			}
+		}
+	}
+	if len(violations) == 0 {
+		return nil
+	}
+	return &DXAPIParameterViolationError{Violations: violations}
+}
+
+// collectParameterViolation validates one parameter value. Constraint violations are
+// appended to violations so every field gets reported; any other validation failure
+// is written as a 422 and returned.
+func (aepr *DXAPIEndPointRequest) collectParameterViolation(rpv *DXAPIEndPointRequestParameterValue, violations *[]DXAPIParameterViolation) (err error) {
+	err = rpv.Validate()
+	if err == nil {
+		return nil
+	}
+	var violationErr *DXAPIParameterViolationError
+	if errors.As(err, &violationErr) {
+		*violations = append(*violations, violationErr.Violations...)
+		return nil
+	}
+	aepr.WriteResponseAsError(http.StatusUnprocessableEntity, err)
+	return err
+}
+
+// writeParameterViolations sends the collected violations as one 422 response.
+func (aepr *DXAPIEndPointRequest) writeParameterViolations(err error) error {
+	var violationErr *DXAPIParameterViolationError
+	if !errors.As(err, &violationErr) {
+		return aepr.WriteResponseAndNewErrorf(http.StatusInternalServerError, "", "CROSS_FIELD_RULE_ERROR:%s", err.Error())
+	}
+	aepr.Log.Warnf("%s", violationErr.Error())
+	if !aepr.ResponseHeaderSent {
+		aepr.WriteResponseAsJSON(http.StatusUnprocessableEntity, nil, map[string]any{
+			"reason":         "PARAMETER_CONSTRAINT_VIOLATION",
+			"reason_message": violationErr.Violations[0].Message,
+			"violations":     violationErr.Violations,
+		})
+	}
+	return err
+}
@@ -0,0 +1,75 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/donnyhardyanto/dxlib/errors"
+	dxlibTypes "github.com/donnyhardyanto/dxlib/types"
+	"github.com/donnyhardyanto/dxlib/utils"
+)
+
+func TestParameterConstraintCheck(t *testing.T) {
+	c := DXAPIEndPointParameterConstraint{
+		MinLength: utils.Ptr(3),
+		MaxLength: utils.Ptr(3),
+		Pattern:   "^[A-Z]{3}$",
+	}
+	if v, err := c.Check("currency", "IDR"); err != nil || len(v) != 0 {
+		t.Errorf("IDR should pass: %v %v", v, err)
+	}
+	if v, _ := c.Check("currency", "idr1"); len(v) != 2 {
+		t.Errorf("idr1 should violate maxLength and pattern, got %v", v)
+	}
+
+	n := DXAPIEndPointParameterConstraint{Minimum: utils.Ptr(1.0), Maximum: utils.Ptr(100.0)}
+	if v, _ := n.Check("page_size", int64(101)); len(v) != 1 || v[0].Code != "MAXIMUM" {
+		t.Errorf("101 should violate maximum, got %v", v)
+	}
+
+	items := DXAPIEndPointParameterConstraint{MaxItems: utils.Ptr(1)}
+	if v, _ := items.Check("ids", []int64{1, 2}); len(v) != 1 || v[0].Code != "MAX_ITEMS" {
+		t.Errorf("two items should violate maxItems, got %v", v)
+	}
+
+	if _, err := (&DXAPIEndPointParameterConstraint{Format: "no-such-format"}).Check("x", "y"); err == nil {
+		t.Errorf("unknown format should be a declaration error")
+	}
+}
+
+func TestCrossFieldRuleEvaluate(t *testing.T) {
+	start := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	end := start.Add(-time.Hour)
+
+	dateRule := DXAPIEndPointCrossFieldRule{Type: CrossFieldRuleGreaterThanOrEqual, Fields: []string{"end_date", "start_date"}}
+	if v, err := dateRule.Evaluate(map[string]any{"start_date": start, "end_date": end}); err != nil || v == nil {
+		t.Errorf("end_date before start_date should violate: %v %v", v, err)
+	}
+	if v, _ := dateRule.Evaluate(map[string]any{"start_date": start}); v != nil {
+		t.Errorf("comparison should be skipped when a side is absent, got %v", v)
+	}
+
+	oneOf := DXAPIEndPointCrossFieldRule{Type: CrossFieldRuleExactlyOneOf, Fields: []string{"a", "b"}}
+	if v, _ := oneOf.Evaluate(map[string]any{"a": "x", "b": "y"}); v == nil {
+		t.Errorf("both a and b set should violate exactly_one_of")
+	}
+	if v, _ := oneOf.Evaluate(map[string]any{"a": "x", "b": ""}); v != nil {
+		t.Errorf("empty string should count as absent, got %v", v)
+	}
+}
+
+func TestParameterConstraintOnDate(t *testing.T) {
+	aepr := &DXAPIEndPointRequest{ParameterValues: map[string]*DXAPIEndPointRequestParameterValue{}}
+	rpv := aepr.NewAPIEndPointRequestParameter(DXAPIEndPointParameter{
+		NameId:     "birth_date",
+		Type:       dxlibTypes.APIParameterTypeDate,
+		Constraint: DXAPIEndPointParameterConstraint{Pattern: "^19"},
+	})
+	if err := rpv.SetRawValue("2001-02-03", "birth_date"); err != nil {
+		t.Fatal(err)
+	}
+	var violationErr *DXAPIParameterViolationError
+	if err := rpv.Validate(); !errors.As(err, &violationErr) || violationErr.Violations[0].Code != "PATTERN" {
+		t.Errorf("a date must be checked against the text sent, got %v", err)
+	}
+}
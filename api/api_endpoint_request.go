@@ -906,6 +906,7 @@ func (aepr *DXAPIEndPointRequest) PreProcessRequest() (err error) {
 		if err != nil {
 			return aepr.WriteResponseAndNewErrorf(http.StatusUnprocessableEntity, "", "ERROR_PARSING_HEADER_X-VAR_AS_JSON: %v", err.Error())
 		}
+		var violations []DXAPIParameterViolation
 		for _, v := range aepr.EndPoint.Parameters {
 			rpv := aepr.NewAPIEndPointRequestParameter(v)
 			aepr.ParameterValues[v.NameId] = rpv
@@ -919,16 +920,20 @@ func (aepr *DXAPIEndPointRequest) PreProcessRequest() (err error) {
 				return aepr.WriteResponseAndNewErrorf(http.StatusUnprocessableEntity, s, s)
 			}
 			if rpv.RawValue != nil {
-				err = rpv.Validate()
+				err = aepr.collectParameterViolation(rpv, &violations)
 				if err != nil {
-					aepr.WriteResponseAsError(http.StatusUnprocessableEntity, err)
 					return err
 				}
 			}
 		}
+		err = aepr.validateCrossFieldRules(violations)
+		if err != nil {
+			return aepr.writeParameterViolations(err)
+		}
 	}
 	switch aepr.EndPoint.Method {
 	case "GET", "DELETE":
+		var violations []DXAPIParameterViolation
 		for _, v := range aepr.EndPoint.Parameters {
 			rpv := aepr.NewAPIEndPointRequestParameter(v)
 			aepr.ParameterValues[v.NameId] = rpv
@@ -942,16 +947,20 @@ func (aepr *DXAPIEndPointRequest) PreProcessRequest() (err error) {
 				return aepr.WriteResponseAndNewErrorf(http.StatusUnprocessableEntity, s, s)
 			}
 			if rpv.RawValue != nil {
-				err = rpv.Validate()
+				err = aepr.collectParameterViolation(rpv, &violations)
 				if err != nil {
-					aepr.WriteResponseAsError(http.StatusUnprocessableEntity, err)
 					return err
 				}
 			}
 		}
+		err = aepr.validateCrossFieldRules(violations)
+		if err != nil {
+			return aepr.writeParameterViolations(err)
+		}
 	case "POST", "PUT":
 		switch aepr.EndPoint.RequestContentType {
 		case utilsHttp.RequestContentTypeApplicationOctetStream:
+			var violations []DXAPIParameterViolation
 			for _, v := range aepr.EndPoint.Parameters {
 				rpv, ok := aepr.ParameterValues[v.NameId]
 				variablePath := v.NameId
@@ -962,13 +971,16 @@ func (aepr *DXAPIEndPointRequest) PreProcessRequest() (err error) {
 					}
 				}
 				if rpv.RawValue != nil {
-					err = rpv.Validate()
+					err = aepr.collectParameterViolation(rpv, &violations)
 					if err != nil {
-						aepr.WriteResponseAsError(http.StatusUnprocessableEntity, err)
 						return err
 					}
 				}
 			}
+			err = aepr.validateCrossFieldRules(violations)
+			if err != nil {
+				return aepr.writeParameterViolations(err)
+			}
 			return aepr.preProcessRequestAsApplicationOctetStream()
 		case utilsHttp.RequestContentTypeApplicationJSON:
 			return aepr.preProcessRequestAsApplicationJSON()
@@ -1222,6 +1234,7 @@ func (aepr *DXAPIEndPointRequest) receivedBodyKeysForLog(bodyAsJSON utils.JSON)
 }
 
 func (aepr *DXAPIEndPointRequest) processEndPointRequestParameterValues(bodyAsJSON utils.JSON) (err error) {
+	var violations []DXAPIParameterViolation
 	for _, v := range aepr.EndPoint.Parameters {
 		rpv := aepr.NewAPIEndPointRequestParameter(v)
 		aepr.ParameterValues[v.NameId] = rpv
@@ -1237,12 +1250,15 @@ func (aepr *DXAPIEndPointRequest) processEndPointRequestParameterValues(bodyAsJS
 				"%s (received_keys: [%s])", s, aepr.receivedBodyKeysForLog(bodyAsJSON))
 		}
 		if rpv.RawValue != nil {
-			err = rpv.Validate()
+			err = aepr.collectParameterViolation(rpv, &violations)
 			if err != nil {
-				aepr.WriteResponseAsError(http.StatusUnprocessableEntity, err)
 				return err
 			}
 		}
 	}
+	err = aepr.validateCrossFieldRules(violations)
+	if err != nil {
+		return aepr.writeParameterViolations(err)
+	}
 	return nil
 }
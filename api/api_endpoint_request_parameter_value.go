@@ -150,12 +150,20 @@ func (aeprpv *DXAPIEndPointRequestParameterValue) validateWhenNotSameWithRawValu
 		if rawValueType != "map[string]interface {}" {
 			return aeprpv.Owner.Log.WarnAndCreateErrorf(ErrorMessageIncompatibleTypeReceived, nameIdPath, aeprpv.Metadata.Type, rawValueType, aeprpv.RawValue)
 		}
+		var violations []DXAPIParameterViolation
 		for _, v := range aeprpv.Children {
 			err = v.Validate()
 			if err != nil {
-				return err
+				var violationErr *DXAPIParameterViolationError
+				if !errors.As(err, &violationErr) {
+					return err
+				}
+				violations = append(violations, violationErr.Violations...)
 			}
 		}
+		if len(violations) > 0 {
+			return &DXAPIParameterViolationError{Violations: violations}
+		}
 	case dxlibTypes.APIParameterTypeJSONPassthrough, dxlibTypes.APIParameterTypeMapStringString:
 		if rawValueType != "map[string]interface {}" {
 			return aeprpv.Owner.Log.WarnAndCreateErrorf(ErrorMessageIncompatibleTypeReceived, nameIdPath, aeprpv.Metadata.Type, rawValueType, aeprpv.RawValue)
@@ -168,12 +176,20 @@ func (aeprpv *DXAPIEndPointRequestParameterValue) validateWhenNotSameWithRawValu
 		if rawValueType != "[]interface {}" {
 			return aeprpv.Owner.Log.WarnAndCreateErrorf(ErrorMessageIncompatibleTypeReceived, nameIdPath, aeprpv.Metadata.Type, rawValueType, aeprpv.RawValue)
 		}
+		var violations []DXAPIParameterViolation
 		for _, j := range aeprpv.ArrayChildren {
 			err = j.Validate()
 			if err != nil {
-				return err
+				var violationErr *DXAPIParameterViolationError
+				if !errors.As(err, &violationErr) {
+					return err
+				}
+				violations = append(violations, violationErr.Violations...)
 			}
 		}
+		if len(violations) > 0 {
+			return &DXAPIParameterViolationError{Violations: violations}
+		}
 	default:
 		return aeprpv.Owner.Log.WarnAndCreateErrorf("INVALID_TYPE_MATCHING:SHOULD_[%s].(%v)_BUT_RECEIVE_(%s)=%v", nameIdPath, aeprpv.Metadata.Type, rawValueType, aeprpv.RawValue)
 	}
@@ -618,5 +634,21 @@ func (aeprpv *DXAPIEndPointRequestParameterValue) Validate() (err error) {
 			return aeprpv.Owner.Log.WarnAndCreateErrorf("INVALID_ENUM_VALUE:%s=%v, allowed=%v", nameIdPath, aeprpv.Value, aeprpv.Metadata.Enum)
 		}
 	}
+	if !aeprpv.Metadata.Constraint.IsEmpty() {
+		constraintValue := aeprpv.Value
+		if _, ok := constraintValue.(time.Time); ok {
+			// date and time values resolve to time.Time; Format, Pattern and the lengths apply to the text sent
+			if s, ok := aeprpv.RawValue.(string); ok {
+				constraintValue = s
+			}
+		}
+		violations, err := aeprpv.Metadata.Constraint.Check(nameIdPath, constraintValue)
+		if err != nil {
+			return aeprpv.Owner.Log.WarnAndCreateErrorf("INVALID_PARAMETER_CONSTRAINT:%s:%s", nameIdPath, err.Error())
+		}
+		if len(violations) > 0 {
+			return &DXAPIParameterViolationError{Violations: violations}
+		}
+	}
 	return nil
 }
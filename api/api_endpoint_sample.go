@@ -0,0 +1,170 @@
+package api
+
+import (
+	"math"
+	"strings"
+	"unicode/utf8"
+
+	dxlibTypes "github.com/donnyhardyanto/dxlib/types"
+	"github.com/donnyhardyanto/dxlib/utils"
+)
+
+// SampleValue returns a value that passes the parameter's type validation and, as far
+// as it can be derived, its Constraint. When the parameter declares an Enum, the first
+// allowed value is used.
+func (aep *DXAPIEndPointParameter) SampleValue() any {
+	if len(aep.Enum) > 0 {
+		return aep.Enum[0]
+	}
+	return aep.Constraint.adjustSample(aep.sampleValueByType())
+}
+
+func (aep *DXAPIEndPointParameter) sampleValueByType() any {
+	switch aep.Type {
+	case dxlibTypes.APIParameterTypeString,
+		dxlibTypes.APIParameterTypeProtectedString,
+		dxlibTypes.APIParameterTypeProtectedSQLString,
+		dxlibTypes.APIParameterTypeProtectedNonEmptyString,
+		dxlibTypes.APIParameterTypeNullableString,
+		dxlibTypes.APIParameterTypeNonEmptyString:
+		return "sample_" + aep.NameId
+	case dxlibTypes.APIParameterTypeEmail:
+		return "user@example.com"
+	case dxlibTypes.APIParameterTypePhoneNumber:
+		return "+6281234567890"
+	case dxlibTypes.APIParameterTypeNPWP:
+		return "012345678901234"
+	case dxlibTypes.APIParameterTypeInt32,
+		dxlibTypes.APIParameterTypeInt32P,
+		dxlibTypes.APIParameterTypeInt64,
+		dxlibTypes.APIParameterTypeInt64P,
+		dxlibTypes.APIParameterTypeNullableInt32,
+		dxlibTypes.APIParameterTypeNullableInt64,
+		dxlibTypes.APIParameterTypeID:
+		return 1
+	case dxlibTypes.APIParameterTypeInt32ZP,
+		dxlibTypes.APIParameterTypeInt64ZP:
+		return 0
+	case dxlibTypes.APIParameterTypeFloat32,
+		dxlibTypes.APIParameterTypeFloat32P,
+		dxlibTypes.APIParameterTypeFloat64,
+		dxlibTypes.APIParameterTypeFloat64P,
+		dxlibTypes.APIParameterTypeMoney:
+		return 1.5
+	case dxlibTypes.APIParameterTypeFloat32ZP,
+		dxlibTypes.APIParameterTypeFloat64ZP:
+		return 0.0
+	case dxlibTypes.APIParameterTypeBoolean:
+		return true
+	case dxlibTypes.APIParameterTypeISO8601:
+		return "2024-01-02T03:04:05Z"
+	case dxlibTypes.APIParameterTypeDate:
+		return "2024-01-02"
+	case dxlibTypes.APIParameterTypeTime:
+		return "03:04:05"
+	case dxlibTypes.APIParameterTypeJSON:
+		return sampleObject(aep.Children)
+	case dxlibTypes.APIParameterTypeJSONPassthrough:
+		return utils.JSON{}
+	case dxlibTypes.APIParameterTypeMapStringString:
+		return map[string]string{"key": "value"}
+	case dxlibTypes.APIParameterTypeArray:
+		return []any{}
+	case dxlibTypes.APIParameterTypeArrayString:
+		return []string{"sample"}
+	case dxlibTypes.APIParameterTypeArrayInt64:
+		return []int64{1}
+	case dxlibTypes.APIParameterTypeArrayJSONTemplate:
+		return []any{sampleObject(aep.Children)}
+	default:
+		return nil
+	}
+}
+
+// SamplePayload returns a request body built from the endpoint parameters. Optional
+// parameters are included so the payload exercises the whole validation path.
+func (aep *DXAPIEndPoint) SamplePayload() utils.JSON {
+	return sampleObject(aep.Parameters)
+}
+
+func sampleObject(parameters []DXAPIEndPointParameter) utils.JSON {
+	r := utils.JSON{}
+	for i := range parameters {
+		r[parameters[i].NameId] = parameters[i].SampleValue()
+	}
+	return r
+}
+
+// adjustSample moves a type-derived sample into the declared bounds. Patterns cannot be
+// inverted, so a patterned parameter keeps its type sample.
+func (c *DXAPIEndPointParameterConstraint) adjustSample(v any) any {
+	switch t := v.(type) {
+	case int:
+		if c.Minimum != nil && float64(t) < *c.Minimum {
+			t = int(math.Ceil(*c.Minimum))
+		}
+		if c.Maximum != nil && float64(t) > *c.Maximum {
+			t = int(math.Floor(*c.Maximum))
+		}
+		return t
+	case float64:
+		if c.Minimum != nil && t < *c.Minimum {
+			t = *c.Minimum
+		}
+		if c.Maximum != nil && t > *c.Maximum {
+			t = *c.Maximum
+		}
+		return t
+	case string:
+		switch c.Format {
+		case ParameterFormatEmail:
+			t = "user@example.com"
+		case ParameterFormatPhoneNumber:
+			t = "+6281234567890"
+		case ParameterFormatNPWP:
+			t = "012345678901234"
+		case ParameterFormatUUID:
+			t = "00000000-0000-0000-0000-000000000001"
+		case ParameterFormatURI:
+			t = "https://example.com"
+		case ParameterFormatIP:
+			t = "192.0.2.1"
+		case ParameterFormatDate:
+			t = "2024-01-02"
+		case ParameterFormatTime:
+			t = "03:04:05"
+		case ParameterFormatDateTime:
+			t = "2024-01-02T03:04:05Z"
+		case ParameterFormatAlphanumeric:
+			t = "sample1"
+		}
+		if c.MinLength != nil && utf8.RuneCountInString(t) < *c.MinLength {
+			t += strings.Repeat("x", *c.MinLength-utf8.RuneCountInString(t))
+		}
+		if c.MaxLength != nil && utf8.RuneCountInString(t) > *c.MaxLength {
+			t = string([]rune(t)[:*c.MaxLength])
+		}
+		return t
+	case []any:
+		for c.MinItems != nil && len(t) < *c.MinItems {
+			if len(t) == 0 {
+				t = append(t, "sample")
+			} else {
+				t = append(t, t[0])
+			}
+		}
+		return t
+	case []string:
+		for c.MinItems != nil && len(t) < *c.MinItems {
+			t = append(t, t[0])
+		}
+		return t
+	case []int64:
+		for c.MinItems != nil && len(t) < *c.MinItems {
+			t = append(t, t[0])
+		}
+		return t
+	default:
+		return v
+	}
+}
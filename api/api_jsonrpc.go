@@ -0,0 +1,510 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/donnyhardyanto/dxlib/errors"
+	"github.com/donnyhardyanto/dxlib/log"
+	dxlibTypes "github.com/donnyhardyanto/dxlib/types"
+	"github.com/donnyhardyanto/dxlib/utils"
+)
+
+// JSON-RPC 2.0 error codes. -32000 is used for every error an endpoint answers with a
+// non-2xx status other than 422 (validation) and 5xx, the HTTP status code and body
+// are carried in the error data.
+const (
+	JSONRPCErrorCodeParseError     = -32700
+	JSONRPCErrorCodeInvalidRequest = -32600
+	JSONRPCErrorCodeMethodNotFound = -32601
+	JSONRPCErrorCodeInvalidParams  = -32602
+	JSONRPCErrorCodeInternalError  = -32603
+	JSONRPCErrorCodeServerError    = -32000
+)
+
+const (
+	JSONRPCVersion              = "2.0"
+	JSONRPCDiscoverMethod       = "rpc.discover"
+	JSONRPCMethodNamingURI      = "uri"   // "/v1/user/create" is called as "v1.user.create"
+	JSONRPCMethodNamingTitle    = "title" // the endpoint Title is the method name
+	JSONRPCDefaultMaxBatchSize  = 50
+	JSONRPCMaxRequestBodyLength = 10 << 20
+)
+
+type DXAPIJSONRPCRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Id      json.RawMessage `json:"id,omitempty"`
+}
+
+// IsNotification reports whether the request carries no id, in which case no response
+// is sent for it.
+func (r *DXAPIJSONRPCRequest) IsNotification() bool {
+	return r.Id == nil
+}
+
+type DXAPIJSONRPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    any    `json:"data,omitempty"`
+}
+
+type DXAPIJSONRPCResponse struct {
+	JSONRPC string             `json:"jsonrpc"`
+	Result  json.RawMessage    `json:"result,omitempty"`
+	Error   *DXAPIJSONRPCError `json:"error,omitempty"`
+	Id      json.RawMessage    `json:"id"`
+}
+
+// DXAPIJSONRPCHandler exposes the JSON endpoints of an API as JSON-RPC 2.0 methods. Every
+// call is dispatched in-process through the endpoint pipeline, so parameter validation,
+// middlewares, privileges, audit log and OnExecute behave exactly as for a plain HTTP
+// call, and the HTTP request headers (credentials, language) apply to every call of a
+// batch. Only EndPointTypeHTTPJSON endpoints are exposed.
+//
+// rpc.discover is answered without authentication, so it is only served when
+// IsDiscoverEnabled is set (DXAPI.JSONRPCDiscoverEnabled); otherwise it is an unknown method.
+type DXAPIJSONRPCHandler struct {
+	API               *DXAPI
+	MethodNaming      string
+	MaxBatchSize      int
+	IsDiscoverEnabled bool
+
+	methodsOnce sync.Once
+	methods     map[string]*DXAPIEndPoint
+	methodNames []string
+}
+
+// NewJSONRPCHandler returns a handler for the endpoints of a. The method table is built
+// on the first call, so endpoints may still be added after the handler is created.
+func (a *DXAPI) NewJSONRPCHandler(methodNaming string) *DXAPIJSONRPCHandler {
+	if methodNaming == "" {
+		methodNaming = JSONRPCMethodNamingURI
+	}
+	return &DXAPIJSONRPCHandler{API: a, MethodNaming: methodNaming, MaxBatchSize: JSONRPCDefaultMaxBatchSize, IsDiscoverEnabled: a.JSONRPCDiscoverEnabled}
+}
+
+// JSONRPCMethodName returns the JSON-RPC method name of the endpoint for the given
+// naming, see JSONRPCMethodNamingURI and JSONRPCMethodNamingTitle.
+func (aep *DXAPIEndPoint) JSONRPCMethodName(methodNaming string) string {
+	if methodNaming == JSONRPCMethodNamingTitle {
+		return aep.Title
+	}
+	return strings.ReplaceAll(strings.Trim(aep.Uri, "/"), "/", ".")
+}
+
+func (h *DXAPIJSONRPCHandler) loadMethods() {
+	h.methodsOnce.Do(func() {
+		h.methods = map[string]*DXAPIEndPoint{}
+		for i := range h.API.EndPoints {
+			endPoint := &h.API.EndPoints[i]
+			if endPoint.EndPointType != EndPointTypeHTTPJSON {
+				continue
+			}
+			name := endPoint.JSONRPCMethodName(h.MethodNaming)
+			if name == "" || name == JSONRPCDiscoverMethod {
+				log.Log.Warnf("JSONRPC_METHOD_NAME_INVALID:%s", endPoint.Uri)
+				continue
+			}
+			if existing, ok := h.methods[name]; ok {
+				log.Log.Warnf("JSONRPC_METHOD_NAME_DUPLICATE:%s:%s:%s", name, existing.Uri, endPoint.Uri)
+				continue
+			}
+			h.methods[name] = endPoint
+			h.methodNames = append(h.methodNames, name)
+		}
+		sort.Strings(h.methodNames)
+	})
+}
+
+func (h *DXAPIJSONRPCHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	h.loadMethods()
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, JSONRPCMaxRequestBodyLength))
+	if err != nil {
+		h.writeResponse(w, newJSONRPCErrorResponse(nil, JSONRPCErrorCodeParseError, "Parse error", err.Error()))
+		return
+	}
+	body = bytes.TrimSpace(body)
+
+	if len(body) > 0 && body[0] == '[' {
+		var items []json.RawMessage
+		if err := json.Unmarshal(body, &items); err != nil {
+			h.writeResponse(w, newJSONRPCErrorResponse(nil, JSONRPCErrorCodeParseError, "Parse error", err.Error()))
+			return
+		}
+		if len(items) == 0 {
+			h.writeResponse(w, newJSONRPCErrorResponse(nil, JSONRPCErrorCodeInvalidRequest, "Invalid Request", "EMPTY_BATCH"))
+			return
+		}
+		if h.MaxBatchSize > 0 && len(items) > h.MaxBatchSize {
+			h.writeResponse(w, newJSONRPCErrorResponse(nil, JSONRPCErrorCodeInvalidRequest, "Invalid Request", "BATCH_TOO_LARGE"))
+			return
+		}
+		responses := make([]*DXAPIJSONRPCResponse, 0, len(items))
+		for _, item := range items {
+			if response := h.call(r, item); response != nil {
+				responses = append(responses, response)
+			}
+		}
+		if len(responses) == 0 {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		h.writeResponse(w, responses)
+		return
+	}
+
+	response := h.call(r, body)
+	if response == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	h.writeResponse(w, response)
+}
+
+func (h *DXAPIJSONRPCHandler) writeResponse(w http.ResponseWriter, v any) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		log.Log.Errorf(err, "JSONRPC_RESPONSE_MARSHAL_ERROR:%+v", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(b)
+}
+
+// call runs one JSON-RPC request and returns its response, nil for a notification.
+func (h *DXAPIJSONRPCHandler) call(r *http.Request, raw json.RawMessage) *DXAPIJSONRPCResponse {
+	var request DXAPIJSONRPCRequest
+	if err := json.Unmarshal(raw, &request); err != nil {
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			return newJSONRPCErrorResponse(nil, JSONRPCErrorCodeParseError, "Parse error", err.Error())
+		}
+		return newJSONRPCErrorResponse(nil, JSONRPCErrorCodeInvalidRequest, "Invalid Request", err.Error())
+	}
+	if request.JSONRPC != JSONRPCVersion || request.Method == "" {
+		return newJSONRPCErrorResponse(request.Id, JSONRPCErrorCodeInvalidRequest, "Invalid Request", nil)
+	}
+
+	response := h.execute(r, &request)
+	if request.IsNotification() {
+		return nil
+	}
+	return response
+}
+
+func (h *DXAPIJSONRPCHandler) execute(r *http.Request, request *DXAPIJSONRPCRequest) *DXAPIJSONRPCResponse {
+	if request.Method == JSONRPCDiscoverMethod && h.IsDiscoverEnabled {
+		return newJSONRPCResultResponse(request.Id, h.Discover())
+	}
+	endPoint, ok := h.methods[request.Method]
+	if !ok {
+		return newJSONRPCErrorResponse(request.Id, JSONRPCErrorCodeMethodNotFound, "Method not found", request.Method)
+	}
+	parameters, err := endPoint.jsonRPCParameters(request.Params)
+	if err != nil {
+		return newJSONRPCErrorResponse(request.Id, JSONRPCErrorCodeInvalidParams, "Invalid params", err.Error())
+	}
+
+	header := r.Header.Clone()
+	header.Del("X-Var")
+	recorder, err := h.API.invokeEndPoint(r.Context(), r, header, endPoint, parameters)
+	if err != nil {
+		return newJSONRPCErrorResponse(request.Id, JSONRPCErrorCodeInvalidParams, "Invalid params", err.Error())
+	}
+	statusCode := recorder.StatusCode()
+	if statusCode >= 200 && statusCode < 300 {
+		return newJSONRPCResultResponse(request.Id, jsonRPCResult(recorder.BodyValue()))
+	}
+	return &DXAPIJSONRPCResponse{JSONRPC: JSONRPCVersion, Error: jsonRPCErrorFromResponse(statusCode, recorder.BodyValue()), Id: request.Id}
+}
+
+// jsonRPCParameters maps JSON-RPC params to the endpoint parameters. By-position params
+// follow the declaration order of DXAPIEndPoint.Parameters.
+func (aep *DXAPIEndPoint) jsonRPCParameters(params json.RawMessage) (utils.JSON, error) {
+	params = bytes.TrimSpace(params)
+	if len(params) == 0 || bytes.Equal(params, []byte("null")) {
+		return utils.JSON{}, nil
+	}
+	switch params[0] {
+	case '{':
+		parameters := utils.JSON{}
+		if err := json.Unmarshal(params, &parameters); err != nil {
+			return nil, err
+		}
+		return parameters, nil
+	case '[':
+		var values []any
+		if err := json.Unmarshal(params, &values); err != nil {
+			return nil, err
+		}
+		if len(values) > len(aep.Parameters) {
+			return nil, errors.Errorf("TOO_MANY_POSITIONAL_PARAMS:%d>%d", len(values), len(aep.Parameters))
+		}
+		parameters := utils.JSON{}
+		for i, v := range values {
+			parameters[aep.Parameters[i].NameId] = v
+		}
+		return parameters, nil
+	default:
+		return nil, errors.Errorf("PARAMS_MUST_BE_OBJECT_OR_ARRAY")
+	}
+}
+
+// jsonRPCResult drops the HTTP status envelope from a successful endpoint response.
+func jsonRPCResult(body any) any {
+	m, ok := body.(map[string]any)
+	if !ok {
+		if s, ok := body.(string); ok && s == "" {
+			return nil
+		}
+		return body
+	}
+	result := utils.JSON{}
+	for k, v := range m {
+		switch k {
+		case "status", "status_code", "reason", "reason_message":
+		default:
+			result[k] = v
+		}
+	}
+	return result
+}
+
+// jsonRPCErrorFromResponse translates what the pipeline answered into a JSON-RPC error:
+// 422 (parameter validation and constraint violations) becomes Invalid params, 5xx
+// becomes Internal error keeping error_log_ref, and any other status, including
+// DXAPIDomainError responses, becomes a server error with the endpoint reason as
+// message. The original status code and body are kept in data.
+func jsonRPCErrorFromResponse(statusCode int, body any) *DXAPIJSONRPCError {
+	data := utils.JSON{"status_code": statusCode}
+	message := http.StatusText(statusCode)
+	if m, ok := body.(map[string]any); ok {
+		for k, v := range m {
+			if k != "status" && k != "status_code" {
+				data[k] = v
+			}
+		}
+		if reason, ok := m["reason"].(string); ok && reason != "" {
+			message = reason
+		}
+	} else if s, ok := body.(string); ok && s != "" {
+		data["body"] = s
+	}
+
+	switch {
+	case statusCode == http.StatusUnprocessableEntity:
+		return &DXAPIJSONRPCError{Code: JSONRPCErrorCodeInvalidParams, Message: message, Data: data}
+	case statusCode >= 500:
+		internal := utils.JSON{"status_code": statusCode}
+		if ref, ok := data["error_log_ref"]; ok {
+			internal["error_log_ref"] = ref
+		}
+		return &DXAPIJSONRPCError{Code: JSONRPCErrorCodeInternalError, Message: "Internal error", Data: internal}
+	default:
+		return &DXAPIJSONRPCError{Code: JSONRPCErrorCodeServerError, Message: message, Data: data}
+	}
+}
+
+func newJSONRPCResultResponse(id json.RawMessage, result any) *DXAPIJSONRPCResponse {
+	b, err := json.Marshal(result)
+	if err != nil {
+		return newJSONRPCErrorResponse(id, JSONRPCErrorCodeInternalError, "Internal error", nil)
+	}
+	return &DXAPIJSONRPCResponse{JSONRPC: JSONRPCVersion, Result: b, Id: id}
+}
+
+func newJSONRPCErrorResponse(id json.RawMessage, code int, message string, data any) *DXAPIJSONRPCResponse {
+	return &DXAPIJSONRPCResponse{JSONRPC: JSONRPCVersion, Error: &DXAPIJSONRPCError{Code: code, Message: message, Data: data}, Id: id}
+}
+
+// Discover returns the OpenRPC document served by rpc.discover, generated from the
+// endpoint specs.
+func (h *DXAPIJSONRPCHandler) Discover() utils.JSON {
+	h.loadMethods()
+	methods := make([]utils.JSON, 0, len(h.methodNames))
+	for _, name := range h.methodNames {
+		endPoint := h.methods[name]
+		params := make([]utils.JSON, 0, len(endPoint.Parameters))
+		for i := range endPoint.Parameters {
+			p := &endPoint.Parameters[i]
+			params = append(params, utils.JSON{
+				"name":        p.NameId,
+				"description": p.Description,
+				"required":    p.IsMustExist,
+				"schema":      p.JSONSchema(),
+			})
+		}
+		method := utils.JSON{
+			"name":           name,
+			"summary":        endPoint.Title,
+			"description":    endPoint.Description,
+			"paramStructure": "either",
+			"params":         params,
+			"result":         utils.JSON{"name": "result", "schema": endPoint.jsonRPCResultSchema()},
+		}
+		if errs := endPoint.jsonRPCErrors(); len(errs) > 0 {
+			method["errors"] = errs
+		}
+		methods = append(methods, method)
+	}
+	return utils.JSON{
+		"openrpc": "1.2.6",
+		"info":    utils.JSON{"title": h.API.NameId, "version": h.API.Version},
+		"methods": methods,
+	}
+}
+
+func (aep *DXAPIEndPoint) jsonRPCResultSchema() utils.JSON {
+	if aep.ResponsePossibilities != nil {
+		for _, rp := range *aep.ResponsePossibilities {
+			if rp.StatusCode >= 200 && rp.StatusCode < 300 && len(rp.DataTemplate) > 0 {
+				return jsonSchemaObject(rp.DataTemplate)
+			}
+		}
+	}
+	return utils.JSON{"type": "object"}
+}
+
+func (aep *DXAPIEndPoint) jsonRPCErrors() []utils.JSON {
+	if aep.ResponsePossibilities == nil {
+		return nil
+	}
+	keys := make([]string, 0, len(*aep.ResponsePossibilities))
+	for k := range *aep.ResponsePossibilities {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var r []utils.JSON
+	for _, k := range keys {
+		rp := (*aep.ResponsePossibilities)[k]
+		if rp.StatusCode < 300 {
+			continue
+		}
+		e := jsonRPCErrorFromResponse(rp.StatusCode, nil)
+		r = append(r, utils.JSON{"code": e.Code, "message": k, "data": utils.JSON{"status_code": rp.StatusCode, "description": rp.Description}})
+	}
+	return r
+}
+
+// JSONSchema describes the parameter, including its Enum and Constraint, as a JSON
+// Schema.
+func (aep *DXAPIEndPointParameter) JSONSchema() utils.JSON {
+	var s utils.JSON
+	switch aep.Type {
+	case dxlibTypes.APIParameterTypeInt32, dxlibTypes.APIParameterTypeInt32P, dxlibTypes.APIParameterTypeInt32ZP,
+		dxlibTypes.APIParameterTypeInt64, dxlibTypes.APIParameterTypeInt64P, dxlibTypes.APIParameterTypeInt64ZP,
+		dxlibTypes.APIParameterTypeNullableInt32, dxlibTypes.APIParameterTypeNullableInt64, dxlibTypes.APIParameterTypeID:
+		s = utils.JSON{"type": "integer"}
+	case dxlibTypes.APIParameterTypeFloat32, dxlibTypes.APIParameterTypeFloat32P, dxlibTypes.APIParameterTypeFloat32ZP,
+		dxlibTypes.APIParameterTypeFloat64, dxlibTypes.APIParameterTypeFloat64P, dxlibTypes.APIParameterTypeFloat64ZP,
+		dxlibTypes.APIParameterTypeMoney:
+		s = utils.JSON{"type": "number"}
+	case dxlibTypes.APIParameterTypeBoolean:
+		s = utils.JSON{"type": "boolean"}
+	case dxlibTypes.APIParameterTypeEmail:
+		s = utils.JSON{"type": "string", "format": "email"}
+	case dxlibTypes.APIParameterTypeISO8601:
+		s = utils.JSON{"type": "string", "format": "date-time"}
+	case dxlibTypes.APIParameterTypeDate:
+		s = utils.JSON{"type": "string", "format": "date"}
+	case dxlibTypes.APIParameterTypeTime:
+		s = utils.JSON{"type": "string", "format": "time"}
+	case dxlibTypes.APIParameterTypeJSON:
+		s = jsonSchemaObject(aep.Children)
+	case dxlibTypes.APIParameterTypeJSONPassthrough:
+		s = utils.JSON{"type": "object"}
+	case dxlibTypes.APIParameterTypeMapStringString:
+		s = utils.JSON{"type": "object", "additionalProperties": utils.JSON{"type": "string"}}
+	case dxlibTypes.APIParameterTypeArray:
+		s = utils.JSON{"type": "array"}
+	case dxlibTypes.APIParameterTypeArrayString:
+		s = utils.JSON{"type": "array", "items": utils.JSON{"type": "string"}}
+	case dxlibTypes.APIParameterTypeArrayInt64:
+		s = utils.JSON{"type": "array", "items": utils.JSON{"type": "integer"}}
+	case dxlibTypes.APIParameterTypeArrayJSONTemplate:
+		s = utils.JSON{"type": "array", "items": jsonSchemaObject(aep.Children)}
+	default:
+		s = utils.JSON{"type": "string"}
+	}
+	s["x-dxlib-type"] = string(aep.Type)
+	if aep.Description != "" {
+		s["description"] = aep.Description
+	}
+	if len(aep.Enum) > 0 {
+		s["enum"] = aep.Enum
+	}
+
+	c := &aep.Constraint
+	if c.Minimum != nil {
+		s["minimum"] = *c.Minimum
+	}
+	if c.Maximum != nil {
+		s["maximum"] = *c.Maximum
+	}
+	if c.MinLength != nil {
+		s["minLength"] = *c.MinLength
+	}
+	if c.MaxLength != nil {
+		s["maxLength"] = *c.MaxLength
+	}
+	if c.Pattern != "" {
+		s["pattern"] = c.Pattern
+	}
+	if c.MinItems != nil {
+		s["minItems"] = *c.MinItems
+	}
+	if c.MaxItems != nil {
+		s["maxItems"] = *c.MaxItems
+	}
+	if c.Format != "" {
+		s["format"] = c.Format
+	}
+
+	if aep.IsNullable {
+		if t, ok := s["type"].(string); ok {
+			s["type"] = []string{t, "null"}
+		}
+	}
+	return s
+}
+
+func jsonSchemaObject[T DXAPIEndPointParameter | *DXAPIEndPointParameter](parameters []T) utils.JSON {
+	properties := utils.JSON{}
+	required := []string{}
+	for _, v := range parameters {
+		var p *DXAPIEndPointParameter
+		switch t := any(v).(type) {
+		case DXAPIEndPointParameter:
+			p = &t
+		case *DXAPIEndPointParameter:
+			p = t
+		}
+		if p == nil {
+			continue
+		}
+		properties[p.NameId] = p.JSONSchema()
+		if p.IsMustExist {
+			required = append(required, p.NameId)
+		}
+	}
+	s := utils.JSON{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		s["required"] = required
+	}
+	return s
+}
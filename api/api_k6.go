@@ -0,0 +1,238 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/donnyhardyanto/dxlib/errors"
+	"github.com/donnyhardyanto/dxlib/utils"
+)
+
+// DXAPIK6Options configures the k6 scenario generator. Zero values fall back to the
+// defaults noted on each field.
+type DXAPIK6Options struct {
+	DXLibImportPath    string                // path of dxlib-k6.js relative to the generated files, default "../dxlib-k6.js"
+	APIAddressEnvName  string                // k6 environment variable holding the API address, default "API_ADDRESS"
+	APIAddress         string                // fallback API address when the environment variable is not set, default "http://localhost:8080"
+	LoginURI           string                // when set, setup() logs in once and every VU reuses the session key
+	LoginPayload       utils.JSON            // request body sent to LoginURI
+	SessionKeyPath     string                // dotted path of the session key in the login response, default "data.session_key"
+	VUs                int                   // default 1
+	Duration           string                // default "30s"
+	DefaultThresholds  []string              // http_req_duration thresholds, default ["p(95)<500"]
+	EndPointThresholds map[string][]string   // http_req_duration thresholds keyed by endpoint Uri, overrides DefaultThresholds
+	PayloadOverrides   map[string]utils.JSON // values keyed by endpoint Uri, merged over the generated sample payload
+}
+
+func (o *DXAPIK6Options) applyDefaults() {
+	if o.DXLibImportPath == "" {
+		o.DXLibImportPath = "../dxlib-k6.js"
+	}
+	if o.APIAddressEnvName == "" {
+		o.APIAddressEnvName = "API_ADDRESS"
+	}
+	if o.APIAddress == "" {
+		o.APIAddress = "http://localhost:8080"
+	}
+	if o.SessionKeyPath == "" {
+		o.SessionKeyPath = "data.session_key"
+	}
+	if o.VUs <= 0 {
+		o.VUs = 1
+	}
+	if o.Duration == "" {
+		o.Duration = "30s"
+	}
+	if len(o.DefaultThresholds) == 0 {
+		o.DefaultThresholds = []string{"p(95)<500"}
+	}
+}
+
+// IsK6Supported reports whether a plain JSON request can exercise the endpoint. Stream
+// uploads, websockets and end-to-end encrypted endpoints need hand-written scenarios.
+func (aep *DXAPIEndPoint) IsK6Supported() bool {
+	switch aep.EndPointType {
+	case EndPointTypeHTTPJSON, EndPointTypeHTTPDownloadStream, EndPointTypeHTTPDownloadStreamV2:
+		return true
+	default:
+		return false
+	}
+}
+
+// ExpectedStatusCodes returns the sorted status codes declared in ResponsePossibilities,
+// or 200 when the endpoint declares none.
+func (aep *DXAPIEndPoint) ExpectedStatusCodes() []int {
+	if aep.ResponsePossibilities == nil || len(*aep.ResponsePossibilities) == 0 {
+		return []int{http.StatusOK}
+	}
+	seen := map[int]bool{}
+	var r []int
+	for _, v := range *aep.ResponsePossibilities {
+		if !seen[v.StatusCode] {
+			seen[v.StatusCode] = true
+			r = append(r, v.StatusCode)
+		}
+	}
+	sort.Ints(r)
+	return r
+}
+
+// GenerateK6Scenario renders a k6 script that sends a sample payload to the endpoint and
+// checks the response against the declared status codes.
+func (aep *DXAPIEndPoint) GenerateK6Scenario(options DXAPIK6Options) (s string, err error) {
+	options.applyDefaults()
+	if !aep.IsK6Supported() {
+		return "", errors.Errorf("K6_ENDPOINT_TYPE_NOT_SUPPORTED:%s:%s", aep.Uri, aep.EndPointType.String())
+	}
+
+	payload := aep.SamplePayload()
+	for k, v := range options.PayloadOverrides[aep.Uri] {
+		payload[k] = v
+	}
+
+	thresholds, ok := options.EndPointThresholds[aep.Uri]
+	if !ok {
+		thresholds = options.DefaultThresholds
+	}
+
+	method := strings.ToUpper(aep.Method)
+	if method == "" {
+		method = http.MethodPost
+	}
+
+	statusCodes := aep.ExpectedStatusCodes()
+	statusCodesAsStrings := make([]string, len(statusCodes))
+	for i, v := range statusCodes {
+		statusCodesAsStrings[i] = fmt.Sprintf("%d", v)
+	}
+
+	uri := jsString(aep.Uri)
+	tag := fmt.Sprintf("{endpoint: %s}", uri)
+	payloadAsBytes, err := json.MarshalIndent(payload, "    ", "    ")
+	if err != nil {
+		return "", errors.Wrapf(err, "K6_SAMPLE_PAYLOAD_MARSHAL_ERROR:%s", aep.Uri)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "// Generated from endpoint %s %s (%s). Do not edit by hand.\n", method, aep.Uri, aep.Title)
+	b.WriteString("import http from 'k6/http';\n")
+	b.WriteString("import {check} from 'k6';\n")
+	fmt.Fprintf(&b, "import dxlib from %s;\n\n", jsString(options.DXLibImportPath))
+
+	b.WriteString("export const options = {\n")
+	fmt.Fprintf(&b, "    vus: %d,\n", options.VUs)
+	fmt.Fprintf(&b, "    duration: %s,\n", jsString(options.Duration))
+	b.WriteString("    thresholds: {\n")
+	fmt.Fprintf(&b, "        %s: %s,\n", jsString("http_req_duration{endpoint:"+aep.Uri+"}"), jsStringArray(thresholds))
+	fmt.Fprintf(&b, "        %s: %s,\n", jsString("checks{endpoint:"+aep.Uri+"}"), jsStringArray([]string{"rate>0.99"}))
+	b.WriteString("    },\n")
+	b.WriteString("};\n\n")
+
+	fmt.Fprintf(&b, "const API_ADDRESS = __ENV[%s] || %s;\n\n", jsString(options.APIAddressEnvName), jsString(options.APIAddress))
+
+	b.WriteString("export async function setup() {\n")
+	b.WriteString("    const client = new dxlib.Client(API_ADDRESS, '', '');\n")
+	b.WriteString("    client.http = http;\n")
+	if options.LoginURI != "" {
+		loginPayloadAsBytes, err := json.MarshalIndent(options.LoginPayload, "    ", "    ")
+		if err != nil {
+			return "", errors.Wrap(err, "K6_LOGIN_PAYLOAD_MARSHAL_ERROR")
+		}
+		fmt.Fprintf(&b, "    const loginResponse = await dxlib.api(client, %s, %s, true);\n", jsString(options.LoginURI), loginPayloadAsBytes)
+		b.WriteString("    let sessionKey = loginResponse.json();\n")
+		fmt.Fprintf(&b, "    for (const part of %s.split('.')) {\n", jsString(options.SessionKeyPath))
+		b.WriteString("        sessionKey = sessionKey?.[part];\n")
+		b.WriteString("    }\n")
+		b.WriteString("    if ((sessionKey === null) || (sessionKey === undefined)) {\n")
+		fmt.Fprintf(&b, "        throw new Error(%s);\n", jsString(options.LoginURI+": session key not found at "+options.SessionKeyPath))
+		b.WriteString("    }\n")
+		b.WriteString("    client.sessionKey = sessionKey;\n")
+	}
+	b.WriteString("    return {sessionKey: client.sessionKey};\n")
+	b.WriteString("}\n\n")
+
+	fmt.Fprintf(&b, "const payload = %s;\n\n", payloadAsBytes)
+
+	b.WriteString("export default async function (data) {\n")
+	b.WriteString("    const client = new dxlib.Client(API_ADDRESS, '', '');\n")
+	b.WriteString("    client.http = http;\n")
+	b.WriteString("    client.sessionKey = data.sessionKey;\n")
+	fmt.Fprintf(&b, "    const response = await dxlib.api(client, %s, payload, false, %s, %s);\n", uri, jsString(method), tag)
+	b.WriteString("    check(response, {\n")
+	fmt.Fprintf(&b, "        %s: (r) => [%s].includes(r.status),\n", jsString("status is one of "+strings.Join(statusCodesAsStrings, ", ")), strings.Join(statusCodesAsStrings, ", "))
+	fmt.Fprintf(&b, "    }, %s);\n", tag)
+	b.WriteString("}\n")
+	return b.String(), nil
+}
+
+// GenerateK6Scenarios renders one k6 script per supported endpoint, keyed by file name.
+func (a *DXAPI) GenerateK6Scenarios(options DXAPIK6Options) (r map[string]string, err error) {
+	r = map[string]string{}
+	for i := range a.EndPoints {
+		aep := &a.EndPoints[i]
+		if !aep.IsK6Supported() {
+			a.Log.Warnf("K6_ENDPOINT_SKIPPED:%s:%s", aep.Uri, aep.EndPointType.String())
+			continue
+		}
+		s, err := aep.GenerateK6Scenario(options)
+		if err != nil {
+			return nil, err
+		}
+		fileName := k6ScenarioFileName(aep.Uri, "")
+		if _, exists := r[fileName]; exists {
+			fileName = k6ScenarioFileName(aep.Uri, aep.Method)
+		}
+		r[fileName] = s
+	}
+	return r, nil
+}
+
+// WriteK6Scenarios writes the generated scripts into dir, creating it when needed.
+func (a *DXAPI) WriteK6Scenarios(dir string, options DXAPIK6Options) (err error) {
+	scenarios, err := a.GenerateK6Scenarios(options)
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(dir, 0755)
+	if err != nil {
+		return errors.Wrapf(err, "K6_SCENARIO_DIR_CREATE_ERROR:%s", dir)
+	}
+	for fileName, s := range scenarios {
+		err = os.WriteFile(filepath.Join(dir, fileName), []byte(s), 0644)
+		if err != nil {
+			return errors.Wrapf(err, "K6_SCENARIO_WRITE_ERROR:%s", fileName)
+		}
+	}
+	return nil
+}
+
+func k6ScenarioFileName(uri string, method string) string {
+	name := strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '-' {
+			return r
+		}
+		return '_'
+	}, strings.Trim(uri, "/"))
+	if name == "" {
+		name = "root"
+	}
+	if method != "" {
+		name += "_" + strings.ToLower(method)
+	}
+	return name + ".js"
+}
+
+func jsString(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
+
+func jsStringArray(a []string) string {
+	b, _ := json.Marshal(a)
+	return string(b)
+}
@@ -0,0 +1,52 @@
+package api
+
+import (
+	"strings"
+	"testing"
+
+	dxlibTypes "github.com/donnyhardyanto/dxlib/types"
+)
+
+func TestGenerateK6Scenario(t *testing.T) {
+	aep := &DXAPIEndPoint{
+		Title:        "User Create",
+		Uri:          "/v1/user/create",
+		Method:       "POST",
+		EndPointType: EndPointTypeHTTPJSON,
+		Parameters: []DXAPIEndPointParameter{
+			{NameId: "email", Type: dxlibTypes.APIParameterTypeEmail, IsMustExist: true},
+			{NameId: "status", Type: dxlibTypes.APIParameterTypeString, Enum: []any{"ACTIVE", "INACTIVE"}},
+			{NameId: "age", Type: dxlibTypes.APIParameterTypeInt64P},
+		},
+		ResponsePossibilities: &DXAPIEndPointResponsePossibilities{
+			"success": {StatusCode: 200},
+			"invalid": {StatusCode: 422},
+		},
+	}
+
+	s, err := aep.GenerateK6Scenario(DXAPIK6Options{
+		LoginURI:           "/v1/login",
+		EndPointThresholds: map[string][]string{"/v1/user/create": {"p(99)<800"}},
+	})
+	if err != nil {
+		t.Fatalf("GenerateK6Scenario: %v", err)
+	}
+	for _, want := range []string{
+		`"email": "user@example.com"`,
+		`"status": "ACTIVE"`,
+		`"age": 1`,
+		`[200, 422].includes(r.status)`,
+		`"http_req_duration{endpoint:/v1/user/create}": ["p(99)\u003c800"]`,
+		`await dxlib.api(client, "/v1/login"`,
+		`await dxlib.api(client, "/v1/user/create", payload, false, "POST", {endpoint: "/v1/user/create"})`,
+	} {
+		if !strings.Contains(s, want) {
+			t.Errorf("scenario missing %q:\n%s", want, s)
+		}
+	}
+
+	aep.EndPointType = EndPointTypeWS
+	if _, err := aep.GenerateK6Scenario(DXAPIK6Options{}); err == nil {
+		t.Errorf("expected websocket endpoint to be rejected")
+	}
+}
@@ -0,0 +1,144 @@
+package api
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/donnyhardyanto/dxlib/errors"
+	"github.com/donnyhardyanto/dxlib/utils"
+)
+
+// Mock mode lets clients develop and run contract tests against an API whose backends
+// (databases, Redis, upstream services) do not exist yet. PreProcessRequest still
+// validates every request; middlewares and OnExecute are skipped and the response is
+// synthesized from the endpoint's ResponsePossibilities.
+//
+// The caller picks the response with the X-Mock-Response header or the _mock_response
+// query parameter, either by possibility key ("success", "invalid_request") or by
+// status code ("409"). X-Mock-Latency-Ms / _mock_latency_ms adds an artificial delay.
+const (
+	MockResponseHeaderName = "X-Mock-Response"
+	MockLatencyHeaderName  = "X-Mock-Latency-Ms"
+	MockResponseQueryName  = "_mock_response"
+	MockLatencyQueryName   = "_mock_latency_ms"
+
+	MockMaxLatencyMs = 60000
+)
+
+// SelectMockResponsePossibility resolves the selector sent by the client to one of the
+// declared response possibilities. An empty selector picks "success", or the lowest
+// 2xx possibility when there is no "success" key. A numeric selector with no matching
+// declaration synthesizes a bare possibility with that status code.
+func (aep *DXAPIEndPoint) SelectMockResponsePossibility(selector string) (key string, rp DXAPIEndPointResponsePossibility, err error) {
+	selector = strings.TrimSpace(selector)
+	var possibilities DXAPIEndPointResponsePossibilities
+	if aep.ResponsePossibilities != nil {
+		possibilities = *aep.ResponsePossibilities
+	}
+
+	keys := make([]string, 0, len(possibilities))
+	for k := range possibilities {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if possibilities[keys[i]].StatusCode != possibilities[keys[j]].StatusCode {
+			return possibilities[keys[i]].StatusCode < possibilities[keys[j]].StatusCode
+		}
+		return keys[i] < keys[j]
+	})
+
+	if selector == "" {
+		if v, ok := possibilities["success"]; ok {
+			return "success", v, nil
+		}
+		for _, k := range keys {
+			if possibilities[k].StatusCode >= 200 && possibilities[k].StatusCode < 300 {
+				return k, possibilities[k], nil
+			}
+		}
+		return "success", DXAPIEndPointResponsePossibility{Owner: aep, StatusCode: http.StatusOK}, nil
+	}
+
+	if v, ok := possibilities[selector]; ok {
+		return selector, v, nil
+	}
+	statusCode, convErr := strconv.Atoi(selector)
+	if convErr != nil {
+		return "", rp, errors.Errorf("MOCK_RESPONSE_NOT_DECLARED:%s", selector)
+	}
+	for _, k := range keys {
+		if possibilities[k].StatusCode == statusCode {
+			return k, possibilities[k], nil
+		}
+	}
+	if http.StatusText(statusCode) == "" {
+		return "", rp, errors.Errorf("MOCK_RESPONSE_INVALID_STATUS_CODE:%d", statusCode)
+	}
+	return selector, DXAPIEndPointResponsePossibility{Owner: aep, StatusCode: statusCode}, nil
+}
+
+// MockResponseBody returns the Example attached to the possibility, or a body built
+// from its DataTemplate. Error possibilities without an example carry the key as reason.
+func (rp *DXAPIEndPointResponsePossibility) MockResponseBody(key string) utils.JSON {
+	if rp.Example != nil {
+		r := utils.JSON{}
+		for k, v := range rp.Example {
+			r[k] = v
+		}
+		return r
+	}
+	r := utils.JSON{}
+	for _, p := range rp.DataTemplate {
+		if p != nil {
+			r[p.NameId] = p.SampleValue()
+		}
+	}
+	if rp.StatusCode >= 300 {
+		r["reason"] = "MOCK_" + strings.ToUpper(key)
+		if rp.Description != "" {
+			r["reason_message"] = rp.Description
+		}
+	}
+	return r
+}
+
+func (aepr *DXAPIEndPointRequest) mockParameter(headerName string, queryName string) string {
+	s := aepr.Request.Header.Get(headerName)
+	if s == "" {
+		s = aepr.Request.URL.Query().Get(queryName)
+	}
+	return s
+}
+
+// executeMock writes the synthesized response for a request that already passed
+// PreProcessRequest.
+func (aepr *DXAPIEndPointRequest) executeMock() (err error) {
+	latencyMs := aepr.EndPoint.Owner.MockLatencyMs
+	if s := aepr.mockParameter(MockLatencyHeaderName, MockLatencyQueryName); s != "" {
+		latencyMs, err = strconv.Atoi(s)
+		if err != nil || latencyMs < 0 {
+			return aepr.WriteResponseAndNewErrorf(http.StatusBadRequest, "", "MOCK_INVALID_LATENCY:%s", s)
+		}
+	}
+	if latencyMs > MockMaxLatencyMs {
+		latencyMs = MockMaxLatencyMs
+	}
+	if latencyMs > 0 {
+		select {
+		case <-time.After(time.Duration(latencyMs) * time.Millisecond):
+		case <-aepr.Context.Done():
+			return aepr.Context.Err()
+		}
+	}
+
+	key, rp, err := aepr.EndPoint.SelectMockResponsePossibility(aepr.mockParameter(MockResponseHeaderName, MockResponseQueryName))
+	if err != nil {
+		return aepr.WriteResponseAndNewErrorf(http.StatusBadRequest, "", "%s", err.Error())
+	}
+	aepr.Log.Debugf("MOCK_RESPONSE:%s:%s:%d", aepr.EndPoint.Uri, key, rp.StatusCode)
+	aepr.WriteResponseAsJSON(rp.StatusCode, rp.Headers, rp.MockResponseBody(key))
+	return nil
+}
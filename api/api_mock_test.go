@@ -0,0 +1,52 @@
+package api
+
+import (
+	"testing"
+
+	dxlibTypes "github.com/donnyhardyanto/dxlib/types"
+	"github.com/donnyhardyanto/dxlib/utils"
+)
+
+func TestSelectMockResponsePossibility(t *testing.T) {
+	aep := &DXAPIEndPoint{
+		Uri: "/v1/user/create",
+		ResponsePossibilities: &DXAPIEndPointResponsePossibilities{
+			"success": {StatusCode: 200, DataTemplate: []*DXAPIEndPointParameter{
+				{NameId: "id", Type: dxlibTypes.APIParameterTypeInt64ZP},
+			}},
+			"invalid_credential": {StatusCode: 409, Example: utils.JSON{"reason": "USER_ALREADY_EXISTS"}},
+			"internal_error":     {StatusCode: 500},
+		},
+	}
+
+	key, rp, err := aep.SelectMockResponsePossibility("")
+	if err != nil || key != "success" || rp.StatusCode != 200 {
+		t.Fatalf("default selection: key=%q status=%d err=%v", key, rp.StatusCode, err)
+	}
+	if body := rp.MockResponseBody(key); body["id"] != 0 {
+		t.Errorf("success body should come from DataTemplate, got %v", body)
+	}
+
+	key, rp, err = aep.SelectMockResponsePossibility("409")
+	if err != nil || key != "invalid_credential" {
+		t.Fatalf("status selection: key=%q err=%v", key, err)
+	}
+	if body := rp.MockResponseBody(key); body["reason"] != "USER_ALREADY_EXISTS" {
+		t.Errorf("409 body should come from Example, got %v", body)
+	}
+
+	key, rp, err = aep.SelectMockResponsePossibility("internal_error")
+	if err != nil || rp.StatusCode != 500 {
+		t.Fatalf("key selection: key=%q err=%v", key, err)
+	}
+	if body := rp.MockResponseBody(key); body["reason"] != "MOCK_INTERNAL_ERROR" {
+		t.Errorf("500 body should carry the possibility key, got %v", body)
+	}
+
+	if _, rp, err = aep.SelectMockResponsePossibility("404"); err != nil || rp.StatusCode != 404 {
+		t.Errorf("undeclared status code should be synthesized: status=%d err=%v", rp.StatusCode, err)
+	}
+	if _, _, err = aep.SelectMockResponsePossibility("no_such_key"); err == nil {
+		t.Errorf("unknown key should be rejected")
+	}
+}
@@ -5,7 +5,9 @@ import (
 	"net/http"
 
 	"github.com/donnyhardyanto/dxlib/api"
+	"github.com/donnyhardyanto/dxlib/databases"
 	"github.com/donnyhardyanto/dxlib/errors"
+	"github.com/donnyhardyanto/dxlib/utils"
 )
 
 func LogRequest(r *http.Request) (map[string]interface{}, error) {
@@ -45,3 +47,22 @@ func Ping(aepr *api.DXAPIEndPointRequest) (err error) {
 	aepr.WriteResponseAsJSON(http.StatusOK, nil, data)
 	return nil
 }
+
+// DatabasePoolStats responds with the live connection pool and concurrency slot
+// statistics of every managed database. Register it on an admin-only endpoint.
+func DatabasePoolStats(aepr *api.DXAPIEndPointRequest) (err error) {
+	aepr.WriteResponseAsJSON(http.StatusOK, nil, utils.JSON{
+		"databases": databases.Manager.PoolStats(),
+	})
+	return nil
+}
+
+// DatabaseSlowQueries responds with the slow query log of every managed database that has
+// one: recent samples (masked args, plan when captured) and p50/p95 per statement
+// fingerprint. Register it on an admin-only endpoint.
+func DatabaseSlowQueries(aepr *api.DXAPIEndPointRequest) (err error) {
+	aepr.WriteResponseAsJSON(http.StatusOK, nil, utils.JSON{
+		"databases": databases.Manager.SlowQueryStats(),
+	})
+	return nil
+}
@@ -116,6 +116,15 @@ func (a *DXApp) Run() (err error) {
 		}
 	}
 
+	if ac := a.argCommand(); ac != nil {
+		err = a.executeArgCommand(ac)
+		if err != nil {
+			log.Log.Error(err.Error(), err)
+			return err
+		}
+		return nil
+	}
+
 	err = a.execute()
 	if err != nil {
 		log.Log.Error(err.Error(), err)
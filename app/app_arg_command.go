@@ -0,0 +1,50 @@
+package app
+
+import (
+	"os"
+
+	"github.com/donnyhardyanto/dxlib/core"
+	"github.com/donnyhardyanto/dxlib/databases"
+	"github.com/donnyhardyanto/dxlib/errors"
+	"github.com/donnyhardyanto/dxlib/log"
+)
+
+// AddArgCommand registers a command-line verb. "<binary> <command> [args...]" loads the
+// configuration and runs callback with the remaining arguments ([]string) as T, instead
+// of starting the app. Databases are connected on first use.
+func (a *DXApp) AddArgCommand(name string, command string, callback DXAppArgCommandFunc) *DXAppArgCommand {
+	ac := &DXAppArgCommand{
+		name:     name,
+		command:  command,
+		callback: &callback,
+	}
+	a.Args.Commands[command] = ac
+	return ac
+}
+
+func (a *DXApp) argCommand() *DXAppArgCommand {
+	if len(os.Args) < 2 {
+		return nil
+	}
+	return a.Args.Commands[os.Args[1]]
+}
+
+func (a *DXApp) executeArgCommand(ac *DXAppArgCommand) (err error) {
+	defer core.RootContextCancel()
+	log.Log.Infof("%v %v: %s", a.Title, a.Version, ac.name)
+	err = a.loadConfiguration()
+	if err != nil {
+		return err
+	}
+	if a.IsStorageExist {
+		defer func() {
+			if errDisconnect := databases.Manager.DisconnectAll(); errDisconnect != nil {
+				log.Log.Warnf("DISCONNECT_ERROR:%s", errDisconnect.Error())
+			}
+		}()
+	}
+	if ac.callback == nil {
+		return errors.Errorf("ARG_COMMAND_HAS_NO_CALLBACK:%s", ac.command)
+	}
+	return (*ac.callback)(a, ac, os.Args[2:])
+}
@@ -0,0 +1,97 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/donnyhardyanto/dxlib/core"
+	"github.com/donnyhardyanto/dxlib/databases"
+	"github.com/donnyhardyanto/dxlib/errors"
+)
+
+// AddMigrateArgCommand registers the schema migration verb for one database:
+//
+//	<binary> <command> status
+//	<binary> <command> up [version] [--dry-run] [--allow-drift]
+//	<binary> <command> down [steps] [--dry-run] [--allow-drift]
+//
+// down reverts one migration unless steps is given.
+func (a *DXApp) AddMigrateArgCommand(command string, databaseNameId string, migrations []*databases.DXDatabaseMigration) *DXAppArgCommand {
+	return a.AddArgCommand("Migrate "+databaseNameId, command, func(s *DXApp, ac *DXAppArgCommand, T any) (err error) {
+		d, ok := databases.Manager.Databases[databaseNameId]
+		if !ok {
+			return errors.Errorf("DATABASE_NOT_FOUND:%s", databaseNameId)
+		}
+		migrator := d.NewMigrator(migrations)
+
+		var positional []string
+		for _, arg := range T.([]string) {
+			switch arg {
+			case "--dry-run":
+				migrator.DryRun = true
+			case "--allow-drift":
+				migrator.AllowDrift = true
+			default:
+				positional = append(positional, arg)
+			}
+		}
+		if len(positional) == 0 {
+			return errors.Errorf("MIGRATE_USAGE:%s status|up [version]|down [steps] [--dry-run] [--allow-drift]", command)
+		}
+
+		ctx := core.RootContext
+		switch positional[0] {
+		case "status":
+			statuses, err := migrator.Status(ctx)
+			if err != nil {
+				return err
+			}
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			_, _ = fmt.Fprintln(w, "VERSION\tNAME\tSTATE\tAPPLIED_AT\tDURATION_MS\tAPPLIED_BY")
+			for _, st := range statuses {
+				appliedAt := ""
+				if !st.AppliedAt.IsZero() {
+					appliedAt = st.AppliedAt.Format("2006-01-02 15:04:05Z07:00")
+				}
+				_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%s\n", st.Version, st.Name, st.State, appliedAt, st.DurationMs, st.AppliedBy)
+			}
+			return w.Flush()
+		case "up":
+			targetVersion := ""
+			if len(positional) > 1 {
+				targetVersion = positional[1]
+			}
+			applied, err := migrator.Up(ctx, targetVersion)
+			if err != nil {
+				return err
+			}
+			_, _ = fmt.Fprintf(os.Stdout, "%d migration(s) %s\n", len(applied), migrateOutcome(migrator, "applied"))
+			return nil
+		case "down":
+			steps := 1
+			if len(positional) > 1 {
+				steps, err = strconv.Atoi(positional[1])
+				if err != nil || steps < 1 {
+					return errors.Errorf("MIGRATE_INVALID_STEPS:%s", positional[1])
+				}
+			}
+			reverted, err := migrator.Down(ctx, steps)
+			if err != nil {
+				return err
+			}
+			_, _ = fmt.Fprintf(os.Stdout, "%d migration(s) %s\n", len(reverted), migrateOutcome(migrator, "reverted"))
+			return nil
+		default:
+			return errors.Errorf("MIGRATE_UNKNOWN_ACTION:%s", positional[0])
+		}
+	})
+}
+
+func migrateOutcome(migrator *databases.DXDatabaseMigrator, done string) string {
+	if migrator.DryRun {
+		return "planned"
+	}
+	return done
+}
@@ -0,0 +1,76 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/donnyhardyanto/dxlib/core"
+	"github.com/donnyhardyanto/dxlib/databases"
+	"github.com/donnyhardyanto/dxlib/errors"
+	"github.com/donnyhardyanto/dxlib/log"
+)
+
+// AddModelReverseArgCommand registers a verb that writes the Go source of a ModelDB
+// built from the live schemas of one database:
+//
+//	<binary> <command> <schema>[,<schema>...] [--package=name] [--func=name] [--out=file]
+//
+// The source goes to stdout unless --out is given. Column types and objects the model
+// cannot express are logged as warnings.
+func (a *DXApp) AddModelReverseArgCommand(command string, databaseNameId string) *DXAppArgCommand {
+	return a.AddArgCommand("Reverse model of "+databaseNameId, command, func(s *DXApp, ac *DXAppArgCommand, T any) (err error) {
+		d, ok := databases.Manager.Databases[databaseNameId]
+		if !ok {
+			return errors.Errorf("DATABASE_NOT_FOUND:%s", databaseNameId)
+		}
+
+		packageName, funcName, outFile := "schema", "Model", ""
+		var schemaNames []string
+		for _, arg := range T.([]string) {
+			switch {
+			case strings.HasPrefix(arg, "--package="):
+				packageName = strings.TrimPrefix(arg, "--package=")
+			case strings.HasPrefix(arg, "--func="):
+				funcName = strings.TrimPrefix(arg, "--func=")
+			case strings.HasPrefix(arg, "--out="):
+				outFile = strings.TrimPrefix(arg, "--out=")
+			default:
+				for _, name := range strings.Split(arg, ",") {
+					if name = strings.TrimSpace(name); name != "" {
+						schemaNames = append(schemaNames, name)
+					}
+				}
+			}
+		}
+		if len(schemaNames) == 0 {
+			return errors.Errorf("MODEL_REVERSE_USAGE:%s <schema>[,<schema>...] [--package=name] [--func=name] [--out=file]", command)
+		}
+
+		catalog, err := d.Catalog(core.RootContext, schemaNames)
+		if err != nil {
+			return err
+		}
+		src, issues, err := catalog.GoSource(packageName, funcName, databaseNameId)
+		if err != nil {
+			return errors.Wrapf(err, "MODEL_REVERSE_GENERATE_ERROR:%s", databaseNameId)
+		}
+		for _, issue := range issues {
+			if issue.Type != "" {
+				log.Log.Warnf("MODEL_REVERSE_ISSUE:%s:%s:%s", issue.Object, issue.Type, issue.Reason)
+			} else {
+				log.Log.Warnf("MODEL_REVERSE_ISSUE:%s:%s", issue.Object, issue.Reason)
+			}
+		}
+
+		if outFile == "" {
+			_, err = os.Stdout.Write(src)
+			return err
+		}
+		if err := os.WriteFile(outFile, src, 0o644); err != nil {
+			return errors.Wrapf(err, "MODEL_REVERSE_WRITE_ERROR:%s", outFile)
+		}
+		_, _ = fmt.Fprintf(os.Stdout, "%d table(s), %d view(s) written to %s, %d issue(s)\n", len(catalog.Tables), len(catalog.Views), outFile, len(issues))
+		return nil
+	})
+}
@@ -13,6 +13,7 @@ const (
 	DXDatabaseTypeOracle
 	DXDatabaseTypeSQLServer
 	DXDatabaseTypePostgresSQLV2
+	DXDatabaseTypeSQLite
 )
 
 func (t DXDatabaseType) String() string {
@@ -27,6 +28,8 @@ func (t DXDatabaseType) String() string {
 		return "mariadb"
 	case DXDatabaseTypePostgresSQLV2:
 		return "postgres_v2"
+	case DXDatabaseTypeSQLite:
+		return "sqlite"
 	default:
 		// This helps you see if the value was 999 or 0 or -1
 		return fmt.Sprintf("unknown(%d)", t)
@@ -34,7 +37,7 @@ func (t DXDatabaseType) String() string {
 }
 
 func (t DXDatabaseType) IsValid() bool {
-	return t > UnknownDatabaseType && t <= DXDatabaseTypeSQLServer
+	return (t > UnknownDatabaseType && t <= DXDatabaseTypeSQLServer) || t == DXDatabaseTypeSQLite
 }
 
 func (t DXDatabaseType) Driver() string {
@@ -49,6 +52,8 @@ func (t DXDatabaseType) Driver() string {
 		return "mysql"
 	case DXDatabaseTypePostgresSQLV2:
 		return "postgres"
+	case DXDatabaseTypeSQLite:
+		return "sqlite"
 	default:
 		return "unknown"
 	}
@@ -68,18 +73,24 @@ func StringToDXDatabaseType(v string) DXDatabaseType {
 		return DXDatabaseTypeSQLServer
 	case "postgres_v2", "postgresql_v2":
 		return DXDatabaseTypePostgresSQLV2
+	case "sqlite", "sqlite3":
+		return DXDatabaseTypeSQLite
 	default:
 		return UnknownDatabaseType
 	}
 }
 
 // NormalizeDriverName maps the Go MySQL driver name "mysql" to "mariadb"
+// (and the cgo SQLite driver name "sqlite3" to "sqlite")
 // so all switch cases can use a single canonical name.
 // Keep Driver() returning "mysql" (actual Go driver) and
 // StringToDXDatabaseType() accepting both "mysql" and "mariadb".
 func NormalizeDriverName(driverName string) string {
-	if driverName == "mysql" {
+	switch driverName {
+	case "mysql":
 		return "mariadb"
+	case "sqlite3":
+		return "sqlite"
 	}
 	return driverName
 }
@@ -13,9 +13,11 @@ import (
 	"net"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 	_ "time/tzdata"
 
+	"github.com/donnyhardyanto/dxlib/databases/db"
 	"github.com/donnyhardyanto/dxlib/databases/sqlfile"
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/jackc/pgx/v5"
@@ -40,7 +42,10 @@ type DXDatabaseTx struct {
 	*sqlx.Tx
 	Database *DXDatabase
 	Log      *log.DXLog
-	Ctx      context.Context
+	Ctx      context.Context // carries this transaction until it commits or rolls back, see ContextWithTx
+
+	savepointCount int
+	isFinished     atomic.Bool
 }
 type DXDatabaseTxCallback func(dtx *DXDatabaseTx) (err error)
 
@@ -93,13 +98,33 @@ type DXDatabase struct {
 	NonSensitiveConnectionString string
 	OnCannotConnect              DXDatabaseEventFunc
 	CreateScriptFiles            []string
-	ConcurrencySemaphore         chan struct{} // Adjust number based on your DB max_connections
+	ConcurrencySemaphore         chan struct{} // nil: not limited; set by concurrency_limit, adjust it to your DB max_connections
+	ConcurrencyAcquireTimeout    time.Duration // 0 waits for a slot until the context is done (see database_pool.go)
+	concurrencyCounters          *dxDatabaseConcurrencyCounters
 
 	// Pool configuration
 	PoolMaxOpenConns           int // Maximum open connections (0 = unlimited)
 	PoolMaxIdleConns           int // Maximum idle connections
 	PoolConnMaxLifetimeMinutes int // Maximum connection lifetime in minutes
 	PoolConnMaxIdleTimeMinutes int // Maximum idle time in minutes before close
+
+	// Read replicas (see database_replica.go)
+	Replicas                          []*DXDatabaseReplica
+	ReplicaMaxLagSeconds              float64 // replicas lagging more are skipped by read routing
+	ReplicaHealthCheckIntervalSeconds int
+	ReadYourWrites                    bool // pin a request's reads to the primary after it writes (see ContextWithReadYourWrites)
+	replicaCursor                     *atomic.Uint64
+	replicaHealthCheckCancel          context.CancelFunc
+
+	TxRetryPolicy *DXDatabaseTxRetryPolicy // nil means DefaultTxRetryPolicy (see database_tx_retry.go)
+
+	SlowQueryLog *db.DXSlowQueryLog // nil means off (see database_slow_query_log.go)
+
+	IsTenantSessionConfigEnabled bool // transactions set the tenant of their context as session config (see database_tenant.go)
+
+	OutboxTableName string // "" means DXDatabaseOutboxDefaultTableName (see database_outbox.go)
+
+	listener *dxDatabaseListener // LISTEN connection of the subscriptions, guarded by databaseListenerMutex (see database_notify.go)
 }
 
 func (d *DXDatabase) EnsureConnection() (err error) {
@@ -137,7 +162,12 @@ func (d *DXDatabase) TransactionBegin(ctx context.Context, isolationLevel DXData
 			Tx:       tx,
 			Database: d,
 			Log:      &log.Log,
-			Ctx:      ctx,
+		}
+		dtx.Ctx = ContextWithTx(ctx, dtx)
+		db.RegisterTxSlowQueryLog(tx, d.SlowQueryLog)
+		if err = dtx.txSetTenantSessionConfigFromContext(ctx); err != nil {
+			_ = tx.Rollback()
+			return nil, err
 		}
 		return dtx, nil
 	default:
@@ -154,7 +184,12 @@ func (d *DXDatabase) TransactionBegin(ctx context.Context, isolationLevel DXData
 		Tx:       tx,
 		Database: d,
 		Log:      &log.Log,
-		Ctx:      ctx,
+	}
+	dtx.Ctx = ContextWithTx(ctx, dtx)
+	db.RegisterTxSlowQueryLog(tx, d.SlowQueryLog)
+	if err = dtx.txSetTenantSessionConfigFromContext(ctx); err != nil {
+		_ = tx.Rollback()
+		return nil, err
 	}
 	return dtx, nil
 }
@@ -285,6 +320,21 @@ func (d *DXDatabase) GetConnectionString() (s string, err error) {
 		if d.ConnectionOptions != "" {
 			s += "&" + d.ConnectionOptions
 		}
+	case base.DXDatabaseTypeSQLite:
+		// modernc.org/sqlite DSN: database_name is the file path. ":memory:" maps to a
+		// named memdb database, shared by every connection of the pool (a plain
+		// ":memory:" would give each pooled connection its own empty database).
+		// Foreign keys are off by default in SQLite, and the busy timeout lets
+		// concurrent writers wait for the single write lock instead of failing.
+		if d.DatabaseName == ":memory:" {
+			s = fmt.Sprintf("file:/%s?vfs=memdb", d.NameId)
+		} else {
+			s = fmt.Sprintf("file:%s?_pragma=journal_mode(WAL)", d.DatabaseName)
+		}
+		s += "&_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)"
+		if d.ConnectionOptions != "" {
+			s += "&" + d.ConnectionOptions
+		}
 	default:
 		err = errors.Errorf("configuration is unusable, value of database_type field of databases %s configuration is not supported (%s)", d.NameId, s)
 	}
@@ -335,8 +385,10 @@ func (d *DXDatabase) ApplyFromConfiguration() (err error) {
 				return errors.Errorf("configuration is unusable, value of database_type field of databases %s configuration is not supported (%s)", d.NameId, s)
 			}
 		}
+		// SQLite is a local file: only database_name is needed
+		isSQLite := d.DatabaseType == base.DXDatabaseTypeSQLite
 		d.Address, ok = databaseConfiguration["address"].(string)
-		if !ok {
+		if !ok && !isSQLite {
 			if d.MustConnected {
 				return errors.Errorf("mandatory address field in Database %s configuration not exist", d.NameId)
 			} else {
@@ -346,7 +398,7 @@ func (d *DXDatabase) ApplyFromConfiguration() (err error) {
 		d.UserName, err = configurationData.GetStringFromSubMap(d.NameId, "user_name")
 		if err != nil {
 			d.UserName, ok = databaseConfiguration["user_name"].(string)
-			if !ok {
+			if !ok && !isSQLite {
 				if d.MustConnected {
 					return errors.Errorf("mandatory user_name field in Database %s configuration not exist", d.NameId)
 				} else {
@@ -357,7 +409,7 @@ func (d *DXDatabase) ApplyFromConfiguration() (err error) {
 		d.UserPassword, err = configurationData.GetStringFromSubMap(d.NameId, "user_password")
 		if err != nil {
 			d.UserPassword, ok = databaseConfiguration["user_password"].(string)
-			if !ok {
+			if !ok && !isSQLite {
 				if d.MustConnected {
 					return errors.Errorf("mandatory user_password field in Database %s configuration not exist", d.NameId)
 				} else {
@@ -417,6 +469,15 @@ func (d *DXDatabase) ApplyFromConfiguration() (err error) {
 		} else {
 			log.Log.Infof("Connecting to Database %s... done", d.NonSensitiveConnectionString)
 		}
+		d.applyTxRetryPolicyFromConfiguration(databaseConfiguration)
+		d.applyConcurrencyFromConfiguration(databaseConfiguration)
+		d.applySlowQueryLogFromConfiguration(databaseConfiguration)
+		d.applyTenantFromConfiguration(databaseConfiguration)
+		d.applyOutboxFromConfiguration(databaseConfiguration)
+		err = d.applyReplicasFromConfiguration(databaseConfiguration)
+		if err != nil {
+			return err
+		}
 		d.IsConfigured = true
 		log.Log.Infof("Configuring to Database %s... done", d.NameId)
 	}
@@ -539,7 +600,10 @@ func (d *DXDatabase) Connect() (err error) {
 			}
 		}
 		d.Connected = true
+		db.RegisterSlowQueryLog(connection, d.SlowQueryLog)
+		registerPoolMetrics()
 		log.Log.Infof("Connecting to databases %s/%s... done CONNECTED", d.NameId, d.NonSensitiveConnectionString)
+		d.startReplicaHealthCheck()
 	}
 	return nil
 }
@@ -547,6 +611,8 @@ func (d *DXDatabase) Connect() (err error) {
 func (d *DXDatabase) Disconnect() (err error) {
 	if d.Connected {
 		log.Log.Infof("Disconnecting to databases %s/%s... start", d.NameId, d.NonSensitiveConnectionString)
+		d.stopReplicaHealthCheck()
+		db.RegisterSlowQueryLog(d.Connection, nil)
 		err := (*d.Connection).Close()
 		if err != nil {
 			return errors.Wrapf(err, "Disconnecting to databases %s/%s error", d.NameId, d.NonSensitiveConnectionString)
@@ -580,7 +646,7 @@ func (d *DXDatabase) ExecuteFile(filename string) (r sql.Result, err error) {
 
 	driverName := base.NormalizeDriverName(d.Connection.DriverName())
 	switch driverName {
-	case "sqlserver", "postgres", "oracle", "mariadb":
+	case "sqlserver", "postgres", "oracle", "mariadb", "sqlite":
 		log.Log.Infof("Executing SQL file %s... start", filename)
 
 		sqlFile := sqlfile.New()
@@ -620,7 +686,7 @@ func (d *DXDatabase) ExecuteSQLContent(content string) (r sql.Result, err error)
 
 	driverName := base.NormalizeDriverName(d.Connection.DriverName())
 	switch driverName {
-	case "sqlserver", "postgres", "oracle", "mariadb":
+	case "sqlserver", "postgres", "oracle", "mariadb", "sqlite":
 		log.Log.Info("Executing SQL content... start")
 		sqlFile := sqlfile.New()
 
@@ -703,6 +769,16 @@ func (d *DXDatabase) ExecuteCreateScriptsFromEmbedded(contentProvider SQLContent
 }
 
 func (d *DXDatabase) Tx(ctx context.Context, log *log.DXLog, isolationLevel sql.IsolationLevel, callback DXDatabaseTxCallback) (err error) {
+	// Join the ambient transaction (see ContextWithTx) through a savepoint: a failing
+	// callback undoes only its own work, the owner still commits or rolls back.
+	if ambientTx := TxFromContext(ctx, d); ambientTx != nil {
+		return ambientTx.Nested(callback)
+	}
+	d.MarkWritten(ctx)
+	return d.txWithRetry(ctx, log, isolationLevel, d.txRetryPolicy(), callback)
+}
+
+func (d *DXDatabase) tx(ctx context.Context, log *log.DXLog, isolationLevel sql.IsolationLevel, callback DXDatabaseTxCallback) (err error) {
 	err = d.EnsureConnection()
 	if err != nil {
 		return err
@@ -749,21 +825,26 @@ func (d *DXDatabase) Tx(ctx context.Context, log *log.DXLog, isolationLevel sql.
 		Tx:       tx,
 		Database: d,
 		Log:      log,
-		Ctx:      ctx,
+	}
+	dtx.Ctx = ContextWithTx(ctx, dtx)
+	db.RegisterTxSlowQueryLog(tx, d.SlowQueryLog)
+	if err = dtx.txSetTenantSessionConfigFromContext(ctx); err != nil {
+		_ = tx.Rollback()
+		return err
 	}
 	err = callback(dtx)
 	if err != nil {
 		log.Errorf(err, "TX_ERROR_IN_CALLBACK: (%v)", err.Error())
-		errTx := tx.Rollback()
+		errTx := dtx.Rollback()
 		if errTx != nil {
 			log.Errorf(err, "SHOULD_NOT_HAPPEN:ERROR_IN_ROLLBACK(%v)", errTx.Error())
 		}
 		return err
 	}
-	err = dtx.Tx.Commit()
+	err = dtx.Commit()
 	if err != nil {
 		log.Errorf(err, "TX_ERROR_IN_COMMIT:Details:%+v", err)
-		errTx := tx.Rollback()
+		errTx := dtx.Rollback()
 		if errTx != nil {
 			log.Errorf(err, "ErrorInCommitRollback:Details:%+v", errTx)
 		}
@@ -0,0 +1,109 @@
+package databases
+
+import (
+	"context"
+	"strings"
+
+	"github.com/donnyhardyanto/dxlib/base"
+	"github.com/donnyhardyanto/dxlib/databases/db"
+	"github.com/donnyhardyanto/dxlib/errors"
+	"github.com/donnyhardyanto/dxlib/log"
+	"github.com/donnyhardyanto/dxlib/utils"
+	"github.com/jackc/pgx/v5"
+)
+
+// BulkInsert inserts rows in one transaction with multi-row statements (see db.TxBulkInsert).
+// On PostgreSQL, when nothing is returned and no value is a db.SQLExpression, the rows are
+// streamed with COPY through PgxPool instead, unless the tenant session config has to be set
+// (COPY runs outside the transaction that would carry it, see IsTenantSessionTxNeeded).
+func (d *DXDatabase) BulkInsert(ctx context.Context, tableName string, rows []utils.JSON, returningFieldNames []string) (affectedRows int64, returningRows []utils.JSON, err error) {
+	if ambientTx := TxFromContext(ctx, d); ambientTx != nil {
+		return ambientTx.BulkInsert(ctx, tableName, rows, returningFieldNames)
+	}
+	err = d.EnsureConnection()
+	if err != nil {
+		return 0, nil, err
+	}
+	if d.PgxPool != nil && len(returningFieldNames) == 0 && canCopyRows(rows) && !d.IsTenantSessionTxNeeded(ctx) {
+		d.MarkWritten(ctx)
+		return d.copyFrom(ctx, tableName, rows)
+	}
+
+	err = d.Tx(ctx, &log.Log, LevelReadCommitted, func(dtx *DXDatabaseTx) error {
+		affectedRows, returningRows, err = dtx.BulkInsert(ctx, tableName, rows, returningFieldNames)
+		return err
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+	return affectedRows, returningRows, nil
+}
+
+// BulkUpsert inserts rows in one transaction, updating existing ones on keyFieldNames conflicts (see db.TxBulkUpsert).
+func (d *DXDatabase) BulkUpsert(ctx context.Context, tableName string, rows []utils.JSON, keyFieldNames []string, updateFieldNames []string,
+	returningFieldNames []string) (affectedRows int64, returningRows []utils.JSON, err error) {
+	err = d.Tx(ctx, &log.Log, LevelReadCommitted, func(dtx *DXDatabaseTx) error {
+		affectedRows, returningRows, err = dtx.BulkUpsert(ctx, tableName, rows, keyFieldNames, updateFieldNames, returningFieldNames)
+		return err
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+	return affectedRows, returningRows, nil
+}
+
+func canCopyRows(rows []utils.JSON) bool {
+	for _, row := range rows {
+		for _, v := range row {
+			if _, ok := v.(db.SQLExpression); ok {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// copyFrom bulk loads rows with the PostgreSQL COPY protocol. COPY runs on its own pool
+// connection, outside any database/sql transaction.
+func (d *DXDatabase) copyFrom(ctx context.Context, tableName string, rows []utils.JSON) (affectedRows int64, returningRows []utils.JSON, err error) {
+	defer func() {
+		if err != nil {
+			err = db.NewDBOperationError("BULK_INSERT_COPY", tableName, utils.JSON{"rows": len(rows)}, err)
+		} else {
+			log.Log.Debugf("DB_BULK_INSERT_COPY table=%s rows=%d", tableName, affectedRows)
+		}
+	}()
+	if err = db.CheckIdentifier(base.DXDatabaseTypePostgreSQL, tableName); err != nil {
+		return 0, nil, errors.Wrap(err, "invalid table name")
+	}
+	columns, err := db.BulkRowColumns(rows)
+	if err != nil {
+		return 0, nil, err
+	}
+	for _, c := range columns {
+		if err = db.CheckIdentifier(base.DXDatabaseTypePostgreSQL, c); err != nil {
+			return 0, nil, errors.Wrapf(err, "invalid field name: %s", c)
+		}
+	}
+	values := make([][]any, len(rows))
+	for i, row := range rows {
+		values[i] = make([]any, len(columns))
+		for j, c := range columns {
+			values[i][j], err = db.DbDriverConvertValueTypeToDBCompatible("postgres", row[c])
+			if err != nil {
+				return 0, nil, errors.Wrapf(err, "failed to convert field value: row %d %s", i, c)
+			}
+		}
+	}
+
+	release, err := d.AcquireConcurrencySlot(ctx)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer release()
+	affectedRows, err = d.PgxPool.CopyFrom(ctx, pgx.Identifier(strings.Split(tableName, ".")), columns, pgx.CopyFromRows(values))
+	if err != nil {
+		return 0, nil, errors.Wrap(err, "COPY_FROM_ERROR")
+	}
+	return affectedRows, nil, nil
+}
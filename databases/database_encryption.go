@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/donnyhardyanto/dxlib/base"
+	"github.com/donnyhardyanto/dxlib/databases/db"
 	"github.com/donnyhardyanto/dxlib/errors"
 	"github.com/donnyhardyanto/dxlib/utils"
 )
@@ -444,6 +445,8 @@ func sessionKeyExpr(dbType base.DXDatabaseType, sessionKey string) string {
 		return fmt.Sprintf("SYS_CONTEXT('CLIENTCONTEXT', '%s')", sessionKey)
 	case base.DXDatabaseTypeMariaDB:
 		return fmt.Sprintf("@%s", strings.ReplaceAll(sessionKey, ".", "_"))
+	case base.DXDatabaseTypeSQLite:
+		return db.SessionKeyExpression(dbType, sessionKey)
 	default:
 		return fmt.Sprintf("'%s'", sessionKey)
 	}
@@ -462,6 +465,8 @@ func encryptExpr(dbType base.DXDatabaseType, argIndex int, sessionKey string) st
 		return fmt.Sprintf("UTL_RAW.CAST_TO_RAW(%s)", ph)
 	case base.DXDatabaseTypeMariaDB:
 		return fmt.Sprintf("AES_ENCRYPT(%s, %s)", ph, keyExpr)
+	case base.DXDatabaseTypeSQLite:
+		return fmt.Sprintf("%s(%s, %s)", db.SQLiteFunctionEncrypt, ph, keyExpr)
 	default:
 		return ph
 	}
@@ -479,6 +484,8 @@ func decryptExpr(dbType base.DXDatabaseType, fieldName string, sessionKey string
 		return fmt.Sprintf("UTL_RAW.CAST_TO_VARCHAR2(%s)", fieldName)
 	case base.DXDatabaseTypeMariaDB:
 		return fmt.Sprintf("AES_DECRYPT(%s, %s)", fieldName, keyExpr)
+	case base.DXDatabaseTypeSQLite:
+		return fmt.Sprintf("%s(%s, %s)", db.SQLiteFunctionDecrypt, fieldName, keyExpr)
 	default:
 		return fieldName
 	}
@@ -507,6 +514,8 @@ func hashExpr(dbType base.DXDatabaseType, argIndex int, saltSessionKey string) s
 		return fmt.Sprintf("DBMS_CRYPTO.HASH(UTL_RAW.CAST_TO_RAW(%s), 4)", valueExpr)
 	case base.DXDatabaseTypeMariaDB:
 		return fmt.Sprintf("SHA2(%s, 256)", valueExpr)
+	case base.DXDatabaseTypeSQLite:
+		return fmt.Sprintf("%s(%s)", db.SQLiteFunctionSHA256, valueExpr)
 	default:
 		return ph
 	}
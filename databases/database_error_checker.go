@@ -213,6 +213,68 @@ func IsDuplicateKeyError(err error) bool {
 	return false
 }
 
+// IsRetryableTxError detects serialization failures and deadlocks, after which the whole
+// transaction can be rolled back and run again: PostgreSQL 40001/40P01, SQL Server
+// 1205/3960, MariaDB 1213 and Oracle ORA-00060/ORA-08177.
+func IsRetryableTxError(err error) bool {
+	return TxRetryReason(err) != ""
+}
+
+// TxRetryReason classifies a retryable transaction error as "serialization_failure" or
+// "deadlock", or returns "" when the error is not retryable.
+func TxRetryReason(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	// PostgreSQL
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case "40001":
+			return "serialization_failure"
+		case "40P01":
+			return "deadlock"
+		}
+		return ""
+	}
+
+	// MySQL/MariaDB
+	var mariaDbErr *mysql.MySQLError
+	if errors.As(err, &mariaDbErr) {
+		if mariaDbErr.Number == 1213 {
+			return "deadlock"
+		}
+		return ""
+	}
+
+	// SQL Server
+	var mssqlErr mssql.Error
+	if errors.As(err, &mssqlErr) {
+		switch mssqlErr.Number {
+		case 1205:
+			return "deadlock"
+		case 3960: // snapshot isolation update conflict
+			return "serialization_failure"
+		}
+		return ""
+	}
+
+	// Error string pattern matching (Oracle, and drivers wrapped beyond errors.As)
+	errMsg := strings.ToLower(err.Error())
+	if strings.Contains(errMsg, "ora-08177") || strings.Contains(errMsg, "sqlstate 40001") ||
+		strings.Contains(errMsg, "could not serialize access") {
+		return "serialization_failure"
+	}
+	if strings.Contains(errMsg, "ora-00060") || strings.Contains(errMsg, "sqlstate 40p01") ||
+		strings.Contains(errMsg, "deadlock detected") || strings.Contains(errMsg, "deadlock found") ||
+		strings.Contains(errMsg, "chosen as the deadlock victim") {
+		return "deadlock"
+	}
+
+	return ""
+}
+
 // IsConnectionError detects databases connection issues across different databases systems
 func IsConnectionError(err error) bool {
 	if err == nil {
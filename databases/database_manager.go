@@ -1,6 +1,8 @@
 package databases
 
 import (
+	"time"
+
 	dxlibv3Configuration "github.com/donnyhardyanto/dxlib/configuration"
 	"github.com/donnyhardyanto/dxlib/log"
 	"github.com/donnyhardyanto/dxlib/utils"
@@ -15,15 +17,14 @@ func (dm *DXDatabaseManager) NewDatabase(nameId string, isConnectAtStart, mustBe
 	if dm.Databases[nameId] != nil {
 		return dm.Databases[nameId]
 	}
-	dbSemaphore := make(chan struct{}, 10)
-
 	d := DXDatabase{
-		NameId:               nameId,
-		IsConfigured:         false,
-		IsConnectAtStart:     isConnectAtStart,
-		MustConnected:        mustBeConnected,
-		Connected:            false,
-		ConcurrencySemaphore: dbSemaphore,
+		NameId:                    nameId,
+		IsConfigured:              false,
+		IsConnectAtStart:          isConnectAtStart,
+		MustConnected:             mustBeConnected,
+		Connected:                 false,
+		ConcurrencyAcquireTimeout: DXDatabaseDefaultConcurrencyAcquireTimeoutMs * time.Millisecond,
+		concurrencyCounters:       &dxDatabaseConcurrencyCounters{},
 	}
 	dm.Databases[nameId] = &d
 	return &d
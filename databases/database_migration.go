@@ -0,0 +1,431 @@
+package databases
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/donnyhardyanto/dxlib/base"
+	"github.com/donnyhardyanto/dxlib/databases/db"
+	"github.com/donnyhardyanto/dxlib/databases/sqlfile"
+	"github.com/donnyhardyanto/dxlib/errors"
+	"github.com/donnyhardyanto/dxlib/log"
+)
+
+const (
+	DXDatabaseMigrationDefaultTableName   = "schema_migrations"
+	DXDatabaseMigrationDefaultLockTimeout = 5 * time.Minute
+)
+
+// DXDatabaseMigration is one versioned schema change. Versions are numeric strings
+// (e.g. "0001" or "20250101120000") and are applied in numeric order.
+type DXDatabaseMigration struct {
+	Version  string
+	Name     string
+	UpSQL    string
+	DownSQL  string // empty: the migration cannot be reverted
+	Checksum string // sha256 of UpSQL, recorded when applied
+}
+
+type DXDatabaseMigrationState string
+
+const (
+	DXDatabaseMigrationStatePending DXDatabaseMigrationState = "pending"
+	DXDatabaseMigrationStateApplied DXDatabaseMigrationState = "applied"
+	DXDatabaseMigrationStateDrifted DXDatabaseMigrationState = "drifted" // applied, but its file changed since
+	DXDatabaseMigrationStateMissing DXDatabaseMigrationState = "missing" // applied, but no longer in the source
+)
+
+type DXDatabaseMigrationStatus struct {
+	Version         string
+	Name            string
+	State           DXDatabaseMigrationState
+	Checksum        string
+	AppliedChecksum string
+	AppliedAt       time.Time
+	DurationMs      int64
+	AppliedBy       string
+}
+
+// migrationFileNamePattern matches "<version>_<name>.up.sql", "<version>_<name>.down.sql"
+// and "<version>_<name>.sql" (up only).
+var migrationFileNamePattern = regexp.MustCompile(`^(\d+)_([^.]+)(\.up|\.down)?\.sql$`)
+
+var migrationTableNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// LoadMigrationsFromDirectory reads the migrations of dir, see LoadMigrationsFromFS.
+func LoadMigrationsFromDirectory(dir string) ([]*DXDatabaseMigration, error) {
+	return LoadMigrationsFromFS(os.DirFS(dir), ".")
+}
+
+// LoadMigrationsFromFS reads the migrations in dir of fsys (e.g. an embed.FS). Files
+// that do not follow the migration file naming are ignored.
+func LoadMigrationsFromFS(fsys fs.FS, dir string) ([]*DXDatabaseMigration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "MIGRATION_READ_DIRECTORY_ERROR:%s", dir)
+	}
+
+	migrations := map[string]*DXDatabaseMigration{}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		match := migrationFileNamePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+		content, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, errors.Wrapf(err, "MIGRATION_READ_FILE_ERROR:%s", entry.Name())
+		}
+
+		version, name, direction := match[1], match[2], match[3]
+		m, ok := migrations[version]
+		if !ok {
+			m = &DXDatabaseMigration{Version: version, Name: name}
+			migrations[version] = m
+		} else if m.Name != name {
+			return nil, errors.Errorf("MIGRATION_DUPLICATE_VERSION:%s:%s,%s", version, m.Name, name)
+		}
+		if direction == ".down" {
+			if m.DownSQL != "" {
+				return nil, errors.Errorf("MIGRATION_DUPLICATE_DOWN:%s", entry.Name())
+			}
+			m.DownSQL = string(content)
+		} else {
+			if m.UpSQL != "" {
+				return nil, errors.Errorf("MIGRATION_DUPLICATE_UP:%s", entry.Name())
+			}
+			m.UpSQL = string(content)
+		}
+	}
+
+	r := make([]*DXDatabaseMigration, 0, len(migrations))
+	for _, m := range migrations {
+		if m.UpSQL == "" {
+			return nil, errors.Errorf("MIGRATION_HAS_NO_UP:%s_%s", m.Version, m.Name)
+		}
+		m.Checksum = MigrationChecksum(m.UpSQL)
+		r = append(r, m)
+	}
+	slices.SortFunc(r, func(a, b *DXDatabaseMigration) int {
+		return compareMigrationVersions(a.Version, b.Version)
+	})
+	return r, nil
+}
+
+// MigrationChecksum returns the checksum recorded for an up script. Line endings are
+// normalized so a checkout on another OS does not count as drift.
+func MigrationChecksum(upSQL string) string {
+	sum := sha256.Sum256([]byte(strings.ReplaceAll(upSQL, "\r\n", "\n")))
+	return hex.EncodeToString(sum[:])
+}
+
+func compareMigrationVersions(a, b string) int {
+	a = strings.TrimLeft(a, "0")
+	b = strings.TrimLeft(b, "0")
+	if len(a) != len(b) {
+		return len(a) - len(b)
+	}
+	return strings.Compare(a, b)
+}
+
+// DXDatabaseMigrator applies and reverts migrations of one database, recording them in
+// TableName. Up and Down hold a database-wide advisory lock, so several instances
+// starting at once migrate one after another instead of concurrently.
+type DXDatabaseMigrator struct {
+	Database    *DXDatabase
+	Migrations  []*DXDatabaseMigration
+	TableName   string
+	AppliedBy   string
+	LockTimeout time.Duration
+	DryRun      bool      // print what Up/Down would execute to Output instead of executing it
+	AllowDrift  bool      // run Up/Down even if applied migrations changed or disappeared
+	Output      io.Writer // nil means os.Stdout
+}
+
+func (d *DXDatabase) NewMigrator(migrations []*DXDatabaseMigration) *DXDatabaseMigrator {
+	appliedBy, err := os.Hostname()
+	if err != nil {
+		appliedBy = "unknown"
+	}
+	return &DXDatabaseMigrator{
+		Database:    d,
+		Migrations:  migrations,
+		TableName:   DXDatabaseMigrationDefaultTableName,
+		AppliedBy:   appliedBy,
+		LockTimeout: DXDatabaseMigrationDefaultLockTimeout,
+	}
+}
+
+type appliedMigration struct {
+	version    string
+	name       string
+	checksum   string
+	appliedAt  time.Time
+	durationMs int64
+	appliedBy  string
+}
+
+func (m *DXDatabaseMigrator) output() io.Writer {
+	if m.Output != nil {
+		return m.Output
+	}
+	return os.Stdout
+}
+
+func (m *DXDatabaseMigrator) dbType() base.DXDatabaseType {
+	return base.StringToDXDatabaseType(base.NormalizeDriverName(m.Database.Connection.DriverName()))
+}
+
+func (m *DXDatabaseMigrator) prepare() error {
+	if !migrationTableNamePattern.MatchString(m.TableName) {
+		return errors.Errorf("MIGRATION_INVALID_TABLE_NAME:%s", m.TableName)
+	}
+	return m.Database.EnsureConnection()
+}
+
+// Status returns the source migrations in version order followed by the applied ones
+// that are missing from the source.
+func (m *DXDatabaseMigrator) Status(ctx context.Context) (statuses []DXDatabaseMigrationStatus, err error) {
+	if err = m.prepare(); err != nil {
+		return nil, err
+	}
+	applied, err := m.appliedMigrations(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, migration := range m.Migrations {
+		status := DXDatabaseMigrationStatus{
+			Version:  migration.Version,
+			Name:     migration.Name,
+			State:    DXDatabaseMigrationStatePending,
+			Checksum: migration.Checksum,
+		}
+		if a, ok := applied[migration.Version]; ok {
+			status.State = DXDatabaseMigrationStateApplied
+			if a.checksum != migration.Checksum {
+				status.State = DXDatabaseMigrationStateDrifted
+			}
+			status.AppliedChecksum = a.checksum
+			status.AppliedAt = a.appliedAt
+			status.DurationMs = a.durationMs
+			status.AppliedBy = a.appliedBy
+			delete(applied, migration.Version)
+		}
+		statuses = append(statuses, status)
+	}
+
+	missing := make([]DXDatabaseMigrationStatus, 0, len(applied))
+	for _, a := range applied {
+		missing = append(missing, DXDatabaseMigrationStatus{
+			Version:         a.version,
+			Name:            a.name,
+			State:           DXDatabaseMigrationStateMissing,
+			AppliedChecksum: a.checksum,
+			AppliedAt:       a.appliedAt,
+			DurationMs:      a.durationMs,
+			AppliedBy:       a.appliedBy,
+		})
+	}
+	slices.SortFunc(missing, func(a, b DXDatabaseMigrationStatus) int {
+		return compareMigrationVersions(a.Version, b.Version)
+	})
+	return append(statuses, missing...), nil
+}
+
+func (m *DXDatabaseMigrator) checkDrift(statuses []DXDatabaseMigrationStatus) error {
+	if m.AllowDrift {
+		return nil
+	}
+	for _, s := range statuses {
+		switch s.State {
+		case DXDatabaseMigrationStateDrifted:
+			return errors.Errorf("MIGRATION_CHECKSUM_DRIFT:%s_%s:APPLIED=%s:CURRENT=%s", s.Version, s.Name, s.AppliedChecksum, s.Checksum)
+		case DXDatabaseMigrationStateMissing:
+			return errors.Errorf("MIGRATION_APPLIED_BUT_MISSING:%s_%s", s.Version, s.Name)
+		default:
+		}
+	}
+	return nil
+}
+
+// Up applies the pending migrations up to and including targetVersion ("" means all),
+// each in its own transaction, and returns the ones applied (or, in dry-run, planned).
+func (m *DXDatabaseMigrator) Up(ctx context.Context, targetVersion string) (r []*DXDatabaseMigration, err error) {
+	if err = m.prepare(); err != nil {
+		return nil, err
+	}
+	err = m.withLock(ctx, func() error {
+		statuses, err := m.Status(ctx)
+		if err != nil {
+			return err
+		}
+		if err = m.checkDrift(statuses); err != nil {
+			return err
+		}
+		pending := map[string]bool{}
+		for _, s := range statuses {
+			if s.State == DXDatabaseMigrationStatePending {
+				pending[s.Version] = true
+			}
+		}
+
+		for _, migration := range m.Migrations {
+			if targetVersion != "" && compareMigrationVersions(migration.Version, targetVersion) > 0 {
+				break
+			}
+			if !pending[migration.Version] {
+				continue
+			}
+			if err = m.run(ctx, migration, true); err != nil {
+				return err
+			}
+			r = append(r, migration)
+		}
+		return nil
+	})
+	return r, err
+}
+
+// Down reverts the last steps applied migrations, newest first, and returns the ones
+// reverted (or, in dry-run, planned).
+func (m *DXDatabaseMigrator) Down(ctx context.Context, steps int) (r []*DXDatabaseMigration, err error) {
+	if err = m.prepare(); err != nil {
+		return nil, err
+	}
+	err = m.withLock(ctx, func() error {
+		statuses, err := m.Status(ctx)
+		if err != nil {
+			return err
+		}
+		if err = m.checkDrift(statuses); err != nil {
+			return err
+		}
+		applied := map[string]bool{}
+		for _, s := range statuses {
+			if s.State == DXDatabaseMigrationStateApplied || s.State == DXDatabaseMigrationStateDrifted {
+				applied[s.Version] = true
+			}
+		}
+
+		for i := len(m.Migrations) - 1; i >= 0 && len(r) < steps; i-- {
+			migration := m.Migrations[i]
+			if !applied[migration.Version] {
+				continue
+			}
+			if migration.DownSQL == "" {
+				return errors.Errorf("MIGRATION_HAS_NO_DOWN:%s_%s", migration.Version, migration.Name)
+			}
+			if err = m.run(ctx, migration, false); err != nil {
+				return err
+			}
+			r = append(r, migration)
+		}
+		return nil
+	})
+	return r, err
+}
+
+// run executes one direction of a migration and its bookkeeping in one transaction.
+// DDL is only rolled back with it on PostgreSQL, SQL Server and SQLite; MariaDB and Oracle
+// commit each DDL statement implicitly.
+func (m *DXDatabaseMigrator) run(ctx context.Context, migration *DXDatabaseMigration, isUp bool) error {
+	direction, script := "up", migration.UpSQL
+	if !isUp {
+		direction, script = "down", migration.DownSQL
+	}
+	sqlFile := sqlfile.New()
+	if err := sqlFile.Content(script); err != nil {
+		return err
+	}
+	dbType := m.dbType()
+	statements := sqlFile.Queries()
+	for i, statement := range statements {
+		statements[i] = migrationStatement(dbType, statement)
+	}
+
+	if m.DryRun {
+		_, _ = fmt.Fprintf(m.output(), "-- %s %s_%s (dry run)\n%s\n\n", direction, migration.Version, migration.Name, strings.Join(statements, "\n"))
+		return nil
+	}
+
+	log.Log.Infof("MIGRATION_%s:%s:%s_%s... start", strings.ToUpper(direction), m.Database.NameId, migration.Version, migration.Name)
+	start := time.Now()
+	err := m.Database.TxWithRetryPolicy(ctx, &log.Log, LevelReadCommitted, NoTxRetryPolicy, func(dtx *DXDatabaseTx) error {
+		for _, statement := range statements {
+			if _, err := db.RawTxExec(dtx.Ctx, dtx.Tx, statement, nil); err != nil {
+				return errors.Wrapf(err, "MIGRATION_%s_ERROR:%s_%s", strings.ToUpper(direction), migration.Version, migration.Name)
+			}
+		}
+		if !isUp {
+			_, err := db.RawTxExec(dtx.Ctx, dtx.Tx, fmt.Sprintf("DELETE FROM %s WHERE version = %s", m.TableName, placeholder(dbType, 1)),
+				[]any{migration.Version})
+			return err
+		}
+		_, err := db.RawTxExec(dtx.Ctx, dtx.Tx, fmt.Sprintf("INSERT INTO %s (version, name, checksum, applied_at, duration_ms, applied_by) VALUES (%s, %s, %s, %s, %s, %s)",
+			m.TableName, placeholder(dbType, 1), placeholder(dbType, 2), placeholder(dbType, 3), placeholder(dbType, 4), placeholder(dbType, 5), placeholder(dbType, 6)),
+			[]any{migration.Version, migration.Name, migration.Checksum, time.Now().UTC(), time.Since(start).Milliseconds(), m.AppliedBy})
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	log.Log.Infof("MIGRATION_%s:%s:%s_%s... done in %s", strings.ToUpper(direction), m.Database.NameId, migration.Version, migration.Name, time.Since(start))
+	return nil
+}
+
+// migrationStatement strips the terminating ';' that Oracle rejects on plain SQL; a
+// PL/SQL block keeps it as part of its END.
+func migrationStatement(dbType base.DXDatabaseType, statement string) string {
+	if dbType != base.DXDatabaseTypeOracle {
+		return statement
+	}
+	trimmed := strings.TrimSuffix(strings.TrimSpace(statement), ";")
+	if strings.HasSuffix(strings.ToUpper(trimmed), "END") {
+		return trimmed + ";"
+	}
+	return trimmed
+}
+
+func (m *DXDatabaseMigrator) appliedMigrations(ctx context.Context) (map[string]appliedMigration, error) {
+	exists, err := m.tableExists(ctx)
+	if err != nil {
+		return nil, err
+	}
+	applied := map[string]appliedMigration{}
+	if !exists {
+		return applied, nil
+	}
+
+	rows, err := m.Database.Connection.QueryContext(ctx,
+		fmt.Sprintf("SELECT version, name, checksum, applied_at, duration_ms, applied_by FROM %s", m.TableName))
+	if err != nil {
+		return nil, errors.Wrapf(err, "MIGRATION_READ_APPLIED_ERROR:%s", m.TableName)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+	for rows.Next() {
+		var a appliedMigration
+		if err = rows.Scan(&a.version, &a.name, &a.checksum, &a.appliedAt, &a.durationMs, &a.appliedBy); err != nil {
+			return nil, errors.Wrapf(err, "MIGRATION_READ_APPLIED_ERROR:%s", m.TableName)
+		}
+		applied[a.version] = a
+	}
+	if err = rows.Err(); err != nil {
+		return nil, errors.Wrapf(err, "MIGRATION_READ_APPLIED_ERROR:%s", m.TableName)
+	}
+	return applied, nil
+}
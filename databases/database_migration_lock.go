@@ -0,0 +1,161 @@
+package databases
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"hash/fnv"
+	"time"
+
+	"github.com/donnyhardyanto/dxlib/base"
+	"github.com/donnyhardyanto/dxlib/errors"
+	"github.com/donnyhardyanto/dxlib/log"
+)
+
+// withLock runs fn while holding the migration lock of the database and makes sure the
+// migration table exists. A dry run takes neither: it changes nothing.
+func (m *DXDatabaseMigrator) withLock(ctx context.Context, fn func() error) (err error) {
+	if m.DryRun {
+		return fn()
+	}
+
+	conn, err := m.Database.Connection.Conn(ctx)
+	if err != nil {
+		return errors.Wrap(err, "MIGRATION_LOCK_CONNECTION_ERROR")
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	lockName := "dxlib_migration_" + m.TableName
+	timeoutSeconds := int64(m.LockTimeout.Seconds())
+	if timeoutSeconds <= 0 {
+		timeoutSeconds = int64(DXDatabaseMigrationDefaultLockTimeout.Seconds())
+	}
+
+	var unlockSQL string
+	var unlockArgs []any
+	switch m.dbType() {
+	case base.DXDatabaseTypePostgreSQL, base.DXDatabaseTypePostgresSQLV2:
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(lockName))
+		key := int64(h.Sum64())
+		lockCtx, cancel := context.WithTimeout(ctx, time.Duration(timeoutSeconds)*time.Second)
+		_, err = conn.ExecContext(lockCtx, "SELECT pg_advisory_lock($1)", key)
+		cancel()
+		unlockSQL, unlockArgs = "SELECT pg_advisory_unlock($1)", []any{key}
+	case base.DXDatabaseTypeMariaDB:
+		var acquired sql.NullInt64
+		err = conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, ?)", lockName, timeoutSeconds).Scan(&acquired)
+		if err == nil && acquired.Int64 != 1 {
+			err = errors.Errorf("MIGRATION_LOCK_TIMEOUT:%s", lockName)
+		}
+		unlockSQL, unlockArgs = "SELECT RELEASE_LOCK(?)", []any{lockName}
+	case base.DXDatabaseTypeSQLServer:
+		var result int64
+		err = conn.QueryRowContext(ctx, "DECLARE @r INT; EXEC @r = sp_getapplock @Resource = @p1, @LockMode = 'Exclusive', @LockOwner = 'Session', @LockTimeout = @p2; SELECT @r",
+			lockName, timeoutSeconds*1000).Scan(&result)
+		if err == nil && result < 0 {
+			err = errors.Errorf("MIGRATION_LOCK_TIMEOUT:%s:RESULT=%d", lockName, result)
+		}
+		unlockSQL, unlockArgs = "EXEC sp_releaseapplock @Resource = @p1, @LockOwner = 'Session'", []any{lockName}
+	case base.DXDatabaseTypeOracle:
+		// DBMS_LOCK needs EXECUTE on SYS.DBMS_LOCK; result 4 means this session already holds it.
+		_, err = conn.ExecContext(ctx, `DECLARE
+  h VARCHAR2(128);
+  r INTEGER;
+BEGIN
+  DBMS_LOCK.ALLOCATE_UNIQUE(:1, h);
+  r := DBMS_LOCK.REQUEST(h, DBMS_LOCK.X_MODE, :2, FALSE);
+  IF r NOT IN (0, 4) THEN
+    RAISE_APPLICATION_ERROR(-20001, 'MIGRATION_LOCK_TIMEOUT:' || r);
+  END IF;
+END;`, lockName, timeoutSeconds)
+		unlockSQL, unlockArgs = `DECLARE
+  h VARCHAR2(128);
+  r INTEGER;
+BEGIN
+  DBMS_LOCK.ALLOCATE_UNIQUE(:1, h);
+  r := DBMS_LOCK.RELEASE(h);
+END;`, []any{lockName}
+	case base.DXDatabaseTypeSQLite:
+		// SQLite has no advisory locks, and holding its write lock here would block
+		// the migration itself: SQLite databases are expected to have one migrator
+	default:
+		return errors.Errorf("MIGRATION_UNSUPPORTED_DATABASE_TYPE:%s", m.Database.DatabaseType.String())
+	}
+	if err != nil {
+		return errors.Wrapf(err, "MIGRATION_LOCK_ERROR:%s", lockName)
+	}
+	defer func() {
+		if unlockSQL == "" {
+			return
+		}
+		// The lock is released even if ctx is already canceled.
+		if _, errUnlock := conn.ExecContext(context.Background(), unlockSQL, unlockArgs...); errUnlock != nil {
+			log.Log.Errorf(errUnlock, "MIGRATION_UNLOCK_ERROR:%s:%v", lockName, errUnlock.Error())
+		}
+	}()
+
+	if err = m.ensureTable(ctx); err != nil {
+		return err
+	}
+	return fn()
+}
+
+func (m *DXDatabaseMigrator) tableExists(ctx context.Context) (bool, error) {
+	var query string
+	switch m.dbType() {
+	case base.DXDatabaseTypePostgreSQL, base.DXDatabaseTypePostgresSQLV2:
+		query = "SELECT COUNT(*) FROM information_schema.tables WHERE table_schema = current_schema() AND table_name = $1"
+	case base.DXDatabaseTypeMariaDB:
+		query = "SELECT COUNT(*) FROM information_schema.tables WHERE table_schema = DATABASE() AND table_name = ?"
+	case base.DXDatabaseTypeSQLite:
+		query = "SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?"
+	case base.DXDatabaseTypeSQLServer:
+		query = "SELECT COUNT(*) FROM INFORMATION_SCHEMA.TABLES WHERE TABLE_SCHEMA = SCHEMA_NAME() AND TABLE_NAME = @p1"
+	case base.DXDatabaseTypeOracle:
+		query = "SELECT COUNT(*) FROM user_tables WHERE table_name = UPPER(:1)"
+	default:
+		return false, errors.Errorf("MIGRATION_UNSUPPORTED_DATABASE_TYPE:%s", m.Database.DatabaseType.String())
+	}
+	var count int64
+	if err := m.Database.Connection.QueryRowContext(ctx, query, m.TableName).Scan(&count); err != nil {
+		return false, errors.Wrapf(err, "MIGRATION_TABLE_CHECK_ERROR:%s", m.TableName)
+	}
+	return count > 0, nil
+}
+
+func (m *DXDatabaseMigrator) ensureTable(ctx context.Context) error {
+	exists, err := m.tableExists(ctx)
+	if err != nil || exists {
+		return err
+	}
+
+	var columns string
+	switch m.dbType() {
+	case base.DXDatabaseTypePostgreSQL, base.DXDatabaseTypePostgresSQLV2:
+		columns = "version VARCHAR(64) PRIMARY KEY, name VARCHAR(255) NOT NULL, checksum VARCHAR(64) NOT NULL, " +
+			"applied_at TIMESTAMP WITH TIME ZONE NOT NULL, duration_ms BIGINT NOT NULL, applied_by VARCHAR(255) NOT NULL"
+	case base.DXDatabaseTypeMariaDB:
+		columns = "version VARCHAR(64) PRIMARY KEY, name VARCHAR(255) NOT NULL, checksum VARCHAR(64) NOT NULL, " +
+			"applied_at DATETIME(6) NOT NULL, duration_ms BIGINT NOT NULL, applied_by VARCHAR(255) NOT NULL"
+	case base.DXDatabaseTypeSQLite:
+		columns = "version TEXT PRIMARY KEY, name TEXT NOT NULL, checksum TEXT NOT NULL, " +
+			"applied_at TIMESTAMP NOT NULL, duration_ms INTEGER NOT NULL, applied_by TEXT NOT NULL"
+	case base.DXDatabaseTypeSQLServer:
+		columns = "version VARCHAR(64) PRIMARY KEY, name NVARCHAR(255) NOT NULL, checksum VARCHAR(64) NOT NULL, " +
+			"applied_at DATETIME2 NOT NULL, duration_ms BIGINT NOT NULL, applied_by NVARCHAR(255) NOT NULL"
+	case base.DXDatabaseTypeOracle:
+		columns = "version VARCHAR2(64) PRIMARY KEY, name VARCHAR2(255) NOT NULL, checksum VARCHAR2(64) NOT NULL, " +
+			"applied_at TIMESTAMP WITH TIME ZONE NOT NULL, duration_ms NUMBER(19) NOT NULL, applied_by VARCHAR2(255) NOT NULL"
+	default:
+		return errors.Errorf("MIGRATION_UNSUPPORTED_DATABASE_TYPE:%s", m.Database.DatabaseType.String())
+	}
+	_, err = m.Database.Connection.ExecContext(ctx, fmt.Sprintf("CREATE TABLE %s (%s)", m.TableName, columns))
+	if err != nil {
+		return errors.Wrapf(err, "MIGRATION_CREATE_TABLE_ERROR:%s", m.TableName)
+	}
+	log.Log.Infof("MIGRATION_TABLE_CREATED:%s:%s", m.Database.NameId, m.TableName)
+	return nil
+}
@@ -0,0 +1,53 @@
+package databases
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/donnyhardyanto/dxlib/base"
+)
+
+func TestLoadMigrationsFromFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"migrations/10_add_index.sql":        {Data: []byte("CREATE INDEX i ON t (a);")},
+		"migrations/2_create_t.up.sql":       {Data: []byte("CREATE TABLE t (a INT);\r\n")},
+		"migrations/2_create_t.down.sql":     {Data: []byte("DROP TABLE t;")},
+		"migrations/0001_init.up.sql":        {Data: []byte("SELECT 1;")},
+		"migrations/README.md":               {Data: []byte("ignored")},
+		"migrations/nested/3_ignored.up.sql": {Data: []byte("SELECT 3;")},
+	}
+	migrations, err := LoadMigrationsFromFS(fsys, "migrations")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var versions []string
+	for _, m := range migrations {
+		versions = append(versions, m.Version)
+	}
+	if len(versions) != 3 || versions[0] != "0001" || versions[1] != "2" || versions[2] != "10" {
+		t.Fatalf("versions = %v, want [0001 2 10]", versions)
+	}
+	if migrations[1].DownSQL != "DROP TABLE t;" || migrations[2].DownSQL != "" {
+		t.Errorf("unexpected down scripts: %q, %q", migrations[1].DownSQL, migrations[2].DownSQL)
+	}
+	if migrations[1].Checksum != MigrationChecksum("CREATE TABLE t (a INT);\n") {
+		t.Errorf("checksum must not depend on line endings")
+	}
+
+	fsys["migrations/2_other.up.sql"] = &fstest.MapFile{Data: []byte("SELECT 2;")}
+	if _, err = LoadMigrationsFromFS(fsys, "migrations"); err == nil {
+		t.Errorf("duplicate version must fail")
+	}
+}
+
+func TestMigrationStatementOracle(t *testing.T) {
+	if got := migrationStatement(base.DXDatabaseTypeOracle, "CREATE TABLE t (a INT);"); got != "CREATE TABLE t (a INT)" {
+		t.Errorf("got %q", got)
+	}
+	if got := migrationStatement(base.DXDatabaseTypeOracle, "BEGIN NULL; END;"); got != "BEGIN NULL; END;" {
+		t.Errorf("got %q", got)
+	}
+	if got := migrationStatement(base.DXDatabaseTypePostgreSQL, "SELECT 1;"); got != "SELECT 1;" {
+		t.Errorf("got %q", got)
+	}
+}
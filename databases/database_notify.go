@@ -0,0 +1,276 @@
+package databases
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/donnyhardyanto/dxlib/base"
+	"github.com/donnyhardyanto/dxlib/core"
+	"github.com/donnyhardyanto/dxlib/databases/models"
+	"github.com/donnyhardyanto/dxlib/errors"
+	"github.com/donnyhardyanto/dxlib/log"
+	dxlibOtel "github.com/donnyhardyanto/dxlib/otel"
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// PostgreSQL LISTEN/NOTIFY: the subscriptions of a database share one listener, a dedicated
+// connection outside PgxPool that LISTENs to every subscribed channel. When the connection
+// fails the listener reconnects with backoff and LISTENs again; notifications sent while it
+// was down are lost, so subscribers that must not miss a change resync after a reconnect.
+
+const (
+	DXDatabaseListenerReconnectInitialBackoff = time.Second
+	DXDatabaseListenerReconnectMaxBackoff     = 30 * time.Second
+)
+
+// DXDatabaseNotification is a notification received on a LISTENed channel
+type DXDatabaseNotification struct {
+	Channel   string
+	Payload   string
+	ProcessId uint32 // backend that sent it
+}
+
+// DXDatabaseNotificationHandler handles the notifications of a subscription. Handlers run one
+// at a time on the listener, so a slow handler delays the notifications of every channel.
+type DXDatabaseNotificationHandler func(ctx context.Context, notification *DXDatabaseNotification)
+
+type dxDatabaseSubscription struct {
+	ctx     context.Context
+	handler DXDatabaseNotificationHandler
+}
+
+type dxDatabaseListener struct {
+	database *DXDatabase
+	cancel   context.CancelFunc
+
+	mu                 sync.Mutex
+	subscriptions      map[string]map[uint64]*dxDatabaseSubscription
+	nextSubscriptionId uint64
+	wake               context.CancelFunc // interrupts the wait for a notification to LISTEN to changed channels
+}
+
+// databaseListenerMutex guards DXDatabase.listener
+var databaseListenerMutex sync.Mutex
+
+// Subscribe calls handler for every notification on channel until ctx is done or unsubscribe
+// is called. PostgreSQL only.
+func (d *DXDatabase) Subscribe(ctx context.Context, channel string, handler DXDatabaseNotificationHandler) (unsubscribe func(), err error) {
+	if d.DatabaseType != base.DXDatabaseTypePostgreSQL && d.DatabaseType != base.DXDatabaseTypePostgresSQLV2 {
+		return nil, errors.Errorf("DB_SUBSCRIBE_UNSUPPORTED_DATABASE_TYPE:%s:%s", d.NameId, d.DatabaseType.String())
+	}
+	if channel == "" || handler == nil {
+		return nil, errors.Errorf("DB_SUBSCRIBE_INVALID_SUBSCRIPTION:%s:%s", d.NameId, channel)
+	}
+	if err = d.EnsureConnection(); err != nil {
+		return nil, err
+	}
+
+	databaseListenerMutex.Lock()
+	if d.listener == nil {
+		listenerCtx, cancel := context.WithCancel(context.Background())
+		d.listener = &dxDatabaseListener{
+			database:      d,
+			cancel:        cancel,
+			subscriptions: map[string]map[uint64]*dxDatabaseSubscription{},
+		}
+		go d.listener.run(listenerCtx)
+	}
+	l := d.listener
+	id := l.add(channel, &dxDatabaseSubscription{ctx: ctx, handler: handler})
+	databaseListenerMutex.Unlock()
+
+	var once sync.Once
+	unsubscribe = func() {
+		once.Do(func() {
+			databaseListenerMutex.Lock()
+			defer databaseListenerMutex.Unlock()
+			if l.remove(channel, id) == 0 && d.listener == l {
+				// the last subscription closes the listener
+				d.listener = nil
+				l.cancel()
+			}
+		})
+	}
+	context.AfterFunc(ctx, unsubscribe)
+	return unsubscribe, nil
+}
+
+func (l *dxDatabaseListener) add(channel string, subscription *dxDatabaseSubscription) uint64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.nextSubscriptionId++
+	if l.subscriptions[channel] == nil {
+		l.subscriptions[channel] = map[uint64]*dxDatabaseSubscription{}
+	}
+	l.subscriptions[channel][l.nextSubscriptionId] = subscription
+	if l.wake != nil {
+		l.wake()
+	}
+	return l.nextSubscriptionId
+}
+
+// remove drops a subscription and returns how many are left
+func (l *dxDatabaseListener) remove(channel string, id uint64) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.subscriptions[channel], id)
+	if len(l.subscriptions[channel]) == 0 {
+		delete(l.subscriptions, channel)
+		if l.wake != nil {
+			l.wake()
+		}
+	}
+	return len(l.subscriptions)
+}
+
+func (l *dxDatabaseListener) run(ctx context.Context) {
+	backoff := DXDatabaseListenerReconnectInitialBackoff
+	for {
+		connected, err := l.listen(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if connected {
+			backoff = DXDatabaseListenerReconnectInitialBackoff
+		}
+		log.Log.Warnf("DB_LISTENER_CONNECTION_LOST:%s:RECONNECT_IN=%s:%v", l.database.NameId, backoff, err)
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		backoff = min(backoff*2, DXDatabaseListenerReconnectMaxBackoff)
+	}
+}
+
+// listen connects, LISTENs to the subscribed channels and dispatches notifications until the
+// connection fails or ctx is done.
+func (l *dxDatabaseListener) listen(ctx context.Context) (connected bool, err error) {
+	pool := l.database.PgxPool
+	if pool == nil {
+		return false, errors.Errorf("DB_LISTENER_DATABASE_NOT_CONNECTED:%s", l.database.NameId)
+	}
+	conn, err := pgx.ConnectConfig(ctx, pool.Config().ConnConfig)
+	if err != nil {
+		return false, errors.Wrapf(err, "DB_LISTENER_CONNECT_ERROR:%s", l.database.NameId)
+	}
+	defer func() {
+		_ = conn.Close(context.Background())
+	}()
+	log.Log.Infof("DB_LISTENER_CONNECTED:%s", l.database.NameId)
+
+	listening := map[string]bool{}
+	for {
+		// wake is set before the channels are synced, so a subscription made meanwhile
+		// ends the wait at once instead of being missed
+		waitCtx, wake := context.WithCancel(ctx)
+		l.mu.Lock()
+		l.wake = wake
+		l.mu.Unlock()
+
+		if err = l.syncChannels(ctx, conn, listening); err != nil {
+			wake()
+			return true, err
+		}
+		notification, err := conn.WaitForNotification(waitCtx)
+		wake()
+		if err != nil {
+			if ctx.Err() != nil {
+				return true, ctx.Err()
+			}
+			if waitCtx.Err() != nil {
+				continue
+			}
+			return true, errors.Wrapf(err, "DB_LISTENER_WAIT_ERROR:%s", l.database.NameId)
+		}
+		l.dispatch(ctx, &DXDatabaseNotification{
+			Channel:   notification.Channel,
+			Payload:   notification.Payload,
+			ProcessId: notification.PID,
+		})
+	}
+}
+
+// syncChannels LISTENs to newly subscribed channels and UNLISTENs from abandoned ones
+func (l *dxDatabaseListener) syncChannels(ctx context.Context, conn *pgx.Conn, listening map[string]bool) error {
+	l.mu.Lock()
+	subscribed := map[string]bool{}
+	for channel := range l.subscriptions {
+		subscribed[channel] = true
+	}
+	l.mu.Unlock()
+
+	for channel := range subscribed {
+		if listening[channel] {
+			continue
+		}
+		if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{channel}.Sanitize()); err != nil {
+			return errors.Wrapf(err, "DB_LISTEN_ERROR:%s:%s", l.database.NameId, channel)
+		}
+		listening[channel] = true
+	}
+	for channel := range listening {
+		if subscribed[channel] {
+			continue
+		}
+		if _, err := conn.Exec(ctx, "UNLISTEN "+pgx.Identifier{channel}.Sanitize()); err != nil {
+			return errors.Wrapf(err, "DB_UNLISTEN_ERROR:%s:%s", l.database.NameId, channel)
+		}
+		delete(listening, channel)
+	}
+	return nil
+}
+
+func (l *dxDatabaseListener) dispatch(ctx context.Context, notification *DXDatabaseNotification) {
+	l.recordNotification(ctx, notification, time.Now())
+
+	l.mu.Lock()
+	subscriptions := make([]*dxDatabaseSubscription, 0, len(l.subscriptions[notification.Channel]))
+	for _, s := range l.subscriptions[notification.Channel] {
+		subscriptions = append(subscriptions, s)
+	}
+	l.mu.Unlock()
+
+	for _, s := range subscriptions {
+		if s.ctx.Err() != nil {
+			continue
+		}
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					log.Log.Errorf(errors.Errorf("%v", r), "DB_NOTIFICATION_HANDLER_PANIC:%s:%s", l.database.NameId, notification.Channel)
+				}
+			}()
+			s.handler(s.ctx, notification)
+		}()
+	}
+}
+
+// recordNotification counts a notification and, for the row change payloads of
+// models.ModelDBTable.NotifyTriggerDDL, records how long after the change it arrived.
+func (l *dxDatabaseListener) recordNotification(ctx context.Context, notification *DXDatabaseNotification, receivedAt time.Time) {
+	if !core.IsOtelEnabled {
+		return
+	}
+	attrs := metric.WithAttributes(
+		attribute.String("db.name", l.database.NameId),
+		attribute.String("db.notification.channel", notification.Channel),
+	)
+	dxlibOtel.DBNotificationCount.Add(ctx, 1, attrs)
+	if !strings.HasPrefix(notification.Payload, "{") {
+		return
+	}
+	var rowChange models.ModelDBRowChange
+	if json.Unmarshal([]byte(notification.Payload), &rowChange) != nil || rowChange.At <= 0 {
+		return
+	}
+	lag := receivedAt.Sub(time.UnixMicro(int64(rowChange.At * 1e6)))
+	dxlibOtel.DBNotificationLag.Record(ctx, max(lag.Seconds(), 0), attrs)
+}
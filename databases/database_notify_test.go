@@ -0,0 +1,33 @@
+package databases
+
+import (
+	"context"
+	"testing"
+)
+
+func TestListenerDispatch(t *testing.T) {
+	l := &dxDatabaseListener{database: &DXDatabase{NameId: "notify_test"}, subscriptions: map[string]map[uint64]*dxDatabaseSubscription{}}
+	var received []string
+	record := func(_ context.Context, n *DXDatabaseNotification) {
+		received = append(received, n.Channel+":"+n.Payload)
+	}
+	canceledCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	l.add("orders", &dxDatabaseSubscription{ctx: context.Background(), handler: func(context.Context, *DXDatabaseNotification) { panic("handler bug") }})
+	l.add("orders", &dxDatabaseSubscription{ctx: context.Background(), handler: record})
+	l.add("orders", &dxDatabaseSubscription{ctx: canceledCtx, handler: record})
+	id := l.add("invoices", &dxDatabaseSubscription{ctx: context.Background(), handler: record})
+
+	l.dispatch(context.Background(), &DXDatabaseNotification{Channel: "orders", Payload: "1"})
+	if len(received) != 1 || received[0] != "orders:1" {
+		t.Fatalf("received = %v", received)
+	}
+	if left := l.remove("invoices", id); left != 1 {
+		t.Fatalf("channels left = %d, want 1", left)
+	}
+	l.dispatch(context.Background(), &DXDatabaseNotification{Channel: "invoices", Payload: "2"})
+	if len(received) != 1 {
+		t.Fatalf("an unsubscribed channel was dispatched: %v", received)
+	}
+}
@@ -0,0 +1,354 @@
+package databases
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/donnyhardyanto/dxlib/base"
+	"github.com/donnyhardyanto/dxlib/databases/db"
+	"github.com/donnyhardyanto/dxlib/databases/models"
+	"github.com/donnyhardyanto/dxlib/errors"
+	"github.com/donnyhardyanto/dxlib/log"
+	"github.com/donnyhardyanto/dxlib/task"
+	"github.com/donnyhardyanto/dxlib/utils"
+	"github.com/jmoiron/sqlx"
+)
+
+// Transactional outbox: DXDatabaseTx.Publish writes an event to the outbox table of the
+// database in the caller's transaction, so the event exists if and only if the change it
+// announces was committed. DXOutboxRelay delivers the events to a DXOutboxSink afterwards,
+// retries failed deliveries with backoff and moves events that keep failing to the
+// dead-letter table. The tables are defined by models.NewModelDBOutboxTables.
+
+// DXDatabaseOutboxDefaultTableName is the outbox table of a database without "outbox_table"
+const DXDatabaseOutboxDefaultTableName = "outbox"
+
+const (
+	DXOutboxRelayDefaultBatchSize      = 100
+	DXOutboxRelayDefaultMaxAttempts    = 10
+	DXOutboxRelayDefaultInitialBackoff = time.Second
+	DXOutboxRelayDefaultMaxBackoff     = time.Hour
+)
+
+// outboxLastErrorMaxLength keeps last_error within its VARCHAR(1024) column
+const outboxLastErrorMaxLength = 1000
+
+// applyOutboxFromConfiguration reads the optional outbox table of a database:
+//
+//	"outbox_table": "app.outbox"
+func (d *DXDatabase) applyOutboxFromConfiguration(databaseConfiguration utils.JSON) {
+	if v, ok := databaseConfiguration["outbox_table"].(string); ok && v != "" {
+		d.OutboxTableName = v
+	}
+}
+
+func (d *DXDatabase) outboxTableName() string {
+	if d.OutboxTableName != "" {
+		return d.OutboxTableName
+	}
+	return DXDatabaseOutboxDefaultTableName
+}
+
+// Publish writes an event with a JSON payload to the outbox in the transaction and returns
+// its id. The relay delivers it once the transaction has committed; a rollback discards it.
+func (dtx *DXDatabaseTx) Publish(topic string, payload utils.JSON) (id int64, err error) {
+	if topic == "" {
+		return 0, errors.New("OUTBOX_TOPIC_IS_EMPTY")
+	}
+	payloadAsBytes, err := json.Marshal(payload)
+	if err != nil {
+		return 0, errors.Wrapf(err, "OUTBOX_PAYLOAD_MARSHAL_ERROR:%s", topic)
+	}
+	ctx := dtx.Ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	now := time.Now().UTC()
+	_, returningFieldValues, err := dtx.Insert(ctx, dtx.Database.outboxTableName(), utils.JSON{
+		"topic":           topic,
+		"payload":         string(payloadAsBytes),
+		"created_at":      now,
+		"attempt_count":   0,
+		"next_attempt_at": now,
+	}, []string{"id"})
+	if err != nil {
+		return 0, errors.Wrapf(err, "OUTBOX_PUBLISH_ERROR:%s", topic)
+	}
+	id, err = outboxInt64(returningFieldValues["id"])
+	if err != nil {
+		return 0, errors.Wrapf(err, "OUTBOX_PUBLISH_ID_ERROR:%s", topic)
+	}
+	return id, nil
+}
+
+// DXOutboxMessage is an outbox event handed to a sink
+type DXOutboxMessage struct {
+	Id           int64
+	Topic        string
+	Payload      []byte // JSON
+	AttemptCount int    // failed deliveries before this one
+}
+
+// DXOutboxSink delivers outbox events to a broker. Delivery is at least once: an event may be
+// sent again when marking it delivered fails, so consumers deduplicate by the event id.
+type DXOutboxSink interface {
+	Send(ctx context.Context, message *DXOutboxMessage) error
+}
+
+// DXOutboxRelay delivers the events of an outbox table to Sink, in id order except for events
+// waiting for a retry. Several relays can share an outbox: each claims its batch with row
+// locks the others skip.
+type DXOutboxRelay struct {
+	Database            *DXDatabase
+	Sink                DXOutboxSink
+	TableName           string
+	DeadLetterTableName string
+	BatchSize           int           // <= 0 means DXOutboxRelayDefaultBatchSize
+	MaxAttempts         int           // failed deliveries before an event is dead-lettered; <= 0 means DXOutboxRelayDefaultMaxAttempts
+	InitialBackoff      time.Duration // wait after the first failed delivery, doubled for each next one
+	MaxBackoff          time.Duration // upper bound of a single wait
+}
+
+// NewOutboxRelay creates a relay of the outbox of d with the default settings
+func (d *DXDatabase) NewOutboxRelay(sink DXOutboxSink) *DXOutboxRelay {
+	tableName := d.outboxTableName()
+	return &DXOutboxRelay{
+		Database:            d,
+		Sink:                sink,
+		TableName:           tableName,
+		DeadLetterTableName: models.OutboxDeadLetterTableName(tableName),
+		BatchSize:           DXOutboxRelayDefaultBatchSize,
+		MaxAttempts:         DXOutboxRelayDefaultMaxAttempts,
+		InitialBackoff:      DXOutboxRelayDefaultInitialBackoff,
+		MaxBackoff:          DXOutboxRelayDefaultMaxBackoff,
+	}
+}
+
+func (r *DXOutboxRelay) batchSize() int {
+	if r.BatchSize <= 0 {
+		return DXOutboxRelayDefaultBatchSize
+	}
+	return r.BatchSize
+}
+
+func (r *DXOutboxRelay) maxAttempts() int {
+	if r.MaxAttempts <= 0 {
+		return DXOutboxRelayDefaultMaxAttempts
+	}
+	return r.MaxAttempts
+}
+
+// backoff returns the wait before retrying an event that failed attemptCount times
+func (r *DXOutboxRelay) backoff(attemptCount int) time.Duration {
+	if r.InitialBackoff <= 0 {
+		return 0
+	}
+	if attemptCount < 1 {
+		attemptCount = 1
+	}
+	step := r.InitialBackoff << (attemptCount - 1)
+	if step <= 0 || (r.MaxBackoff > 0 && step > r.MaxBackoff) {
+		step = r.MaxBackoff
+	}
+	return step
+}
+
+// outboxClaimSQL returns the query that claims up to batchSize due events of tableName,
+// locking them and skipping events other relays have locked. SQLite has a single writer and
+// needs no row locks.
+func outboxClaimSQL(dbType base.DXDatabaseType, tableName string, batchSize int) (string, error) {
+	columns := "id, topic, payload, attempt_count"
+	where := "delivered_at IS NULL AND next_attempt_at <= :now"
+	switch dbType {
+	case base.DXDatabaseTypePostgreSQL, base.DXDatabaseTypePostgresSQLV2, base.DXDatabaseTypeMariaDB:
+		return fmt.Sprintf("SELECT %s FROM %s WHERE %s ORDER BY id LIMIT %d FOR UPDATE SKIP LOCKED", columns, tableName, where, batchSize), nil
+	case base.DXDatabaseTypeSQLServer:
+		return fmt.Sprintf("SELECT TOP (%d) %s FROM %s WITH (UPDLOCK, READPAST, ROWLOCK) WHERE %s ORDER BY id", batchSize, columns, tableName, where), nil
+	case base.DXDatabaseTypeOracle:
+		// Oracle refuses FOR UPDATE together with FETCH FIRST, and a ROWNUM predicate counts
+		// rows before SKIP LOCKED skips the locked ones, claiming nothing once the first
+		// batchSize due events are locked by another relay. SKIP LOCKED locks rows as they
+		// are fetched, so the batch has no limit here: outboxClaimRows stops reading after
+		// batchSize rows. The batch is not ordered.
+		return fmt.Sprintf("SELECT %s FROM %s WHERE %s FOR UPDATE SKIP LOCKED", columns, tableName, where), nil
+	case base.DXDatabaseTypeSQLite:
+		return fmt.Sprintf("SELECT %s FROM %s WHERE %s ORDER BY id LIMIT %d", columns, tableName, where, batchSize), nil
+	default:
+		return "", errors.Errorf("OUTBOX_UNSUPPORTED_DATABASE_TYPE:%s", dbType.String())
+	}
+}
+
+// outboxClaimRows runs the claim query of outboxClaimSQL and returns at most batchSize rows
+func outboxClaimRows(ctx context.Context, tx *sqlx.Tx, dbType base.DXDatabaseType, claimSQL string, args utils.JSON, batchSize int) ([]utils.JSON, error) {
+	if dbType != base.DXDatabaseTypeOracle {
+		_, rows, err := db.TxQueryRows(ctx, tx, nil, claimSQL, args)
+		return rows, err
+	}
+	query, queryArgs := db.OracleSafeBindNames(claimSQL, args)
+	_, seq, err := db.RawTxQueryRowsSeq(ctx, tx, nil, query, queryArgs)
+	if err != nil {
+		return nil, err
+	}
+	var rows []utils.JSON
+	for row, err := range seq {
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, row)
+		if len(rows) == batchSize {
+			break
+		}
+	}
+	return rows, nil
+}
+
+// RelayBatch delivers up to BatchSize due events in one transaction and returns how many
+// were delivered. Failed deliveries are rescheduled or dead-lettered, they do not fail the
+// batch.
+func (r *DXOutboxRelay) RelayBatch(ctx context.Context) (delivered int, err error) {
+	dbType := r.Database.DatabaseType
+	tableName := db.QualifyTableNameForExec(dbType, r.TableName)
+	claimSQL, err := outboxClaimSQL(dbType, tableName, r.batchSize())
+	if err != nil {
+		return 0, err
+	}
+	// Sending is not repeatable, so the transaction is not retried.
+	err = r.Database.TxWithRetryPolicy(ctx, nil, sql.LevelReadCommitted, NoTxRetryPolicy, func(dtx *DXDatabaseTx) error {
+		delivered = 0
+		now := time.Now().UTC()
+		rows, err := outboxClaimRows(ctx, dtx.Tx, dbType, claimSQL, utils.JSON{"now": now}, r.batchSize())
+		if err != nil {
+			return errors.Wrapf(err, "OUTBOX_CLAIM_ERROR:%s", r.TableName)
+		}
+		for _, row := range rows {
+			message, err := outboxMessageFromRow(row)
+			if err != nil {
+				return errors.Wrapf(err, "OUTBOX_ROW_ERROR:%s", r.TableName)
+			}
+			if errSend := r.Sink.Send(ctx, message); errSend != nil {
+				if err = r.fail(ctx, dtx, tableName, message, errSend, now); err != nil {
+					return err
+				}
+				continue
+			}
+			_, err = db.TxExec(ctx, dtx.Tx, "UPDATE "+tableName+" SET delivered_at = :now WHERE id = :id", utils.JSON{"now": now, "id": message.Id})
+			if err != nil {
+				return errors.Wrapf(err, "OUTBOX_MARK_DELIVERED_ERROR:%s:%d", r.TableName, message.Id)
+			}
+			delivered++
+		}
+		return nil
+	})
+	return delivered, err
+}
+
+// fail reschedules an event whose delivery failed, or moves it to the dead-letter table once
+// it has failed MaxAttempts times.
+func (r *DXOutboxRelay) fail(ctx context.Context, dtx *DXDatabaseTx, tableName string, message *DXOutboxMessage, errSend error, now time.Time) error {
+	attemptCount := message.AttemptCount + 1
+	lastError := errSend.Error()
+	if len(lastError) > outboxLastErrorMaxLength {
+		lastError = strings.ToValidUTF8(lastError[:outboxLastErrorMaxLength], "")
+	}
+	args := utils.JSON{"id": message.Id, "attempt_count": attemptCount, "last_error": lastError, "now": now}
+
+	if attemptCount >= r.maxAttempts() {
+		deadLetterTableName := db.QualifyTableNameForExec(r.Database.DatabaseType, r.DeadLetterTableName)
+		_, err := db.TxExec(ctx, dtx.Tx, "INSERT INTO "+deadLetterTableName+" (outbox_id, topic, payload, created_at, attempt_count, last_error, dead_at) "+
+			"SELECT id, topic, payload, created_at, :attempt_count, :last_error, :now FROM "+tableName+" WHERE id = :id", args)
+		if err != nil {
+			return errors.Wrapf(err, "OUTBOX_DEAD_LETTER_ERROR:%s:%d", r.TableName, message.Id)
+		}
+		_, err = db.TxExec(ctx, dtx.Tx, "DELETE FROM "+tableName+" WHERE id = :id", utils.JSON{"id": message.Id})
+		if err != nil {
+			return errors.Wrapf(err, "OUTBOX_DEAD_LETTER_ERROR:%s:%d", r.TableName, message.Id)
+		}
+		log.Log.Warnf("OUTBOX_EVENT_DEAD_LETTERED:%s:%d:%s:ATTEMPTS=%d:%s", r.TableName, message.Id, message.Topic, attemptCount, lastError)
+		return nil
+	}
+
+	wait := r.backoff(attemptCount)
+	args["next_attempt_at"] = now.Add(wait)
+	_, err := db.TxExec(ctx, dtx.Tx, "UPDATE "+tableName+" SET attempt_count = :attempt_count, last_error = :last_error, next_attempt_at = :next_attempt_at WHERE id = :id", args)
+	if err != nil {
+		return errors.Wrapf(err, "OUTBOX_RESCHEDULE_ERROR:%s:%d", r.TableName, message.Id)
+	}
+	log.Log.Warnf("OUTBOX_EVENT_DELIVERY_FAILED:%s:%d:%s:ATTEMPT=%d/%d:BACKOFF=%s:%s", r.TableName, message.Id, message.Topic, attemptCount, r.maxAttempts(), wait, lastError)
+	return nil
+}
+
+// RegisterTask registers the relay in task.Manager as an "always" task named nameId that
+// relays every afterDelaySec seconds, draining full batches without waiting. Like any task,
+// its schedule can be overridden in the "tasks" configuration.
+func (r *DXOutboxRelay) RegisterTask(nameId string, afterDelaySec int64) (*task.DXTask, error) {
+	return task.Manager.NewTask(nameId, "always", afterDelaySec, func(t *task.DXTask) error {
+		for t.Context.Err() == nil {
+			delivered, err := r.RelayBatch(t.Context)
+			if err != nil {
+				// The task stops at its first error; the relay tries again next round instead.
+				t.Log.Errorf(err, "OUTBOX_RELAY_ERROR:%s:%s", r.Database.NameId, r.TableName)
+				return nil
+			}
+			if delivered < r.batchSize() {
+				return nil
+			}
+		}
+		return nil
+	})
+}
+
+func outboxMessageFromRow(row utils.JSON) (message *DXOutboxMessage, err error) {
+	message = &DXOutboxMessage{}
+	if message.Id, err = outboxInt64(row["id"]); err != nil {
+		return nil, err
+	}
+	attemptCount, err := outboxInt64(row["attempt_count"])
+	if err != nil {
+		return nil, err
+	}
+	message.AttemptCount = int(attemptCount)
+	switch v := row["topic"].(type) {
+	case string:
+		message.Topic = v
+	case []byte:
+		message.Topic = string(v)
+	default:
+		return nil, errors.Errorf("OUTBOX_INVALID_TOPIC:%d:%T", message.Id, v)
+	}
+	switch v := row["payload"].(type) {
+	case []byte:
+		message.Payload = v
+	case string:
+		message.Payload = []byte(v)
+	default:
+		// drivers that decode JSON columns themselves
+		if message.Payload, err = json.Marshal(v); err != nil {
+			return nil, err
+		}
+	}
+	return message, nil
+}
+
+func outboxInt64(v any) (int64, error) {
+	switch t := v.(type) {
+	case int64:
+		return t, nil
+	case int32:
+		return int64(t), nil
+	case int:
+		return int64(t), nil
+	case float64:
+		return int64(t), nil
+	case []byte:
+		return strconv.ParseInt(string(t), 10, 64)
+	case nil:
+		return 0, errors.New("OUTBOX_VALUE_IS_NULL")
+	default:
+		return strconv.ParseInt(fmt.Sprint(t), 10, 64)
+	}
+}
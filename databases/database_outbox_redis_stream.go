@@ -0,0 +1,24 @@
+package databases
+
+import (
+	"context"
+
+	"github.com/donnyhardyanto/dxlib/redis"
+)
+
+// DXOutboxRedisStreamSink delivers outbox events to Redis Streams, one stream per topic named
+// StreamPrefix+topic. Each entry carries outbox_id, topic and payload.
+type DXOutboxRedisStreamSink struct {
+	Redis        *redis.DXRedis
+	StreamPrefix string
+	MaxLen       int64 // approximate length the streams are trimmed to, 0 keeps every entry
+}
+
+func (s *DXOutboxRedisStreamSink) Send(ctx context.Context, message *DXOutboxMessage) error {
+	_, err := s.Redis.StreamAdd(ctx, s.StreamPrefix+message.Topic, map[string]any{
+		"outbox_id": message.Id,
+		"topic":     message.Topic,
+		"payload":   string(message.Payload),
+	}, s.MaxLen)
+	return err
+}
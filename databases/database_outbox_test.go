@@ -0,0 +1,125 @@
+package databases
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/donnyhardyanto/dxlib/base"
+	"github.com/donnyhardyanto/dxlib/databases/models"
+	"github.com/donnyhardyanto/dxlib/errors"
+	"github.com/donnyhardyanto/dxlib/utils"
+	"github.com/jmoiron/sqlx"
+)
+
+type testOutboxSink struct {
+	failTopic string
+	sent      []*DXOutboxMessage
+}
+
+func (s *testOutboxSink) Send(_ context.Context, message *DXOutboxMessage) error {
+	if message.Topic == s.failTopic {
+		return errors.New("broker unavailable")
+	}
+	s.sent = append(s.sent, message)
+	return nil
+}
+
+func TestOutboxPublishAndRelay(t *testing.T) {
+	connection, err := sqlx.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	connection.SetMaxOpenConns(1)
+	defer func() {
+		_ = connection.Close()
+	}()
+	outbox, deadLetter := models.NewModelDBOutboxTables(nil, "outbox", 1)
+	for _, table := range []*models.ModelDBTable{outbox, deadLetter} {
+		ddl, err := table.CreateDDL(base.DXDatabaseTypeSQLite)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = connection.Exec(ddl); err != nil {
+			t.Fatalf("%v\n%s", err, ddl)
+		}
+	}
+
+	ctx := context.Background()
+	d := &DXDatabase{NameId: "outbox_test", DatabaseType: base.DXDatabaseTypeSQLite, Connection: connection, Connected: true}
+	err = d.Tx(ctx, nil, sql.LevelDefault, func(dtx *DXDatabaseTx) error {
+		if _, err := dtx.Publish("order.created", utils.JSON{"order_id": 1}); err != nil {
+			return err
+		}
+		_, err := dtx.Publish("order.shipped", utils.JSON{"order_id": 1})
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = d.Tx(ctx, nil, sql.LevelDefault, func(dtx *DXDatabaseTx) error {
+		if _, err := dtx.Publish("order.cancelled", utils.JSON{"order_id": 2}); err != nil {
+			return err
+		}
+		return errors.New("rolled back")
+	})
+
+	sink := &testOutboxSink{failTopic: "order.shipped"}
+	relay := d.NewOutboxRelay(sink)
+	relay.MaxAttempts = 2
+	relay.InitialBackoff = 0
+	for range 2 {
+		if _, err = relay.RelayBatch(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(sink.sent) != 1 || sink.sent[0].Topic != "order.created" || string(sink.sent[0].Payload) != `{"order_id":1}` {
+		t.Fatalf("sent = %+v", sink.sent)
+	}
+
+	var pending, deadLetters int
+	if err = connection.Get(&pending, "SELECT COUNT(*) FROM outbox WHERE delivered_at IS NULL"); err != nil {
+		t.Fatal(err)
+	}
+	var lastError string
+	if err = connection.QueryRow("SELECT COUNT(*), MAX(last_error) FROM outbox_dead_letter WHERE topic = 'order.shipped' AND attempt_count = 2").Scan(&deadLetters, &lastError); err != nil {
+		t.Fatal(err)
+	}
+	if pending != 0 || deadLetters != 1 || !strings.Contains(lastError, "broker unavailable") {
+		t.Fatalf("pending = %d, dead letters = %d (%s)", pending, deadLetters, lastError)
+	}
+
+	// A relay built without NewOutboxRelay gets the default batch size and attempts
+	err = d.Tx(ctx, nil, sql.LevelDefault, func(dtx *DXDatabaseTx) error {
+		_, err := dtx.Publish("order.shipped", utils.JSON{"order_id": 3})
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	bareRelay := &DXOutboxRelay{Database: d, Sink: sink, TableName: relay.TableName, DeadLetterTableName: relay.DeadLetterTableName}
+	if _, err = bareRelay.RelayBatch(ctx); err != nil {
+		t.Fatal(err)
+	}
+	var attemptCount int
+	if err = connection.Get(&attemptCount, "SELECT attempt_count FROM outbox WHERE delivered_at IS NULL"); err != nil || attemptCount != 1 {
+		t.Fatalf("attempt count = %d, err = %v", attemptCount, err)
+	}
+
+	if b := (&DXOutboxRelay{InitialBackoff: time.Second, MaxBackoff: 5 * time.Second}).backoff(4); b != 5*time.Second {
+		t.Fatalf("backoff = %s, want the 5s cap", b)
+	}
+}
+
+func TestOutboxClaimSQLOracle(t *testing.T) {
+	claimSQL, err := outboxClaimSQL(base.DXDatabaseTypeOracle, "outbox", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// ROWNUM would count the rows other relays locked, which SKIP LOCKED then drops
+	if strings.Contains(claimSQL, "ROWNUM") || !strings.HasSuffix(claimSQL, "FOR UPDATE SKIP LOCKED") {
+		t.Errorf("Oracle claim: %s", claimSQL)
+	}
+}
@@ -0,0 +1,353 @@
+package databases
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/donnyhardyanto/dxlib/core"
+	"github.com/donnyhardyanto/dxlib/errors"
+	"github.com/donnyhardyanto/dxlib/log"
+	dxlibOtel "github.com/donnyhardyanto/dxlib/otel"
+	"github.com/donnyhardyanto/dxlib/utils"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const DXDatabaseDefaultConcurrencyAcquireTimeoutMs = 30000
+
+// dxDatabaseConcurrencyCounters are the cumulative counters of a ConcurrencySemaphore.
+type dxDatabaseConcurrencyCounters struct {
+	waiting      atomic.Int64
+	waitCount    atomic.Int64
+	waitNanos    atomic.Int64
+	timeoutCount atomic.Int64
+}
+
+// DXDatabaseConcurrencyStats is a snapshot of a database's ConcurrencySemaphore.
+// WaitCount and WaitDurationMs only count acquisitions that had to wait.
+type DXDatabaseConcurrencyStats struct {
+	Limit          int     `json:"limit"`
+	InUse          int     `json:"in_use"`
+	Waiting        int64   `json:"waiting"`
+	Saturation     float64 `json:"saturation"`
+	WaitCount      int64   `json:"wait_count"`
+	WaitDurationMs int64   `json:"wait_duration_ms"`
+	TimeoutCount   int64   `json:"timeout_count"`
+	AcquireTimeout string  `json:"acquire_timeout"`
+}
+
+// DXDatabasePgxPoolStats is a snapshot of pgxpool.Stat (PostgreSQL only).
+type DXDatabasePgxPoolStats struct {
+	MaxConns                int32 `json:"max_conns"`
+	TotalConns              int32 `json:"total_conns"`
+	AcquiredConns           int32 `json:"acquired_conns"`
+	IdleConns               int32 `json:"idle_conns"`
+	ConstructingConns       int32 `json:"constructing_conns"`
+	AcquireCount            int64 `json:"acquire_count"`
+	AcquireDurationMs       int64 `json:"acquire_duration_ms"`
+	EmptyAcquireCount       int64 `json:"empty_acquire_count"`
+	EmptyAcquireWaitTimeMs  int64 `json:"empty_acquire_wait_time_ms"`
+	CanceledAcquireCount    int64 `json:"canceled_acquire_count"`
+	NewConnsCount           int64 `json:"new_conns_count"`
+	MaxLifetimeDestroyCount int64 `json:"max_lifetime_destroy_count"`
+	MaxIdleDestroyCount     int64 `json:"max_idle_destroy_count"`
+}
+
+// DXDatabasePoolStats is a snapshot of a database's connection pool (sql.DB.Stats), its
+// pgxpool when it has one, its ConcurrencySemaphore and those of its replicas.
+type DXDatabasePoolStats struct {
+	NameId             string                     `json:"nameid"`
+	DatabaseType       string                     `json:"database_type"`
+	Connected          bool                       `json:"connected"`
+	MaxOpenConnections int                        `json:"max_open_connections"`
+	OpenConnections    int                        `json:"open_connections"`
+	InUse              int                        `json:"in_use"`
+	Idle               int                        `json:"idle"`
+	WaitCount          int64                      `json:"wait_count"`
+	WaitDurationMs     int64                      `json:"wait_duration_ms"`
+	MaxIdleClosed      int64                      `json:"max_idle_closed"`
+	MaxIdleTimeClosed  int64                      `json:"max_idle_time_closed"`
+	MaxLifetimeClosed  int64                      `json:"max_lifetime_closed"`
+	Pgx                *DXDatabasePgxPoolStats    `json:"pgx,omitempty"`
+	Concurrency        DXDatabaseConcurrencyStats `json:"concurrency"`
+	Replicas           []DXDatabasePoolStats      `json:"replicas,omitempty"`
+}
+
+// applyConcurrencyFromConfiguration reads the optional concurrency settings of a database:
+//
+//	"concurrency_limit": 10,
+//	"concurrency_acquire_timeout_ms": 30000
+//
+// Without a concurrency_limit, or with 0, the database is not limited. A timeout of 0
+// waits until the context is done.
+func (d *DXDatabase) applyConcurrencyFromConfiguration(databaseConfiguration utils.JSON) {
+	limit := 0
+	if v, ok := configurationNumber(databaseConfiguration["concurrency_limit"]); ok && v > 0 {
+		limit = int(v)
+	}
+	if cap(d.ConcurrencySemaphore) != limit {
+		// Slots taken from the old semaphore are released to it (see AcquireConcurrencySlot).
+		d.ConcurrencySemaphore = newConcurrencySemaphore(limit)
+	}
+	timeoutMs := float64(DXDatabaseDefaultConcurrencyAcquireTimeoutMs)
+	if v, ok := configurationNumber(databaseConfiguration["concurrency_acquire_timeout_ms"]); ok && v >= 0 {
+		timeoutMs = v
+	}
+	d.ConcurrencyAcquireTimeout = time.Duration(timeoutMs) * time.Millisecond
+	if d.concurrencyCounters == nil {
+		d.concurrencyCounters = &dxDatabaseConcurrencyCounters{}
+	}
+}
+
+// newConcurrencySemaphore returns a semaphore of limit slots, nil (not limited) when limit is 0
+func newConcurrencySemaphore(limit int) chan struct{} {
+	if limit <= 0 {
+		return nil
+	}
+	return make(chan struct{}, limit)
+}
+
+// AcquireConcurrencySlot takes a slot of ConcurrencySemaphore, waiting at most
+// ConcurrencyAcquireTimeout. The returned release gives the slot back and must be
+// called exactly once. A database without a semaphore is not limited, and work joining
+// the transaction of ctx runs on the connection that transaction already has.
+//
+// A slot is held for one statement (Insert, Update, Delete, BulkInsert,
+// WithReadConnection), never across a Tx callback: the callback may call the database
+// again with any context, and a nested call must not wait for a slot its caller holds.
+func (d *DXDatabase) AcquireConcurrencySlot(ctx context.Context) (release func(), err error) {
+	semaphore := d.ConcurrencySemaphore
+	if semaphore == nil || TxFromContext(ctx, d) != nil {
+		return func() {}, nil
+	}
+	release = func() { <-semaphore }
+	select {
+	case semaphore <- struct{}{}:
+		d.recordConcurrencyWait(ctx, 0)
+		return release, nil
+	default:
+	}
+
+	counters := d.concurrencyCounters
+	if counters != nil {
+		counters.waiting.Add(1)
+		defer counters.waiting.Add(-1)
+	}
+	start := time.Now()
+	var timeout <-chan time.Time
+	if d.ConcurrencyAcquireTimeout > 0 {
+		timer := time.NewTimer(d.ConcurrencyAcquireTimeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	select {
+	case semaphore <- struct{}{}:
+		wait := time.Since(start)
+		if counters != nil {
+			counters.waitCount.Add(1)
+			counters.waitNanos.Add(int64(wait))
+		}
+		d.recordConcurrencyWait(ctx, wait)
+		return release, nil
+	case <-timeout:
+		if counters != nil {
+			counters.timeoutCount.Add(1)
+		}
+		if core.IsOtelEnabled {
+			dxlibOtel.DBSlotTimeoutCount.Add(ctx, 1, metric.WithAttributes(attribute.String("db.name", d.NameId)))
+		}
+		log.Log.Warnf("DB_CONCURRENCY_ACQUIRE_TIMEOUT:%s:LIMIT=%d:TIMEOUT=%s", d.NameId, cap(semaphore), d.ConcurrencyAcquireTimeout)
+		return nil, errors.Errorf("DB_CONCURRENCY_ACQUIRE_TIMEOUT:%s:ALL_%d_SLOTS_BUSY_FOR_%s", d.NameId, cap(semaphore), d.ConcurrencyAcquireTimeout)
+	case <-ctx.Done():
+		return nil, errors.Wrapf(ctx.Err(), "DB_CONCURRENCY_ACQUIRE_ABORTED:%s", d.NameId)
+	}
+}
+
+// withConcurrencySlot runs fn holding a concurrency slot
+func (d *DXDatabase) withConcurrencySlot(ctx context.Context, fn func() error) error {
+	release, err := d.AcquireConcurrencySlot(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+	return fn()
+}
+
+func (d *DXDatabase) recordConcurrencyWait(ctx context.Context, wait time.Duration) {
+	if core.IsOtelEnabled {
+		dxlibOtel.DBSlotWaitDuration.Record(ctx, wait.Seconds(), metric.WithAttributes(attribute.String("db.name", d.NameId)))
+	}
+}
+
+// ConcurrencyStats returns a snapshot of the ConcurrencySemaphore.
+func (d *DXDatabase) ConcurrencyStats() DXDatabaseConcurrencyStats {
+	s := DXDatabaseConcurrencyStats{
+		Limit:          cap(d.ConcurrencySemaphore),
+		InUse:          len(d.ConcurrencySemaphore),
+		AcquireTimeout: d.ConcurrencyAcquireTimeout.String(),
+	}
+	if s.Limit > 0 {
+		s.Saturation = float64(s.InUse) / float64(s.Limit)
+	}
+	if c := d.concurrencyCounters; c != nil {
+		s.Waiting = c.waiting.Load()
+		s.WaitCount = c.waitCount.Load()
+		s.WaitDurationMs = time.Duration(c.waitNanos.Load()).Milliseconds()
+		s.TimeoutCount = c.timeoutCount.Load()
+	}
+	return s
+}
+
+// PoolStats returns a snapshot of the connection pool of d and of its replicas.
+func (d *DXDatabase) PoolStats() DXDatabasePoolStats {
+	s := DXDatabasePoolStats{
+		NameId:       d.NameId,
+		DatabaseType: d.DatabaseType.String(),
+		Connected:    d.Connected,
+		Concurrency:  d.ConcurrencyStats(),
+	}
+	if connection := d.Connection; connection != nil {
+		st := connection.Stats()
+		s.MaxOpenConnections = st.MaxOpenConnections
+		s.OpenConnections = st.OpenConnections
+		s.InUse = st.InUse
+		s.Idle = st.Idle
+		s.WaitCount = st.WaitCount
+		s.WaitDurationMs = st.WaitDuration.Milliseconds()
+		s.MaxIdleClosed = st.MaxIdleClosed
+		s.MaxIdleTimeClosed = st.MaxIdleTimeClosed
+		s.MaxLifetimeClosed = st.MaxLifetimeClosed
+	}
+	if pool := d.PgxPool; pool != nil {
+		st := pool.Stat()
+		s.Pgx = &DXDatabasePgxPoolStats{
+			MaxConns:                st.MaxConns(),
+			TotalConns:              st.TotalConns(),
+			AcquiredConns:           st.AcquiredConns(),
+			IdleConns:               st.IdleConns(),
+			ConstructingConns:       st.ConstructingConns(),
+			AcquireCount:            st.AcquireCount(),
+			AcquireDurationMs:       st.AcquireDuration().Milliseconds(),
+			EmptyAcquireCount:       st.EmptyAcquireCount(),
+			EmptyAcquireWaitTimeMs:  st.EmptyAcquireWaitTime().Milliseconds(),
+			CanceledAcquireCount:    st.CanceledAcquireCount(),
+			NewConnsCount:           st.NewConnsCount(),
+			MaxLifetimeDestroyCount: st.MaxLifetimeDestroyCount(),
+			MaxIdleDestroyCount:     st.MaxIdleDestroyCount(),
+		}
+	}
+	for _, r := range d.Replicas {
+		s.Replicas = append(s.Replicas, r.Database.PoolStats())
+	}
+	return s
+}
+
+// connectionCounts returns the physical connection counts: pgxpool's when d has one (the
+// sql.DB on top of it only sees the connections it borrowed), sql.DB's otherwise.
+func (s DXDatabasePoolStats) connectionCounts() (idle, inUse, max, waitCount int64, waitDuration time.Duration) {
+	if s.Pgx != nil {
+		return int64(s.Pgx.IdleConns), int64(s.Pgx.AcquiredConns), int64(s.Pgx.MaxConns),
+			s.Pgx.EmptyAcquireCount, time.Duration(s.Pgx.EmptyAcquireWaitTimeMs) * time.Millisecond
+	}
+	return int64(s.Idle), int64(s.InUse), int64(s.MaxOpenConnections),
+		s.WaitCount, time.Duration(s.WaitDurationMs) * time.Millisecond
+}
+
+// PoolStats returns the pool statistics of every managed database, by NameId.
+func (dm *DXDatabaseManager) PoolStats() []DXDatabasePoolStats {
+	nameIds := make([]string, 0, len(dm.Databases))
+	for nameId := range dm.Databases {
+		nameIds = append(nameIds, nameId)
+	}
+	sort.Strings(nameIds)
+	stats := make([]DXDatabasePoolStats, 0, len(nameIds))
+	for _, nameId := range nameIds {
+		stats = append(stats, dm.Databases[nameId].PoolStats())
+	}
+	return stats
+}
+
+var registerPoolMetricsOnce sync.Once
+
+// registerPoolMetrics registers the observable pool gauges of all managed databases
+// (and their replicas) once OTel is set up.
+func registerPoolMetrics() {
+	if !core.IsOtelEnabled {
+		return
+	}
+	registerPoolMetricsOnce.Do(func() {
+		err := registerPoolMetricInstruments()
+		if err != nil {
+			log.Log.Warnf("DB_POOL_METRICS_REGISTER_ERROR:%s", err.Error())
+		}
+	})
+}
+
+func registerPoolMetricInstruments() (err error) {
+	meter := otel.Meter("dxlib")
+	connectionCount, err := meter.Int64ObservableGauge("db.client.connection.count",
+		metric.WithDescription("Number of database connections by state (idle, used)"))
+	if err != nil {
+		return err
+	}
+	connectionMax, err := meter.Int64ObservableGauge("db.client.connection.max",
+		metric.WithDescription("Maximum number of open database connections"))
+	if err != nil {
+		return err
+	}
+	connectionWaitCount, err := meter.Int64ObservableCounter("db.client.connection.wait.count",
+		metric.WithDescription("Total number of connection requests that had to wait for a free connection"))
+	if err != nil {
+		return err
+	}
+	connectionWaitTime, err := meter.Float64ObservableCounter("db.client.connection.wait.duration",
+		metric.WithUnit("s"),
+		metric.WithDescription("Total time spent waiting for a free connection"))
+	if err != nil {
+		return err
+	}
+	slotUsage, err := meter.Int64ObservableGauge("db.client.concurrency.usage",
+		metric.WithDescription("Number of database concurrency slots by state (used, waiting)"))
+	if err != nil {
+		return err
+	}
+	slotLimit, err := meter.Int64ObservableGauge("db.client.concurrency.limit",
+		metric.WithDescription("Size of the database concurrency semaphore"))
+	if err != nil {
+		return err
+	}
+	slotSaturation, err := meter.Float64ObservableGauge("db.client.concurrency.saturation",
+		metric.WithDescription("Fraction of database concurrency slots in use"))
+	if err != nil {
+		return err
+	}
+
+	_, err = meter.RegisterCallback(func(ctx context.Context, o metric.Observer) error {
+		var observe func(s DXDatabasePoolStats)
+		observe = func(s DXDatabasePoolStats) {
+			name := attribute.String("db.name", s.NameId)
+			idle, inUse, max, waitCount, waitDuration := s.connectionCounts()
+			o.ObserveInt64(connectionCount, idle, metric.WithAttributes(name, attribute.String("state", "idle")))
+			o.ObserveInt64(connectionCount, inUse, metric.WithAttributes(name, attribute.String("state", "used")))
+			o.ObserveInt64(connectionMax, max, metric.WithAttributes(name))
+			o.ObserveInt64(connectionWaitCount, waitCount, metric.WithAttributes(name))
+			o.ObserveFloat64(connectionWaitTime, waitDuration.Seconds(), metric.WithAttributes(name))
+			o.ObserveInt64(slotUsage, int64(s.Concurrency.InUse), metric.WithAttributes(name, attribute.String("state", "used")))
+			o.ObserveInt64(slotUsage, s.Concurrency.Waiting, metric.WithAttributes(name, attribute.String("state", "waiting")))
+			o.ObserveInt64(slotLimit, int64(s.Concurrency.Limit), metric.WithAttributes(name))
+			o.ObserveFloat64(slotSaturation, s.Concurrency.Saturation, metric.WithAttributes(name))
+			for _, r := range s.Replicas {
+				observe(r)
+			}
+		}
+		for _, s := range Manager.PoolStats() {
+			observe(s)
+		}
+		return nil
+	}, connectionCount, connectionMax, connectionWaitCount, connectionWaitTime, slotUsage, slotLimit, slotSaturation)
+	return err
+}
@@ -0,0 +1,93 @@
+package databases
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/donnyhardyanto/dxlib/base"
+	"github.com/donnyhardyanto/dxlib/utils"
+	"github.com/jmoiron/sqlx"
+)
+
+func TestAcquireConcurrencySlot(t *testing.T) {
+	d := &DXDatabase{NameId: "test"}
+	d.applyConcurrencyFromConfiguration(utils.JSON{})
+	if d.ConcurrencySemaphore != nil {
+		t.Fatal("a database without a concurrency_limit must not be limited")
+	}
+	d.applyConcurrencyFromConfiguration(utils.JSON{"concurrency_limit": 1, "concurrency_acquire_timeout_ms": float64(20)})
+
+	release, err := d.AcquireConcurrencySlot(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.AcquireConcurrencySlot(context.Background()); err == nil || !strings.Contains(err.Error(), "DB_CONCURRENCY_ACQUIRE_TIMEOUT") {
+		t.Fatalf("err = %v, want DB_CONCURRENCY_ACQUIRE_TIMEOUT", err)
+	}
+	if s := d.ConcurrencyStats(); s.Limit != 1 || s.InUse != 1 || s.Saturation != 1 || s.TimeoutCount != 1 {
+		t.Errorf("stats = %+v", s)
+	}
+
+	go func() {
+		time.Sleep(5 * time.Millisecond)
+		release()
+	}()
+	d.ConcurrencyAcquireTimeout = time.Second
+	release2, err := d.AcquireConcurrencySlot(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	release2()
+	if s := d.ConcurrencyStats(); s.InUse != 0 || s.WaitCount != 1 || s.Waiting != 0 {
+		t.Errorf("stats = %+v", s)
+	}
+}
+
+func TestConcurrencySlotNotHeldAcrossTx(t *testing.T) {
+	connection, err := sqlx.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	connection.SetMaxOpenConns(2)
+	defer func() {
+		_ = connection.Close()
+	}()
+	d := &DXDatabase{NameId: "test", DatabaseType: base.DXDatabaseTypeSQLite, Connection: connection, Connected: true}
+	d.applyConcurrencyFromConfiguration(utils.JSON{"concurrency_limit": 1, "concurrency_acquire_timeout_ms": float64(100)})
+
+	// a nested call made with the request context, not dtx.Ctx, at a limit of 1
+	ctx := context.Background()
+	err = d.Tx(ctx, nil, sql.LevelDefault, func(dtx *DXDatabaseTx) error {
+		return d.WithReadConnection(ctx, func(connection *sqlx.DB) error {
+			var one int
+			return connection.Get(&one, "SELECT 1")
+		})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := d.ConcurrencyStats(); s.InUse != 0 || s.TimeoutCount != 0 {
+		t.Errorf("stats = %+v", s)
+	}
+}
+
+func TestReplicaHasOwnConcurrencySemaphore(t *testing.T) {
+	d := &DXDatabase{NameId: "test", DatabaseType: base.DXDatabaseTypePostgreSQL, DatabaseName: "test"}
+	d.applyConcurrencyFromConfiguration(utils.JSON{"concurrency_limit": 4})
+	err := d.applyReplicasFromConfiguration(utils.JSON{"replicas": []any{
+		"10.0.0.2:5432",
+		utils.JSON{"address": "10.0.0.3:5432", "concurrency_limit": 2},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range []int{4, 2} {
+		semaphore := d.Replicas[i].Database.ConcurrencySemaphore
+		if semaphore == d.ConcurrencySemaphore || cap(semaphore) != want {
+			t.Errorf("replica %d semaphore: shared=%v limit=%d, want own of %d", i, semaphore == d.ConcurrencySemaphore, cap(semaphore), want)
+		}
+	}
+}
@@ -0,0 +1,479 @@
+package databases
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/donnyhardyanto/dxlib/base"
+	"github.com/donnyhardyanto/dxlib/databases/db"
+	"github.com/donnyhardyanto/dxlib/errors"
+	"github.com/donnyhardyanto/dxlib/log"
+	"github.com/donnyhardyanto/dxlib/utils"
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	DXDatabaseReplicaDefaultMaxLagSeconds              = 10.0
+	DXDatabaseReplicaDefaultHealthCheckIntervalSeconds = 15
+)
+
+// DXDatabaseReplica is a read replica of a DXDatabase. Its Database is a regular
+// DXDatabase (own pool, own connection string); routing state is kept here and updated
+// by the health check of the primary.
+type DXDatabaseReplica struct {
+	Database *DXDatabase
+
+	mutex       sync.RWMutex
+	isHealthy   bool
+	lagSeconds  float64
+	lastCheckAt time.Time
+	lastError   error
+}
+
+// DXDatabaseReplicaStatus is a snapshot of a replica's routing state.
+type DXDatabaseReplicaStatus struct {
+	NameId      string    `json:"nameid"`
+	Address     string    `json:"address"`
+	IsHealthy   bool      `json:"is_healthy"`
+	LagSeconds  float64   `json:"lag_seconds"`
+	LastCheckAt time.Time `json:"last_check_at"`
+	LastError   string    `json:"last_error,omitempty"`
+}
+
+func (r *DXDatabaseReplica) Status() DXDatabaseReplicaStatus {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	s := DXDatabaseReplicaStatus{
+		NameId:      r.Database.NameId,
+		Address:     r.Database.Address,
+		IsHealthy:   r.isHealthy,
+		LagSeconds:  r.lagSeconds,
+		LastCheckAt: r.lastCheckAt,
+	}
+	if r.lastError != nil {
+		s.LastError = r.lastError.Error()
+	}
+	return s
+}
+
+func (r *DXDatabaseReplica) isUsable(maxLagSeconds float64) bool {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return r.isHealthy && r.lagSeconds <= maxLagSeconds && r.Database.Connection != nil
+}
+
+func (r *DXDatabaseReplica) setStatus(isHealthy bool, lagSeconds float64, err error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.isHealthy = isHealthy
+	r.lagSeconds = lagSeconds
+	r.lastCheckAt = time.Now()
+	r.lastError = err
+}
+
+// markUnhealthy takes the replica out of rotation until the next successful check.
+func (r *DXDatabaseReplica) markUnhealthy(err error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.isHealthy = false
+	r.lastError = err
+}
+
+// AddReplica registers rd as a read replica of d. rd must be a database of the same type
+// holding the same data, e.g. the ReadOnlyDatabaseNameId database of a module. Replicas
+// are only routed to after their first successful health check.
+func (d *DXDatabase) AddReplica(rd *DXDatabase) *DXDatabaseReplica {
+	r := &DXDatabaseReplica{Database: rd}
+	d.Replicas = append(d.Replicas, r)
+	if d.replicaCursor == nil {
+		d.replicaCursor = &atomic.Uint64{}
+	}
+	if d.ReplicaMaxLagSeconds <= 0 {
+		d.ReplicaMaxLagSeconds = DXDatabaseReplicaDefaultMaxLagSeconds
+	}
+	if d.ReplicaHealthCheckIntervalSeconds <= 0 {
+		d.ReplicaHealthCheckIntervalSeconds = DXDatabaseReplicaDefaultHealthCheckIntervalSeconds
+	}
+	return r
+}
+
+// applyReplicasFromConfiguration reads the optional replica settings of a database:
+//
+//	"replicas": ["10.0.0.2:5432", {"address": "10.0.0.3:5432", "user_name": "...", "user_password": "...", "concurrency_limit": 20}],
+//	"replica_max_lag_seconds": 10,
+//	"replica_health_check_interval_seconds": 15,
+//	"read_your_writes": true
+//
+// A replica inherits every connection setting it does not override. It has a concurrency
+// semaphore of its own, by default as large as the primary's.
+func (d *DXDatabase) applyReplicasFromConfiguration(databaseConfiguration utils.JSON) (err error) {
+	d.ReadYourWrites = true
+	if v, ok := databaseConfiguration["read_your_writes"].(bool); ok {
+		d.ReadYourWrites = v
+	}
+	if v, ok := configurationNumber(databaseConfiguration["replica_max_lag_seconds"]); ok {
+		d.ReplicaMaxLagSeconds = v
+	}
+	if v, ok := configurationNumber(databaseConfiguration["replica_health_check_interval_seconds"]); ok {
+		d.ReplicaHealthCheckIntervalSeconds = int(v)
+	}
+
+	replicas, _ := databaseConfiguration["replicas"].([]any)
+	for i, v := range replicas {
+		rd := &DXDatabase{
+			NameId:                     fmt.Sprintf("%s/replica-%d", d.NameId, i),
+			IsConfigured:               true,
+			DatabaseType:               d.DatabaseType,
+			UserName:                   d.UserName,
+			UserPassword:               d.UserPassword,
+			DatabaseName:               d.DatabaseName,
+			ConnectionOptions:          d.ConnectionOptions,
+			PoolMaxOpenConns:           d.PoolMaxOpenConns,
+			PoolMaxIdleConns:           d.PoolMaxIdleConns,
+			PoolConnMaxLifetimeMinutes: d.PoolConnMaxLifetimeMinutes,
+			PoolConnMaxIdleTimeMinutes: d.PoolConnMaxIdleTimeMinutes,
+			ConcurrencySemaphore:       newConcurrencySemaphore(cap(d.ConcurrencySemaphore)),
+			ConcurrencyAcquireTimeout:  d.ConcurrencyAcquireTimeout,
+			concurrencyCounters:        &dxDatabaseConcurrencyCounters{},
+
+			IsTenantSessionConfigEnabled: d.IsTenantSessionConfigEnabled,
+		}
+		rd.SlowQueryLog = d.newReplicaSlowQueryLog(rd.NameId)
+		switch t := v.(type) {
+		case string:
+			rd.Address = t
+		case utils.JSON:
+			rd.Address, _ = t["address"].(string)
+			if s, ok := t["user_name"].(string); ok {
+				rd.UserName = s
+			}
+			if s, ok := t["user_password"].(string); ok {
+				rd.UserPassword = s
+			}
+			if s, ok := t["database_name"].(string); ok {
+				rd.DatabaseName = s
+			}
+			if s, ok := t["connection_options"].(string); ok {
+				rd.ConnectionOptions = s
+			}
+			if v, ok := configurationNumber(t["concurrency_limit"]); ok && v > 0 {
+				rd.ConcurrencySemaphore = newConcurrencySemaphore(int(v))
+			}
+		}
+		if rd.Address == "" {
+			return errors.Errorf("REPLICA_ADDRESS_MISSING:%s[%d]", d.NameId, i)
+		}
+		rd.NonSensitiveConnectionString = rd.GetNonSensitiveConnectionString()
+		rd.ConnectionString, err = rd.GetConnectionString()
+		if err != nil {
+			return errors.Wrapf(err, "REPLICA_CONNECTION_STRING_ERROR:%s[%d]", d.NameId, i)
+		}
+		d.AddReplica(rd)
+	}
+	return nil
+}
+
+func configurationNumber(v any) (float64, bool) {
+	switch t := v.(type) {
+	case int:
+		return float64(t), true
+	case int64:
+		return float64(t), true
+	case float64:
+		return t, true
+	default:
+		return 0, false
+	}
+}
+
+// startReplicaHealthCheck checks every replica once, then keeps checking them in the
+// background until stopReplicaHealthCheck.
+func (d *DXDatabase) startReplicaHealthCheck() {
+	if len(d.Replicas) == 0 || d.replicaHealthCheckCancel != nil {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	d.replicaHealthCheckCancel = cancel
+	d.CheckReplicas(ctx)
+	go func() {
+		ticker := time.NewTicker(time.Duration(d.ReplicaHealthCheckIntervalSeconds) * time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				d.CheckReplicas(ctx)
+			}
+		}
+	}()
+}
+
+func (d *DXDatabase) stopReplicaHealthCheck() {
+	if d.replicaHealthCheckCancel == nil {
+		return
+	}
+	d.replicaHealthCheckCancel()
+	d.replicaHealthCheckCancel = nil
+	for _, r := range d.Replicas {
+		r.setStatus(false, 0, nil)
+		if err := r.Database.Disconnect(); err != nil {
+			log.Log.Warnf("REPLICA_DISCONNECT_ERROR:%s:%+v", r.Database.NameId, err)
+		}
+	}
+}
+
+// CheckReplicas connects to every replica not yet connected and refreshes its health and
+// replication lag. A replica lagging more than ReplicaMaxLagSeconds stays connected but
+// is skipped by read routing.
+func (d *DXDatabase) CheckReplicas(ctx context.Context) {
+	for _, r := range d.Replicas {
+		err := r.Database.ensureReplicaConnection()
+		if err != nil {
+			r.setStatus(false, 0, err)
+			log.Log.Warnf("REPLICA_CONNECT_ERROR:%s:%s", r.Database.NameId, err.Error())
+			continue
+		}
+		checkCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		lagSeconds, err := r.Database.ReplicationLagSeconds(checkCtx)
+		cancel()
+		if err != nil {
+			r.setStatus(false, 0, err)
+			log.Log.Warnf("REPLICA_LAG_CHECK_ERROR:%s:%s", r.Database.NameId, err.Error())
+			continue
+		}
+		r.setStatus(true, lagSeconds, nil)
+		if lagSeconds > d.ReplicaMaxLagSeconds {
+			log.Log.Warnf("REPLICA_LAG_EXCEEDED:%s:%.1fs>%.1fs", r.Database.NameId, lagSeconds, d.ReplicaMaxLagSeconds)
+		}
+	}
+}
+
+// ensureReplicaConnection is EnsureConnection for a replica: a failed attempt closes
+// the half-opened pool instead of leaving it for the next attempt to overwrite.
+func (d *DXDatabase) ensureReplicaConnection() (err error) {
+	if d.Connected {
+		return nil
+	}
+	err = d.Connect()
+	if err == nil {
+		return nil
+	}
+	if d.Connection != nil {
+		_ = d.Connection.Close()
+		d.Connection = nil
+	}
+	if d.PgxPool != nil {
+		d.PgxPool.Close()
+		d.PgxPool = nil
+	}
+	return err
+}
+
+// ReplicationLagSeconds returns how far this database, as a replica, is behind its
+// primary, using the dialect's replication views. A database that is not replicating
+// reports 0.
+func (d *DXDatabase) ReplicationLagSeconds(ctx context.Context) (lagSeconds float64, err error) {
+	if d.Connection == nil {
+		return 0, errors.New("DB_NOT_CONNECTED")
+	}
+	switch d.DatabaseType {
+	case base.DXDatabaseTypePostgreSQL, base.DXDatabaseTypePostgresSQLV2:
+		// An idle primary produces no new WAL, so replay_timestamp ages without any lag;
+		// a replica that has replayed everything it received counts as current.
+		err = d.Connection.GetContext(ctx, &lagSeconds, `SELECT CASE
+			WHEN NOT pg_is_in_recovery() THEN 0
+			WHEN pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+			ELSE COALESCE(EXTRACT(EPOCH FROM (now() - pg_last_xact_replay_timestamp())), 0)
+		END::float8`)
+	case base.DXDatabaseTypeSQLServer:
+		// Always On secondary: time since the last redone commit. No row means the
+		// database is not part of an availability group.
+		err = d.Connection.GetContext(ctx, &lagSeconds, `SELECT CAST(ISNULL(MAX(DATEDIFF(SECOND, last_commit_time, GETDATE())), 0) AS FLOAT)
+			FROM sys.dm_hadr_database_replica_states WHERE is_local = 1 AND database_id = DB_ID()`)
+	case base.DXDatabaseTypeOracle:
+		// Active Data Guard standby: 'apply lag' is an INTERVAL DAY TO SECOND string.
+		var v sql.NullFloat64
+		err = d.Connection.GetContext(ctx, &v, `SELECT MAX(EXTRACT(DAY FROM TO_DSINTERVAL(value)) * 86400 + EXTRACT(HOUR FROM TO_DSINTERVAL(value)) * 3600 +
+			EXTRACT(MINUTE FROM TO_DSINTERVAL(value)) * 60 + EXTRACT(SECOND FROM TO_DSINTERVAL(value)))
+			FROM v$dataguard_stats WHERE name = 'apply lag' AND value IS NOT NULL`)
+		lagSeconds = v.Float64
+	case base.DXDatabaseTypeMariaDB:
+		lagSeconds, err = mariaDBReplicationLagSeconds(ctx, d.Connection)
+	default:
+		return 0, errors.Errorf("REPLICATION_LAG_NOT_SUPPORTED:%s", d.DatabaseType.String())
+	}
+	if err != nil {
+		return 0, errors.Wrapf(err, "REPLICATION_LAG_QUERY_ERROR:%s", d.NameId)
+	}
+	return lagSeconds, nil
+}
+
+func mariaDBReplicationLagSeconds(ctx context.Context, connection *sqlx.DB) (float64, error) {
+	rows, err := connection.QueryxContext(ctx, "SHOW SLAVE STATUS")
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+	if !rows.Next() {
+		return 0, rows.Err()
+	}
+	row := map[string]any{}
+	if err := rows.MapScan(row); err != nil {
+		return 0, err
+	}
+	for k, v := range row {
+		if !strings.EqualFold(k, "Seconds_Behind_Master") {
+			continue
+		}
+		if v == nil {
+			// NULL: the replication threads are not running.
+			return 0, errors.New("REPLICATION_NOT_RUNNING")
+		}
+		var lagSeconds float64
+		if _, err := fmt.Sscan(fmt.Sprintf("%s", v), &lagSeconds); err != nil {
+			return 0, errors.Wrapf(err, "REPLICATION_LAG_PARSE_ERROR:%v", v)
+		}
+		return lagSeconds, nil
+	}
+	return 0, errors.New("REPLICATION_LAG_COLUMN_NOT_FOUND")
+}
+
+// ReplicaStatuses returns the routing state of every replica.
+func (d *DXDatabase) ReplicaStatuses() []DXDatabaseReplicaStatus {
+	r := make([]DXDatabaseReplicaStatus, 0, len(d.Replicas))
+	for _, replica := range d.Replicas {
+		r = append(r, replica.Status())
+	}
+	return r
+}
+
+// pickReplica returns the next usable replica in round-robin order, or nil when reads
+// must go to the primary: no usable replica, the context is pinned to the primary, or
+// the context carries an ambient transaction of d.
+func (d *DXDatabase) pickReplica(ctx context.Context) *DXDatabaseReplica {
+	if len(d.Replicas) == 0 || d.replicaCursor == nil {
+		return nil
+	}
+	if d.IsPinnedToPrimary(ctx) || TxFromContext(ctx, d) != nil {
+		return nil
+	}
+	n := uint64(len(d.Replicas))
+	start := d.replicaCursor.Add(1)
+	for i := uint64(0); i < n; i++ {
+		r := d.Replicas[(start+i)%n]
+		if r.isUsable(d.ReplicaMaxLagSeconds) {
+			return r
+		}
+	}
+	return nil
+}
+
+// ReadConnection returns the connection a read-only query should use: a healthy replica
+// within the lag limit, otherwise the primary.
+func (d *DXDatabase) ReadConnection(ctx context.Context) *sqlx.DB {
+	if r := d.pickReplica(ctx); r != nil {
+		return r.Database.Connection
+	}
+	return d.Connection
+}
+
+// WithReadConnection runs fn on a replica when one is usable. A connection error on the
+// replica takes it out of rotation and fn is run again on the primary.
+// Each attempt holds a concurrency slot of the database it runs on.
+func (d *DXDatabase) WithReadConnection(ctx context.Context, fn func(connection *sqlx.DB) error) (err error) {
+	if r := d.pickReplica(ctx); r != nil {
+		err = r.Database.withConcurrencySlot(ctx, func() error {
+			return fn(r.Database.Connection)
+		})
+		if err == nil || !db.IsConnectionError(err) {
+			return err
+		}
+		r.markUnhealthy(err)
+		log.Log.Warnf("REPLICA_READ_FAILED_FALLBACK_TO_PRIMARY:%s:%s", r.Database.NameId, err.Error())
+	}
+	return d.withConcurrencySlot(ctx, func() error {
+		return fn(d.Connection)
+	})
+}
+
+// ReadTx is Tx for read-only work (e.g. a paging query that needs session settings): it
+// runs on a usable replica, falling back to the primary like WithReadConnection. The
+// callback must not write, and may run a second time on the primary unless its error
+// is marked with TxNotRepeatable.
+func (d *DXDatabase) ReadTx(ctx context.Context, log *log.DXLog, isolationLevel sql.IsolationLevel, callback DXDatabaseTxCallback) (err error) {
+	if ambientTx := TxFromContext(ctx, d); ambientTx != nil {
+		return callback(ambientTx)
+	}
+	if r := d.pickReplica(ctx); r != nil {
+		err = r.Database.txWithRetry(ctx, log, isolationLevel, d.txRetryPolicy(), callback)
+		if err == nil || !db.IsConnectionError(err) {
+			return err
+		}
+		r.markUnhealthy(err)
+		if isTxNotRepeatable(err) {
+			return err
+		}
+		log.Warnf("REPLICA_READ_TX_FAILED_FALLBACK_TO_PRIMARY:%s:%s", r.Database.NameId, err.Error())
+	}
+	return d.txWithRetry(ctx, log, isolationLevel, d.txRetryPolicy(), callback)
+}
+
+type readYourWritesContextKey struct{}
+
+// readYourWritesState records which databases a request has written to.
+type readYourWritesState struct {
+	written sync.Map
+}
+
+type primaryContextKey struct{}
+
+// ContextWithReadYourWrites returns a context that pins reads of a database to its
+// primary once something has been written to that database through the context, so a
+// request reads its own writes regardless of replica lag. A context that already
+// carries the state is returned as is, so nested units of work share it.
+func ContextWithReadYourWrites(ctx context.Context) context.Context {
+	if _, ok := ctx.Value(readYourWritesContextKey{}).(*readYourWritesState); ok {
+		return ctx
+	}
+	return context.WithValue(ctx, readYourWritesContextKey{}, &readYourWritesState{})
+}
+
+// ContextWithPrimary returns a context whose reads all go to the primaries.
+func ContextWithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryContextKey{}, true)
+}
+
+// MarkWritten records a write to d on ctx (see ContextWithReadYourWrites). Writes made
+// through DXDatabase and its transactions are recorded already; call it after writing
+// through Connection directly.
+func (d *DXDatabase) MarkWritten(ctx context.Context) {
+	if ctx == nil || !d.ReadYourWrites || len(d.Replicas) == 0 {
+		return
+	}
+	if s, ok := ctx.Value(readYourWritesContextKey{}).(*readYourWritesState); ok {
+		s.written.Store(d.NameId, struct{}{})
+	}
+}
+
+// IsPinnedToPrimary reports whether reads of d through ctx must go to the primary.
+func (d *DXDatabase) IsPinnedToPrimary(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	if v, _ := ctx.Value(primaryContextKey{}).(bool); v {
+		return true
+	}
+	if s, ok := ctx.Value(readYourWritesContextKey{}).(*readYourWritesState); ok {
+		_, written := s.written.Load(d.NameId)
+		return written
+	}
+	return false
+}
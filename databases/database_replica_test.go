@@ -0,0 +1,44 @@
+package databases
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+)
+
+func TestReplicaRoutingAndReadYourWrites(t *testing.T) {
+	connection, err := sqlx.Open("postgres", "host=127.0.0.1 dbname=replica_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = connection.Close()
+	}()
+
+	d := &DXDatabase{NameId: "primary", ReadYourWrites: true}
+	fresh := d.AddReplica(&DXDatabase{NameId: "fresh", Connection: connection})
+	lagging := d.AddReplica(&DXDatabase{NameId: "lagging", Connection: connection})
+	fresh.setStatus(true, 1, nil)
+	lagging.setStatus(true, d.ReplicaMaxLagSeconds+1, nil)
+
+	ctx := ContextWithReadYourWrites(context.Background())
+	for i := 0; i < 4; i++ {
+		if r := d.pickReplica(ctx); r != fresh {
+			t.Fatalf("only the replica within the lag limit should be picked, got %v", r)
+		}
+	}
+
+	d.MarkWritten(ctx)
+	if r := d.pickReplica(ctx); r != nil {
+		t.Errorf("reads after a write should be pinned to the primary, got %s", r.Database.NameId)
+	}
+	if r := d.pickReplica(ContextWithReadYourWrites(context.Background())); r != fresh {
+		t.Errorf("another request should still be routed to the replica")
+	}
+
+	fresh.markUnhealthy(nil)
+	if r := d.pickReplica(context.Background()); r != nil {
+		t.Errorf("no usable replica should fall back to the primary, got %s", r.Database.NameId)
+	}
+}
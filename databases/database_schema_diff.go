@@ -0,0 +1,74 @@
+package databases
+
+import (
+	"context"
+
+	"github.com/donnyhardyanto/dxlib/base"
+	"github.com/donnyhardyanto/dxlib/databases/db"
+	"github.com/donnyhardyanto/dxlib/databases/models"
+	"github.com/donnyhardyanto/dxlib/databases/sqlfile"
+	"github.com/donnyhardyanto/dxlib/errors"
+	"github.com/donnyhardyanto/dxlib/log"
+)
+
+// Catalog reads the tables, columns, keys, indexes, triggers and views of the given
+// schemas from the live database.
+func (d *DXDatabase) Catalog(ctx context.Context, schemaNames []string) (*models.ModelDBCatalog, error) {
+	if err := d.EnsureConnection(); err != nil {
+		return nil, err
+	}
+	dbType := base.StringToDXDatabaseType(base.NormalizeDriverName(d.Connection.DriverName()))
+	catalog, err := models.ReadModelDBCatalog(ctx, d.Connection.DB, dbType, schemaNames)
+	if err != nil {
+		return nil, errors.Wrapf(err, "SCHEMA_DIFF_READ_CATALOG_ERROR:%s", d.NameId)
+	}
+	return catalog, nil
+}
+
+// SchemaDiff reads the catalog of the model's schemas and returns the DDL that brings
+// the database in line with model.
+func (d *DXDatabase) SchemaDiff(ctx context.Context, model *models.ModelDB) (*models.ModelDBSchemaDiff, error) {
+	schemaNames := make([]string, 0, len(model.Schemas))
+	for _, schema := range model.Schemas {
+		schemaNames = append(schemaNames, schema.Name)
+	}
+	catalog, err := d.Catalog(ctx, schemaNames)
+	if err != nil {
+		return nil, err
+	}
+	diff, err := model.Diff(catalog.DBType, catalog)
+	if err != nil {
+		return nil, errors.Wrapf(err, "SCHEMA_DIFF_ERROR:%s", d.NameId)
+	}
+	return diff, nil
+}
+
+// ApplySchemaDiff executes the changes of diff in order, one statement at a time and
+// outside a transaction (only PostgreSQL has transactional DDL). Destructive changes
+// are skipped, with a warning, unless allowDestructive; unsupported ones always are.
+func (d *DXDatabase) ApplySchemaDiff(ctx context.Context, diff *models.ModelDBSchemaDiff, allowDestructive bool) error {
+	if err := d.EnsureConnection(); err != nil {
+		return err
+	}
+	for _, change := range diff.Changes {
+		if change.IsUnsupported {
+			log.Log.Warnf("SCHEMA_DIFF_UNSUPPORTED_CHANGE_SKIPPED:%s:%s:%s %s", d.NameId, change.Kind, change.Object, change.Detail)
+			continue
+		}
+		if change.IsDestructive && !allowDestructive {
+			log.Log.Warnf("SCHEMA_DIFF_DESTRUCTIVE_CHANGE_SKIPPED:%s:%s:%s %s", d.NameId, change.Kind, change.Object, change.Detail)
+			continue
+		}
+		sqlFile := sqlfile.New()
+		if err := sqlFile.Content(change.DDL); err != nil {
+			return err
+		}
+		for _, statement := range sqlFile.Queries() {
+			if _, err := db.RawExec(ctx, d.Connection, migrationStatement(diff.DBType, statement), nil); err != nil {
+				return errors.Wrapf(err, "SCHEMA_DIFF_APPLY_ERROR:%s:%s:%s", d.NameId, change.Kind, change.Object)
+			}
+		}
+		log.Log.Infof("SCHEMA_DIFF_APPLIED:%s:%s:%s %s", d.NameId, change.Kind, change.Object, change.Detail)
+	}
+	return nil
+}
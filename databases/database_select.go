@@ -7,20 +7,43 @@ import (
 	"github.com/donnyhardyanto/dxlib/errors"
 	"github.com/donnyhardyanto/dxlib/log"
 	"github.com/donnyhardyanto/dxlib/utils"
+	"github.com/jmoiron/sqlx"
 )
 
 func (d *DXDatabase) Select(ctx context.Context, tableName string, fieldTypeMapping db.DXDatabaseTableFieldTypeMapping, showFieldNames []string, whereAndFieldNameValues utils.JSON, joinSQLPart any,
 	groupBy []string, havingClause utils.JSON, orderByFieldNameDirections db.DXDatabaseTableFieldsOrderBy,
 	limit any, offset any, forUpdatePart any) (rowsInfo *db.DXDatabaseTableRowsInfo, resultDataRows []utils.JSON, err error) {
 
+	if ambientTx := TxFromContext(ctx, d); ambientTx != nil {
+		return ambientTx.Select(ctx, tableName, fieldTypeMapping, showFieldNames, whereAndFieldNameValues, joinSQLPart, groupBy, havingClause,
+			orderByFieldNameDirections, limit, offset, forUpdatePart)
+	}
 	err = d.EnsureConnection()
 	if err != nil {
 		return nil, nil, err
 	}
 
+	// Locking reads must see, and lock, the primary's rows.
+	if forUpdatePart == true {
+		ctx = ContextWithPrimary(ctx)
+	}
+	if d.IsTenantSessionTxNeeded(ctx) {
+		err = d.ReadTx(ctx, &log.Log, LevelReadCommitted, func(dtx *DXDatabaseTx) (err error) {
+			rowsInfo, resultDataRows, err = dtx.Select(dtx.Ctx, tableName, fieldTypeMapping, showFieldNames, whereAndFieldNameValues, joinSQLPart, groupBy, havingClause,
+				orderByFieldNameDirections, limit, offset, forUpdatePart)
+			return err
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		return rowsInfo, resultDataRows, nil
+	}
 	for tryCount := 0; tryCount < 4; tryCount++ {
-		rowsInfo, resultDataRows, err = db.Select(ctx, d.Connection, tableName, fieldTypeMapping, showFieldNames, whereAndFieldNameValues, joinSQLPart, groupBy, havingClause,
-			orderByFieldNameDirections, limit, offset, forUpdatePart)
+		err = d.WithReadConnection(ctx, func(connection *sqlx.DB) (err error) {
+			rowsInfo, resultDataRows, err = db.Select(ctx, connection, tableName, fieldTypeMapping, showFieldNames, whereAndFieldNameValues, joinSQLPart, groupBy, havingClause,
+				orderByFieldNameDirections, limit, offset, forUpdatePart)
+			return err
+		})
 		if err == nil {
 			return rowsInfo, resultDataRows, nil
 		}
@@ -65,13 +88,29 @@ func (d *DXDatabase) ShouldSelectOne(ctx context.Context, tableName string, fiel
 }
 
 func (d *DXDatabase) Count(ctx context.Context, tableName string, whereAndFieldNameValues utils.JSON, joinSQLPart any) (count int64, err error) {
+	if ambientTx := TxFromContext(ctx, d); ambientTx != nil {
+		return ambientTx.Count(ctx, tableName, whereAndFieldNameValues, joinSQLPart, nil, nil)
+	}
+	if d.IsTenantSessionTxNeeded(ctx) {
+		err = d.ReadTx(ctx, &log.Log, LevelReadCommitted, func(dtx *DXDatabaseTx) (err error) {
+			count, err = dtx.Count(dtx.Ctx, tableName, whereAndFieldNameValues, joinSQLPart, nil, nil)
+			return err
+		})
+		if err != nil {
+			return 0, err
+		}
+		return count, nil
+	}
 	err = d.EnsureConnection()
 	if err != nil {
 		return 0, err
 	}
 
 	for tryCount := 0; tryCount < 4; tryCount++ {
-		count, err = db.Count(ctx, d.Connection, tableName, "", whereAndFieldNameValues, joinSQLPart, nil, "", "")
+		err = d.WithReadConnection(ctx, func(connection *sqlx.DB) (err error) {
+			count, err = db.Count(ctx, connection, tableName, "", whereAndFieldNameValues, joinSQLPart, nil, "", "")
+			return err
+		})
 		if err == nil {
 			return count, nil
 		}
@@ -96,8 +135,11 @@ func (d *DXDatabase) SelectPaging(ctx context.Context, pageIndex int64, rowsPerP
 	}
 
 	for tryCount := 0; tryCount < 4; tryCount++ {
-		totalRowCount, rowsInfo, resultDataRows, err = db.SelectPaging(ctx, d.Connection, pageIndex, rowsPerPage, tableName, fieldTypeMapping, fieldNames, whereAndFieldNameValues, joinSQLPart,
-			groupBy, havingClause, orderByFieldNameDirections)
+		err = d.WithReadConnection(ctx, func(connection *sqlx.DB) (err error) {
+			totalRowCount, rowsInfo, resultDataRows, err = db.SelectPaging(ctx, connection, pageIndex, rowsPerPage, tableName, fieldTypeMapping, fieldNames, whereAndFieldNameValues, joinSQLPart,
+				groupBy, havingClause, orderByFieldNameDirections)
+			return err
+		})
 		if err == nil {
 			return totalRowCount, rowsInfo, resultDataRows, nil
 		}
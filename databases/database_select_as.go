@@ -0,0 +1,72 @@
+package databases
+
+import (
+	"context"
+
+	"github.com/donnyhardyanto/dxlib/base"
+	"github.com/donnyhardyanto/dxlib/databases/db"
+	"github.com/donnyhardyanto/dxlib/utils"
+)
+
+// Typed variants of the select methods, mapping the rows into T with db.MapRows. Go has
+// no generic methods, so they take the database or transaction as first argument.
+
+// SelectAs is DXDatabase.Select returning the rows as []T.
+func SelectAs[T any](ctx context.Context, d *DXDatabase, tableName string, fieldTypeMapping db.DXDatabaseTableFieldTypeMapping, fieldNames []string, where utils.JSON, joinSQLPart any,
+	groupBy []string, havingClause utils.JSON, orderBy db.DXDatabaseTableFieldsOrderBy, limit any, offset any, forUpdatePart any) ([]T, error) {
+	_, rows, err := d.Select(ctx, tableName, fieldTypeMapping, fieldNames, where, joinSQLPart, groupBy, havingClause, orderBy, limit, offset, forUpdatePart)
+	if err != nil {
+		return nil, err
+	}
+	return db.MapRows[T](d.DatabaseType, rows)
+}
+
+// SelectOneAs is DXDatabase.SelectOne returning the row as *T, nil when not found.
+func SelectOneAs[T any](ctx context.Context, d *DXDatabase, tableName string, fieldTypeMapping db.DXDatabaseTableFieldTypeMapping, fieldNames []string, where utils.JSON, joinSQLPart any,
+	groupBy []string, havingClause utils.JSON, orderBy db.DXDatabaseTableFieldsOrderBy, offset any, forUpdatePart any) (*T, error) {
+	_, row, err := d.SelectOne(ctx, tableName, fieldTypeMapping, fieldNames, where, joinSQLPart, groupBy, havingClause, orderBy, offset, forUpdatePart)
+	return mapOptionalRow[T](d.DatabaseType, row, err)
+}
+
+// ShouldSelectOneAs is DXDatabase.ShouldSelectOne returning the row as *T.
+func ShouldSelectOneAs[T any](ctx context.Context, d *DXDatabase, tableName string, fieldTypeMapping db.DXDatabaseTableFieldTypeMapping, fieldNames []string, where utils.JSON, joinSQLPart any,
+	groupBy []string, havingClause utils.JSON, orderBy db.DXDatabaseTableFieldsOrderBy, offset any, forUpdatePart any) (*T, error) {
+	_, row, err := d.ShouldSelectOne(ctx, tableName, fieldTypeMapping, fieldNames, where, joinSQLPart, groupBy, havingClause, orderBy, offset, forUpdatePart)
+	return mapOptionalRow[T](d.DatabaseType, row, err)
+}
+
+// TxSelectAs is DXDatabaseTx.Select returning the rows as []T.
+func TxSelectAs[T any](dtx *DXDatabaseTx, tableName string, fieldTypeMapping db.DXDatabaseTableFieldTypeMapping, fieldNames []string, where utils.JSON, joinSQLPart any,
+	groupBy []string, havingClause utils.JSON, orderBy db.DXDatabaseTableFieldsOrderBy, limit any, offset any, forUpdatePart any) ([]T, error) {
+	_, rows, err := dtx.Select(dtx.Ctx, tableName, fieldTypeMapping, fieldNames, where, joinSQLPart, groupBy, havingClause, orderBy, limit, offset, forUpdatePart)
+	if err != nil {
+		return nil, err
+	}
+	return db.MapRows[T](dtx.Database.DatabaseType, rows)
+}
+
+// TxSelectOneAs is DXDatabaseTx.SelectOne returning the row as *T, nil when not found.
+func TxSelectOneAs[T any](dtx *DXDatabaseTx, tableName string, fieldTypeMapping db.DXDatabaseTableFieldTypeMapping, fieldNames []string, where utils.JSON, joinSQLPart any,
+	groupBy []string, havingClause utils.JSON, orderBy db.DXDatabaseTableFieldsOrderBy, offset any, forUpdatePart any) (*T, error) {
+	_, row, err := dtx.SelectOne(dtx.Ctx, tableName, fieldTypeMapping, fieldNames, where, joinSQLPart, groupBy, havingClause, orderBy, offset, forUpdatePart)
+	return mapOptionalRow[T](dtx.Database.DatabaseType, row, err)
+}
+
+// TxShouldSelectOneAs is DXDatabaseTx.ShouldSelectOne returning the row as *T.
+func TxShouldSelectOneAs[T any](dtx *DXDatabaseTx, tableName string, fieldTypeMapping db.DXDatabaseTableFieldTypeMapping, fieldNames []string, where utils.JSON, joinSQLPart any,
+	groupBy []string, havingClause utils.JSON, orderBy db.DXDatabaseTableFieldsOrderBy, offset any, forUpdatePart any) (*T, error) {
+	_, row, err := dtx.ShouldSelectOne(dtx.Ctx, tableName, fieldTypeMapping, fieldNames, where, joinSQLPart, groupBy, havingClause, orderBy, offset, forUpdatePart)
+	return mapOptionalRow[T](dtx.Database.DatabaseType, row, err)
+}
+
+// mapOptionalRow maps the result of a SelectOne: nil when there is no row.
+func mapOptionalRow[T any](dbType base.DXDatabaseType, row utils.JSON, err error) (*T, error) {
+	if err != nil || row == nil {
+		return nil, err
+	}
+	r, err := db.MapRow[T](dbType, row)
+	if err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
@@ -0,0 +1,85 @@
+package databases
+
+import (
+	"sort"
+	"time"
+
+	"github.com/donnyhardyanto/dxlib/databases/db"
+	"github.com/donnyhardyanto/dxlib/utils"
+)
+
+// DXDatabaseSlowQueryStats is a snapshot of the slow query log of a database.
+type DXDatabaseSlowQueryStats struct {
+	NameId           string                           `json:"nameid"`
+	ThresholdMs      int64                            `json:"threshold_ms"`
+	IsExplainEnabled bool                             `json:"is_explain_enabled"`
+	Fingerprints     []db.DXSlowQueryFingerprintStats `json:"fingerprints"`
+	Samples          []db.DXSlowQuerySample           `json:"samples"`
+}
+
+// applySlowQueryLogFromConfiguration reads the optional slow query log settings of a database:
+//
+//	"slow_query_threshold_ms": 500,
+//	"slow_query_log_capacity": 200,
+//	"slow_query_explain": true
+//
+// Without a threshold the log is off.
+func (d *DXDatabase) applySlowQueryLogFromConfiguration(databaseConfiguration utils.JSON) {
+	thresholdMs, ok := configurationNumber(databaseConfiguration["slow_query_threshold_ms"])
+	if !ok || thresholdMs <= 0 {
+		return
+	}
+	capacity, _ := configurationNumber(databaseConfiguration["slow_query_log_capacity"])
+	isExplainEnabled, _ := databaseConfiguration["slow_query_explain"].(bool)
+	d.SetSlowQueryLog(db.NewDXSlowQueryLog(d.NameId, d.DatabaseType, time.Duration(thresholdMs*float64(time.Millisecond)), int(capacity), isExplainEnabled))
+}
+
+// SetSlowQueryLog sets the slow query log of d (nil turns it off), taking effect at once when
+// d is connected.
+func (d *DXDatabase) SetSlowQueryLog(sl *db.DXSlowQueryLog) {
+	d.SlowQueryLog = sl
+	db.RegisterSlowQueryLog(d.Connection, sl)
+}
+
+// newReplicaSlowQueryLog gives a replica its own log with the settings of the primary's
+func (d *DXDatabase) newReplicaSlowQueryLog(replicaNameId string) *db.DXSlowQueryLog {
+	if d.SlowQueryLog == nil {
+		return nil
+	}
+	sl := db.NewDXSlowQueryLog(replicaNameId, d.DatabaseType, d.SlowQueryLog.Threshold, d.SlowQueryLog.Capacity(), d.SlowQueryLog.IsExplainEnabled)
+	sl.ExplainTimeout = d.SlowQueryLog.ExplainTimeout
+	return sl
+}
+
+// SlowQueryStats returns a snapshot of the slow query log of d; ok is false when it has none.
+func (d *DXDatabase) SlowQueryStats() (s DXDatabaseSlowQueryStats, ok bool) {
+	sl := d.SlowQueryLog
+	if sl == nil {
+		return s, false
+	}
+	return DXDatabaseSlowQueryStats{
+		NameId:           d.NameId,
+		ThresholdMs:      sl.Threshold.Milliseconds(),
+		IsExplainEnabled: sl.IsExplainEnabled,
+		Fingerprints:     sl.FingerprintStats(),
+		Samples:          sl.Samples(),
+	}, true
+}
+
+// SlowQueryStats returns the slow query logs of every managed database and replica that has one,
+// by NameId.
+func (dm *DXDatabaseManager) SlowQueryStats() []DXDatabaseSlowQueryStats {
+	stats := []DXDatabaseSlowQueryStats{}
+	for _, d := range dm.Databases {
+		if s, ok := d.SlowQueryStats(); ok {
+			stats = append(stats, s)
+		}
+		for _, r := range d.Replicas {
+			if s, ok := r.Database.SlowQueryStats(); ok {
+				stats = append(stats, s)
+			}
+		}
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].NameId < stats[j].NameId })
+	return stats
+}
@@ -0,0 +1,78 @@
+package databases
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/donnyhardyanto/dxlib/databases/models"
+	"github.com/donnyhardyanto/dxlib/errors"
+	"github.com/donnyhardyanto/dxlib/utils"
+)
+
+type tenantContextKey struct{}
+
+// ContextWithTenantId returns a context carrying the tenant a request acts for, e.g. the
+// TenantId of the LoginSystem its session belongs to. Tables in tenancy mode (see
+// tables.DXRawTable.TenantFieldName) scope every query made through the context to it.
+func ContextWithTenantId(ctx context.Context, tenantId int64) context.Context {
+	return context.WithValue(ctx, tenantContextKey{}, tenantId)
+}
+
+// TenantIdFromContext returns the tenant carried by ctx; ok is false when it has none.
+func TenantIdFromContext(ctx context.Context) (tenantId int64, ok bool) {
+	if ctx == nil {
+		return 0, false
+	}
+	tenantId, ok = ctx.Value(tenantContextKey{}).(int64)
+	return tenantId, ok
+}
+
+// applyTenantFromConfiguration reads the optional tenancy setting of a database:
+//
+//	"tenant_session_config": true
+//
+// With it, every transaction begun with a tenant in its context sets
+// models.TenantSessionConfigKey, which PostgreSQL row-level security policies of
+// models.ModelDBTable.TenantRLSPolicyDDL read; statements with a tenant but no transaction
+// get one of their own (see IsTenantSessionTxNeeded).
+func (d *DXDatabase) applyTenantFromConfiguration(databaseConfiguration utils.JSON) {
+	if v, ok := databaseConfiguration["tenant_session_config"].(bool); ok {
+		d.IsTenantSessionConfigEnabled = v
+	}
+}
+
+// TxSetTenantSessionConfig sets models.TenantSessionConfigKey to tenantId for the rest of
+// the transaction.
+func (dtx *DXDatabaseTx) TxSetTenantSessionConfig(tenantId int64) error {
+	err := txSetSessionConfig(dtx, dtx.Database.DatabaseType, models.TenantSessionConfigKey, strconv.FormatInt(tenantId, 10))
+	if err != nil {
+		return errors.Wrapf(err, "TX_SET_TENANT_SESSION_CONFIG_ERROR:%d", tenantId)
+	}
+	return nil
+}
+
+// txSetTenantSessionConfigFromContext sets the tenant session config of a new transaction
+// when its database asks for it and ctx carries a tenant.
+func (dtx *DXDatabaseTx) txSetTenantSessionConfigFromContext(ctx context.Context) error {
+	if !dtx.Database.IsTenantSessionConfigEnabled {
+		return nil
+	}
+	tenantId, ok := TenantIdFromContext(ctx)
+	if !ok {
+		return nil
+	}
+	return dtx.TxSetTenantSessionConfig(tenantId)
+}
+
+// IsTenantSessionTxNeeded reports whether a statement through ctx has to run in a transaction
+// for row-level security to see its tenant: the database sets the tenant session config and
+// ctx carries a tenant but no ambient transaction. The session config only lives as long as a
+// transaction, so a statement on a bare pool connection would run without it. Select, Count,
+// Insert, Update, Delete and BulkInsert of DXDatabase open that transaction themselves.
+func (d *DXDatabase) IsTenantSessionTxNeeded(ctx context.Context) bool {
+	if !d.IsTenantSessionConfigEnabled || TxFromContext(ctx, d) != nil {
+		return false
+	}
+	_, ok := TenantIdFromContext(ctx)
+	return ok
+}
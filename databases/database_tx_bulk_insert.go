@@ -0,0 +1,19 @@
+package databases
+
+import (
+	"context"
+
+	"github.com/donnyhardyanto/dxlib/databases/db"
+	"github.com/donnyhardyanto/dxlib/utils"
+)
+
+// BulkInsert inserts rows with multi-row statements chunked by the dialect's parameter limit (see db.TxBulkInsert).
+func (dtx *DXDatabaseTx) BulkInsert(ctx context.Context, tableName string, rows []utils.JSON, returningFieldNames []string) (affectedRows int64, returningRows []utils.JSON, err error) {
+	return db.TxBulkInsert(ctx, dtx.Tx, tableName, rows, nil, returningFieldNames)
+}
+
+// BulkUpsert inserts rows, updating existing ones on keyFieldNames conflicts (see db.TxBulkUpsert).
+func (dtx *DXDatabaseTx) BulkUpsert(ctx context.Context, tableName string, rows []utils.JSON, keyFieldNames []string, updateFieldNames []string,
+	returningFieldNames []string) (affectedRows int64, returningRows []utils.JSON, err error) {
+	return db.TxBulkUpsert(ctx, dtx.Tx, tableName, rows, nil, keyFieldNames, updateFieldNames, returningFieldNames)
+}
@@ -0,0 +1,45 @@
+package databases
+
+import (
+	"context"
+)
+
+type txContextKey struct {
+	databaseNameId string
+}
+
+// ContextWithTx returns a context carrying dtx as the ambient transaction of its
+// database. DXDatabase.Tx called with that context joins dtx through a savepoint (see
+// DXDatabaseTx.Nested) instead of opening a new transaction, and DXDatabase.Insert,
+// Update, Delete, Select and Count run on dtx, so several units of work (e.g. the
+// sub-requests of a batch, or table helpers called with dtx.Ctx) commit or roll back
+// together. Each database has its own slot, keyed by NameId. The context carries dtx only
+// until dtx commits or rolls back: a context that outlives the transaction (e.g. dtx.Ctx
+// kept past the callback of DXDatabase.Tx) no longer joins it.
+func ContextWithTx(ctx context.Context, dtx *DXDatabaseTx) context.Context {
+	return context.WithValue(ctx, txContextKey{databaseNameId: dtx.Database.NameId}, dtx)
+}
+
+// TxFromContext returns the ambient transaction of d carried by ctx, or nil.
+func TxFromContext(ctx context.Context, d *DXDatabase) *DXDatabaseTx {
+	if ctx == nil || d == nil {
+		return nil
+	}
+	dtx, _ := ctx.Value(txContextKey{databaseNameId: d.NameId}).(*DXDatabaseTx)
+	if dtx == nil || dtx.isFinished.Load() {
+		return nil
+	}
+	return dtx
+}
+
+// Commit commits the transaction, which stops being the ambient transaction of dtx.Ctx
+func (dtx *DXDatabaseTx) Commit() error {
+	dtx.isFinished.Store(true)
+	return dtx.Tx.Commit()
+}
+
+// Rollback rolls the transaction back, which stops being the ambient transaction of dtx.Ctx
+func (dtx *DXDatabaseTx) Rollback() error {
+	dtx.isFinished.Store(true)
+	return dtx.Tx.Rollback()
+}
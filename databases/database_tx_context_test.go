@@ -0,0 +1,41 @@
+package databases
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/donnyhardyanto/dxlib/base"
+	"github.com/jmoiron/sqlx"
+)
+
+func TestTxContextEndsWithTx(t *testing.T) {
+	connection, err := sqlx.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	connection.SetMaxOpenConns(1)
+	defer func() {
+		_ = connection.Close()
+	}()
+	d := &DXDatabase{NameId: "tx_context_test", DatabaseType: base.DXDatabaseTypeSQLite, Connection: connection, Connected: true}
+
+	var txCtx context.Context
+	err = d.Tx(context.Background(), nil, sql.LevelDefault, func(dtx *DXDatabaseTx) error {
+		txCtx = dtx.Ctx
+		if TxFromContext(txCtx, d) != dtx {
+			t.Error("dtx.Ctx must carry dtx inside the callback")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if TxFromContext(txCtx, d) != nil {
+		t.Fatal("dtx.Ctx must not carry a committed transaction")
+	}
+	// a context kept past the callback starts a transaction of its own
+	if err = d.Tx(txCtx, nil, sql.LevelDefault, func(dtx *DXDatabaseTx) error { return nil }); err != nil {
+		t.Fatal(err)
+	}
+}
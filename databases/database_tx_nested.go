@@ -0,0 +1,78 @@
+package databases
+
+import (
+	"fmt"
+
+	"github.com/donnyhardyanto/dxlib/base"
+	"github.com/donnyhardyanto/dxlib/errors"
+)
+
+// Nested runs callback inside a savepoint of dtx. When callback fails (or panics) only
+// its own work is rolled back and the error is returned; dtx stays usable, and whether
+// it commits is still decided by its owner.
+func (dtx *DXDatabaseTx) Nested(callback DXDatabaseTxCallback) (err error) {
+	dtx.savepointCount++
+	savepointName := fmt.Sprintf("dx_sp_%d", dtx.savepointCount)
+	driverName := base.NormalizeDriverName(dtx.Tx.DriverName())
+
+	_, err = dtx.Tx.ExecContext(dtx.Ctx, savepointSQL(driverName, savepointName))
+	if err != nil {
+		return errors.Wrapf(err, "TX_SAVEPOINT_ERROR:%s", savepointName)
+	}
+
+	completed := false
+	defer func() {
+		if completed {
+			return
+		}
+		// callback panicked; undo its work before the panic unwinds the owner.
+		_, errRollback := dtx.Tx.ExecContext(dtx.Ctx, rollbackToSavepointSQL(driverName, savepointName))
+		if errRollback != nil {
+			dtx.Log.Errorf(errRollback, "TX_ROLLBACK_TO_SAVEPOINT_ERROR:%s:%v", savepointName, errRollback.Error())
+		}
+	}()
+	err = callback(dtx)
+	completed = true
+
+	if err != nil {
+		_, errRollback := dtx.Tx.ExecContext(dtx.Ctx, rollbackToSavepointSQL(driverName, savepointName))
+		if errRollback != nil {
+			dtx.Log.Errorf(errRollback, "TX_ROLLBACK_TO_SAVEPOINT_ERROR:%s:%v", savepointName, errRollback.Error())
+			return errors.Wrapf(err, "TX_ROLLBACK_TO_SAVEPOINT_ERROR:%s:%s", savepointName, errRollback.Error())
+		}
+		return err
+	}
+
+	if releaseSQL := releaseSavepointSQL(driverName, savepointName); releaseSQL != "" {
+		_, err = dtx.Tx.ExecContext(dtx.Ctx, releaseSQL)
+		if err != nil {
+			return errors.Wrapf(err, "TX_RELEASE_SAVEPOINT_ERROR:%s", savepointName)
+		}
+	}
+	return nil
+}
+
+func savepointSQL(driverName string, savepointName string) string {
+	if driverName == "sqlserver" {
+		return "SAVE TRANSACTION " + savepointName
+	}
+	return "SAVEPOINT " + savepointName
+}
+
+func rollbackToSavepointSQL(driverName string, savepointName string) string {
+	if driverName == "sqlserver" {
+		return "ROLLBACK TRANSACTION " + savepointName
+	}
+	return "ROLLBACK TO SAVEPOINT " + savepointName
+}
+
+// releaseSavepointSQL returns "" for SQL Server and Oracle, which have no RELEASE; their
+// savepoints simply end with the transaction.
+func releaseSavepointSQL(driverName string, savepointName string) string {
+	switch driverName {
+	case "sqlserver", "oracle":
+		return ""
+	default:
+		return "RELEASE SAVEPOINT " + savepointName
+	}
+}
@@ -0,0 +1,196 @@
+package databases
+
+import (
+	"context"
+	"database/sql"
+	"math/rand/v2"
+	"time"
+
+	"github.com/donnyhardyanto/dxlib/core"
+	"github.com/donnyhardyanto/dxlib/errors"
+	"github.com/donnyhardyanto/dxlib/log"
+	dxlibOtel "github.com/donnyhardyanto/dxlib/otel"
+	"github.com/donnyhardyanto/dxlib/utils"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// DXDatabaseTxRetryPolicy controls how DXDatabase.Tx re-runs a transaction that failed
+// with a serialization failure or deadlock. Every attempt is a new transaction and runs
+// the whole callback again, so the callback must not have side effects outside the
+// transaction that cannot be repeated (sending mail, publishing to a queue, writing the
+// HTTP response, ...). Such a callback returns its error through TxNotRepeatable, or
+// runs with NoTxRetryPolicy.
+type DXDatabaseTxRetryPolicy struct {
+	MaxAttempts    int                  // attempts including the first; <= 1 disables retry
+	InitialBackoff time.Duration        // backoff before the second attempt, doubled for each next one
+	MaxBackoff     time.Duration        // upper bound of a single backoff
+	IsRetryable    func(err error) bool // nil means IsRetryableTxError
+}
+
+// DefaultTxRetryPolicy is the policy of DXDatabase.Tx when the database sets none: up
+// to three attempts.
+var DefaultTxRetryPolicy = DXDatabaseTxRetryPolicy{
+	MaxAttempts:    3,
+	InitialBackoff: 20 * time.Millisecond,
+	MaxBackoff:     time.Second,
+}
+
+// NoTxRetryPolicy runs a transaction once.
+var NoTxRetryPolicy = DXDatabaseTxRetryPolicy{MaxAttempts: 1}
+
+// DXDatabaseTxNotRepeatableError marks the error of a callback that must not run again,
+// e.g. one that already wrote part of an HTTP response: the transaction is not retried
+// and ReadTx does not run it again on the primary. See TxNotRepeatable.
+type DXDatabaseTxNotRepeatableError struct {
+	Err error
+}
+
+func (e *DXDatabaseTxNotRepeatableError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *DXDatabaseTxNotRepeatableError) Unwrap() error {
+	return e.Err
+}
+
+// TxNotRepeatable wraps err as a DXDatabaseTxNotRepeatableError, nil stays nil
+func TxNotRepeatable(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &DXDatabaseTxNotRepeatableError{Err: err}
+}
+
+func isTxNotRepeatable(err error) bool {
+	var notRepeatableErr *DXDatabaseTxNotRepeatableError
+	return errors.As(err, &notRepeatableErr)
+}
+
+func (p *DXDatabaseTxRetryPolicy) isRetryable(err error) bool {
+	if p.IsRetryable != nil {
+		return p.IsRetryable(err)
+	}
+	return IsRetryableTxError(err)
+}
+
+// backoff returns the wait before the given attempt (2 = first retry): a random
+// duration between half and all of the exponential step, so concurrent transactions
+// that conflicted once do not collide again in lockstep.
+func (p *DXDatabaseTxRetryPolicy) backoff(attempt int) time.Duration {
+	if p.InitialBackoff <= 0 {
+		return 0
+	}
+	step := p.InitialBackoff << (attempt - 2)
+	if step <= 0 || (p.MaxBackoff > 0 && step > p.MaxBackoff) {
+		step = p.MaxBackoff
+	}
+	half := step / 2
+	return half + rand.N(step-half+1)
+}
+
+// applyTxRetryPolicyFromConfiguration reads the optional retry settings of a database,
+// each defaulting to DefaultTxRetryPolicy; "tx_retry_max_attempts": 1 disables retry:
+//
+//	"tx_retry_max_attempts": 3,
+//	"tx_retry_initial_backoff_ms": 20,
+//	"tx_retry_max_backoff_ms": 1000
+func (d *DXDatabase) applyTxRetryPolicyFromConfiguration(databaseConfiguration utils.JSON) {
+	policy := DefaultTxRetryPolicy
+	if v, ok := configurationNumber(databaseConfiguration["tx_retry_max_attempts"]); ok {
+		policy.MaxAttempts = int(v)
+	}
+	if v, ok := configurationNumber(databaseConfiguration["tx_retry_initial_backoff_ms"]); ok {
+		policy.InitialBackoff = time.Duration(v) * time.Millisecond
+	}
+	if v, ok := configurationNumber(databaseConfiguration["tx_retry_max_backoff_ms"]); ok {
+		policy.MaxBackoff = time.Duration(v) * time.Millisecond
+	}
+	d.TxRetryPolicy = &policy
+}
+
+// TxWithRetryPolicy is Tx with a retry policy for this call only.
+func (d *DXDatabase) TxWithRetryPolicy(ctx context.Context, log *log.DXLog, isolationLevel sql.IsolationLevel, policy DXDatabaseTxRetryPolicy,
+	callback DXDatabaseTxCallback) (err error) {
+	if ambientTx := TxFromContext(ctx, d); ambientTx != nil {
+		return ambientTx.Nested(callback)
+	}
+	d.MarkWritten(ctx)
+	return d.txWithRetry(ctx, log, isolationLevel, policy, callback)
+}
+
+func (d *DXDatabase) txRetryPolicy() DXDatabaseTxRetryPolicy {
+	if d.TxRetryPolicy != nil {
+		return *d.TxRetryPolicy
+	}
+	return DefaultTxRetryPolicy
+}
+
+// txWithRetry runs tx until it succeeds, fails with an error the policy does not retry,
+// runs out of attempts, or ctx is done. A transaction joined through the context is
+// never retried here: the error reaches the owner of the outer transaction, which
+// retries as a whole.
+func (d *DXDatabase) txWithRetry(ctx context.Context, l *log.DXLog, isolationLevel sql.IsolationLevel, policy DXDatabaseTxRetryPolicy,
+	callback DXDatabaseTxCallback) (err error) {
+	if l == nil {
+		l = &log.Log
+	}
+	var span trace.Span
+	if core.IsOtelEnabled {
+		ctx, span = otel.Tracer("dxlib.db").Start(ctx, "DXDatabase.Tx",
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(
+				attribute.String("db.system", d.DatabaseType.String()),
+				attribute.String("db.name", d.NameId),
+				attribute.String("db.tx.isolation_level", isolationLevel.String()),
+			))
+	}
+
+	attempt := 1
+	for ; ; attempt++ {
+		err = d.tx(ctx, l, isolationLevel, callback)
+		if err == nil || attempt >= policy.MaxAttempts || isTxNotRepeatable(err) || !policy.isRetryable(err) {
+			break
+		}
+		reason := TxRetryReason(err)
+		if reason == "" {
+			reason = "custom"
+		}
+		wait := policy.backoff(attempt + 1)
+		l.Warnf("TX_RETRY:%s:ATTEMPT=%d/%d:REASON=%s:BACKOFF=%s:%s", d.NameId, attempt+1, policy.MaxAttempts, reason, wait, err.Error())
+		if span != nil {
+			span.AddEvent("tx.retry", trace.WithAttributes(
+				attribute.Int("db.tx.attempt", attempt+1),
+				attribute.String("db.tx.retry_reason", reason),
+				attribute.String("exception.message", err.Error()),
+			))
+			dxlibOtel.DBTxRetryCount.Add(ctx, 1, metric.WithAttributes(
+				attribute.String("db.system", d.DatabaseType.String()),
+				attribute.String("db.tx.retry_reason", reason),
+			))
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			err = errors.Wrapf(ctx.Err(), "TX_RETRY_ABORTED:%s", err.Error())
+		case <-timer.C:
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	if span != nil {
+		span.SetAttributes(attribute.Int("db.tx.attempts", attempt))
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}
+	return err
+}
@@ -0,0 +1,85 @@
+package databases
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/donnyhardyanto/dxlib/base"
+	"github.com/donnyhardyanto/dxlib/errors"
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jmoiron/sqlx"
+	mssql "github.com/microsoft/go-mssqldb"
+)
+
+func TestTxRetryReason(t *testing.T) {
+	cases := []struct {
+		err    error
+		reason string
+	}{
+		{errors.Wrap(&pgconn.PgError{Code: "40001"}, "commit"), "serialization_failure"},
+		{&pgconn.PgError{Code: "40P01"}, "deadlock"},
+		{&pgconn.PgError{Code: "23505"}, ""},
+		{&mysql.MySQLError{Number: 1213}, "deadlock"},
+		{mssql.Error{Number: 1205}, "deadlock"},
+		{errors.New("ORA-08177: can't serialize access for this transaction"), "serialization_failure"},
+		{errors.New("ORA-00060: deadlock detected while waiting for resource"), "deadlock"},
+		{errors.New("ORA-00001: unique constraint violated"), ""},
+	}
+	for _, c := range cases {
+		if got := TxRetryReason(c.err); got != c.reason {
+			t.Errorf("TxRetryReason(%v) = %q, want %q", c.err, got, c.reason)
+		}
+	}
+}
+
+func TestTxRetryPolicyBackoff(t *testing.T) {
+	p := DXDatabaseTxRetryPolicy{MaxAttempts: 5, InitialBackoff: 10 * time.Millisecond, MaxBackoff: 25 * time.Millisecond}
+	for i := 0; i < 100; i++ {
+		if b := p.backoff(2); b < 5*time.Millisecond || b > 10*time.Millisecond {
+			t.Fatalf("first retry backoff %s outside [5ms, 10ms]", b)
+		}
+		if b := p.backoff(5); b < 12500*time.Microsecond || b > 25*time.Millisecond {
+			t.Fatalf("capped backoff %s outside [12.5ms, 25ms]", b)
+		}
+	}
+}
+
+func TestTxRetriesByDefault(t *testing.T) {
+	connection, err := sqlx.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	connection.SetMaxOpenConns(1)
+	defer func() {
+		_ = connection.Close()
+	}()
+	d := &DXDatabase{NameId: "tx_retry_test", DatabaseType: base.DXDatabaseTypeSQLite, Connection: connection, Connected: true}
+
+	attempts := 0
+	callback := func(dtx *DXDatabaseTx) error {
+		attempts++
+		return &pgconn.PgError{Code: "40001"}
+	}
+	_ = d.Tx(context.Background(), nil, sql.LevelDefault, callback)
+	if attempts != DefaultTxRetryPolicy.MaxAttempts {
+		t.Fatalf("Tx ran the callback %d times, want %d", attempts, DefaultTxRetryPolicy.MaxAttempts)
+	}
+
+	attempts = 0
+	_ = d.TxWithRetryPolicy(context.Background(), nil, sql.LevelDefault, NoTxRetryPolicy, callback)
+	if attempts != 1 {
+		t.Fatalf("TxWithRetryPolicy ran the callback %d times, want once", attempts)
+	}
+
+	attempts = 0
+	_ = d.Tx(context.Background(), nil, sql.LevelDefault, func(dtx *DXDatabaseTx) error {
+		attempts++
+		return TxNotRepeatable(&pgconn.PgError{Code: "40001"})
+	})
+	if attempts != 1 {
+		t.Fatalf("a not repeatable callback ran %d times, want once", attempts)
+	}
+}
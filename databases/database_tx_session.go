@@ -7,6 +7,7 @@ import (
 
 	"github.com/donnyhardyanto/dxlib/base"
 	"github.com/donnyhardyanto/dxlib/configuration"
+	"github.com/donnyhardyanto/dxlib/databases/db"
 	"github.com/donnyhardyanto/dxlib/databases/models"
 	"github.com/donnyhardyanto/dxlib/errors"
 	"github.com/donnyhardyanto/dxlib/log"
@@ -80,6 +81,10 @@ func txSetSessionConfig(dtx *DXDatabaseTx, dbType base.DXDatabaseType, key strin
 		query := fmt.Sprintf("SET @%s = ?", varName)
 		_, err := dtx.Tx.Exec(query, value)
 		return err
+	case base.DXDatabaseTypeSQLite:
+		// SQLite: a per-connection TEMP table read by db.SessionKeyExpression
+		_, err := dtx.Tx.Exec(db.SQLiteSetSessionKeySQL, key, value)
+		return err
 	default:
 		return fmt.Errorf("unsupported databases type for TxSetSessionKey: %v", dbType)
 	}
@@ -10,10 +10,29 @@ import (
 )
 
 func (d *DXDatabase) Update(ctx context.Context, tableName string, setFieldValues utils.JSON, whereAndFieldNameValues utils.JSON, returningFieldNames []string) (result sql.Result, returningFieldValues []utils.JSON, err error) {
+	if ambientTx := TxFromContext(ctx, d); ambientTx != nil {
+		return ambientTx.Update(ctx, tableName, setFieldValues, whereAndFieldNameValues, returningFieldNames)
+	}
+	if d.IsTenantSessionTxNeeded(ctx) {
+		err = d.Tx(ctx, &log.Log, LevelReadCommitted, func(dtx *DXDatabaseTx) (err error) {
+			result, returningFieldValues, err = dtx.Update(dtx.Ctx, tableName, setFieldValues, whereAndFieldNameValues, returningFieldNames)
+			return err
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		return result, returningFieldValues, nil
+	}
 	err = d.EnsureConnection()
 	if err != nil {
 		return nil, nil, err
 	}
+	d.MarkWritten(ctx)
+	release, err := d.AcquireConcurrencySlot(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer release()
 
 	for tryCount := 0; tryCount < 4; tryCount++ {
 		result, returningFieldValues, err = db.Update(ctx, d.Connection, tableName, setFieldValues, whereAndFieldNameValues, returningFieldNames)
@@ -0,0 +1,530 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/donnyhardyanto/dxlib/base"
+	"github.com/donnyhardyanto/dxlib/errors"
+	"github.com/donnyhardyanto/dxlib/log"
+	"github.com/donnyhardyanto/dxlib/utils"
+	"github.com/jmoiron/sqlx"
+)
+
+// BulkColumnExpressions wraps the bind placeholder of a column in a SQL expression, e.g. an
+// encryption function. Keyed by column name; the function receives the placeholder of one row.
+type BulkColumnExpressions map[string]func(bindPlaceholder string) string
+
+// bulkLimits returns the maximum bind parameters and rows per statement of a dialect.
+//   - PostgreSQL : 65535 binds (wire protocol Int16 count)
+//   - SQL Server : 2100 binds, 1000 rows per VALUES table constructor
+//   - Oracle     : 65535 binds, INSERT ALL kept to 1000 rows to bound parse time
+//   - MariaDB    : 65535 binds (prepared statement protocol)
+//   - SQLite     : 32766 binds (SQLITE_MAX_VARIABLE_NUMBER since 3.32)
+func bulkLimits(dbType base.DXDatabaseType) (maxBinds int, maxRows int) {
+	switch dbType {
+	case base.DXDatabaseTypeSQLServer:
+		return 2000, 1000
+	case base.DXDatabaseTypeOracle:
+		return 65535, 1000
+	case base.DXDatabaseTypeSQLite:
+		return 32766, 0
+	default:
+		return 65535, 0
+	}
+}
+
+// bulkRowsPerStatement returns how many rows of columnCount columns fit in one statement.
+func bulkRowsPerStatement(dbType base.DXDatabaseType, columnCount int) int {
+	maxBinds, maxRows := bulkLimits(dbType)
+	n := maxBinds / max(columnCount, 1)
+	if maxRows > 0 && n > maxRows {
+		n = maxRows
+	}
+	return max(n, 1)
+}
+
+// BulkRowColumns returns the sorted column names of rows. Every row must have the same keys.
+func BulkRowColumns(rows []utils.JSON) ([]string, error) {
+	if len(rows) == 0 {
+		return nil, errors.New("rows cannot be empty")
+	}
+	columns := sortedKeys(rows[0])
+	if len(columns) == 0 {
+		return nil, errors.New("rows cannot have no columns")
+	}
+	for i, row := range rows[1:] {
+		if len(row) != len(columns) {
+			return nil, errors.Errorf("BULK_ROW_COLUMNS_MISMATCH:ROW=%d", i+1)
+		}
+		for _, c := range columns {
+			if _, ok := row[c]; !ok {
+				return nil, errors.Errorf("BULK_ROW_COLUMNS_MISMATCH:ROW=%d,FIELD=%s", i+1, c)
+			}
+		}
+	}
+	return columns, nil
+}
+
+// bulkStatement is the validated input shared by TxBulkInsert and TxBulkUpsert.
+type bulkStatement struct {
+	driverName          string
+	dbType              base.DXDatabaseType
+	tableName           string
+	columns             []string
+	rows                []utils.JSON
+	columnExpressions   BulkColumnExpressions
+	returningFieldNames []string
+}
+
+func prepareBulkStatement(driverNameRaw string, tableName string, rows []utils.JSON, columnExpressions BulkColumnExpressions,
+	returningFieldNames []string) (b *bulkStatement, err error) {
+	driverName := base.NormalizeDriverName(strings.ToLower(driverNameRaw))
+	dbType := base.StringToDXDatabaseType(driverName)
+
+	if tableName == "" {
+		return nil, errors.New("table name cannot be empty")
+	}
+	// MariaDB virtual-schema: collapse schema.table to a single backtick id (no-op on other engines).
+	tableName = QualifyTableNameForExec(dbType, tableName)
+	if err := CheckIdentifier(dbType, tableName); err != nil {
+		return nil, errors.Wrap(err, "invalid table name")
+	}
+	columns, err := BulkRowColumns(rows)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range columns {
+		if err := CheckIdentifier(dbType, c); err != nil {
+			return nil, errors.Wrapf(err, "invalid field name: %s", c)
+		}
+	}
+	for _, c := range returningFieldNames {
+		if err := CheckIdentifier(dbType, c); err != nil {
+			return nil, errors.Wrapf(err, "invalid returning field name: %s", c)
+		}
+	}
+
+	convertedRows := make([]utils.JSON, len(rows))
+	for i, row := range rows {
+		converted := make(utils.JSON, len(row))
+		for k, v := range row {
+			cv, err := DbDriverConvertValueTypeToDBCompatible(driverName, v)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to convert field value: row %d %s", i, k)
+			}
+			converted[k] = cv
+		}
+		convertedRows[i] = converted
+	}
+
+	return &bulkStatement{
+		driverName:          driverName,
+		dbType:              dbType,
+		tableName:           tableName,
+		columns:             columns,
+		rows:                convertedRows,
+		columnExpressions:   columnExpressions,
+		returningFieldNames: returningFieldNames,
+	}, nil
+}
+
+// column returns the column identifier as written in the statement (quoted-uppercase on Oracle).
+func (b *bulkStatement) column(c string) string {
+	if b.dbType == base.DXDatabaseTypeOracle {
+		return DbDriverFormatIdentifier(b.driverName, c)
+	}
+	return c
+}
+
+func (b *bulkStatement) columnList(prefix string) string {
+	parts := make([]string, len(b.columns))
+	for i, c := range b.columns {
+		parts[i] = prefix + b.column(c)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// values returns the value expressions of each row of chunk, adding their binds to args.
+// Binds are named b<row>_<column index>: short, so Oracle's ":p_" rewrite stays within the
+// identifier length limit, and never equal to a column name.
+func (b *bulkStatement) values(chunk []utils.JSON, args utils.JSON) [][]string {
+	r := make([][]string, len(chunk))
+	for i, row := range chunk {
+		r[i] = make([]string, len(b.columns))
+		for j, c := range b.columns {
+			v := row[c]
+			if e, ok := v.(SQLExpression); ok {
+				r[i][j] = e.String()
+				continue
+			}
+			bindName := fmt.Sprintf("b%d_%d", i, j)
+			args[bindName] = v
+			ph := ":" + bindName
+			if expr := b.columnExpressions[c]; expr != nil {
+				ph = expr(ph)
+			}
+			r[i][j] = ph
+		}
+	}
+	return r
+}
+
+func joinValueRows(valueRows [][]string) string {
+	parts := make([]string, len(valueRows))
+	for i, v := range valueRows {
+		parts[i] = "(" + strings.Join(v, ", ") + ")"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// chunks splits the rows by the dialect limits.
+func (b *bulkStatement) chunks() [][]utils.JSON {
+	n := bulkRowsPerStatement(b.dbType, len(b.columns))
+	var r [][]utils.JSON
+	for start := 0; start < len(b.rows); start += n {
+		r = append(r, b.rows[start:min(start+n, len(b.rows))])
+	}
+	return r
+}
+
+// run executes one chunk statement, as a query when it returns rows.
+func (b *bulkStatement) run(ctx context.Context, tx *sqlx.Tx, sqlStatement string, args utils.JSON, returnsRows bool) (int64, []utils.JSON, error) {
+	if returnsRows {
+		_, rows, err := TxQueryRows(ctx, tx, nil, sqlStatement, args)
+		if err != nil {
+			return 0, nil, err
+		}
+		return int64(len(rows)), rows, nil
+	}
+	result, err := TxExec(ctx, tx, sqlStatement, args)
+	if err != nil {
+		return 0, nil, err
+	}
+	return rowsAffected(result), nil, nil
+}
+
+func rowsAffected(result sql.Result) int64 {
+	if result == nil {
+		return 0
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// TxBulkInsert inserts rows with multi-row statements, chunked by the dialect's bind parameter
+// limit, instead of one round trip per row.
+//
+// Per dialect:
+//   - PostgreSQL / MariaDB / SQLite : INSERT ... VALUES (...), (...) [RETURNING ...]
+//   - SQL Server                    : INSERT ... OUTPUT INSERTED.x VALUES (...), (...)
+//   - Oracle                        : INSERT ALL INTO ... SELECT 1 FROM DUAL; with returningFieldNames
+//     one INSERT ... RETURNING INTO per row, as INSERT ALL cannot return values
+//
+// Every row must have the same keys. columnExpressions (may be nil) wraps the value of a column,
+// e.g. to encrypt it. returningRows follow the order of rows.
+func TxBulkInsert(ctx context.Context, tx *sqlx.Tx, tableName string, rows []utils.JSON, columnExpressions BulkColumnExpressions,
+	returningFieldNames []string) (affectedRows int64, returningRows []utils.JSON, err error) {
+	defer func() {
+		if err != nil {
+			err = NewDBOperationError("BULK_INSERT", tableName, utils.JSON{"rows": len(rows)}, err)
+		} else {
+			log.Log.Debugf("DB_BULK_INSERT table=%s rows=%d", tableName, len(rows))
+		}
+	}()
+	if tx == nil {
+		return 0, nil, errors.New("databases transaction connection is nil")
+	}
+	b, err := prepareBulkStatement(tx.DriverName(), tableName, rows, columnExpressions, returningFieldNames)
+	if err != nil {
+		return 0, nil, err
+	}
+	hasReturning := len(returningFieldNames) > 0
+
+	if b.dbType == base.DXDatabaseTypeOracle && hasReturning {
+		for _, row := range b.rows {
+			args := utils.JSON{}
+			valueRows := b.values([]utils.JSON{row}, args)
+			baseSQL := fmt.Sprintf("INSERT INTO %s (%s) VALUES %s", b.tableName, b.columnList(""), joinValueRows(valueRows))
+			_, returning, err := oracleInsertReturningInto(ctx, tx, baseSQL, args, returningFieldNames)
+			if err != nil {
+				return affectedRows, nil, err
+			}
+			affectedRows++
+			returningRows = append(returningRows, returning)
+		}
+		return affectedRows, returningRows, nil
+	}
+
+	for _, chunk := range b.chunks() {
+		args := utils.JSON{}
+		valueRows := b.values(chunk, args)
+		var sqlStatement string
+		switch b.dbType {
+		case base.DXDatabaseTypePostgreSQL, base.DXDatabaseTypePostgresSQLV2, base.DXDatabaseTypeMariaDB, base.DXDatabaseTypeSQLite:
+			sqlStatement = fmt.Sprintf("INSERT INTO %s (%s) VALUES %s", b.tableName, b.columnList(""), joinValueRows(valueRows))
+			if hasReturning {
+				sqlStatement += " RETURNING " + strings.Join(returningFieldNames, ", ")
+			}
+		case base.DXDatabaseTypeSQLServer:
+			output := ""
+			if hasReturning {
+				outputFields := make([]string, len(returningFieldNames))
+				for i, c := range returningFieldNames {
+					outputFields[i] = "INSERTED." + c
+				}
+				output = " OUTPUT " + strings.Join(outputFields, ", ")
+			}
+			sqlStatement = fmt.Sprintf("INSERT INTO %s (%s)%s VALUES %s", b.tableName, b.columnList(""), output, joinValueRows(valueRows))
+		case base.DXDatabaseTypeOracle:
+			var sb strings.Builder
+			sb.WriteString("INSERT ALL")
+			for _, v := range valueRows {
+				sb.WriteString(fmt.Sprintf(" INTO %s (%s) VALUES (%s)", b.tableName, b.columnList(""), strings.Join(v, ", ")))
+			}
+			sb.WriteString(" SELECT 1 FROM DUAL")
+			sqlStatement = sb.String()
+		default:
+			return affectedRows, nil, errors.Errorf("unsupported databases driver: %s", tx.DriverName())
+		}
+
+		n, chunkRows, err := b.run(ctx, tx, sqlStatement, args, hasReturning)
+		if err != nil {
+			return affectedRows, nil, err
+		}
+		affectedRows += n
+		returningRows = append(returningRows, chunkRows...)
+	}
+	return affectedRows, returningRows, nil
+}
+
+// TxBulkUpsert inserts rows, updating the existing row instead when keyFieldNames conflict.
+//
+// Per dialect:
+//   - PostgreSQL / SQLite : INSERT ... VALUES (...), (...) ON CONFLICT (keys) DO UPDATE ... [RETURNING ...]
+//   - MariaDB             : INSERT ... VALUES (...), (...) ON DUPLICATE KEY UPDATE ...
+//   - SQL Server          : MERGE ... WITH (HOLDLOCK) USING (VALUES (...), (...)) ... OUTPUT inserted.x
+//   - Oracle              : MERGE ... USING (SELECT ... FROM DUAL UNION ALL ...)
+//
+// MariaDB and Oracle cannot return values from these statements; with returningFieldNames the
+// rows are selected back by key after each chunk. keyFieldNames must be a UNIQUE or PRIMARY KEY
+// and unique within rows. updateFieldNames defaults to every non-key column; when there is none,
+// existing rows are left untouched (and PostgreSQL / SQLite return no row for them).
+func TxBulkUpsert(ctx context.Context, tx *sqlx.Tx, tableName string, rows []utils.JSON, columnExpressions BulkColumnExpressions,
+	keyFieldNames []string, updateFieldNames []string, returningFieldNames []string) (affectedRows int64, returningRows []utils.JSON, err error) {
+	defer func() {
+		if err != nil {
+			err = NewDBOperationError("BULK_UPSERT", tableName, utils.JSON{"rows": len(rows), "keys": keyFieldNames}, err)
+		} else {
+			log.Log.Debugf("DB_BULK_UPSERT table=%s rows=%d keys=%v", tableName, len(rows), keyFieldNames)
+		}
+	}()
+	if tx == nil {
+		return 0, nil, errors.New("databases transaction connection is nil")
+	}
+	if len(keyFieldNames) == 0 {
+		return 0, nil, errors.New("keyFieldNames cannot be empty")
+	}
+	b, err := prepareBulkStatement(tx.DriverName(), tableName, rows, columnExpressions, returningFieldNames)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	isKey := map[string]bool{}
+	for _, k := range keyFieldNames {
+		if _, ok := rows[0][k]; !ok {
+			return 0, nil, errors.Errorf("BULK_UPSERT_KEY_FIELD_NOT_IN_ROWS:%s", k)
+		}
+		if columnExpressions[k] != nil {
+			return 0, nil, errors.Errorf("BULK_UPSERT_KEY_FIELD_HAS_EXPRESSION:%s", k)
+		}
+		isKey[k] = true
+	}
+	if updateFieldNames == nil {
+		for _, c := range b.columns {
+			if !isKey[c] {
+				updateFieldNames = append(updateFieldNames, c)
+			}
+		}
+	}
+	for _, c := range updateFieldNames {
+		if _, ok := rows[0][c]; !ok {
+			return 0, nil, errors.Errorf("BULK_UPSERT_UPDATE_FIELD_NOT_IN_ROWS:%s", c)
+		}
+	}
+	seenKeys := make(map[string]bool, len(b.rows))
+	for i, row := range b.rows {
+		k := bulkKeyString(row, keyFieldNames)
+		if seenKeys[k] {
+			return 0, nil, errors.Errorf("BULK_UPSERT_DUPLICATE_KEY:ROW=%d", i)
+		}
+		seenKeys[k] = true
+	}
+	hasReturning := len(returningFieldNames) > 0
+
+	for _, chunk := range b.chunks() {
+		args := utils.JSON{}
+		valueRows := b.values(chunk, args)
+		returnsRows := hasReturning
+		var sqlStatement string
+		switch b.dbType {
+		case base.DXDatabaseTypePostgreSQL, base.DXDatabaseTypePostgresSQLV2, base.DXDatabaseTypeSQLite:
+			conflictAction := "DO NOTHING"
+			if len(updateFieldNames) > 0 {
+				assigns := make([]string, len(updateFieldNames))
+				for i, c := range updateFieldNames {
+					assigns[i] = fmt.Sprintf("%s = EXCLUDED.%s", c, c)
+				}
+				conflictAction = "DO UPDATE SET " + strings.Join(assigns, ", ")
+			}
+			sqlStatement = fmt.Sprintf("INSERT INTO %s (%s) VALUES %s ON CONFLICT (%s) %s",
+				b.tableName, b.columnList(""), joinValueRows(valueRows), strings.Join(keyFieldNames, ", "), conflictAction)
+			if hasReturning {
+				sqlStatement += " RETURNING " + strings.Join(returningFieldNames, ", ")
+			}
+		case base.DXDatabaseTypeMariaDB:
+			// Without update columns the key is assigned to itself: a no-op that keeps
+			// the duplicate from failing the statement.
+			assignCols := updateFieldNames
+			if len(assignCols) == 0 {
+				assignCols = keyFieldNames[:1]
+			}
+			assigns := make([]string, len(assignCols))
+			for i, c := range assignCols {
+				assigns[i] = fmt.Sprintf("%s = VALUES(%s)", c, c)
+			}
+			sqlStatement = fmt.Sprintf("INSERT INTO %s (%s) VALUES %s ON DUPLICATE KEY UPDATE %s",
+				b.tableName, b.columnList(""), joinValueRows(valueRows), strings.Join(assigns, ", "))
+			returnsRows = false
+		case base.DXDatabaseTypeSQLServer:
+			// HOLDLOCK: plain MERGE races under concurrent writers (see buildUpsertSQLServerSQL)
+			sqlStatement = fmt.Sprintf("MERGE INTO %s WITH (HOLDLOCK) AS tgt USING (VALUES %s) AS src (%s) ON %s",
+				b.tableName, joinValueRows(valueRows), b.columnList(""), b.matchCondition(keyFieldNames))
+			if len(updateFieldNames) > 0 {
+				sqlStatement += " WHEN MATCHED THEN UPDATE SET " + b.assignFromSource(updateFieldNames)
+			}
+			sqlStatement += fmt.Sprintf(" WHEN NOT MATCHED THEN INSERT (%s) VALUES (%s)", b.columnList(""), b.columnList("src."))
+			if hasReturning {
+				outputFields := make([]string, len(returningFieldNames))
+				for i, c := range returningFieldNames {
+					outputFields[i] = "inserted." + c
+				}
+				sqlStatement += " OUTPUT " + strings.Join(outputFields, ", ")
+			}
+			sqlStatement += ";"
+		case base.DXDatabaseTypeOracle:
+			selects := make([]string, len(valueRows))
+			for i, v := range valueRows {
+				fields := make([]string, len(v))
+				for j, c := range b.columns {
+					fields[j] = fmt.Sprintf("%s AS %s", v[j], b.column(c))
+				}
+				selects[i] = "SELECT " + strings.Join(fields, ", ") + " FROM DUAL"
+			}
+			sqlStatement = fmt.Sprintf("MERGE INTO %s tgt USING (%s) src ON (%s)",
+				b.tableName, strings.Join(selects, " UNION ALL "), b.matchCondition(keyFieldNames))
+			if len(updateFieldNames) > 0 {
+				sqlStatement += " WHEN MATCHED THEN UPDATE SET " + b.assignFromSource(updateFieldNames)
+			}
+			sqlStatement += fmt.Sprintf(" WHEN NOT MATCHED THEN INSERT (%s) VALUES (%s)", b.columnList(""), b.columnList("src."))
+			returnsRows = false
+		default:
+			return affectedRows, nil, errors.Errorf("unsupported databases driver: %s", tx.DriverName())
+		}
+
+		n, chunkRows, err := b.run(ctx, tx, sqlStatement, args, returnsRows)
+		if err != nil {
+			return affectedRows, nil, err
+		}
+		affectedRows += n
+		if hasReturning && !returnsRows {
+			chunkRows, err = b.selectByKeys(ctx, tx, chunk, keyFieldNames)
+			if err != nil {
+				return affectedRows, nil, err
+			}
+		}
+		returningRows = append(returningRows, chunkRows...)
+	}
+	return affectedRows, returningRows, nil
+}
+
+func (b *bulkStatement) matchCondition(keyFieldNames []string) string {
+	conds := make([]string, len(keyFieldNames))
+	for i, k := range keyFieldNames {
+		conds[i] = fmt.Sprintf("tgt.%s = src.%s", b.column(k), b.column(k))
+	}
+	return strings.Join(conds, " AND ")
+}
+
+func (b *bulkStatement) assignFromSource(fieldNames []string) string {
+	assigns := make([]string, len(fieldNames))
+	for i, c := range fieldNames {
+		assigns[i] = fmt.Sprintf("tgt.%s = src.%s", b.column(c), b.column(c))
+	}
+	return strings.Join(assigns, ", ")
+}
+
+// selectByKeys reads the returning fields of the chunk rows back by key, in chunk order.
+func (b *bulkStatement) selectByKeys(ctx context.Context, tx *sqlx.Tx, chunk []utils.JSON, keyFieldNames []string) ([]utils.JSON, error) {
+	args := utils.JSON{}
+	rowConds := make([]string, len(chunk))
+	for i, row := range chunk {
+		conds := make([]string, len(keyFieldNames))
+		for j, k := range keyFieldNames {
+			bindName := fmt.Sprintf("k%d_%d", i, j)
+			args[bindName] = row[k]
+			conds[j] = fmt.Sprintf("%s = :%s", b.column(k), bindName)
+		}
+		rowConds[i] = "(" + strings.Join(conds, " AND ") + ")"
+	}
+	selectFields := make([]string, 0, len(b.returningFieldNames)+len(keyFieldNames))
+	for _, c := range b.returningFieldNames {
+		selectFields = append(selectFields, b.column(c))
+	}
+	for _, k := range keyFieldNames {
+		selectFields = append(selectFields, b.column(k))
+	}
+	sqlStatement := fmt.Sprintf("SELECT %s FROM %s WHERE %s",
+		strings.Join(selectFields, ", "), b.tableName, strings.Join(rowConds, " OR "))
+	_, selected, err := TxQueryRows(ctx, tx, nil, sqlStatement, args)
+	if err != nil {
+		return nil, err
+	}
+
+	byKey := make(map[string]utils.JSON, len(selected))
+	for _, s := range selected {
+		byKey[bulkKeyString(s, keyFieldNames)] = s
+	}
+	r := make([]utils.JSON, 0, len(chunk))
+	for _, row := range chunk {
+		s, ok := byKey[bulkKeyString(row, keyFieldNames)]
+		if !ok {
+			return nil, errors.Errorf("BULK_UPSERT_ROW_NOT_FOUND_AFTER_UPSERT:%s", bulkKeyString(row, keyFieldNames))
+		}
+		returning := utils.JSON{}
+		for _, c := range b.returningFieldNames {
+			returning[c] = s[c]
+		}
+		r = append(r, returning)
+	}
+	return r, nil
+}
+
+// bulkKeyString renders the key values of a row for matching; driver value types differ
+// (e.g. Oracle returns numbers as strings, MariaDB text as []byte), so values are compared by their text.
+func bulkKeyString(row utils.JSON, keyFieldNames []string) string {
+	parts := make([]string, len(keyFieldNames))
+	for i, k := range keyFieldNames {
+		if v, ok := row[k].([]byte); ok {
+			parts[i] = string(v)
+			continue
+		}
+		parts[i] = fmt.Sprint(row[k])
+	}
+	return strings.Join(parts, "\x00")
+}
@@ -0,0 +1,65 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/donnyhardyanto/dxlib/base"
+	"github.com/donnyhardyanto/dxlib/utils"
+)
+
+func TestSQLiteBulkInsertAndUpsert(t *testing.T) {
+	ctx := context.Background()
+	db := openSQLiteTestDB(t)
+	dbType := base.DXDatabaseTypeSQLite
+	if _, err := db.Exec(SQLiteSetSessionKeySQL, "app.key", "passphrase"); err != nil {
+		t.Fatal(err)
+	}
+
+	// More rows than fit in one statement, so the insert is chunked
+	n := bulkRowsPerStatement(dbType, 2) + 5
+	rows := make([]utils.JSON, n)
+	for i := range rows {
+		rows[i] = utils.JSON{"code": fmt.Sprintf("C%05d", i), "name": "n"}
+	}
+	encrypt := BulkColumnExpressions{"secret": func(ph string) string {
+		return fmt.Sprintf("%s(%s, %s)", SQLiteFunctionEncrypt, ph, SessionKeyExpression(dbType, "app.key"))
+	}}
+
+	tx := db.MustBegin()
+	affected, returning, err := TxBulkInsert(ctx, tx, "app.items", rows, nil, []string{"id", "code"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if affected != int64(n) || len(returning) != n || returning[n-1]["code"] != rows[n-1]["code"] {
+		t.Fatalf("affected=%d returning=%d last=%v", affected, len(returning), returning[n-1])
+	}
+
+	_, returning, err = TxBulkUpsert(ctx, tx, "app.items", []utils.JSON{
+		{"code": "C00001", "name": "renamed", "secret": "s1"},
+		{"code": "NEW", "name": "new", "secret": "s2"},
+	}, encrypt, []string{"code"}, nil, []string{"id"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// SQLite burns an AUTOINCREMENT id on the conflicting row, so only the order is checked
+	if fmt.Sprint(returning[0]["id"]) != "2" || returning[1]["id"].(int64) <= int64(n) {
+		t.Fatalf("upsert returning = %v", returning)
+	}
+	if err = tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	var name, plain string
+	if err = db.QueryRow(`SELECT name, `+DecryptExpression(dbType, "secret", "app.key")+` FROM "app.items" WHERE code = 'C00001'`).Scan(&name, &plain); err != nil {
+		t.Fatal(err)
+	}
+	if name != "renamed" || plain != "s1" {
+		t.Fatalf("name=%q secret=%q", name, plain)
+	}
+
+	if _, _, err = TxBulkInsert(ctx, db.MustBegin(), "app.items", []utils.JSON{{"code": "X"}, {"name": "Y"}}, nil, nil); err == nil {
+		t.Fatal("rows with different fields must be rejected")
+	}
+}
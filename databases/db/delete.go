@@ -106,8 +106,8 @@ func Delete(ctx context.Context, db *sqlx.DB, tableName string, whereAndFieldNam
 
 	// Handle databases-specific DELETE with RETURNING
 	switch driverName {
-	case "postgres", "mariadb":
-		// PostgreSQL and MariaDB support RETURNING clause
+	case "postgres", "mariadb", "sqlite":
+		// PostgreSQL, MariaDB and SQLite (3.35+) support RETURNING clause
 		baseSQL := strings.Join([]string{
 			"DELETE FROM",
 			tableName,
@@ -281,8 +281,8 @@ func TxDelete(ctx context.Context, tx *sqlx.Tx, tableName string, whereAndFieldN
 
 	// Handle databases-specific DELETE with RETURNING
 	switch driverName {
-	case "postgres", "mariadb":
-		// PostgreSQL and MariaDB support RETURNING clause
+	case "postgres", "mariadb", "sqlite":
+		// PostgreSQL, MariaDB and SQLite (3.35+) support RETURNING clause
 		baseSQL := strings.Join([]string{
 			"DELETE FROM",
 			tableName,
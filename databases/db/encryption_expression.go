@@ -18,6 +18,8 @@ func SessionKeyExpression(dbType base.DXDatabaseType, sessionKey string) string
 		return fmt.Sprintf("SYS_CONTEXT('CLIENTCONTEXT', '%s')", sessionKey)
 	case base.DXDatabaseTypeMariaDB:
 		return fmt.Sprintf("@%s", strings.ReplaceAll(sessionKey, ".", "_"))
+	case base.DXDatabaseTypeSQLite:
+		return fmt.Sprintf("(SELECT value FROM temp.%s WHERE key = '%s')", SQLiteSessionContextTable, sessionKey)
 	default:
 		return fmt.Sprintf("'%s'", sessionKey)
 	}
@@ -36,6 +38,8 @@ func DecryptExpression(dbType base.DXDatabaseType, fieldName string, sessionKey
 		return fmt.Sprintf("UTL_RAW.CAST_TO_VARCHAR2(%s)", fieldName)
 	case base.DXDatabaseTypeMariaDB:
 		return fmt.Sprintf("AES_DECRYPT(%s, %s)", fieldName, keyExpr)
+	case base.DXDatabaseTypeSQLite:
+		return fmt.Sprintf("%s(%s, %s)", SQLiteFunctionDecrypt, fieldName, keyExpr)
 	default:
 		return fieldName
 	}
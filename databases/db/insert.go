@@ -124,8 +124,8 @@ func Insert(ctx context.Context, db *sqlx.DB, tableName string, setFieldValues u
 
 	// Handle databases-specific RETURNING clauses
 	switch driverName {
-	case "postgres", "mariadb":
-		// PostgreSQL and MariaDB support RETURNING clause with the same syntax
+	case "postgres", "mariadb", "sqlite":
+		// PostgreSQL, MariaDB and SQLite (3.35+) support RETURNING clause with the same syntax
 		sqlStatement := fmt.Sprintf("%s RETURNING %s", baseSQL, strings.Join(returningFieldNames, ", "))
 		_, rows, err := QueryRows(ctx, db, nil, sqlStatement, convertedFieldValues)
 		if err != nil {
@@ -166,62 +166,9 @@ func Insert(ctx context.Context, db *sqlx.DB, tableName string, setFieldValues u
 
 	case "oracle":
 		// Oracle uses RETURNING INTO syntax
-		// Build RETURNING INTO clause
-		var returningFields []string
-		var returningIntoFields []string
-		outParams := make([]interface{}, 0, len(returningFieldNames))
-		outDests := make([]*string, len(returningFieldNames))
-
-		for i, key := range returningFieldNames {
-			// RETURNING column quoted-uppercase (reserved-word-safe, e.g. "UID");
-			// the :name_out bind keeps the original key (out-bind names are never
-			// bare column names, so no reserved-word risk).
-			returningFields = append(returningFields, DbDriverFormatIdentifier(driverName, key))
-			returningIntoFields = append(returningIntoFields, fmt.Sprintf(":%s_out", key))
-
-			// Out binds are SIZED STRINGS (go_ora.Out): an untyped sql.Out dest is
-			// ORA-03146 (go-ora cannot size the TTC buffer), and the column's Go type
-			// is unknown here — Oracle implicitly converts any RETURNING value to the
-			// VARCHAR2 bind. Integer-looking values are coerced back below.
-			outDests[i] = new(string)
-			outParams = append(outParams, sql.Named(key+"_out", go_ora.Out{Dest: outDests[i], Size: 4000}))
-		}
-
-		sqlStatement := fmt.Sprintf("%s RETURNING %s INTO %s",
-			baseSQL,
-			strings.Join(returningFields, ", "),
-			strings.Join(returningIntoFields, ", "))
-
-		// Rewrite the VALUES binds to reserved-word-safe ":p_<name>" (ORA-01745)
-		// with matching sql.Named args; :<key>_out binds are untouched (word
-		// boundary). SQLExpression entries carry no bind, and OracleSafeBindNames
-		// leaves an arg without a matching placeholder unrewritten — so exclude them.
-		bindValues := utils.JSON{}
-		for name, value := range convertedFieldValues {
-			if _, ok := value.(SQLExpression); !ok {
-				bindValues[name] = value
-			}
-		}
-		modifiedSQL, namedArgs := OracleSafeBindNames(sqlStatement, bindValues)
-		namedArgs = append(namedArgs, outParams...)
-
-		// Execute directly for Oracle with output parameters
-		result, err = db.ExecContext(ctx, modifiedSQL, namedArgs...)
+		result, returningFieldValues, err = oracleInsertReturningInto(ctx, db, baseSQL, convertedFieldValues, returningFieldNames)
 		if err != nil {
-			return nil, nil, errors.Wrap(err, "error executing oracle insert with RETURNING INTO")
-		}
-
-		// Extract output parameters. The out binds are strings (see above); a
-		// CANONICAL integer (round-trips through ParseInt/FormatInt — so "0100"
-		// or "+1" stay strings) comes back as int64 so numeric consumers
-		// (utils/json.GetInt64) keep working. A NULL value arrives as "".
-		for i, key := range returningFieldNames {
-			s := *outDests[i]
-			if n, convErr := strconv.ParseInt(s, 10, 64); convErr == nil && strconv.FormatInt(n, 10) == s {
-				returningFieldValues[key] = n
-			} else {
-				returningFieldValues[key] = s
-			}
+			return nil, nil, err
 		}
 
 	default:
@@ -303,8 +250,8 @@ func TxInsert(ctx context.Context, tx *sqlx.Tx, tableName string, setFieldValues
 
 	// Handle databases-specific RETURNING clauses
 	switch driverName {
-	case "postgres", "mariadb":
-		// PostgreSQL and MariaDB support RETURNING clause with the same syntax
+	case "postgres", "mariadb", "sqlite":
+		// PostgreSQL, MariaDB and SQLite (3.35+) support RETURNING clause with the same syntax
 		sqlStatement := fmt.Sprintf("%s RETURNING %s", baseSQL, strings.Join(returningFieldNames, ", "))
 		_, rows, err := TxQueryRows(ctx, tx, nil, sqlStatement, convertedFieldValues)
 		if err != nil {
@@ -345,62 +292,9 @@ func TxInsert(ctx context.Context, tx *sqlx.Tx, tableName string, setFieldValues
 
 	case "oracle":
 		// Oracle uses RETURNING INTO syntax
-		// Build RETURNING INTO clause
-		var returningFields []string
-		var returningIntoFields []string
-		outParams := make([]interface{}, 0, len(returningFieldNames))
-		outDests := make([]*string, len(returningFieldNames))
-
-		for i, key := range returningFieldNames {
-			// RETURNING column quoted-uppercase (reserved-word-safe, e.g. "UID");
-			// the :name_out bind keeps the original key (out-bind names are never
-			// bare column names, so no reserved-word risk).
-			returningFields = append(returningFields, DbDriverFormatIdentifier(driverName, key))
-			returningIntoFields = append(returningIntoFields, fmt.Sprintf(":%s_out", key))
-
-			// Out binds are SIZED STRINGS (go_ora.Out): an untyped sql.Out dest is
-			// ORA-03146 (go-ora cannot size the TTC buffer), and the column's Go type
-			// is unknown here — Oracle implicitly converts any RETURNING value to the
-			// VARCHAR2 bind. Integer-looking values are coerced back below.
-			outDests[i] = new(string)
-			outParams = append(outParams, sql.Named(key+"_out", go_ora.Out{Dest: outDests[i], Size: 4000}))
-		}
-
-		sqlStatement := fmt.Sprintf("%s RETURNING %s INTO %s",
-			baseSQL,
-			strings.Join(returningFields, ", "),
-			strings.Join(returningIntoFields, ", "))
-
-		// Rewrite the VALUES binds to reserved-word-safe ":p_<name>" (ORA-01745)
-		// with matching sql.Named args; :<key>_out binds are untouched (word
-		// boundary). SQLExpression entries carry no bind, and OracleSafeBindNames
-		// leaves an arg without a matching placeholder unrewritten — so exclude them.
-		bindValues := utils.JSON{}
-		for name, value := range convertedFieldValues {
-			if _, ok := value.(SQLExpression); !ok {
-				bindValues[name] = value
-			}
-		}
-		modifiedSQL, namedArgs := OracleSafeBindNames(sqlStatement, bindValues)
-		namedArgs = append(namedArgs, outParams...)
-
-		// Execute directly for Oracle with output parameters
-		_, err = tx.ExecContext(ctx, modifiedSQL, namedArgs...)
+		result, returningFieldValues, err = oracleInsertReturningInto(ctx, tx, baseSQL, convertedFieldValues, returningFieldNames)
 		if err != nil {
-			return nil, nil, errors.Wrap(err, "error executing oracle insert with RETURNING INTO")
-		}
-
-		// Extract output parameters. The out binds are strings (see above); a
-		// CANONICAL integer (round-trips through ParseInt/FormatInt — so "0100"
-		// or "+1" stay strings) comes back as int64 so numeric consumers
-		// (utils/json.GetInt64) keep working. A NULL value arrives as "".
-		for i, key := range returningFieldNames {
-			s := *outDests[i]
-			if n, convErr := strconv.ParseInt(s, 10, 64); convErr == nil && strconv.FormatInt(n, 10) == s {
-				returningFieldValues[key] = n
-			} else {
-				returningFieldValues[key] = s
-			}
+			return nil, nil, err
 		}
 
 	default:
@@ -410,3 +304,66 @@ func TxInsert(ctx context.Context, tx *sqlx.Tx, tableName string, setFieldValues
 
 	return result, returningFieldValues, nil
 }
+
+// oracleInsertReturningInto executes a single-row Oracle INSERT with RETURNING INTO out binds.
+func oracleInsertReturningInto(ctx context.Context, execer sqlx.ExecerContext, baseSQL string, fieldValues utils.JSON, returningFieldNames []string) (result sql.Result, returningFieldValues utils.JSON, err error) {
+	// Build RETURNING INTO clause
+	var returningFields []string
+	var returningIntoFields []string
+	outParams := make([]interface{}, 0, len(returningFieldNames))
+	outDests := make([]*string, len(returningFieldNames))
+
+	for i, key := range returningFieldNames {
+		// RETURNING column quoted-uppercase (reserved-word-safe, e.g. "UID");
+		// the :name_out bind keeps the original key (out-bind names are never
+		// bare column names, so no reserved-word risk).
+		returningFields = append(returningFields, DbDriverFormatIdentifier("oracle", key))
+		returningIntoFields = append(returningIntoFields, fmt.Sprintf(":%s_out", key))
+
+		// Out binds are SIZED STRINGS (go_ora.Out): an untyped sql.Out dest is
+		// ORA-03146 (go-ora cannot size the TTC buffer), and the column's Go type
+		// is unknown here — Oracle implicitly converts any RETURNING value to the
+		// VARCHAR2 bind. Integer-looking values are coerced back below.
+		outDests[i] = new(string)
+		outParams = append(outParams, sql.Named(key+"_out", go_ora.Out{Dest: outDests[i], Size: 4000}))
+	}
+
+	sqlStatement := fmt.Sprintf("%s RETURNING %s INTO %s",
+		baseSQL,
+		strings.Join(returningFields, ", "),
+		strings.Join(returningIntoFields, ", "))
+
+	// Rewrite the VALUES binds to reserved-word-safe ":p_<name>" (ORA-01745)
+	// with matching sql.Named args; :<key>_out binds are untouched (word
+	// boundary). SQLExpression entries carry no bind, and OracleSafeBindNames
+	// leaves an arg without a matching placeholder unrewritten — so exclude them.
+	bindValues := utils.JSON{}
+	for name, value := range fieldValues {
+		if _, ok := value.(SQLExpression); !ok {
+			bindValues[name] = value
+		}
+	}
+	modifiedSQL, namedArgs := OracleSafeBindNames(sqlStatement, bindValues)
+	namedArgs = append(namedArgs, outParams...)
+
+	// Execute directly for Oracle with output parameters
+	result, err = execer.ExecContext(ctx, modifiedSQL, namedArgs...)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "error executing oracle insert with RETURNING INTO")
+	}
+
+	// Extract output parameters. The out binds are strings (see above); a
+	// CANONICAL integer (round-trips through ParseInt/FormatInt — so "0100"
+	// or "+1" stay strings) comes back as int64 so numeric consumers
+	// (utils/json.GetInt64) keep working. A NULL value arrives as "".
+	returningFieldValues = utils.JSON{}
+	for i, key := range returningFieldNames {
+		s := *outDests[i]
+		if n, convErr := strconv.ParseInt(s, 10, 64); convErr == nil && strconv.FormatInt(n, 10) == s {
+			returningFieldValues[key] = n
+		} else {
+			returningFieldValues[key] = s
+		}
+	}
+	return result, returningFieldValues, nil
+}
@@ -1,6 +1,7 @@
 package db
 
 import (
+	"database/sql"
 	"fmt"
 	"strings"
 
@@ -44,7 +45,8 @@ func isSensitiveField(fieldName string) bool {
 	return false
 }
 
-// maskSensitiveArgs creates a copy of arguments with sensitive values masked
+// maskSensitiveArgs creates a copy of arguments with sensitive values masked. Only named
+// arguments (sql.NamedArg, e.g. Oracle binds) carry a name to judge by.
 func maskSensitiveArgs(args []any) []any {
 	if len(args) == 0 {
 		return nil
@@ -52,6 +54,11 @@ func maskSensitiveArgs(args []any) []any {
 
 	masked := make([]any, len(args))
 	copy(masked, args)
+	for i, arg := range masked {
+		if namedArg, ok := arg.(sql.NamedArg); ok && isSensitiveField(namedArg.Name) {
+			masked[i] = sql.Named(namedArg.Name, maskedValue)
+		}
+	}
 	return masked
 }
 
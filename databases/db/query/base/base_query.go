@@ -13,7 +13,7 @@ import (
 // It supports both named parameters (map/struct) and positional parameters (slice)
 // If fieldTypeMapping is provided, applies type conversion to the results
 func BaseQueryRows2(ctx context.Context, db *sqlx.DB, query string, arg any, fieldTypeMapping databaseDb.DXDatabaseTableFieldTypeMapping) (rowsInfo *databaseDb.DXDatabaseTableRowsInfo, r []utils.JSON, err error) {
-	ctx, endOtel := databaseDb.DbOtelStart(ctx, "db.SELECT", query, 3)
+	ctx, endOtel := databaseDb.DbStatementStart(ctx, db, "db.SELECT", query, arg, 3)
 	defer func() { endOtel(err, int64(len(r))) }()
 
 	r = []utils.JSON{}
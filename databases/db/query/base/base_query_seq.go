@@ -0,0 +1,72 @@
+package base
+
+import (
+	"context"
+
+	"github.com/donnyhardyanto/dxlib/databases"
+	databaseDb "github.com/donnyhardyanto/dxlib/databases/db"
+	"github.com/donnyhardyanto/dxlib/errors"
+	"github.com/donnyhardyanto/dxlib/utils"
+	"github.com/jmoiron/sqlx"
+)
+
+// BaseQueryRowsSeq2 is BaseQueryRows2 yielding the rows as they are read (see
+// databaseDb.RowsSeq). The query runs before it returns; the sequence must be ranged
+// over to release the connection.
+func BaseQueryRowsSeq2(ctx context.Context, db *sqlx.DB, query string, arg any, fieldTypeMapping databaseDb.DXDatabaseTableFieldTypeMapping) (rowsInfo *databaseDb.DXDatabaseTableRowsInfo, r databaseDb.DXDatabaseRowsSeq, err error) {
+	ctx, endOtel := databaseDb.DbStatementStart(ctx, db, "db.SELECT", query, arg, 3)
+	if arg == nil {
+		arg = utils.JSON{}
+	}
+
+	var rows *sqlx.Rows
+	switch v := arg.(type) {
+	case []any:
+		rows, err = db.QueryxContext(ctx, query, v...)
+	default:
+		rows, err = sqlx.NamedQueryContext(ctx, db, query, arg)
+	}
+	if err != nil {
+		endOtel(err, -1)
+		return nil, nil, errors.Wrapf(err, "NAMED_QUERY_ROWS_ERROR:QUERY=%s", query)
+	}
+
+	rowsInfo = &databaseDb.DXDatabaseTableRowsInfo{}
+	rowsInfo.Columns, err = rows.Columns()
+	if err != nil {
+		_ = rows.Close()
+		endOtel(err, -1)
+		return nil, nil, errors.Wrapf(err, "NAMED_QUERY_ROWS_COLUMNS_ERROR:QUERY=%s", query)
+	}
+	return rowsInfo, databaseDb.RowsSeq(rows, db.DriverName(), fieldTypeMapping, endOtel), nil
+}
+
+// TxBaseQueryRowsSeq2 is TxBaseQueryRows2 yielding the rows as they are read. The
+// sequence must be consumed before the transaction ends.
+func TxBaseQueryRowsSeq2(ctx context.Context, dtx *databases.DXDatabaseTx, query string, arg any, fieldTypeMapping databaseDb.DXDatabaseTableFieldTypeMapping) (rowsInfo *databaseDb.DXDatabaseTableRowsInfo, r databaseDb.DXDatabaseRowsSeq, err error) {
+	ctx, endOtel := databaseDb.DbStatementStart(ctx, dtx.Tx, "db.TX_SELECT", query, arg, 3)
+	if arg == nil {
+		arg = utils.JSON{}
+	}
+
+	var rows *sqlx.Rows
+	switch v := arg.(type) {
+	case []any:
+		rows, err = dtx.Tx.QueryxContext(ctx, query, v...)
+	default:
+		rows, err = sqlx.NamedQueryContext(ctx, dtx.Tx, query, arg)
+	}
+	if err != nil {
+		endOtel(err, -1)
+		return nil, nil, errors.Wrapf(err, "TX_QUERY_ROWS_ERROR:QUERY=%s", query)
+	}
+
+	rowsInfo = &databaseDb.DXDatabaseTableRowsInfo{}
+	rowsInfo.Columns, err = rows.Columns()
+	if err != nil {
+		_ = rows.Close()
+		endOtel(err, -1)
+		return nil, nil, errors.Wrapf(err, "TX_QUERY_ROWS_COLUMNS_ERROR:QUERY=%s", query)
+	}
+	return rowsInfo, databaseDb.RowsSeq(rows, dtx.Tx.DriverName(), fieldTypeMapping, endOtel), nil
+}
@@ -14,7 +14,7 @@ import (
 // It supports both named parameters (map/struct) and positional parameters (slice)
 // If fieldTypeMapping is provided, applies type conversion to the results
 func TxBaseQueryRows2(ctx context.Context, dtx *databases.DXDatabaseTx, query string, arg any, fieldTypeMapping databaseDb.DXDatabaseTableFieldTypeMapping) (rowsInfo *databaseDb.DXDatabaseTableRowsInfo, r []utils.JSON, err error) {
-	ctx, endOtel := databaseDb.DbOtelStart(ctx, "db.TX_SELECT", query, 3)
+	ctx, endOtel := databaseDb.DbStatementStart(ctx, dtx.Tx, "db.TX_SELECT", query, arg, 3)
 	defer func() { endOtel(err, int64(len(r))) }()
 
 	r = []utils.JSON{}
@@ -0,0 +1,254 @@
+package builder
+
+import (
+	"strings"
+
+	"github.com/donnyhardyanto/dxlib/base"
+	utils2 "github.com/donnyhardyanto/dxlib/databases/db/query/utils"
+	"github.com/donnyhardyanto/dxlib/errors"
+)
+
+// === Full-Text Search ===
+
+// FullTextSearchMode selects how the search value is interpreted
+type FullTextSearchMode string
+
+const (
+	// FullTextSearchModeNatural takes plain user input: PostgreSQL websearch_to_tsquery, MariaDB
+	// NATURAL LANGUAGE MODE, SQL Server FREETEXT, Oracle Text ACCUM of the escaped words
+	FullTextSearchModeNatural FullTextSearchMode = "natural"
+	// FullTextSearchModeBoolean passes the engine's query syntax through: PostgreSQL to_tsquery,
+	// MariaDB BOOLEAN MODE, SQL Server CONTAINS, Oracle Text CONTAINS. Not for raw user input.
+	FullTextSearchModeBoolean FullTextSearchMode = "boolean"
+)
+
+// DefaultFullTextSearchConfig is the PostgreSQL text search configuration when none is set
+const DefaultFullTextSearchConfig = "simple"
+
+// FullTextSearchDef describes the full-text index a search runs against. It must match the index
+// (models.NewModelDBFullTextIndexForTable) or the engine cannot use it: MariaDB requires a
+// FULLTEXT index on exactly FieldNames, and on Oracle the CONTEXT index is on the first field.
+// SQLite has no full-text index on regular tables and falls back to LIKE without ranking.
+type FullTextSearchDef struct {
+	FieldNames      []string           // searched columns
+	VectorFieldName string             // PostgreSQL: tsvector column; empty = to_tsvector over FieldNames
+	Config          string             // PostgreSQL text search configuration, default DefaultFullTextSearchConfig
+	Mode            FullTextSearchMode // default FullTextSearchModeNatural
+	TableName       string             // SQL Server: full-text indexed table, needed for ranking
+	KeyFieldName    string             // SQL Server: key column of the full-text index, needed for ranking
+}
+
+// Validate checks the identifiers of def
+func (def FullTextSearchDef) Validate() error {
+	if len(def.FieldNames) == 0 {
+		return errors.New("FULL_TEXT_SEARCH_FIELD_NAMES_EMPTY")
+	}
+	for _, f := range append(append([]string{}, def.FieldNames...), def.VectorFieldName, def.TableName, def.KeyFieldName) {
+		if f != "" && !utils2.IsValidIdentifier(f) {
+			return errors.Errorf("INVALID_FULL_TEXT_SEARCH_FIELD:%s", f)
+		}
+	}
+	if def.Config != "" && !utils2.IsValidIdentifier(def.Config) {
+		return errors.Errorf("INVALID_FULL_TEXT_SEARCH_CONFIG:%s", def.Config)
+	}
+	if def.Mode != "" && def.Mode != FullTextSearchModeNatural && def.Mode != FullTextSearchModeBoolean {
+		return errors.Errorf("INVALID_FULL_TEXT_SEARCH_MODE:%s", def.Mode)
+	}
+	return nil
+}
+
+func (def FullTextSearchDef) isBoolean() bool {
+	return def.Mode == FullTextSearchModeBoolean
+}
+
+// FullTextVectorExpression is the PostgreSQL tsvector of fields under config. The expression index and
+// generated column of models.ModelDBIndex full-text indexes are built the same way.
+func FullTextVectorExpression(config string, quotedFields []string) string {
+	if config == "" {
+		config = DefaultFullTextSearchConfig
+	}
+	parts := make([]string, len(quotedFields))
+	for i, f := range quotedFields {
+		parts[i] = "coalesce(" + f + ", '')"
+	}
+	return "to_tsvector('" + config + "', " + strings.Join(parts, " || ' ' || ") + ")"
+}
+
+// oracleTextAccumQuery turns user input into an Oracle Text query: every word escaped with {}
+// and combined with ACCUM, so rows matching more words score higher.
+func oracleTextAccumQuery(value string) string {
+	var terms []string
+	for _, w := range strings.Fields(value) {
+		w = strings.NewReplacer("{", "", "}", "").Replace(w)
+		if w != "" {
+			terms = append(terms, "{"+w+"}")
+		}
+	}
+	return strings.Join(terms, " ACCUM ")
+}
+
+func (def FullTextSearchDef) quotedFields(dbType base.DXDatabaseType) []string {
+	fields := make([]string, len(def.FieldNames))
+	for i, f := range def.FieldNames {
+		fields[i] = utils2.QuoteIdentifierByDbType(dbType, f)
+	}
+	return fields
+}
+
+func (def FullTextSearchDef) postgresQuery(bind func(any) string, value string) string {
+	config := def.Config
+	if config == "" {
+		config = DefaultFullTextSearchConfig
+	}
+	function := "websearch_to_tsquery"
+	if def.isBoolean() {
+		function = "to_tsquery"
+	}
+	return function + "('" + config + "', " + bind(value) + ")"
+}
+
+func (def FullTextSearchDef) postgresVector() string {
+	if def.VectorFieldName != "" {
+		return utils2.QuoteIdentifierByDbType(base.DXDatabaseTypePostgreSQL, def.VectorFieldName)
+	}
+	return FullTextVectorExpression(def.Config, def.quotedFields(base.DXDatabaseTypePostgreSQL))
+}
+
+func (def FullTextSearchDef) mariaDBMatch(bind func(any) string, value string) string {
+	mode := "IN NATURAL LANGUAGE MODE"
+	if def.isBoolean() {
+		mode = "IN BOOLEAN MODE"
+	}
+	return "MATCH (" + strings.Join(def.quotedFields(base.DXDatabaseTypeMariaDB), ", ") + ") AGAINST (" + bind(value) + " " + mode + ")"
+}
+
+func (def FullTextSearchDef) oracleValue(value string) string {
+	if def.isBoolean() {
+		return value
+	}
+	return oracleTextAccumQuery(value)
+}
+
+// FullTextSearchCondition returns the predicate matching value, binding values through bind (which
+// returns the placeholder). It returns "" when value has nothing to search for.
+func FullTextSearchCondition(dbType base.DXDatabaseType, def FullTextSearchDef, value string, bind func(any) string) (string, error) {
+	if err := def.Validate(); err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(value) == "" {
+		return "", nil
+	}
+	switch dbType {
+	case base.DXDatabaseTypePostgreSQL, base.DXDatabaseTypePostgresSQLV2:
+		return def.postgresVector() + " @@ " + def.postgresQuery(bind, value), nil
+	case base.DXDatabaseTypeMariaDB:
+		return def.mariaDBMatch(bind, value), nil
+	case base.DXDatabaseTypeSQLServer:
+		function := "FREETEXT"
+		if def.isBoolean() {
+			function = "CONTAINS"
+		}
+		return function + "((" + strings.Join(def.quotedFields(dbType), ", ") + "), " + bind(value) + ")", nil
+	case base.DXDatabaseTypeOracle:
+		query := def.oracleValue(value)
+		if query == "" {
+			return "", nil
+		}
+		return "CONTAINS(" + def.quotedFields(dbType)[0] + ", " + bind(query) + ", 1) > 0", nil
+	default:
+		// No full-text index on regular SQLite tables: substring match without ranking
+		escaped := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+		placeholder := bind("%" + escaped + "%")
+		var parts []string
+		for _, f := range def.quotedFields(dbType) {
+			parts = append(parts, "LOWER("+f+") LIKE LOWER("+placeholder+`) ESCAPE '\'`)
+		}
+		return "(" + strings.Join(parts, " OR ") + ")", nil
+	}
+}
+
+// FullTextSearchRank returns the relevance of a row for value (higher is better), or "" when the
+// dialect cannot rank it. On Oracle the rank is only valid together with FullTextSearchCondition in
+// the same query, and on SQL Server it needs def.TableName and def.KeyFieldName.
+func FullTextSearchRank(dbType base.DXDatabaseType, def FullTextSearchDef, value string, bind func(any) string) (string, error) {
+	if err := def.Validate(); err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(value) == "" {
+		return "", nil
+	}
+	switch dbType {
+	case base.DXDatabaseTypePostgreSQL, base.DXDatabaseTypePostgresSQLV2:
+		return "ts_rank(" + def.postgresVector() + ", " + def.postgresQuery(bind, value) + ")", nil
+	case base.DXDatabaseTypeMariaDB:
+		return def.mariaDBMatch(bind, value), nil
+	case base.DXDatabaseTypeSQLServer:
+		if def.TableName == "" || def.KeyFieldName == "" {
+			return "", nil
+		}
+		function := "FREETEXTTABLE"
+		if def.isBoolean() {
+			function = "CONTAINSTABLE"
+		}
+		return "(SELECT ft.[RANK] FROM " + function + "(" + utils2.QuoteFieldWithPrefixByDbType(dbType, def.TableName) + ", (" +
+			strings.Join(def.quotedFields(dbType), ", ") + "), " + bind(value) + ") ft WHERE ft.[KEY] = " +
+			utils2.QuoteIdentifierByDbType(dbType, def.KeyFieldName) + ")", nil
+	case base.DXDatabaseTypeOracle:
+		if def.oracleValue(value) == "" {
+			return "", nil
+		}
+		return "SCORE(1)", nil
+	default:
+		return "", nil
+	}
+}
+
+// SearchFullText adds the full-text predicate of def for value (see FullTextSearchCondition)
+func (qb *SelectQueryBuilder) SearchFullText(value string, def FullTextSearchDef) *SelectQueryBuilder {
+	if qb.Error != nil {
+		return qb
+	}
+	condition, err := FullTextSearchCondition(qb.DbType, def, value, qb.bindFullTextParam)
+	if err != nil {
+		qb.Error = err
+		return qb
+	}
+	return qb.And(condition)
+}
+
+// OrderByFullTextRank orders by relevance for value, most relevant first. It adds nothing when the
+// dialect cannot rank (see FullTextSearchRank).
+func (qb *SelectQueryBuilder) OrderByFullTextRank(value string, def FullTextSearchDef) *SelectQueryBuilder {
+	if qb.Error != nil {
+		return qb
+	}
+	rank, err := FullTextSearchRank(qb.DbType, def, value, qb.bindFullTextParam)
+	if err != nil {
+		qb.Error = err
+		return qb
+	}
+	if rank != "" {
+		qb.RawOrderBys = append(qb.RawOrderBys, rank+" DESC")
+	}
+	return qb
+}
+
+func (qb *SelectQueryBuilder) bindFullTextParam(value any) string {
+	name := qb.GenerateParamName("fts")
+	qb.Args[name] = value
+	return ":" + name
+}
+
+// SearchFullText adds the full-text predicate of def for value with a named parameter
+// (see FullTextSearchCondition). An invalid def matches no rows; check it with Validate.
+func (cg *ConditionGroup) SearchFullText(dbType base.DXDatabaseType, value string, def FullTextSearchDef) *ConditionGroup {
+	condition, err := FullTextSearchCondition(dbType, def, value, func(v any) string {
+		param := cg.nextParam("fts")
+		cg.Args[param] = v
+		return ":" + param
+	})
+	if err != nil {
+		return cg.And("1=0")
+	}
+	return cg.And(condition)
+}
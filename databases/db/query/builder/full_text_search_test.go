@@ -0,0 +1,31 @@
+package builder
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/donnyhardyanto/dxlib/base"
+)
+
+func TestFullTextSearchPostgreSQL(t *testing.T) {
+	def := FullTextSearchDef{FieldNames: []string{"title", "body"}}
+	for _, dbType := range []base.DXDatabaseType{base.DXDatabaseTypePostgreSQL, base.DXDatabaseTypePostgresSQLV2} {
+		var args []any
+		bind := func(v any) string {
+			args = append(args, v)
+			return fmt.Sprintf(":p%d", len(args))
+		}
+		condition, err := FullTextSearchCondition(dbType, def, "red shoes", bind)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := `to_tsvector('simple', coalesce("title", '') || ' ' || coalesce("body", '')) @@ websearch_to_tsquery('simple', :p1)`
+		if condition != want {
+			t.Errorf("%s condition:\n%s\nwant:\n%s", dbType.String(), condition, want)
+		}
+		rank, err := FullTextSearchRank(dbType, def, "red shoes", bind)
+		if err != nil || rank == "" || rank[:8] != "ts_rank(" {
+			t.Errorf("%s rank = %q, err = %v", dbType.String(), rank, err)
+		}
+	}
+}
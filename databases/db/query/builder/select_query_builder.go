@@ -100,7 +100,9 @@ type SelectQueryBuilder struct {
 	LimitValue       int64    // LIMIT clause value (0 = no limit)
 	OffsetValue      int64    // OFFSET clause value (0 = no offset)
 	ForUpdatePart    any      // FOR UPDATE clause (string like "FOR UPDATE", or bool true)
+	SetOperations    []SetOperationDef // UNION / INTERSECT / EXCEPT members, see Union
 	havingArgCount   int      // internal counter for unique HAVING param names
+	subqueryCount    int      // internal counter for unique nested builder param prefixes
 }
 
 // NewSelectQueryBuilder creates a new SelectQueryBuilder
@@ -0,0 +1,367 @@
+package builder
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/donnyhardyanto/dxlib/base"
+	utils2 "github.com/donnyhardyanto/dxlib/databases/db/query/utils"
+	"github.com/donnyhardyanto/dxlib/errors"
+	"github.com/donnyhardyanto/dxlib/utils"
+)
+
+// === Subqueries ===
+// A nested builder is rendered for the outer builder's DbType when it is added, so later changes to it
+// have no effect. Its named parameters are renamed with a prefix unique within the outer builder
+// (sq0_, sq1_, ...) and merged into the outer Args, so both may use the same parameter names.
+
+// SubquerySQL renders sub as a parenthesized subquery and merges its args, for use in conditions or
+// select fields the other methods do not cover
+func (qb *SelectQueryBuilder) SubquerySQL(sub *SelectQueryBuilder) (string, error) {
+	if qb.Error != nil {
+		return "", qb.Error
+	}
+	selectSQL, err := qb.mergeNestedBuilder(sub)
+	if err != nil {
+		return "", err
+	}
+	return "(" + selectSQL + ")", nil
+}
+
+// Exists adds EXISTS (sub). Correlate sub with the outer query through EqField.
+func (qb *SelectQueryBuilder) Exists(sub *SelectQueryBuilder) *SelectQueryBuilder {
+	return qb.andSubquery("EXISTS ", sub)
+}
+
+// NotExists adds NOT EXISTS (sub)
+func (qb *SelectQueryBuilder) NotExists(sub *SelectQueryBuilder) *SelectQueryBuilder {
+	return qb.andSubquery("NOT EXISTS ", sub)
+}
+
+// InSubquery adds field IN (sub); sub must select exactly one column
+func (qb *SelectQueryBuilder) InSubquery(fieldName string, sub *SelectQueryBuilder) *SelectQueryBuilder {
+	if !utils2.IsValidIdentifier(fieldName) {
+		qb.Error = errors.Errorf("INVALID_IN_SUBQUERY_FIELD:%s", fieldName)
+		return qb
+	}
+	return qb.andSubquery(qb.QuoteFieldWithPrefix(fieldName)+" IN ", sub)
+}
+
+// NotInSubquery adds field NOT IN (sub). A NULL in the subquery result makes it match no rows, so
+// filter NULLs out in sub or prefer NotExists.
+func (qb *SelectQueryBuilder) NotInSubquery(fieldName string, sub *SelectQueryBuilder) *SelectQueryBuilder {
+	if !utils2.IsValidIdentifier(fieldName) {
+		qb.Error = errors.Errorf("INVALID_IN_SUBQUERY_FIELD:%s", fieldName)
+		return qb
+	}
+	return qb.andSubquery(qb.QuoteFieldWithPrefix(fieldName)+" NOT IN ", sub)
+}
+
+// EqField adds fieldName = otherFieldName, comparing two columns (e.g. o.customer_id = c.id in a
+// correlated subquery)
+func (qb *SelectQueryBuilder) EqField(fieldName string, otherFieldName string) *SelectQueryBuilder {
+	if qb.Error != nil {
+		return qb
+	}
+	for _, f := range []string{fieldName, otherFieldName} {
+		if !utils2.IsValidIdentifier(f) {
+			qb.Error = errors.Errorf("INVALID_EQ_FIELD:%s", f)
+			return qb
+		}
+	}
+	return qb.And(qb.QuoteFieldWithPrefix(fieldName) + " = " + qb.QuoteFieldWithPrefix(otherFieldName))
+}
+
+func (qb *SelectQueryBuilder) andSubquery(prefix string, sub *SelectQueryBuilder) *SelectQueryBuilder {
+	if qb.Error != nil {
+		return qb
+	}
+	subquerySQL, err := qb.SubquerySQL(sub)
+	if err != nil {
+		qb.Error = err
+		return qb
+	}
+	return qb.And(prefix + subquerySQL)
+}
+
+// mergeNestedBuilder renders sub for qb.DbType with its params renamed, and adds them to qb.Args
+func (qb *SelectQueryBuilder) mergeNestedBuilder(sub *SelectQueryBuilder) (string, error) {
+	if sub == nil {
+		return "", errors.New("SUBQUERY_BUILDER_IS_NIL")
+	}
+	if sub.ForUpdatePart != nil {
+		return "", errors.New("SUBQUERY_FOR_UPDATE_NOT_ALLOWED")
+	}
+	selectSQL, args, err := sub.BuildSelectSQL(qb.DbType)
+	if err != nil {
+		return "", err
+	}
+	prefix := fmt.Sprintf("sq%d_", qb.subqueryCount)
+	qb.subqueryCount++
+	selectSQL = renameNamedParams(selectSQL, args, prefix)
+	for k, v := range args {
+		qb.Args[prefix+k] = v
+	}
+	return selectSQL, nil
+}
+
+// renameNamedParams prefixes the :name placeholders of the params in args. Quoted strings and
+// identifiers are left alone, as are PostgreSQL :: casts.
+func renameNamedParams(sql string, args utils.JSON, prefix string) string {
+	var sb strings.Builder
+	var quote byte
+	for i := 0; i < len(sql); i++ {
+		c := sql[i]
+		if quote != 0 {
+			if c == quote {
+				quote = 0
+			}
+			sb.WriteByte(c)
+			continue
+		}
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '[':
+			quote = ']'
+		case c == ':' && i+1 < len(sql) && sql[i+1] == ':':
+			sb.WriteString("::")
+			i++
+			continue
+		case c == ':':
+			end := i + 1
+			for end < len(sql) && isParamNameChar(sql[end]) {
+				end++
+			}
+			name := sql[i+1 : end]
+			if _, ok := args[name]; ok && name != "" {
+				sb.WriteString(":" + prefix + name)
+				i = end - 1
+				continue
+			}
+		}
+		sb.WriteByte(c)
+	}
+	return sb.String()
+}
+
+func isParamNameChar(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+// === Set Operations ===
+
+// SetOperationType combines the rows of two SELECTs
+type SetOperationType string
+
+const (
+	SetOperationUnion     SetOperationType = "UNION"
+	SetOperationUnionAll  SetOperationType = "UNION ALL"
+	SetOperationIntersect SetOperationType = "INTERSECT"
+	SetOperationExcept    SetOperationType = "EXCEPT" // MINUS on Oracle
+)
+
+// SetOperationDef is a rendered set operation member
+type SetOperationDef struct {
+	Type      SetOperationType
+	SelectSQL string // member SELECT, params already renamed and merged into the outer Args
+}
+
+func (t SetOperationType) keyword(dbType base.DXDatabaseType) string {
+	if t == SetOperationExcept && dbType == base.DXDatabaseTypeOracle {
+		return "MINUS"
+	}
+	return string(t)
+}
+
+// Union appends UNION other. The members are combined left to right; the ORDER BY, LIMIT and
+// OFFSET of qb apply to the combined rows, so members must have none (SQLite does not allow
+// parenthesized members). Members must select the same number of columns.
+func (qb *SelectQueryBuilder) Union(other *SelectQueryBuilder) *SelectQueryBuilder {
+	return qb.addSetOperation(SetOperationUnion, other)
+}
+
+// UnionAll appends UNION ALL other, keeping duplicate rows (see Union)
+func (qb *SelectQueryBuilder) UnionAll(other *SelectQueryBuilder) *SelectQueryBuilder {
+	return qb.addSetOperation(SetOperationUnionAll, other)
+}
+
+// Intersect appends INTERSECT other (see Union)
+func (qb *SelectQueryBuilder) Intersect(other *SelectQueryBuilder) *SelectQueryBuilder {
+	return qb.addSetOperation(SetOperationIntersect, other)
+}
+
+// Except appends EXCEPT other, MINUS on Oracle (see Union)
+func (qb *SelectQueryBuilder) Except(other *SelectQueryBuilder) *SelectQueryBuilder {
+	return qb.addSetOperation(SetOperationExcept, other)
+}
+
+func (qb *SelectQueryBuilder) addSetOperation(opType SetOperationType, other *SelectQueryBuilder) *SelectQueryBuilder {
+	if qb.Error != nil {
+		return qb
+	}
+	if other == nil {
+		qb.Error = errors.New("SET_OPERATION_BUILDER_IS_NIL")
+		return qb
+	}
+	if len(other.OrderByDefs) > 0 || len(other.RawOrderBys) > 0 || other.LimitValue > 0 || other.OffsetValue > 0 {
+		qb.Error = errors.Errorf("SET_OPERATION_MEMBER_ORDER_BY_OR_PAGING_NOT_ALLOWED:%s", other.SourceName)
+		return qb
+	}
+	if len(other.SetOperations) > 0 {
+		// Precedence differs per dialect (INTERSECT binds tighter except on SQLite): chain on qb instead
+		qb.Error = errors.Errorf("SET_OPERATION_MEMBER_HAS_SET_OPERATIONS:%s", other.SourceName)
+		return qb
+	}
+	selectSQL, err := qb.mergeNestedBuilder(other)
+	if err != nil {
+		qb.Error = err
+		return qb
+	}
+	qb.SetOperations = append(qb.SetOperations, SetOperationDef{Type: opType, SelectSQL: selectSQL})
+	return qb
+}
+
+// === Window Functions ===
+
+// WindowFunctionDef defines a window function select field:
+// Function(Arguments) OVER (PARTITION BY ... ORDER BY ...) AS Alias
+type WindowFunctionDef struct {
+	Function    string       // ROW_NUMBER, RANK, DENSE_RANK, PERCENT_RANK, CUME_DIST, NTILE, LAG, LEAD, FIRST_VALUE, LAST_VALUE, COUNT, SUM, AVG, MIN, MAX
+	Arguments   []string     // field names; "*" for COUNT; an integer for NTILE and the LAG/LEAD offset
+	PartitionBy []string     // optional partition field names
+	OrderBy     []OrderByDef // window order; required by the ranking functions, NTILE, LAG and LEAD
+	Alias       string       // output column name
+}
+
+// windowFunctionArity is the min and max argument count, and whether the window needs ORDER BY
+var windowFunctionArity = map[string]struct {
+	Min, Max     int
+	NeedsOrderBy bool
+}{
+	"ROW_NUMBER":   {0, 0, true},
+	"RANK":         {0, 0, true},
+	"DENSE_RANK":   {0, 0, true},
+	"PERCENT_RANK": {0, 0, true},
+	"CUME_DIST":    {0, 0, true},
+	"NTILE":        {1, 1, true},
+	"LAG":          {1, 2, true},
+	"LEAD":         {1, 2, true},
+	"FIRST_VALUE":  {1, 1, false},
+	"LAST_VALUE":   {1, 1, false},
+	"COUNT":        {1, 1, false},
+	"SUM":          {1, 1, false},
+	"AVG":          {1, 1, false},
+	"MIN":          {1, 1, false},
+	"MAX":          {1, 1, false},
+}
+
+var windowIntegerArgumentPattern = regexp.MustCompile(`^[0-9]+$`)
+
+// SelectWindow adds a window function to the select fields. With no other OutFields the query would
+// select only the window column, so add the rest with Select.
+func (qb *SelectQueryBuilder) SelectWindow(def WindowFunctionDef) *SelectQueryBuilder {
+	if qb.Error != nil {
+		return qb
+	}
+	field, err := WindowFunctionSQL(qb.DbType, def)
+	if err != nil {
+		qb.Error = err
+		return qb
+	}
+	qb.OutFields = append(qb.OutFields, field)
+	return qb
+}
+
+// WindowFunctionSQL renders def for dbType. NULLS FIRST/LAST in the window order is emulated with a
+// CASE on MariaDB and SQL Server, which lack the syntax.
+func WindowFunctionSQL(dbType base.DXDatabaseType, def WindowFunctionDef) (string, error) {
+	function := strings.ToUpper(def.Function)
+	arity, ok := windowFunctionArity[function]
+	if !ok {
+		return "", errors.Errorf("INVALID_WINDOW_FUNCTION:%s", def.Function)
+	}
+	if len(def.Arguments) < arity.Min || len(def.Arguments) > arity.Max {
+		return "", errors.Errorf("INVALID_WINDOW_FUNCTION_ARGUMENT_COUNT:%s:%d", function, len(def.Arguments))
+	}
+	if arity.NeedsOrderBy && len(def.OrderBy) == 0 {
+		return "", errors.Errorf("WINDOW_FUNCTION_ORDER_BY_REQUIRED:%s", function)
+	}
+	if !utils2.IsValidIdentifier(def.Alias) || strings.Contains(def.Alias, ".") {
+		return "", errors.Errorf("INVALID_WINDOW_FUNCTION_ALIAS:%s", def.Alias)
+	}
+
+	arguments := make([]string, len(def.Arguments))
+	for i, a := range def.Arguments {
+		isIntegerPosition := function == "NTILE" || ((function == "LAG" || function == "LEAD") && i == 1)
+		switch {
+		case isIntegerPosition:
+			if !windowIntegerArgumentPattern.MatchString(a) {
+				return "", errors.Errorf("INVALID_WINDOW_FUNCTION_INTEGER_ARGUMENT:%s:%s", function, a)
+			}
+			arguments[i] = a
+		case a == "*" && function == "COUNT":
+			arguments[i] = a
+		case utils2.IsValidIdentifier(a):
+			arguments[i] = utils2.QuoteFieldWithPrefixByDbType(dbType, a)
+		default:
+			return "", errors.Errorf("INVALID_WINDOW_FUNCTION_ARGUMENT:%s:%s", function, a)
+		}
+	}
+
+	var over []string
+	if len(def.PartitionBy) > 0 {
+		partitionBy := make([]string, len(def.PartitionBy))
+		for i, f := range def.PartitionBy {
+			if !utils2.IsValidIdentifier(f) {
+				return "", errors.Errorf("INVALID_WINDOW_PARTITION_BY_FIELD:%s", f)
+			}
+			partitionBy[i] = utils2.QuoteFieldWithPrefixByDbType(dbType, f)
+		}
+		over = append(over, "PARTITION BY "+strings.Join(partitionBy, ", "))
+	}
+	if len(def.OrderBy) > 0 {
+		orderBy := make([]string, len(def.OrderBy))
+		for i, o := range def.OrderBy {
+			part, err := windowOrderBySQL(dbType, o)
+			if err != nil {
+				return "", err
+			}
+			orderBy[i] = part
+		}
+		over = append(over, "ORDER BY "+strings.Join(orderBy, ", "))
+	}
+
+	return function + "(" + strings.Join(arguments, ", ") + ") OVER (" + strings.Join(over, " ") + ") AS " +
+		utils2.QuoteIdentifierByDbType(dbType, def.Alias), nil
+}
+
+func windowOrderBySQL(dbType base.DXDatabaseType, o OrderByDef) (string, error) {
+	if !utils2.IsValidIdentifier(o.FieldName) {
+		return "", errors.Errorf("INVALID_WINDOW_ORDER_BY_FIELD:%s", o.FieldName)
+	}
+	direction := strings.ToUpper(o.Direction)
+	if direction == "" {
+		direction = "ASC"
+	}
+	if direction != "ASC" && direction != "DESC" {
+		return "", errors.Errorf("INVALID_ORDER_BY_DIRECTION:%s", o.Direction)
+	}
+	field := utils2.QuoteFieldWithPrefixByDbType(dbType, o.FieldName)
+	switch strings.ToLower(o.NullPlacement) {
+	case "":
+		return field + " " + direction, nil
+	case "first", "last":
+	default:
+		return "", errors.Errorf("INVALID_ORDER_BY_NULL_PLACEMENT:%s", o.NullPlacement)
+	}
+	if dbType == base.DXDatabaseTypeMariaDB || dbType == base.DXDatabaseTypeSQLServer {
+		nullRank := "0 ELSE 1"
+		if strings.ToLower(o.NullPlacement) == "last" {
+			nullRank = "1 ELSE 0"
+		}
+		return "CASE WHEN " + field + " IS NULL THEN " + nullRank + " END, " + field + " " + direction, nil
+	}
+	return field + " " + direction + " NULLS " + strings.ToUpper(o.NullPlacement), nil
+}
@@ -0,0 +1,61 @@
+package builder
+
+import (
+	"testing"
+
+	"github.com/donnyhardyanto/dxlib/base"
+)
+
+func TestSelectQueryComposition(t *testing.T) {
+	orders := NewSelectQueryBuilderWithSource(base.DXDatabaseTypePostgreSQL, "app.orders")
+	orders.Select("1").EqField("orders.customer_id", "c.id").
+		AndWithParam(`"status" = :status AND "note" <> ':status' AND "total"::text <> ''`, "status", "open")
+
+	vip := NewSelectQueryBuilderWithSource(base.DXDatabaseTypePostgreSQL, "app.vip")
+	vip.Select("customer_id").AndWithParam(`"status" = :status`, "status", "active")
+
+	qb := NewSelectQueryBuilderWithSource(base.DXDatabaseTypePostgreSQL, "app.customers")
+	qb.AndWithParam(`"status" = :status`, "status", "enabled").Exists(orders).InSubquery("id", vip)
+	qb.SelectWindow(WindowFunctionDef{Function: "row_number", PartitionBy: []string{"region"},
+		OrderBy: []OrderByDef{{FieldName: "created_at", Direction: "desc", NullPlacement: "last"}}, Alias: "rn"})
+
+	sql, args, err := qb.BuildSelectSQL(base.DXDatabaseTypePostgreSQL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `SELECT ROW_NUMBER() OVER (PARTITION BY "region" ORDER BY "created_at" DESC NULLS LAST) AS "rn" FROM app.customers WHERE "status" = :status` +
+		` AND EXISTS (SELECT 1 FROM app.orders WHERE "orders"."customer_id" = "c"."id" AND "status" = :sq0_status AND "note" <> ':status' AND "total"::text <> '')` +
+		` AND "id" IN (SELECT customer_id FROM app.vip WHERE "status" = :sq1_status)`
+	if sql != want {
+		t.Fatalf("sql:\n%s\nwant:\n%s", sql, want)
+	}
+	if args["status"] != "enabled" || args["sq0_status"] != "open" || args["sq1_status"] != "active" {
+		t.Fatalf("args = %v", args)
+	}
+
+	archived := NewSelectQueryBuilderWithSource(base.DXDatabaseTypeOracle, "app.archived_customers")
+	archived.Select("id")
+	union := NewSelectQueryBuilderWithSource(base.DXDatabaseTypeOracle, "app.customers")
+	union.Select("id").Except(archived).AddOrderBy("id", "asc", "").Limit(10)
+	sql, _, err = union.BuildSelectSQL(base.DXDatabaseTypeOracle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want = `SELECT "ID" FROM app.customers MINUS SELECT "ID" FROM app.archived_customers ORDER BY "ID" ASC FETCH NEXT 10 ROWS ONLY`; sql != want {
+		t.Fatalf("sql:\n%s\nwant:\n%s", sql, want)
+	}
+
+	window, err := WindowFunctionSQL(base.DXDatabaseTypeSQLServer, WindowFunctionDef{Function: "LAG", Arguments: []string{"amount", "1"},
+		OrderBy: []OrderByDef{{FieldName: "paid_at", Direction: "asc", NullPlacement: "first"}}, Alias: "previous_amount"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want = `LAG([amount], 1) OVER (ORDER BY CASE WHEN [paid_at] IS NULL THEN 0 ELSE 1 END, [paid_at] ASC) AS [previous_amount]`; window != want {
+		t.Fatalf("window:\n%s\nwant:\n%s", window, want)
+	}
+
+	paged := NewSelectQueryBuilderWithSource(base.DXDatabaseTypePostgreSQL, "app.customers").Limit(5)
+	if qb.Union(paged); qb.Error == nil {
+		t.Fatal("a set operation member with LIMIT must be rejected")
+	}
+}
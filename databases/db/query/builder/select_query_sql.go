@@ -0,0 +1,176 @@
+package builder
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/donnyhardyanto/dxlib/base"
+	databaseDb "github.com/donnyhardyanto/dxlib/databases/db"
+	"github.com/donnyhardyanto/dxlib/errors"
+	"github.com/donnyhardyanto/dxlib/utils"
+)
+
+// BuildSelectSQL builds the SELECT SQL and named args (SourceName, OutFields, WHERE, JOIN, GROUP BY,
+// HAVING, set operations, ORDER BY, LIMIT, OFFSET, FOR UPDATE) for dbType.
+func (qb *SelectQueryBuilder) BuildSelectSQL(dbType base.DXDatabaseType) (string, utils.JSON, error) {
+	query, args, err := qb.BuildSelectBodySQL(dbType)
+	if err != nil {
+		return "", nil, err
+	}
+
+	// Build ORDER BY clause
+	orderByClause, err := qb.BuildOrderByClause()
+	if err != nil {
+		return "", nil, err
+	}
+	if orderByClause != "" {
+		query += " ORDER BY " + orderByClause
+	}
+
+	// Add LIMIT/OFFSET clause if specified (database-specific)
+	if qb.LimitValue > 0 || qb.OffsetValue > 0 {
+		switch dbType {
+		case base.DXDatabaseTypePostgreSQL, base.DXDatabaseTypePostgresSQLV2, base.DXDatabaseTypeMariaDB:
+			if qb.LimitValue > 0 {
+				query += " LIMIT " + strconv.FormatInt(qb.LimitValue, 10)
+			}
+			if qb.OffsetValue > 0 {
+				query += " OFFSET " + strconv.FormatInt(qb.OffsetValue, 10)
+			}
+		case base.DXDatabaseTypeSQLite:
+			// SQLite only accepts OFFSET after a LIMIT; -1 means unlimited
+			limit := qb.LimitValue
+			if limit <= 0 {
+				limit = -1
+			}
+			query += " LIMIT " + strconv.FormatInt(limit, 10)
+			if qb.OffsetValue > 0 {
+				query += " OFFSET " + strconv.FormatInt(qb.OffsetValue, 10)
+			}
+		case base.DXDatabaseTypeSQLServer:
+			// SQL Server requires ORDER BY for OFFSET-FETCH; a compound query only orders by its select list
+			if orderByClause == "" {
+				if len(qb.SetOperations) > 0 {
+					query += " ORDER BY 1"
+				} else {
+					query += " ORDER BY (SELECT NULL)"
+				}
+			}
+			offset := qb.OffsetValue
+			if offset < 0 {
+				offset = 0
+			}
+			query += " OFFSET " + strconv.FormatInt(offset, 10) + " ROWS"
+			if qb.LimitValue > 0 {
+				query += " FETCH NEXT " + strconv.FormatInt(qb.LimitValue, 10) + " ROWS ONLY"
+			}
+		case base.DXDatabaseTypeOracle:
+			if qb.OffsetValue > 0 {
+				query += " OFFSET " + strconv.FormatInt(qb.OffsetValue, 10) + " ROWS"
+			}
+			if qb.LimitValue > 0 {
+				query += " FETCH NEXT " + strconv.FormatInt(qb.LimitValue, 10) + " ROWS ONLY"
+			}
+		default:
+			return "", nil, errors.Errorf("LIMIT_OFFSET_NOT_SUPPORTED_FOR_DRIVER:%s", dbType.Driver())
+		}
+	}
+
+	// Add FOR UPDATE if specified
+	if qb.ForUpdatePart != nil {
+		if s, ok := qb.ForUpdatePart.(string); ok && s != "" {
+			if len(qb.SetOperations) > 0 {
+				return "", nil, errors.New("FOR_UPDATE_NOT_ALLOWED_WITH_SET_OPERATIONS")
+			}
+			query += " " + s
+		}
+	}
+
+	return query, args, nil
+}
+
+// BuildSelectBodySQL builds the SELECT SQL without ORDER BY, paging and locking: the SELECT ... FROM
+// ... WHERE ... GROUP BY ... HAVING part followed by the set operations. Count queries wrap it.
+func (qb *SelectQueryBuilder) BuildSelectBodySQL(dbType base.DXDatabaseType) (string, utils.JSON, error) {
+	// Check for errors accumulated in SelectQueryBuilder
+	if qb.Error != nil {
+		return "", nil, qb.Error
+	}
+
+	// Validate SourceName is set
+	if qb.SourceName == "" {
+		return "", nil, errors.New("QUERY_BUILDER_SOURCE_NAME_NOT_SET")
+	}
+
+	// Build SELECT fields from qb.OutFields, default to "*". Oracle: plain
+	// identifiers are quoted-uppercase (an unquoted reserved word like uid
+	// resolves to the built-in function — wrong data, no error).
+	selectFieldsPart := "*"
+	if len(qb.OutFields) > 0 {
+		outFields := qb.OutFields
+		if dbType == base.DXDatabaseTypeOracle {
+			outFields = make([]string, len(qb.OutFields))
+			for i, f := range qb.OutFields {
+				outFields[i] = databaseDb.OracleSelectListField(f)
+			}
+		}
+		selectFieldsPart = strings.Join(outFields, ", ")
+	}
+
+	// Build WHERE clause; args is a copy so building never changes qb.Args
+	whereClause, whereArgs, err := qb.Build()
+	if err != nil {
+		return "", nil, err
+	}
+	args := utils.JSON{}
+	for k, v := range whereArgs {
+		args[k] = v
+	}
+
+	// Build JOIN clause
+	joinClause, err := qb.BuildJoinClause()
+	if err != nil {
+		return "", nil, err
+	}
+
+	// Build GROUP BY clause
+	groupByClause, err := qb.BuildGroupByClause()
+	if err != nil {
+		return "", nil, err
+	}
+
+	// Build HAVING clause and merge args
+	havingClause, havingArgs, err := qb.BuildHavingClause()
+	if err != nil {
+		return "", nil, err
+	}
+	for k, v := range havingArgs {
+		args[k] = v
+	}
+
+	// Build full query. The source name is adapted per engine: MariaDB's virtual
+	// schema carries `schema.table` as ONE backtick identifier (raw `s.t` parses
+	// as database s, table t -> "table doesn't exist"); no-op elsewhere.
+	sourceName := databaseDb.QualifyTableNameForExec(dbType, qb.SourceName)
+	query := "SELECT " + selectFieldsPart + " FROM " + sourceName
+
+	if joinClause != "" {
+		query += " " + joinClause
+	}
+	if whereClause != "" {
+		query += " WHERE " + whereClause
+	}
+	if groupByClause != "" {
+		query += " " + groupByClause
+	}
+	if havingClause != "" {
+		query += " " + havingClause
+	}
+
+	// Set operation members were rendered and their args merged into qb.Args when added
+	for _, op := range qb.SetOperations {
+		query += " " + op.Type.keyword(dbType) + " " + op.SelectSQL
+	}
+
+	return query, args, nil
+}
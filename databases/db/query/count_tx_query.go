@@ -0,0 +1,101 @@
+package query
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/donnyhardyanto/dxlib/base"
+	"github.com/donnyhardyanto/dxlib/databases"
+	"github.com/donnyhardyanto/dxlib/databases/db/query/builder"
+	"github.com/donnyhardyanto/dxlib/databases/db/query/named"
+	"github.com/donnyhardyanto/dxlib/errors"
+	"github.com/donnyhardyanto/dxlib/utils"
+)
+
+// TxCappedCountWithSelectQueryBuilder2 counts the rows of qb like TxCountWithSelectQueryBuilder2, but stops
+// after maxCount+1 rows, so the cost is bounded on big lists. isCapped is true when there are more than
+// maxCount rows; count is then maxCount.
+func TxCappedCountWithSelectQueryBuilder2(ctx context.Context, dtx *databases.DXDatabaseTx, qb *builder.SelectQueryBuilder, maxCount int64) (count int64, isCapped bool, err error) {
+	if maxCount <= 0 {
+		return 0, false, errors.Errorf("INVALID_MAX_COUNT:%d", maxCount)
+	}
+	source, args, err := buildCountSourceSQL(dtx, qb)
+	if err != nil {
+		return 0, false, err
+	}
+
+	limit := strconv.FormatInt(maxCount+1, 10)
+	var inner string
+	switch base.StringToDXDatabaseType(base.NormalizeDriverName(dtx.Tx.DriverName())) {
+	case base.DXDatabaseTypeSQLServer:
+		inner = "SELECT TOP (" + limit + ") 1 AS one FROM " + source
+	case base.DXDatabaseTypeOracle:
+		inner = "SELECT 1 AS one FROM " + source + " FETCH FIRST " + limit + " ROWS ONLY"
+	default:
+		inner = "SELECT 1 AS one FROM " + source + " LIMIT " + limit
+	}
+
+	_, row, err := named.TxNamedQueryRow2(ctx, dtx, "SELECT COUNT(*) AS count FROM ("+inner+") capped_rows", args, nil)
+	if err != nil {
+		return 0, false, err
+	}
+	count, err = countFromRow(row)
+	if err != nil {
+		return 0, false, err
+	}
+	if count > maxCount {
+		return maxCount, true, nil
+	}
+	return count, false, nil
+}
+
+// TxEstimateTableRowCount returns the row count the database statistics hold for tableName (schema.table):
+// pg_class.reltuples on PostgreSQL, sys.dm_db_partition_stats on SQL Server, ALL_TABLES.NUM_ROWS on Oracle and
+// information_schema.TABLES.TABLE_ROWS on MariaDB. isAvailable is false when the table has no statistics
+// yet or the dialect has none (SQLite).
+func TxEstimateTableRowCount(ctx context.Context, dtx *databases.DXDatabaseTx, tableName string) (count int64, isAvailable bool, err error) {
+	schemaName, name, isQualified := strings.Cut(tableName, ".")
+	if !isQualified {
+		schemaName, name = "", tableName
+	}
+
+	var query string
+	args := utils.JSON{}
+	switch base.StringToDXDatabaseType(base.NormalizeDriverName(dtx.Tx.DriverName())) {
+	case base.DXDatabaseTypePostgreSQL:
+		// reltuples is -1 for a table never vacuumed or analyzed
+		query = "SELECT CAST(reltuples AS BIGINT) AS count FROM pg_class WHERE oid = to_regclass(:table_name) AND reltuples >= 0"
+		args["table_name"] = tableName
+	case base.DXDatabaseTypeSQLServer:
+		query = "SELECT SUM(row_count) AS count FROM sys.dm_db_partition_stats WHERE object_id = OBJECT_ID(:table_name) AND index_id IN (0, 1)"
+		args["table_name"] = tableName
+	case base.DXDatabaseTypeOracle:
+		// Objects are created quoted-UPPERCASE, see QuoteIdentifierByDbType
+		query = "SELECT NUM_ROWS AS count FROM ALL_TABLES WHERE OWNER = SYS_CONTEXT('USERENV', 'CURRENT_SCHEMA') AND TABLE_NAME = :table_name"
+		if schemaName != "" {
+			query = "SELECT NUM_ROWS AS count FROM ALL_TABLES WHERE OWNER = :owner AND TABLE_NAME = :table_name"
+			args["owner"] = strings.ToUpper(schemaName)
+		}
+		args["table_name"] = strings.ToUpper(name)
+	case base.DXDatabaseTypeMariaDB:
+		// The schema is virtual: the dotted name is one table of the current database
+		query = "SELECT TABLE_ROWS AS count FROM information_schema.TABLES WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = :table_name"
+		args["table_name"] = tableName
+	default:
+		return 0, false, nil
+	}
+
+	_, row, err := named.TxNamedQueryRow2(ctx, dtx, query, args, nil)
+	if err != nil {
+		return 0, false, err
+	}
+	if row == nil || row["count"] == nil {
+		return 0, false, nil
+	}
+	count, err = countFromRow(row)
+	if err != nil {
+		return 0, false, err
+	}
+	return count, true, nil
+}
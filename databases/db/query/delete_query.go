@@ -40,7 +40,7 @@ func buildDeleteSQL(driverName string, qb *builder.DeleteQueryBuilder) (string,
 	// Handle RETURNING/OUTPUT
 	if len(qb.OutFields) > 0 {
 		switch driverName {
-		case "postgres", "mariadb":
+		case "postgres", "mariadb", "sqlite":
 			returningClause, err := qb.BuildReturningClause()
 			if err != nil {
 				return "", nil, err
@@ -46,7 +46,7 @@ func buildInsertSQL(driverName string, qb *builder.InsertQueryBuilder) (string,
 	// Handle RETURNING/OUTPUT
 	if len(qb.OutFields) > 0 {
 		switch driverName {
-		case "postgres", "mariadb":
+		case "postgres", "mariadb", "sqlite":
 			returningClause, err := qb.BuildReturningClause()
 			if err != nil {
 				return "", nil, err
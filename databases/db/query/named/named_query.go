@@ -18,8 +18,8 @@ import (
 func NamedQueryRows2(ctx context.Context, db *sqlx.DB, query string, arg utils.JSON, fieldTypeMapping databaseDb.DXDatabaseTableFieldTypeMapping) (rowsInfo *databaseDb.DXDatabaseTableRowsInfo, r []utils.JSON, err error) {
 	dbType := base.StringToDXDatabaseType(db.DriverName())
 
-	// MariaDB and Oracle do not support named parameters, convert to positional
-	if dbType == base.DXDatabaseTypeMariaDB || dbType == base.DXDatabaseTypeOracle {
+	// MariaDB, Oracle and SQLite are given positional parameters
+	if dbType == base.DXDatabaseTypeMariaDB || dbType == base.DXDatabaseTypeOracle || dbType == base.DXDatabaseTypeSQLite {
 		positionalQuery, positionalArgs, err := query2.ParameterizedSQLQueryNamedBasedToIndexBased(dbType, query, arg)
 		if err != nil {
 			return nil, nil, errors.Wrapf(err, "PARAMETER_CONVERSION_ERROR:QUERY=%s", query)
@@ -70,3 +70,16 @@ func ShouldNamedQueryRow2(ctx context.Context, db *sqlx.DB, query string, arg ut
 	}
 	return rowsInfo, r, nil
 }
+
+// NamedQueryRowsSeq2 is NamedQueryRows2 yielding the rows as they are read.
+func NamedQueryRowsSeq2(ctx context.Context, db *sqlx.DB, query string, arg utils.JSON, fieldTypeMapping databaseDb.DXDatabaseTableFieldTypeMapping) (rowsInfo *databaseDb.DXDatabaseTableRowsInfo, r databaseDb.DXDatabaseRowsSeq, err error) {
+	dbType := base.StringToDXDatabaseType(db.DriverName())
+	if dbType == base.DXDatabaseTypeMariaDB || dbType == base.DXDatabaseTypeOracle || dbType == base.DXDatabaseTypeSQLite {
+		positionalQuery, positionalArgs, err := query2.ParameterizedSQLQueryNamedBasedToIndexBased(dbType, query, arg)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "PARAMETER_CONVERSION_ERROR:QUERY=%s", query)
+		}
+		return base2.BaseQueryRowsSeq2(ctx, db, positionalQuery, positionalArgs, fieldTypeMapping)
+	}
+	return base2.BaseQueryRowsSeq2(ctx, db, query, arg, fieldTypeMapping)
+}
@@ -16,14 +16,14 @@ import (
 // Converts named parameters (:name) to database-specific parameter format.
 // PostgreSQL: $1, $2, ... (positional)
 // SQL Server: @p1, @p2, ... (named, but different syntax)
-// MariaDB/MySQL: ? (positional)
+// MariaDB/MySQL, SQLite: ? (positional)
 // Oracle: :1, :2, ... (positional)
 func TxNamedQueryRows2(ctx context.Context, dtx *databases.DXDatabaseTx, query string, arg utils.JSON, fieldTypeMapping databaseDb.DXDatabaseTableFieldTypeMapping) (rowsInfo *databaseDb.DXDatabaseTableRowsInfo, r []utils.JSON, err error) {
 	dbType := base.StringToDXDatabaseType(dtx.Tx.DriverName())
 
 	// All databases need parameter conversion from :name format
 	// PostgreSQL uses $1, $2, ... (positional)
-	// MariaDB uses ? (positional)
+	// MariaDB and SQLite use ? (positional)
 	// Oracle uses :1, :2, ... (positional)
 	// SQL Server uses @p1, @p2, ... (named but different syntax)
 	positionalQuery, positionalArgs, err := query2.ParameterizedSQLQueryNamedBasedToIndexBased(dbType, query, arg)
@@ -72,3 +72,13 @@ func TxShouldNamedQueryRow2(ctx context.Context, dtx *databases.DXDatabaseTx, qu
 	}
 	return rowsInfo, r, nil
 }
+
+// TxNamedQueryRowsSeq2 is TxNamedQueryRows2 yielding the rows as they are read.
+func TxNamedQueryRowsSeq2(ctx context.Context, dtx *databases.DXDatabaseTx, query string, arg utils.JSON, fieldTypeMapping databaseDb.DXDatabaseTableFieldTypeMapping) (rowsInfo *databaseDb.DXDatabaseTableRowsInfo, r databaseDb.DXDatabaseRowsSeq, err error) {
+	dbType := base.StringToDXDatabaseType(dtx.Tx.DriverName())
+	positionalQuery, positionalArgs, err := query2.ParameterizedSQLQueryNamedBasedToIndexBased(dbType, query, arg)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "PARAMETER_CONVERSION_ERROR:QUERY=%s", query)
+	}
+	return base2.TxBaseQueryRowsSeq2(ctx, dtx, positionalQuery, positionalArgs, fieldTypeMapping)
+}
@@ -2,8 +2,6 @@ package query
 
 import (
 	"context"
-	"strconv"
-	"strings"
 
 	"github.com/donnyhardyanto/dxlib/base"
 	databaseDb "github.com/donnyhardyanto/dxlib/databases/db"
@@ -14,134 +12,19 @@ import (
 	"github.com/jmoiron/sqlx"
 )
 
+// buildSelectSQL builds the SELECT SQL and named args from SelectQueryBuilder (SourceName, OutFields,
+// WHERE, JOIN, GROUP BY, HAVING, ORDER BY, LIMIT, OFFSET, FOR UPDATE) for a normalized driver name.
+func buildSelectSQL(driverName string, qb *builder.SelectQueryBuilder) (string, utils.JSON, error) {
+	return qb.BuildSelectSQL(base.StringToDXDatabaseType(driverName))
+}
+
 // SelectWithSelectQueryBuilder2 executes a query using SelectQueryBuilder and returns all matching rows.
 // Builds SELECT query from SelectQueryBuilder (SourceName, OutFields, WHERE, JOIN, GROUP BY, HAVING, ORDER BY, LIMIT, OFFSET) and calls NamedQueryRows2.
 func SelectWithSelectQueryBuilder2(ctx context.Context, db *sqlx.DB, qb *builder.SelectQueryBuilder, fieldTypeMapping databaseDb.DXDatabaseTableFieldTypeMapping) (rowsInfo *databaseDb.DXDatabaseTableRowsInfo, r []utils.JSON, err error) {
-
-	// Check for errors accumulated in SelectQueryBuilder
-	if qb.Error != nil {
-		return nil, nil, qb.Error
-	}
-
-	// Validate SourceName is set
-	if qb.SourceName == "" {
-		return nil, nil, errors.New("QUERY_BUILDER_SOURCE_NAME_NOT_SET")
-	}
-
-	driverName := base.NormalizeDriverName(db.DriverName())
-
-	// Build SELECT fields from qb.OutFields, default to "*". Oracle: plain
-	// identifiers are quoted-uppercase (an unquoted reserved word like uid
-	// resolves to the built-in function — wrong data, no error).
-	selectFieldsPart := "*"
-	if len(qb.OutFields) > 0 {
-		outFields := qb.OutFields
-		if driverName == "oracle" {
-			outFields = make([]string, len(qb.OutFields))
-			for i, f := range qb.OutFields {
-				outFields[i] = databaseDb.OracleSelectListField(f)
-			}
-		}
-		selectFieldsPart = strings.Join(outFields, ", ")
-	}
-
-	// Build WHERE clause
-	whereClause, args, err := qb.Build()
-	if err != nil {
-		return nil, nil, err
-	}
-
-	// Build JOIN clause
-	joinClause, err := qb.BuildJoinClause()
-	if err != nil {
-		return nil, nil, err
-	}
-
-	// Build GROUP BY clause
-	groupByClause, err := qb.BuildGroupByClause()
-	if err != nil {
-		return nil, nil, err
-	}
-
-	// Build HAVING clause and merge args
-	havingClause, havingArgs, err := qb.BuildHavingClause()
+	query, args, err := buildSelectSQL(base.NormalizeDriverName(db.DriverName()), qb)
 	if err != nil {
 		return nil, nil, err
 	}
-	for k, v := range havingArgs {
-		args[k] = v
-	}
-
-	// Build ORDER BY clause
-	orderByClause, err := qb.BuildOrderByClause()
-	if err != nil {
-		return nil, nil, err
-	}
-
-	// Build full query. The source name is adapted per engine: MariaDB's virtual
-	// schema carries `schema.table` as ONE backtick identifier (raw `s.t` parses
-	// as database s, table t -> "table doesn't exist"); no-op elsewhere.
-	sourceName := databaseDb.QualifyTableNameForExec(base.StringToDXDatabaseType(driverName), qb.SourceName)
-	query := "SELECT " + selectFieldsPart + " FROM " + sourceName
-
-	if joinClause != "" {
-		query += " " + joinClause
-	}
-	if whereClause != "" {
-		query += " WHERE " + whereClause
-	}
-	if groupByClause != "" {
-		query += " " + groupByClause
-	}
-	if havingClause != "" {
-		query += " " + havingClause
-	}
-	if orderByClause != "" {
-		query += " ORDER BY " + orderByClause
-	}
-
-	// Add LIMIT/OFFSET clause if specified (database-specific)
-	if qb.LimitValue > 0 || qb.OffsetValue > 0 {
-		switch driverName {
-		case "postgres", "mariadb":
-			if qb.LimitValue > 0 {
-				query += " LIMIT " + strconv.FormatInt(qb.LimitValue, 10)
-			}
-			if qb.OffsetValue > 0 {
-				query += " OFFSET " + strconv.FormatInt(qb.OffsetValue, 10)
-			}
-		case "sqlserver":
-			// SQL Server requires ORDER BY for OFFSET-FETCH
-			if orderByClause == "" {
-				query += " ORDER BY (SELECT NULL)"
-			}
-			offset := qb.OffsetValue
-			if offset < 0 {
-				offset = 0
-			}
-			query += " OFFSET " + strconv.FormatInt(offset, 10) + " ROWS"
-			if qb.LimitValue > 0 {
-				query += " FETCH NEXT " + strconv.FormatInt(qb.LimitValue, 10) + " ROWS ONLY"
-			}
-		case "oracle":
-			if qb.OffsetValue > 0 {
-				query += " OFFSET " + strconv.FormatInt(qb.OffsetValue, 10) + " ROWS"
-			}
-			if qb.LimitValue > 0 {
-				query += " FETCH NEXT " + strconv.FormatInt(qb.LimitValue, 10) + " ROWS ONLY"
-			}
-		default:
-			return nil, nil, errors.Errorf("LIMIT_OFFSET_NOT_SUPPORTED_FOR_DRIVER:%s", driverName)
-		}
-	}
-
-	// Add FOR UPDATE if specified
-	if qb.ForUpdatePart != nil {
-		if s, ok := qb.ForUpdatePart.(string); ok && s != "" {
-			query += " " + s
-		}
-	}
-
 	return named.NamedQueryRows2(ctx, db, query, args, fieldTypeMapping)
 }
 
@@ -202,6 +85,19 @@ func CountWithSelectQueryBuilder2(ctx context.Context, db *sqlx.DB, qb *builder.
 		return 0, errors.New("QUERY_BUILDER_SOURCE_NAME_NOT_SET")
 	}
 
+	// A compound query (UNION, ...) is counted as a derived table
+	if len(qb.SetOperations) > 0 {
+		body, args, err := qb.BuildSelectBodySQL(base.StringToDXDatabaseType(base.NormalizeDriverName(db.DriverName())))
+		if err != nil {
+			return 0, err
+		}
+		_, row, err := named.NamedQueryRow2(ctx, db, "SELECT COUNT(*) AS count FROM ("+body+") set_rows", args, nil)
+		if err != nil {
+			return 0, err
+		}
+		return countFromRow(row)
+	}
+
 	// Build WHERE clause
 	whereClause, args, err := qb.Build()
 	if err != nil {
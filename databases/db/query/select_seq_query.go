@@ -0,0 +1,101 @@
+package query
+
+import (
+	"context"
+	"strconv"
+	"sync/atomic"
+
+	"github.com/donnyhardyanto/dxlib/base"
+	"github.com/donnyhardyanto/dxlib/databases"
+	databaseDb "github.com/donnyhardyanto/dxlib/databases/db"
+	"github.com/donnyhardyanto/dxlib/databases/db/query/builder"
+	"github.com/donnyhardyanto/dxlib/databases/db/query/named"
+	query2 "github.com/donnyhardyanto/dxlib/databases/db/query/utils"
+	"github.com/donnyhardyanto/dxlib/errors"
+	"github.com/donnyhardyanto/dxlib/utils"
+	"github.com/jmoiron/sqlx"
+)
+
+// DefaultSelectSeqBatchSize is the number of rows fetched per round trip by a PostgreSQL
+// server-side cursor when no batch size is given.
+const DefaultSelectSeqBatchSize = 1000
+
+var selectSeqCursorCount atomic.Uint64
+
+// SelectSeqWithSelectQueryBuilder2 is SelectWithSelectQueryBuilder2 yielding the rows as
+// they are read from the driver instead of returning them all. The query runs before it
+// returns; range over the sequence to release the connection.
+func SelectSeqWithSelectQueryBuilder2(ctx context.Context, db *sqlx.DB, qb *builder.SelectQueryBuilder, fieldTypeMapping databaseDb.DXDatabaseTableFieldTypeMapping) (rowsInfo *databaseDb.DXDatabaseTableRowsInfo, r databaseDb.DXDatabaseRowsSeq, err error) {
+	query, args, err := buildSelectSQL(base.NormalizeDriverName(db.DriverName()), qb)
+	if err != nil {
+		return nil, nil, err
+	}
+	return named.NamedQueryRowsSeq2(ctx, db, query, args, fieldTypeMapping)
+}
+
+// TxSelectSeqWithSelectQueryBuilder2 is TxSelectWithSelectQueryBuilder2 yielding the rows
+// as they are read. The sequence must be consumed before the transaction ends.
+//
+// PostgreSQL reads through a server-side cursor, FETCH-ing batchSize rows at a time
+// (DefaultSelectSeqBatchSize when <= 0), and only fetches the next batch once the
+// consumer has taken the previous one. The MariaDB, SQL Server and Oracle drivers
+// already read rows off the wire on demand, so they stream the plain result set.
+func TxSelectSeqWithSelectQueryBuilder2(ctx context.Context, dtx *databases.DXDatabaseTx, qb *builder.SelectQueryBuilder, fieldTypeMapping databaseDb.DXDatabaseTableFieldTypeMapping,
+	batchSize int) (rowsInfo *databaseDb.DXDatabaseTableRowsInfo, r databaseDb.DXDatabaseRowsSeq, err error) {
+	driverName := base.NormalizeDriverName(dtx.Tx.DriverName())
+	query, args, err := buildSelectSQL(driverName, qb)
+	if err != nil {
+		return nil, nil, err
+	}
+	dbType := base.StringToDXDatabaseType(driverName)
+	if dbType != base.DXDatabaseTypePostgreSQL {
+		return named.TxNamedQueryRowsSeq2(ctx, dtx, query, args, fieldTypeMapping)
+	}
+	if batchSize <= 0 {
+		batchSize = DefaultSelectSeqBatchSize
+	}
+
+	positionalQuery, positionalArgs, err := query2.ParameterizedSQLQueryNamedBasedToIndexBased(dbType, query, args)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "PARAMETER_CONVERSION_ERROR:QUERY=%s", query)
+	}
+	cursorName := "dx_cursor_" + strconv.FormatUint(selectSeqCursorCount.Add(1), 10)
+	if _, err = dtx.Tx.ExecContext(ctx, "DECLARE "+cursorName+" NO SCROLL CURSOR FOR "+positionalQuery, positionalArgs...); err != nil {
+		return nil, nil, errors.Wrapf(err, "CURSOR_DECLARE_ERROR:QUERY=%s", query)
+	}
+	fetch := "FETCH FORWARD " + strconv.Itoa(batchSize) + " FROM " + cursorName
+	closeCursor := func() {
+		_, _ = dtx.Tx.ExecContext(ctx, "CLOSE "+cursorName)
+	}
+
+	// The first batch is fetched now for the columns.
+	rowsInfo, batch, err := databaseDb.RawTxQueryRowsSeq(ctx, dtx.Tx, fieldTypeMapping, fetch, nil)
+	if err != nil {
+		closeCursor()
+		return nil, nil, err
+	}
+	return rowsInfo, func(yield func(utils.JSON, error) bool) {
+		defer closeCursor()
+		for {
+			count := 0
+			for row, err := range batch {
+				if err != nil {
+					yield(nil, err)
+					return
+				}
+				count++
+				if !yield(row, nil) {
+					return
+				}
+			}
+			if count < batchSize {
+				return
+			}
+			_, batch, err = databaseDb.RawTxQueryRowsSeq(ctx, dtx.Tx, fieldTypeMapping, fetch, nil)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+		}
+	}, nil
+}
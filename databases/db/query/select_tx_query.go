@@ -3,7 +3,6 @@ package query
 import (
 	"context"
 	"strconv"
-	"strings"
 
 	"github.com/donnyhardyanto/dxlib/base"
 	"github.com/donnyhardyanto/dxlib/databases"
@@ -17,131 +16,10 @@ import (
 // TxSelectWithSelectQueryBuilder2 executes a query within a transaction using SelectQueryBuilder and returns all matching rows.
 // Builds SELECT query from SelectQueryBuilder (SourceName, OutFields, WHERE, JOIN, GROUP BY, HAVING, ORDER BY, LIMIT, OFFSET) and calls TxNamedQueryRows2.
 func TxSelectWithSelectQueryBuilder2(ctx context.Context, dtx *databases.DXDatabaseTx, qb *builder.SelectQueryBuilder, fieldTypeMapping databaseDb.DXDatabaseTableFieldTypeMapping) (rowsInfo *databaseDb.DXDatabaseTableRowsInfo, r []utils.JSON, err error) {
-
-	// Check for errors accumulated in SelectQueryBuilder
-	if qb.Error != nil {
-		return nil, nil, qb.Error
-	}
-
-	// Validate SourceName is set
-	if qb.SourceName == "" {
-		return nil, nil, errors.New("QUERY_BUILDER_SOURCE_NAME_NOT_SET")
-	}
-
-	driverName := base.NormalizeDriverName(dtx.Tx.DriverName())
-
-	// Build SELECT fields from qb.OutFields, default to "*". Oracle: plain
-	// identifiers are quoted-uppercase (an unquoted reserved word like uid
-	// resolves to the built-in function — wrong data, no error).
-	selectFieldsPart := "*"
-	if len(qb.OutFields) > 0 {
-		outFields := qb.OutFields
-		if driverName == "oracle" {
-			outFields = make([]string, len(qb.OutFields))
-			for i, f := range qb.OutFields {
-				outFields[i] = databaseDb.OracleSelectListField(f)
-			}
-		}
-		selectFieldsPart = strings.Join(outFields, ", ")
-	}
-
-	// Build WHERE clause
-	whereClause, args, err := qb.Build()
-	if err != nil {
-		return nil, nil, err
-	}
-
-	// Build JOIN clause
-	joinClause, err := qb.BuildJoinClause()
-	if err != nil {
-		return nil, nil, err
-	}
-
-	// Build GROUP BY clause
-	groupByClause, err := qb.BuildGroupByClause()
-	if err != nil {
-		return nil, nil, err
-	}
-
-	// Build HAVING clause and merge args
-	havingClause, havingArgs, err := qb.BuildHavingClause()
-	if err != nil {
-		return nil, nil, err
-	}
-	for k, v := range havingArgs {
-		args[k] = v
-	}
-
-	// Build ORDER BY clause
-	orderByClause, err := qb.BuildOrderByClause()
+	query, args, err := buildSelectSQL(base.NormalizeDriverName(dtx.Tx.DriverName()), qb)
 	if err != nil {
 		return nil, nil, err
 	}
-
-	// Build full query. The source name is adapted per engine: MariaDB's virtual
-	// schema carries `schema.table` as ONE backtick identifier (raw `s.t` parses
-	// as database s, table t -> "table doesn't exist"); no-op elsewhere.
-	sourceName := databaseDb.QualifyTableNameForExec(base.StringToDXDatabaseType(driverName), qb.SourceName)
-	query := "SELECT " + selectFieldsPart + " FROM " + sourceName
-
-	if joinClause != "" {
-		query += " " + joinClause
-	}
-	if whereClause != "" {
-		query += " WHERE " + whereClause
-	}
-	if groupByClause != "" {
-		query += " " + groupByClause
-	}
-	if havingClause != "" {
-		query += " " + havingClause
-	}
-	if orderByClause != "" {
-		query += " ORDER BY " + orderByClause
-	}
-
-	// Add LIMIT/OFFSET clause if specified (database-specific)
-	if qb.LimitValue > 0 || qb.OffsetValue > 0 {
-		switch driverName {
-		case "postgres", "mariadb":
-			if qb.LimitValue > 0 {
-				query += " LIMIT " + strconv.FormatInt(qb.LimitValue, 10)
-			}
-			if qb.OffsetValue > 0 {
-				query += " OFFSET " + strconv.FormatInt(qb.OffsetValue, 10)
-			}
-		case "sqlserver":
-			// SQL Server requires ORDER BY for OFFSET-FETCH
-			if orderByClause == "" {
-				query += " ORDER BY (SELECT NULL)"
-			}
-			offset := qb.OffsetValue
-			if offset < 0 {
-				offset = 0
-			}
-			query += " OFFSET " + strconv.FormatInt(offset, 10) + " ROWS"
-			if qb.LimitValue > 0 {
-				query += " FETCH NEXT " + strconv.FormatInt(qb.LimitValue, 10) + " ROWS ONLY"
-			}
-		case "oracle":
-			if qb.OffsetValue > 0 {
-				query += " OFFSET " + strconv.FormatInt(qb.OffsetValue, 10) + " ROWS"
-			}
-			if qb.LimitValue > 0 {
-				query += " FETCH NEXT " + strconv.FormatInt(qb.LimitValue, 10) + " ROWS ONLY"
-			}
-		default:
-			return nil, nil, errors.Errorf("LIMIT_OFFSET_NOT_SUPPORTED_FOR_DRIVER:%s", driverName)
-		}
-	}
-
-	// Add FOR UPDATE if specified
-	if qb.ForUpdatePart != nil {
-		if s, ok := qb.ForUpdatePart.(string); ok && s != "" {
-			query += " " + s
-		}
-	}
-
 	return named.TxNamedQueryRows2(ctx, dtx, query, args, fieldTypeMapping)
 }
 
@@ -190,66 +68,87 @@ func TxShouldSelectOneWithSelectQueryBuilder2(ctx context.Context, dtx *database
 // TxCountWithSelectQueryBuilder2 executes a COUNT query within a transaction using SelectQueryBuilder and returns the count.
 // Builds SELECT COUNT(*) query from SelectQueryBuilder (SourceName, WHERE, JOIN, GROUP BY, HAVING).
 func TxCountWithSelectQueryBuilder2(ctx context.Context, dtx *databases.DXDatabaseTx, qb *builder.SelectQueryBuilder) (count int64, err error) {
+	source, args, err := buildCountSourceSQL(dtx, qb)
+	if err != nil {
+		return 0, err
+	}
+
+	_, row, err := named.TxNamedQueryRow2(ctx, dtx, "SELECT COUNT(*) AS count FROM "+source, args, nil)
+	if err != nil {
+		return 0, err
+	}
+	return countFromRow(row)
+}
 
+// buildCountSourceSQL builds the FROM part of a count query (source, JOIN, WHERE, GROUP BY, HAVING) and its args.
+func buildCountSourceSQL(dtx *databases.DXDatabaseTx, qb *builder.SelectQueryBuilder) (string, utils.JSON, error) {
 	// Check for errors accumulated in SelectQueryBuilder
 	if qb.Error != nil {
-		return 0, qb.Error
+		return "", nil, qb.Error
 	}
 
 	// Validate SourceName is set
 	if qb.SourceName == "" {
-		return 0, errors.New("QUERY_BUILDER_SOURCE_NAME_NOT_SET")
+		return "", nil, errors.New("QUERY_BUILDER_SOURCE_NAME_NOT_SET")
+	}
+
+	// A compound query (UNION, ...) is counted as a derived table
+	if len(qb.SetOperations) > 0 {
+		body, args, err := qb.BuildSelectBodySQL(base.StringToDXDatabaseType(base.NormalizeDriverName(dtx.Tx.DriverName())))
+		if err != nil {
+			return "", nil, err
+		}
+		return "(" + body + ") set_rows", args, nil
 	}
 
 	// Build WHERE clause
 	whereClause, args, err := qb.Build()
 	if err != nil {
-		return 0, err
+		return "", nil, err
 	}
 
 	// Build JOIN clause
 	joinClause, err := qb.BuildJoinClause()
 	if err != nil {
-		return 0, err
+		return "", nil, err
 	}
 
 	// Build GROUP BY clause
 	groupByClause, err := qb.BuildGroupByClause()
 	if err != nil {
-		return 0, err
+		return "", nil, err
 	}
 
 	// Build HAVING clause and merge args
 	havingClause, havingArgs, err := qb.BuildHavingClause()
 	if err != nil {
-		return 0, err
+		return "", nil, err
 	}
 	for k, v := range havingArgs {
 		args[k] = v
 	}
 
-	// Build full COUNT query. MariaDB virtual-schema source-name collapse, as in
+	// MariaDB virtual-schema source-name collapse, as in
 	// TxSelectWithSelectQueryBuilder2; no-op on other engines.
-	query := "SELECT COUNT(*) AS count FROM " +
-		databaseDb.QualifyTableNameForExec(base.StringToDXDatabaseType(base.NormalizeDriverName(dtx.Tx.DriverName())), qb.SourceName)
+	source := databaseDb.QualifyTableNameForExec(base.StringToDXDatabaseType(base.NormalizeDriverName(dtx.Tx.DriverName())), qb.SourceName)
 
 	if joinClause != "" {
-		query += " " + joinClause
+		source += " " + joinClause
 	}
 	if whereClause != "" {
-		query += " WHERE " + whereClause
+		source += " WHERE " + whereClause
 	}
 	if groupByClause != "" {
-		query += " " + groupByClause
+		source += " " + groupByClause
 	}
 	if havingClause != "" {
-		query += " " + havingClause
+		source += " " + havingClause
 	}
+	return source, args, nil
+}
 
-	_, row, err := named.TxNamedQueryRow2(ctx, dtx, query, args, nil)
-	if err != nil {
-		return 0, err
-	}
+// countFromRow extracts the "count" column of a count query row; no row counts as 0.
+func countFromRow(row utils.JSON) (int64, error) {
 	if row == nil {
 		return 0, nil
 	}
@@ -267,6 +166,12 @@ func TxCountWithSelectQueryBuilder2(ctx context.Context, dtx *databases.DXDataba
 		return int64(v), nil
 	case float64:
 		return int64(v), nil
+	case uint64:
+		return int64(v), nil
+	case string:
+		return strconv.ParseInt(v, 10, 64)
+	case []byte:
+		return strconv.ParseInt(string(v), 10, 64)
 	default:
 		return 0, errors.Errorf("UNEXPECTED_COUNT_TYPE:%T", countVal)
 	}
@@ -56,7 +56,7 @@ func buildUpdateSQL(driverName string, qb *builder.UpdateQueryBuilder) (string,
 	// Handle RETURNING/OUTPUT
 	if len(qb.OutFields) > 0 {
 		switch driverName {
-		case "postgres", "mariadb":
+		case "postgres", "mariadb", "sqlite":
 			returningClause, err := qb.BuildReturningClause()
 			if err != nil {
 				return "", nil, err
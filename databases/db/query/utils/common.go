@@ -43,8 +43,8 @@ func QuoteIdentifierByDbType(dbType base.DXDatabaseType, identifier string) stri
 		// default) a double-quoted "name" is a STRING LITERAL, so e.g.
 		// ORDER BY "created_at" silently sorts by a constant (no error, no sort).
 		return "`" + strings.ReplaceAll(identifier, "`", "``") + "`"
-	case base.DXDatabaseTypePostgreSQL:
-		// PostgreSQL uses "identifier" - escape " as ""
+	case base.DXDatabaseTypePostgreSQL, base.DXDatabaseTypeSQLite:
+		// PostgreSQL and SQLite use "identifier" - escape " as ""
 		return "\"" + strings.ReplaceAll(identifier, "\"", "\"\"") + "\""
 	default:
 		// PostgreSQL style as fallback
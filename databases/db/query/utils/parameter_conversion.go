@@ -75,7 +75,7 @@ func ParameterizedSQLQueryNamedBasedToIndexBased(dbType base.DXDatabaseType, que
 					result.WriteString("$" + strconv.Itoa(paramIndex))
 				case base.DXDatabaseTypeSQLServer:
 					result.WriteString("@p" + strconv.Itoa(paramIndex))
-				case base.DXDatabaseTypeMariaDB:
+				case base.DXDatabaseTypeMariaDB, base.DXDatabaseTypeSQLite:
 					result.WriteString("?")
 				case base.DXDatabaseTypeOracle:
 					result.WriteString(":" + strconv.Itoa(paramIndex))
@@ -12,7 +12,7 @@ import (
 )
 
 func RawExec(ctx context.Context, db *sqlx.DB, query string, arg []any) (result sql.Result, err error) {
-	ctx, endOtel := DbOtelStart(ctx, "db.EXEC", query, 3)
+	ctx, endOtel := DbStatementStart(ctx, db, "db.EXEC", query, arg, 3)
 	defer func() {
 		var ra int64 = -1
 		if result != nil {
@@ -33,7 +33,7 @@ func RawExec(ctx context.Context, db *sqlx.DB, query string, arg []any) (result
 }
 
 func RawTxExec(ctx context.Context, tx *sqlx.Tx, query string, arg []any) (result sql.Result, err error) {
-	ctx, endOtel := DbOtelStart(ctx, "db.TX_EXEC", query, 3)
+	ctx, endOtel := DbStatementStart(ctx, tx, "db.TX_EXEC", query, arg, 3)
 	defer func() {
 		var ra int64 = -1
 		if result != nil {
@@ -79,8 +79,8 @@ func Exec(ctx context.Context, db *sqlx.DB, sqlStatement string, sqlArguments ut
 		// ":p_<name>" paired with matching sql.Named args (ORA-01745 otherwise).
 		modifiedSQL, args = OracleSafeBindNames(sqlStatement, sqlArguments)
 
-	case base.DXDatabaseTypeMariaDB:
-		// MariaDB uses ? placeholders
+	case base.DXDatabaseTypeMariaDB, base.DXDatabaseTypeSQLite:
+		// MariaDB and SQLite use ? placeholders
 		// Convert to question mark format if needed for IN clauses
 		modifiedSQL, args, err = sqlx.In(modifiedSQL, args...)
 		if err != nil {
@@ -131,8 +131,8 @@ func TxExec(
 		// ":p_<name>" paired with matching sql.Named args (ORA-01745 otherwise).
 		modifiedSQL, args = OracleSafeBindNames(sqlStatement, sqlArguments)
 
-	case base.DXDatabaseTypeMariaDB:
-		// MariaDB uses ? placeholders
+	case base.DXDatabaseTypeMariaDB, base.DXDatabaseTypeSQLite:
+		// MariaDB and SQLite use ? placeholders
 		// Convert to question mark format if needed for IN clauses
 		modifiedSQL, args, err = sqlx.In(modifiedSQL, args...)
 		if err != nil {
@@ -11,7 +11,7 @@ import (
 )
 
 func RawQueryRows(ctx context.Context, db *sqlx.DB, fieldTypeMapping DXDatabaseTableFieldTypeMapping, query string, arg []any) (rowsInfo *DXDatabaseTableRowsInfo, r []utils.JSON, err error) {
-	ctx, endOtel := DbOtelStart(ctx, "db.SELECT", query, 3)
+	ctx, endOtel := DbStatementStart(ctx, db, "db.SELECT", query, arg, 3)
 	defer func() { endOtel(err, int64(len(r))) }()
 
 	r = []utils.JSON{}
@@ -43,7 +43,7 @@ func RawQueryRows(ctx context.Context, db *sqlx.DB, fieldTypeMapping DXDatabaseT
 }
 
 func RawTxQueryRows(ctx context.Context, tx *sqlx.Tx, fieldTypeMapping DXDatabaseTableFieldTypeMapping, query string, arg []any) (rowsInfo *DXDatabaseTableRowsInfo, r []utils.JSON, err error) {
-	ctx, endOtel := DbOtelStart(ctx, "db.TX_SELECT", query, 3)
+	ctx, endOtel := DbStatementStart(ctx, tx, "db.TX_SELECT", query, arg, 3)
 	defer func() { endOtel(err, int64(len(r))) }()
 
 	r = []utils.JSON{}
@@ -105,8 +105,8 @@ func QueryRows(
 		// ":p_<name>" paired with matching sql.Named args (ORA-01745 otherwise).
 		modifiedSQL, args = OracleSafeBindNames(sqlStatement, sqlArguments)
 
-	case base.DXDatabaseTypeMariaDB:
-		// MariaDB uses ? placeholders
+	case base.DXDatabaseTypeMariaDB, base.DXDatabaseTypeSQLite:
+		// MariaDB and SQLite use ? placeholders
 		// Convert to question mark format if needed for IN clauses
 		modifiedSQL, args, err = sqlx.In(modifiedSQL, args...)
 		if err != nil {
@@ -159,8 +159,8 @@ func RawCount(
 		// ":p_<name>" paired with matching sql.Named args (ORA-01745 otherwise).
 		modifiedSQL, args = OracleSafeBindNames(s, sqlArguments)
 
-	case base.DXDatabaseTypeMariaDB:
-		// MariaDB uses ? placeholders
+	case base.DXDatabaseTypeMariaDB, base.DXDatabaseTypeSQLite:
+		// MariaDB and SQLite use ? placeholders
 		// Convert to question mark format if needed for IN clauses
 		modifiedSQL, args, err = sqlx.In(modifiedSQL, args...)
 		if err != nil {
@@ -232,8 +232,8 @@ func TxQueryRows(
 		// ":p_<name>" paired with matching sql.Named args (ORA-01745 otherwise).
 		modifiedSQL, args = OracleSafeBindNames(sqlStatement, sqlArguments)
 
-	case base.DXDatabaseTypeMariaDB:
-		// MariaDB uses ? placeholders
+	case base.DXDatabaseTypeMariaDB, base.DXDatabaseTypeSQLite:
+		// MariaDB and SQLite use ? placeholders
 		// Convert to question mark format if needed for IN clauses
 		modifiedSQL, args, err = sqlx.In(modifiedSQL, args...)
 		if err != nil {
@@ -0,0 +1,390 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/donnyhardyanto/dxlib/base"
+	"github.com/donnyhardyanto/dxlib/errors"
+	"github.com/donnyhardyanto/dxlib/utils"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// Row mapping scans the utils.JSON rows returned by the select functions into structs.
+// A struct field is matched to a column by its `db` tag, or by its lower-cased name when
+// it has none; `db:"-"` skips a field and embedded structs are flattened. Column names
+// are matched case-insensitively (Oracle returns upper-case names).
+//
+// Values are normalized from whatever the driver returned: numbers given as strings or
+// []byte (Oracle NUMBER, MariaDB DECIMAL), decimal.Decimal for money columns, time.Time
+// from timestamps or their text form, []int64 and other slices from PostgreSQL arrays or
+// their JSON text, and SQL Server uniqueidentifier bytes into uuid.UUID or string. A
+// field implementing sql.Scanner, decimal.Decimal included, is given the raw value.
+
+var (
+	rowMappingScannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+	rowMappingTimeType    = reflect.TypeOf(time.Time{})
+	rowMappingUUIDType    = reflect.TypeOf(uuid.UUID{})
+
+	rowMappingStructFields sync.Map // reflect.Type -> map[string][]int
+)
+
+// MapRow maps one row into a new T, which must be a struct. A column with no matching
+// field is an error, so a renamed column or a typo in a tag does not silently drop data.
+func MapRow[T any](dbType base.DXDatabaseType, row utils.JSON) (r T, err error) {
+	err = mapRowInto(dbType, row, reflect.ValueOf(&r).Elem())
+	return r, err
+}
+
+// MapRows maps every row into a new T; see MapRow.
+func MapRows[T any](dbType base.DXDatabaseType, rows []utils.JSON) (r []T, err error) {
+	r = make([]T, len(rows))
+	for i, row := range rows {
+		if err = mapRowInto(dbType, row, reflect.ValueOf(&r[i]).Elem()); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+func mapRowInto(dbType base.DXDatabaseType, row utils.JSON, dest reflect.Value) error {
+	if dest.Kind() != reflect.Struct {
+		return errors.Errorf("ROW_MAPPING_DESTINATION_NOT_STRUCT:%s", dest.Type())
+	}
+	fields := rowMappingFieldsOf(dest.Type())
+	for column, value := range row {
+		index, ok := fields[strings.ToLower(column)]
+		if !ok {
+			return errors.Errorf("ROW_MAPPING_UNMAPPED_COLUMN:%s:%s", dest.Type(), column)
+		}
+		field := dest.FieldByIndex(index)
+		if err := convertRowValue(dbType, value, field); err != nil {
+			return errors.Wrapf(err, "ROW_MAPPING_CONVERSION_ERROR:%s.%s:%T", dest.Type(), column, value)
+		}
+	}
+	return nil
+}
+
+// rowMappingFieldsOf returns the field index of each lower-cased column name of t.
+func rowMappingFieldsOf(t reflect.Type) map[string][]int {
+	if cached, ok := rowMappingStructFields.Load(t); ok {
+		return cached.(map[string][]int)
+	}
+	fields := map[string][]int{}
+	var walk func(t reflect.Type, prefix []int)
+	walk = func(t reflect.Type, prefix []int) {
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			tag := f.Tag.Get("db")
+			if tag == "-" {
+				continue
+			}
+			index := append(append([]int{}, prefix...), i)
+			if f.Anonymous && tag == "" && f.Type.Kind() == reflect.Struct {
+				walk(f.Type, index)
+				continue
+			}
+			if !f.IsExported() {
+				continue
+			}
+			name, _, _ := strings.Cut(tag, ",")
+			if name == "" {
+				name = f.Name
+			}
+			name = strings.ToLower(name)
+			// An outer field wins over a promoted one, as in Go
+			if _, exists := fields[name]; !exists || len(index) < len(fields[name]) {
+				fields[name] = index
+			}
+		}
+	}
+	walk(t, nil)
+	rowMappingStructFields.Store(t, fields)
+	return fields
+}
+
+func convertRowValue(dbType base.DXDatabaseType, value any, dest reflect.Value) error {
+	// uuid.UUID is a sql.Scanner too, but knows nothing of SQL Server's byte order
+	if dest.Type() == rowMappingUUIDType && value != nil {
+		u, err := rowValueToUUID(dbType, value)
+		if err != nil {
+			return err
+		}
+		dest.Set(reflect.ValueOf(u))
+		return nil
+	}
+	if dest.CanAddr() && dest.Addr().Type().Implements(rowMappingScannerType) {
+		return dest.Addr().Interface().(sql.Scanner).Scan(value)
+	}
+	if value == nil {
+		dest.SetZero()
+		return nil
+	}
+	if dest.Kind() == reflect.Pointer {
+		p := reflect.New(dest.Type().Elem())
+		if err := convertRowValue(dbType, value, p.Elem()); err != nil {
+			return err
+		}
+		dest.Set(p)
+		return nil
+	}
+	if v := reflect.ValueOf(value); v.Type().AssignableTo(dest.Type()) {
+		dest.Set(v)
+		return nil
+	}
+
+	if dest.Type() == rowMappingTimeType {
+		t, err := rowValueToTime(value)
+		if err != nil {
+			return err
+		}
+		dest.Set(reflect.ValueOf(t))
+		return nil
+	}
+
+	switch dest.Kind() {
+	case reflect.String:
+		dest.SetString(rowValueToString(dbType, value))
+	case reflect.Bool:
+		b, err := rowValueToBool(value)
+		if err != nil {
+			return err
+		}
+		dest.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := rowValueToInt64(value)
+		if err != nil {
+			return err
+		}
+		if dest.OverflowInt(n) {
+			return errors.Errorf("ROW_MAPPING_INT_OVERFLOW:%d:%s", n, dest.Type())
+		}
+		dest.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := rowValueToInt64(value)
+		if err != nil {
+			return err
+		}
+		if n < 0 || dest.OverflowUint(uint64(n)) {
+			return errors.Errorf("ROW_MAPPING_INT_OVERFLOW:%d:%s", n, dest.Type())
+		}
+		dest.SetUint(uint64(n))
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(rowValueToString(dbType, value), 64)
+		if err != nil {
+			return err
+		}
+		dest.SetFloat(f)
+	case reflect.Slice, reflect.Map, reflect.Struct, reflect.Array:
+		if dest.Kind() == reflect.Slice && dest.Type().Elem().Kind() == reflect.Uint8 {
+			if s, ok := value.(string); ok {
+				dest.SetBytes([]byte(s))
+				return nil
+			}
+		}
+		return rowValueToJSONTarget(value, dest)
+	default:
+		return errors.Errorf("ROW_MAPPING_UNSUPPORTED_FIELD_TYPE:%s", dest.Type())
+	}
+	return nil
+}
+
+func rowValueToString(dbType base.DXDatabaseType, value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case []byte:
+		if dbType == base.DXDatabaseTypeSQLServer && len(v) == 16 {
+			if u, err := rowValueToUUID(dbType, v); err == nil {
+				return u.String()
+			}
+		}
+		return string(v)
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func rowValueToInt64(value any) (int64, error) {
+	switch v := value.(type) {
+	case int64:
+		return v, nil
+	case int:
+		return int64(v), nil
+	case int32:
+		return int64(v), nil
+	case int16:
+		return int64(v), nil
+	case int8:
+		return int64(v), nil
+	case uint8:
+		return int64(v), nil
+	case uint16:
+		return int64(v), nil
+	case uint32:
+		return int64(v), nil
+	case uint64:
+		if v > math.MaxInt64 {
+			return 0, errors.Errorf("ROW_MAPPING_INT_OVERFLOW:%d", v)
+		}
+		return int64(v), nil
+	case float64:
+		if v != math.Trunc(v) {
+			return 0, errors.Errorf("ROW_MAPPING_NOT_AN_INTEGER:%v", v)
+		}
+		return int64(v), nil
+	case bool:
+		if v {
+			return 1, nil
+		}
+		return 0, nil
+	case decimal.Decimal:
+		if !v.IsInteger() {
+			return 0, errors.Errorf("ROW_MAPPING_NOT_AN_INTEGER:%s", v)
+		}
+		return v.IntPart(), nil
+	case string, []byte:
+		s := strings.TrimSpace(rowValueToString(base.UnknownDatabaseType, v))
+		if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return n, nil
+		}
+		// Oracle NUMBER may come back as "12.0" or "1E+3"
+		d, err := decimal.NewFromString(s)
+		if err != nil || !d.IsInteger() {
+			return 0, errors.Errorf("ROW_MAPPING_NOT_AN_INTEGER:%s", s)
+		}
+		return d.IntPart(), nil
+	default:
+		return 0, errors.Errorf("ROW_MAPPING_NOT_AN_INTEGER:%T", value)
+	}
+}
+
+func rowValueToBool(value any) (bool, error) {
+	switch v := value.(type) {
+	case bool:
+		return v, nil
+	case string, []byte:
+		switch strings.ToUpper(strings.TrimSpace(rowValueToString(base.UnknownDatabaseType, v))) {
+		case "1", "T", "TRUE", "Y", "YES":
+			return true, nil
+		case "0", "F", "FALSE", "N", "NO", "":
+			return false, nil
+		}
+		return false, errors.Errorf("ROW_MAPPING_NOT_A_BOOLEAN:%v", v)
+	default:
+		n, err := rowValueToInt64(value)
+		if err != nil {
+			return false, errors.Errorf("ROW_MAPPING_NOT_A_BOOLEAN:%v", v)
+		}
+		return n != 0, nil
+	}
+}
+
+var rowMappingTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999-07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02",
+}
+
+func rowValueToTime(value any) (time.Time, error) {
+	switch v := value.(type) {
+	case time.Time:
+		return v, nil
+	case string, []byte:
+		s := strings.TrimSpace(rowValueToString(base.UnknownDatabaseType, v))
+		for _, layout := range rowMappingTimeLayouts {
+			if t, err := time.Parse(layout, s); err == nil {
+				return t, nil
+			}
+		}
+		return time.Time{}, errors.Errorf("ROW_MAPPING_NOT_A_TIME:%s", s)
+	default:
+		return time.Time{}, errors.Errorf("ROW_MAPPING_NOT_A_TIME:%T", value)
+	}
+}
+
+// rowValueToUUID also reads SQL Server uniqueidentifier bytes, whose first three
+// groups are little-endian.
+func rowValueToUUID(dbType base.DXDatabaseType, value any) (uuid.UUID, error) {
+	switch v := value.(type) {
+	case [16]byte:
+		return v, nil
+	case []byte:
+		if len(v) != 16 {
+			return uuid.ParseBytes(v)
+		}
+		var u uuid.UUID
+		copy(u[:], v)
+		if dbType == base.DXDatabaseTypeSQLServer {
+			u[0], u[1], u[2], u[3] = v[3], v[2], v[1], v[0]
+			u[4], u[5] = v[5], v[4]
+			u[6], u[7] = v[7], v[6]
+		}
+		return u, nil
+	case string:
+		return uuid.Parse(v)
+	default:
+		return uuid.Nil, errors.Errorf("ROW_MAPPING_NOT_A_UUID:%T", value)
+	}
+}
+
+// rowValueToJSONTarget fills a slice, map or struct field from a JSON column, a
+// PostgreSQL array (already a Go slice, or its "{1,2}" text form) or a nested value.
+func rowValueToJSONTarget(value any, dest reflect.Value) error {
+	var data []byte
+	switch v := value.(type) {
+	case string:
+		data = []byte(v)
+	case []byte:
+		data = v
+	case json.RawMessage:
+		data = v
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		data = b
+	}
+	if s := strings.TrimSpace(string(data)); dest.Kind() == reflect.Slice && strings.HasPrefix(s, "{") && strings.HasSuffix(s, "}") {
+		// PostgreSQL array text: only simple (unquoted, unnested) elements are supported
+		inner := s[1 : len(s)-1]
+		if strings.ContainsAny(inner, `"{`) {
+			return errors.Errorf("ROW_MAPPING_UNSUPPORTED_ARRAY_TEXT:%s", s)
+		}
+		var parts []string
+		if inner != "" {
+			parts = strings.Split(inner, ",")
+		}
+		result := reflect.MakeSlice(dest.Type(), len(parts), len(parts))
+		for i, part := range parts {
+			var element any = part
+			if part == "NULL" {
+				element = nil
+			}
+			if err := convertRowValue(base.DXDatabaseTypePostgreSQL, element, result.Index(i)); err != nil {
+				return err
+			}
+		}
+		dest.Set(result)
+		return nil
+	}
+	return json.Unmarshal(data, dest.Addr().Interface())
+}
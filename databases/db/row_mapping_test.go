@@ -0,0 +1,65 @@
+package db
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/donnyhardyanto/dxlib/base"
+	"github.com/donnyhardyanto/dxlib/utils"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+type rowMappingAudit struct {
+	CreatedAt time.Time `db:"created_at"`
+}
+
+type rowMappingItem struct {
+	rowMappingAudit
+	Id       int64           `db:"id"`
+	Price    decimal.Decimal `db:"price"`
+	Tags     []int64         `db:"tag_ids"`
+	Note     *string         `db:"note"`
+	IsActive bool            `db:"is_active"`
+	Ref      uuid.UUID       `db:"ref"`
+	Internal string          `db:"-"`
+}
+
+func TestMapRowNormalizesDriverTypes(t *testing.T) {
+	ref := uuid.MustParse("6f9619ff-8b86-d011-b42d-00c04fc964ff")
+	// SQL Server returns the first three groups little-endian
+	refBytes := []byte{0xff, 0x19, 0x96, 0x6f, 0x86, 0x8b, 0x11, 0xd0, 0xb4, 0x2d, 0x00, 0xc0, 0x4f, 0xc9, 0x64, 0xff}
+
+	item, err := MapRow[rowMappingItem](base.DXDatabaseTypeSQLServer, utils.JSON{
+		"ID":         "42",
+		"price":      []byte("1234.50"),
+		"tag_ids":    "{1,2,3}",
+		"note":       nil,
+		"is_active":  int64(1),
+		"ref":        refBytes,
+		"created_at": "2024-05-01 10:20:30",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if item.Id != 42 || !item.Price.Equal(decimal.RequireFromString("1234.5")) || len(item.Tags) != 3 || item.Tags[2] != 3 ||
+		item.Note != nil || !item.IsActive || item.Ref != ref || item.CreatedAt.Hour() != 10 {
+		t.Fatalf("mapped = %+v", item)
+	}
+
+	items, err := MapRows[rowMappingItem](base.DXDatabaseTypePostgreSQL, []utils.JSON{{"tag_ids": []any{int64(7)}, "note": "n"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items[0].Tags) != 1 || items[0].Tags[0] != 7 || *items[0].Note != "n" {
+		t.Fatalf("mapped = %+v", items[0])
+	}
+}
+
+func TestMapRowUnmappedColumn(t *testing.T) {
+	_, err := MapRow[rowMappingItem](base.DXDatabaseTypePostgreSQL, utils.JSON{"id": int64(1), "internal": "x"})
+	if err == nil || !strings.Contains(err.Error(), "ROW_MAPPING_UNMAPPED_COLUMN") || !strings.Contains(err.Error(), "internal") {
+		t.Fatalf("err = %v", err)
+	}
+}
@@ -0,0 +1,118 @@
+package db
+
+import (
+	"context"
+	"iter"
+
+	"github.com/donnyhardyanto/dxlib/errors"
+	"github.com/donnyhardyanto/dxlib/utils"
+	"github.com/jmoiron/sqlx"
+)
+
+// DXDatabaseRowsSeq yields rows one at a time instead of materialising a []utils.JSON.
+// A scan error is yielded once, with a nil row, and ends the sequence.
+type DXDatabaseRowsSeq = iter.Seq2[utils.JSON, error]
+
+// RowsSeq yields the rows of an open result set, deformatted like RawQueryRows. The
+// result set is closed when the loop ends, including on break; onDone, when not nil, is
+// called then with the error (if any) and the number of rows yielded.
+//
+// A sequence that is never ranged over keeps its connection busy: range over it, or
+// close the rows yourself.
+func RowsSeq(rows *sqlx.Rows, driverName string, fieldTypeMapping DXDatabaseTableFieldTypeMapping, onDone func(err error, count int64)) DXDatabaseRowsSeq {
+	return func(yield func(utils.JSON, error) bool) {
+		var err error
+		var count int64
+		defer func() {
+			_ = rows.Close()
+			if onDone != nil {
+				onDone(err, count)
+			}
+		}()
+		for rows.Next() {
+			rowJSON := make(utils.JSON)
+			if err = rows.MapScan(rowJSON); err != nil {
+				err = errors.Wrap(err, "failed to scan row")
+				yield(nil, err)
+				return
+			}
+			if rowJSON, err = DeformatKeys(rowJSON, driverName, fieldTypeMapping); err != nil {
+				err = errors.Wrap(err, "failed to deformat keys")
+				yield(nil, err)
+				return
+			}
+			count++
+			if !yield(rowJSON, nil) {
+				return
+			}
+		}
+		if err = rows.Err(); err != nil {
+			err = errors.Wrap(err, "failed to iterate rows")
+			yield(nil, err)
+		}
+	}
+}
+
+// BatchSeq groups the rows of seq into slices of at most size rows. The next batch is
+// only read once the consumer asks for it, so memory stays bounded by size.
+func BatchSeq(seq DXDatabaseRowsSeq, size int) iter.Seq2[[]utils.JSON, error] {
+	if size <= 0 {
+		size = 1
+	}
+	return func(yield func([]utils.JSON, error) bool) {
+		batch := make([]utils.JSON, 0, size)
+		for row, err := range seq {
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			batch = append(batch, row)
+			if len(batch) == size {
+				if !yield(batch, nil) {
+					return
+				}
+				batch = make([]utils.JSON, 0, size)
+			}
+		}
+		if len(batch) > 0 {
+			yield(batch, nil)
+		}
+	}
+}
+
+// RawQueryRowsSeq is RawQueryRows yielding the rows as they are read. The query runs
+// before RawQueryRowsSeq returns, so query errors and the columns are known up front.
+func RawQueryRowsSeq(ctx context.Context, db *sqlx.DB, fieldTypeMapping DXDatabaseTableFieldTypeMapping, query string, arg []any) (rowsInfo *DXDatabaseTableRowsInfo, r DXDatabaseRowsSeq, err error) {
+	ctx, endOtel := DbStatementStart(ctx, db, "db.SELECT", query, arg, 3)
+	rows, err := db.QueryxContext(ctx, query, arg...)
+	if err != nil {
+		endOtel(err, -1)
+		return nil, nil, errors.Wrapf(err, "DB_QUERY_ERROR sql=%s", query)
+	}
+	rowsInfo = &DXDatabaseTableRowsInfo{}
+	rowsInfo.Columns, err = rows.Columns()
+	if err != nil {
+		_ = rows.Close()
+		endOtel(err, -1)
+		return nil, nil, errors.Wrap(err, "failed to get columns")
+	}
+	return rowsInfo, RowsSeq(rows, db.DriverName(), fieldTypeMapping, endOtel), nil
+}
+
+// RawTxQueryRowsSeq is RawTxQueryRows yielding the rows as they are read.
+func RawTxQueryRowsSeq(ctx context.Context, tx *sqlx.Tx, fieldTypeMapping DXDatabaseTableFieldTypeMapping, query string, arg []any) (rowsInfo *DXDatabaseTableRowsInfo, r DXDatabaseRowsSeq, err error) {
+	ctx, endOtel := DbStatementStart(ctx, tx, "db.TX_SELECT", query, arg, 3)
+	rows, err := tx.QueryxContext(ctx, query, arg...)
+	if err != nil {
+		endOtel(err, -1)
+		return nil, nil, errors.Wrapf(err, "DB_TX_QUERY_ERROR sql=%s", query)
+	}
+	rowsInfo = &DXDatabaseTableRowsInfo{}
+	rowsInfo.Columns, err = rows.Columns()
+	if err != nil {
+		_ = rows.Close()
+		endOtel(err, -1)
+		return nil, nil, errors.Wrap(err, "failed to get columns")
+	}
+	return rowsInfo, RowsSeq(rows, tx.DriverName(), fieldTypeMapping, endOtel), nil
+}
@@ -203,8 +203,8 @@ func SQLPartConstructSelect(driverName string, tableName string, fieldNames []st
 	// the ROWNUM predicate instead — but ROWNUM filters BEFORE the final ORDER BY
 	// sort, so LIMIT+ORDER BY+FOR UPDATE would silently lock arbitrary rows, not
 	// the first N: that combination and a non-zero OFFSET under lock are rejected
-	// loudly (a "claim oldest N" query needs a hand-written nested claim like the
-	// relay's outboxClaimSQL). Un-ordered LIMIT under lock (e.g. a unique-key
+	// loudly (a "claim N" query needs a hand-written claim that stops reading after
+	// N rows, like the relay's outboxClaimRows). Un-ordered LIMIT under lock (e.g. a unique-key
 	// SelectOne with limit 1) is safe.
 	if driverName == "oracle" && forUpdatePart == true {
 		if offsetAsInt64 > 0 {
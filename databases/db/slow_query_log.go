@@ -0,0 +1,478 @@
+package db
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	"weak"
+
+	"github.com/donnyhardyanto/dxlib/base"
+	"github.com/donnyhardyanto/dxlib/errors"
+	"github.com/donnyhardyanto/dxlib/log"
+	"github.com/donnyhardyanto/dxlib/utils"
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	DXSlowQueryLogDefaultCapacity       = 200
+	DXSlowQueryLogDefaultExplainTimeout = 10 * time.Second
+	dbSlowQueryMaxPlanLen               = 16384
+)
+
+// DXSlowQuerySample is one statement that took longer than the threshold. Args are masked
+// (see slowQueryArgsForLog); Plan is filled in when the asynchronous EXPLAIN finishes.
+type DXSlowQuerySample struct {
+	Id           int64     `json:"id"`
+	At           time.Time `json:"at"`
+	Database     string    `json:"database"`
+	Fingerprint  string    `json:"fingerprint"`
+	SQL          string    `json:"sql"`
+	Args         string    `json:"args"`
+	DurationMs   float64   `json:"duration_ms"`
+	RowsAffected int64     `json:"rows_affected"`
+	Error        string    `json:"error,omitempty"`
+	Function     string    `json:"function,omitempty"`
+	File         string    `json:"file,omitempty"`
+	Line         int       `json:"line,omitempty"`
+	Plan         string    `json:"plan,omitempty"`
+	PlanError    string    `json:"plan_error,omitempty"`
+}
+
+// DXSlowQueryFingerprintStats aggregates the samples in the ring with the same normalised SQL.
+type DXSlowQueryFingerprintStats struct {
+	Fingerprint   string    `json:"fingerprint"`
+	NormalizedSQL string    `json:"normalized_sql"`
+	Count         int       `json:"count"`
+	P50Ms         float64   `json:"p50_ms"`
+	P95Ms         float64   `json:"p95_ms"`
+	MaxMs         float64   `json:"max_ms"`
+	LastAt        time.Time `json:"last_at"`
+}
+
+// DXSlowQueryLog keeps the statements of one database that exceed Threshold in a ring of
+// Capacity samples, and logs them as warnings. With IsExplainEnabled the plan of a slow SELECT
+// is captured in the background on another pooled connection, one EXPLAIN at a time.
+type DXSlowQueryLog struct {
+	DatabaseNameId   string
+	DatabaseType     base.DXDatabaseType
+	Threshold        time.Duration // <= 0 disables the log
+	IsExplainEnabled bool
+	ExplainTimeout   time.Duration // 0 means DXSlowQueryLogDefaultExplainTimeout
+
+	mutex          sync.Mutex
+	samples        []DXSlowQuerySample
+	next           int
+	lastId         int64
+	explainDB      *sqlx.DB
+	isExplainBusy  atomic.Bool
+	normalizedSQLs map[string]string
+}
+
+// NewDXSlowQueryLog creates a slow query log for a database; capacity <= 0 means
+// DXSlowQueryLogDefaultCapacity.
+func NewDXSlowQueryLog(databaseNameId string, databaseType base.DXDatabaseType, threshold time.Duration, capacity int, isExplainEnabled bool) *DXSlowQueryLog {
+	if capacity <= 0 {
+		capacity = DXSlowQueryLogDefaultCapacity
+	}
+	return &DXSlowQueryLog{
+		DatabaseNameId:   databaseNameId,
+		DatabaseType:     databaseType,
+		Threshold:        threshold,
+		IsExplainEnabled: isExplainEnabled,
+		samples:          make([]DXSlowQuerySample, 0, capacity),
+		normalizedSQLs:   map[string]string{},
+	}
+}
+
+// Capacity is the number of samples the ring keeps
+func (sl *DXSlowQueryLog) Capacity() int {
+	return cap(sl.samples)
+}
+
+// The statement functions of this package only see *sqlx.DB / *sqlx.Tx, so the log of a
+// database is found through its connection pool, and through its transactions while they
+// are alive (weak keys: an entry goes away with the transaction).
+var (
+	dbSlowQueryLogs   sync.Map // *sqlx.DB -> *DXSlowQueryLog
+	txSlowQueryLogs   sync.Map // weak.Pointer[sqlx.Tx] -> *DXSlowQueryLog
+	dbSlowQueryLogSet atomic.Bool
+)
+
+// RegisterSlowQueryLog makes the statements run on connection go through sl; a nil sl
+// unregisters. EXPLAINs run on connection as well.
+func RegisterSlowQueryLog(connection *sqlx.DB, sl *DXSlowQueryLog) {
+	if connection == nil {
+		return
+	}
+	if sl == nil {
+		dbSlowQueryLogs.Delete(connection)
+		return
+	}
+	sl.mutex.Lock()
+	sl.explainDB = connection
+	sl.mutex.Unlock()
+	dbSlowQueryLogs.Store(connection, sl)
+	dbSlowQueryLogSet.Store(true)
+}
+
+// RegisterTxSlowQueryLog makes the statements run in tx go through sl (see RegisterSlowQueryLog)
+func RegisterTxSlowQueryLog(tx *sqlx.Tx, sl *DXSlowQueryLog) {
+	if tx == nil || sl == nil {
+		return
+	}
+	key := weak.Make(tx)
+	txSlowQueryLogs.Store(key, sl)
+	runtime.AddCleanup(tx, func(key weak.Pointer[sqlx.Tx]) { txSlowQueryLogs.Delete(key) }, key)
+	dbSlowQueryLogSet.Store(true)
+}
+
+func slowQueryLogOf(executor any) *DXSlowQueryLog {
+	if !dbSlowQueryLogSet.Load() {
+		return nil
+	}
+	var v any
+	var ok bool
+	switch e := executor.(type) {
+	case *sqlx.DB:
+		v, ok = dbSlowQueryLogs.Load(e)
+	case *sqlx.Tx:
+		v, ok = txSlowQueryLogs.Load(weak.Make(e))
+	}
+	if !ok {
+		return nil
+	}
+	sl := v.(*DXSlowQueryLog)
+	if sl.Threshold <= 0 {
+		return nil
+	}
+	return sl
+}
+
+// DbStatementStart is DbOtelStart that also times the statement for the slow query log of
+// executor (*sqlx.DB or *sqlx.Tx). args are the statement arguments ([]any, or a map/struct
+// for named queries), only read when the statement is slow.
+func DbStatementStart(ctx context.Context, executor any, spanName string, query string, args any, callerSkip int) (context.Context, func(err error, rowsAffected int64)) {
+	ctx, endOtel := DbOtelStart(ctx, spanName, query, callerSkip+1)
+	sl := slowQueryLogOf(executor)
+	if sl == nil {
+		return ctx, endOtel
+	}
+	funcName, fileName, lineNo := dbOtelCallerInfo(callerSkip)
+	start := time.Now()
+	return ctx, func(err error, rowsAffected int64) {
+		endOtel(err, rowsAffected)
+		if elapsed := time.Since(start); elapsed >= sl.Threshold {
+			sl.record(DXSlowQuerySample{
+				At:           start,
+				SQL:          dbOtelTruncate(query, dbOtelMaxStatementLen),
+				Args:         slowQueryArgsForLog(args),
+				DurationMs:   float64(elapsed.Microseconds()) / 1000,
+				RowsAffected: rowsAffected,
+				Function:     funcName,
+				File:         fileName,
+				Line:         lineNo,
+			}, err, query, args)
+		}
+	}
+}
+
+// slowQueryArgsForLog formats the arguments of a sample. Positional values carry no column name
+// to judge them by (the named wrappers have already converted their utils.JSON by then), so all
+// of them are masked; only a sql.NamedArg with a non-sensitive name keeps its value.
+func slowQueryArgsForLog(args any) string {
+	switch v := args.(type) {
+	case nil:
+		return "[]"
+	case []any:
+		return formatArgsForLog(maskPositionalArgs(v))
+	case utils.JSON:
+		return formatJSONForLog(v)
+	default:
+		return fmt.Sprintf("(%T)", args)
+	}
+}
+
+func maskPositionalArgs(args []any) []any {
+	masked := maskSensitiveArgs(args)
+	for i, arg := range masked {
+		if _, ok := arg.(sql.NamedArg); !ok {
+			masked[i] = maskedValue
+		}
+	}
+	return masked
+}
+
+func (sl *DXSlowQueryLog) record(sample DXSlowQuerySample, err error, query string, args any) {
+	normalizedSQL := NormalizeSQLForFingerprint(query)
+	sample.Fingerprint = SQLFingerprint(normalizedSQL)
+	sample.Database = sl.DatabaseNameId
+	if err != nil {
+		sample.Error = err.Error()
+	}
+
+	sl.mutex.Lock()
+	sl.lastId++
+	sample.Id = sl.lastId
+	if len(sl.samples) < cap(sl.samples) {
+		sl.samples = append(sl.samples, sample)
+	} else {
+		sl.samples[sl.next] = sample
+	}
+	sl.next = (sl.next + 1) % cap(sl.samples)
+	sl.normalizedSQLs[sample.Fingerprint] = normalizedSQL
+	if len(sl.normalizedSQLs) > 4*cap(sl.samples) {
+		sl.pruneNormalizedSQLs()
+	}
+	explainDB := sl.explainDB
+	sl.mutex.Unlock()
+
+	log.Log.Warnf("DB_SLOW_QUERY:%s:%.1fms rows=%d fingerprint=%s caller=%s (%s:%d) sql=%s args=%s",
+		sl.DatabaseNameId, sample.DurationMs, sample.RowsAffected, sample.Fingerprint, sample.Function, sample.File, sample.Line, sample.SQL, sample.Args)
+
+	if sl.IsExplainEnabled && explainDB != nil && err == nil && dbOtelParseOperation(query) == "SELECT" && sl.isExplainBusy.CompareAndSwap(false, true) {
+		go func() {
+			defer sl.isExplainBusy.Store(false)
+			sl.explain(explainDB, sample.Id, query, args)
+		}()
+	}
+}
+
+// pruneNormalizedSQLs keeps the normalised SQL of the fingerprints still in the ring; mutex held
+func (sl *DXSlowQueryLog) pruneNormalizedSQLs() {
+	kept := map[string]string{}
+	for _, s := range sl.samples {
+		kept[s.Fingerprint] = sl.normalizedSQLs[s.Fingerprint]
+	}
+	sl.normalizedSQLs = kept
+}
+
+func (sl *DXSlowQueryLog) explain(explainDB *sqlx.DB, id int64, query string, args any) {
+	timeout := sl.ExplainTimeout
+	if timeout <= 0 {
+		timeout = DXSlowQueryLogDefaultExplainTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	plan, err := explainStatement(ctx, explainDB, sl.DatabaseType, query, args)
+	if len(plan) > dbSlowQueryMaxPlanLen {
+		plan = plan[:dbSlowQueryMaxPlanLen] + "..."
+	}
+	sl.mutex.Lock()
+	for i := range sl.samples {
+		if sl.samples[i].Id == id {
+			sl.samples[i].Plan = plan
+			if err != nil {
+				sl.samples[i].PlanError = err.Error()
+			}
+		}
+	}
+	sl.mutex.Unlock()
+	if err != nil {
+		log.Log.Warnf("DB_SLOW_QUERY_EXPLAIN_ERROR:%s:%d:%+v", sl.DatabaseNameId, id, err)
+		return
+	}
+	log.Log.Infof("DB_SLOW_QUERY_PLAN:%s:%d\n%s", sl.DatabaseNameId, id, plan)
+}
+
+// explainStatement returns the estimated plan of a SELECT without running it, on a connection of
+// its own: EXPLAIN (PostgreSQL, MariaDB), EXPLAIN QUERY PLAN (SQLite), SHOWPLAN_TEXT (SQL Server)
+// and EXPLAIN PLAN + DBMS_XPLAN (Oracle, without bind values).
+func explainStatement(ctx context.Context, explainDB *sqlx.DB, dbType base.DXDatabaseType, query string, args any) (plan string, err error) {
+	positionalArgs, query, err := explainPositionalArgs(explainDB, query, args)
+	if err != nil {
+		return "", err
+	}
+	conn, err := explainDB.Connx(ctx)
+	if err != nil {
+		return "", errors.Wrap(err, "SLOW_QUERY_EXPLAIN_CONNECTION_ERROR")
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	switch dbType {
+	case base.DXDatabaseTypePostgreSQL, base.DXDatabaseTypePostgresSQLV2:
+		return explainRows(ctx, conn, "EXPLAIN "+query, positionalArgs)
+	case base.DXDatabaseTypeMariaDB:
+		return explainRows(ctx, conn, "EXPLAIN "+query, positionalArgs)
+	case base.DXDatabaseTypeSQLite:
+		return explainRows(ctx, conn, "EXPLAIN QUERY PLAN "+query, positionalArgs)
+	case base.DXDatabaseTypeSQLServer:
+		if _, err = conn.ExecContext(ctx, "SET SHOWPLAN_TEXT ON"); err != nil {
+			return "", errors.Wrap(err, "SLOW_QUERY_EXPLAIN_SHOWPLAN_ON_ERROR")
+		}
+		defer func() {
+			if _, errOff := conn.ExecContext(context.Background(), "SET SHOWPLAN_TEXT OFF"); errOff != nil {
+				// Never give a connection that only returns plans back to the pool
+				_ = conn.Raw(func(any) error { return driver.ErrBadConn })
+			}
+		}()
+		return explainRows(ctx, conn, query, positionalArgs)
+	case base.DXDatabaseTypeOracle:
+		statementId := fmt.Sprintf("dxlib_%d", time.Now().UnixNano())
+		if _, err = conn.ExecContext(ctx, "EXPLAIN PLAN SET STATEMENT_ID = '"+statementId+"' FOR "+query); err != nil {
+			return "", errors.Wrap(err, "SLOW_QUERY_EXPLAIN_PLAN_ERROR")
+		}
+		defer func() {
+			_, _ = conn.ExecContext(context.Background(), "DELETE FROM PLAN_TABLE WHERE STATEMENT_ID = '"+statementId+"'")
+		}()
+		return explainRows(ctx, conn, "SELECT PLAN_TABLE_OUTPUT FROM TABLE(DBMS_XPLAN.DISPLAY('PLAN_TABLE', '"+statementId+"', 'TYPICAL'))", nil)
+	default:
+		return "", errors.Errorf("SLOW_QUERY_EXPLAIN_NOT_SUPPORTED:%s", dbType.String())
+	}
+}
+
+// explainPositionalArgs turns named args into the positional args of the rebound query
+func explainPositionalArgs(explainDB *sqlx.DB, query string, args any) ([]any, string, error) {
+	switch v := args.(type) {
+	case nil:
+		return nil, query, nil
+	case []any:
+		return v, query, nil
+	default:
+		namedQuery, positionalArgs, err := sqlx.Named(query, args)
+		if err != nil {
+			return nil, "", errors.Wrap(err, "SLOW_QUERY_EXPLAIN_NAMED_ARGS_ERROR")
+		}
+		return positionalArgs, explainDB.Rebind(namedQuery), nil
+	}
+}
+
+// explainRows reads every row of a plan query as one line
+func explainRows(ctx context.Context, conn *sqlx.Conn, query string, args []any) (string, error) {
+	rows, err := conn.QueryxContext(ctx, query, args...)
+	if err != nil {
+		return "", errors.Wrap(err, "SLOW_QUERY_EXPLAIN_ERROR")
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+	columns, err := rows.Columns()
+	if err != nil {
+		return "", errors.Wrap(err, "SLOW_QUERY_EXPLAIN_COLUMNS_ERROR")
+	}
+	var lines []string
+	for rows.Next() {
+		values, err := rows.SliceScan()
+		if err != nil {
+			return "", errors.Wrap(err, "SLOW_QUERY_EXPLAIN_SCAN_ERROR")
+		}
+		if len(values) == 1 {
+			lines = append(lines, explainValueString(values[0]))
+			continue
+		}
+		parts := make([]string, len(values))
+		for i, value := range values {
+			parts[i] = columns[i] + "=" + explainValueString(value)
+		}
+		lines = append(lines, strings.Join(parts, " "))
+	}
+	return strings.Join(lines, "\n"), rows.Err()
+}
+
+func explainValueString(v any) string {
+	switch t := v.(type) {
+	case nil:
+		return "NULL"
+	case []byte:
+		return string(t)
+	default:
+		return fmt.Sprint(t)
+	}
+}
+
+var (
+	sqlFingerprintStringRegexp      = regexp.MustCompile(`'(?:[^']|'')*'`)
+	sqlFingerprintPlaceholderRegexp = regexp.MustCompile(`\$\d+|@p\d+|\?`)
+	sqlFingerprintNamedRegexp       = regexp.MustCompile(`(^|[^:]):[A-Za-z_]\w*`)
+	sqlFingerprintNumberRegexp      = regexp.MustCompile(`\b\d+(?:\.\d+)?\b`)
+	sqlFingerprintListRegexp        = regexp.MustCompile(`\(\s*\?(?:\s*,\s*\?)+\s*\)`)
+	sqlFingerprintSpaceRegexp       = regexp.MustCompile(`\s+`)
+)
+
+// NormalizeSQLForFingerprint replaces literals and placeholders with ?, collapses value lists
+// to (?...) and whitespace to one space, so statements differing only in values match.
+func NormalizeSQLForFingerprint(query string) string {
+	s := sqlFingerprintStringRegexp.ReplaceAllString(query, "?")
+	s = sqlFingerprintPlaceholderRegexp.ReplaceAllString(s, "?")
+	s = sqlFingerprintNamedRegexp.ReplaceAllString(s, "$1?")
+	s = sqlFingerprintNumberRegexp.ReplaceAllString(s, "?")
+	s = sqlFingerprintListRegexp.ReplaceAllString(s, "(?...)")
+	return strings.TrimSpace(sqlFingerprintSpaceRegexp.ReplaceAllString(s, " "))
+}
+
+// SQLFingerprint identifies a normalised statement (hex of its SHA-256, 16 bytes)
+func SQLFingerprint(normalizedSQL string) string {
+	hash := sha256.Sum256([]byte(strings.ToLower(normalizedSQL)))
+	return hex.EncodeToString(hash[:16])
+}
+
+// Samples returns the samples in the ring, newest first
+func (sl *DXSlowQueryLog) Samples() []DXSlowQuerySample {
+	sl.mutex.Lock()
+	defer sl.mutex.Unlock()
+	samples := make([]DXSlowQuerySample, len(sl.samples))
+	copy(samples, sl.samples)
+	sort.Slice(samples, func(i, j int) bool { return samples[i].Id > samples[j].Id })
+	return samples
+}
+
+// FingerprintStats aggregates the samples in the ring by fingerprint, slowest p95 first
+func (sl *DXSlowQueryLog) FingerprintStats() []DXSlowQueryFingerprintStats {
+	samples := sl.Samples()
+	sl.mutex.Lock()
+	normalizedSQLs := make(map[string]string, len(sl.normalizedSQLs))
+	for k, v := range sl.normalizedSQLs {
+		normalizedSQLs[k] = v
+	}
+	sl.mutex.Unlock()
+
+	durations := map[string][]float64{}
+	stats := map[string]*DXSlowQueryFingerprintStats{}
+	for _, s := range samples {
+		st, ok := stats[s.Fingerprint]
+		if !ok {
+			st = &DXSlowQueryFingerprintStats{Fingerprint: s.Fingerprint, NormalizedSQL: normalizedSQLs[s.Fingerprint], LastAt: s.At}
+			stats[s.Fingerprint] = st
+		}
+		st.Count++
+		durations[s.Fingerprint] = append(durations[s.Fingerprint], s.DurationMs)
+	}
+	r := make([]DXSlowQueryFingerprintStats, 0, len(stats))
+	for fingerprint, st := range stats {
+		d := durations[fingerprint]
+		sort.Float64s(d)
+		st.P50Ms = percentile(d, 0.50)
+		st.P95Ms = percentile(d, 0.95)
+		st.MaxMs = d[len(d)-1]
+		r = append(r, *st)
+	}
+	sort.Slice(r, func(i, j int) bool {
+		if r[i].P95Ms != r[j].P95Ms {
+			return r[i].P95Ms > r[j].P95Ms
+		}
+		return r[i].Fingerprint < r[j].Fingerprint
+	})
+	return r
+}
+
+// percentile is the nearest-rank percentile of sorted values
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+	return sorted[max(rank, 0)]
+}
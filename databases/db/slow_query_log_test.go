@@ -0,0 +1,85 @@
+package db
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/donnyhardyanto/dxlib/base"
+	"github.com/donnyhardyanto/dxlib/utils"
+)
+
+func TestSlowQueryLogSQLite(t *testing.T) {
+	ctx := context.Background()
+	db := openSQLiteTestDB(t)
+	sl := NewDXSlowQueryLog("test", base.DXDatabaseTypeSQLite, time.Nanosecond, 3, true)
+	RegisterSlowQueryLog(db, sl)
+	t.Cleanup(func() { RegisterSlowQueryLog(db, nil) })
+
+	for _, code := range []string{"A", "B"} {
+		if _, _, err := QueryRows(ctx, db, nil, `SELECT id FROM "app.items" WHERE code = :code AND name <> 'x'`, utils.JSON{"code": code}); err != nil {
+			t.Fatal(err)
+		}
+		// One EXPLAIN at a time: let the first finish so the second gets one too
+		for deadline := time.Now().Add(5 * time.Second); sl.isExplainBusy.Load() && time.Now().Before(deadline); {
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	samples := sl.Samples()
+	if len(samples) != 2 || samples[0].Fingerprint != samples[1].Fingerprint {
+		t.Fatalf("samples = %+v", samples)
+	}
+	if !strings.Contains(samples[0].Plan, "USING INDEX") || samples[0].Function == "" {
+		t.Fatalf("sample = %+v", samples[0])
+	}
+	stats := sl.FingerprintStats()
+	if len(stats) != 1 || stats[0].Count != 2 || stats[0].NormalizedSQL != `SELECT id FROM "app.items" WHERE code = ? AND name <> ?` {
+		t.Fatalf("stats = %+v", stats)
+	}
+
+	for range 3 {
+		if _, err := Exec(ctx, db, `DELETE FROM "app.items" WHERE id IN (1, 2, 3)`, utils.JSON{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if samples = sl.Samples(); len(samples) != 3 || samples[0].Id != 5 || samples[2].Id != 3 {
+		t.Fatalf("the ring must keep the 3 newest samples: %+v", samples)
+	}
+	if got := NormalizeSQLForFingerprint("SELECT x::text FROM t WHERE id IN ($1, $2) AND n = 10"); got != "SELECT x::text FROM t WHERE id IN (?...) AND n = ?" {
+		t.Fatalf("normalized = %s", got)
+	}
+}
+
+func TestSlowQueryLogMasksPositionalArgs(t *testing.T) {
+	ctx := context.Background()
+	db := openSQLiteTestDB(t)
+	if _, err := db.Exec(`CREATE TABLE app_users (id INTEGER PRIMARY KEY, password TEXT)`); err != nil {
+		t.Fatal(err)
+	}
+	sl := NewDXSlowQueryLog("test", base.DXDatabaseTypeSQLite, time.Nanosecond, 10, false)
+	RegisterSlowQueryLog(db, sl)
+	t.Cleanup(func() { RegisterSlowQueryLog(db, nil) })
+
+	const password = "hunter2-do-not-log"
+	if _, err := Exec(ctx, db, `INSERT INTO app_users (id, password) VALUES (:id, :password)`, utils.JSON{"id": 1, "password": password}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := RawExec(ctx, db, `UPDATE app_users SET password = ? WHERE id = ?`, []any{password, 1}); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := RawQueryRows(ctx, db, nil, `SELECT id FROM app_users WHERE password = ?`, []any{password}); err != nil {
+		t.Fatal(err)
+	}
+
+	samples := sl.Samples()
+	if len(samples) != 3 {
+		t.Fatalf("samples = %+v", samples)
+	}
+	for _, s := range samples {
+		if strings.Contains(s.Args, password) || !strings.Contains(s.Args, maskedValue) {
+			t.Errorf("the password must be masked: %s %s", s.SQL, s.Args)
+		}
+	}
+}
@@ -15,6 +15,7 @@ var (
 		base.DXDatabaseTypeMariaDB:    regexp.MustCompile("^[a-zA-Z0-9_$]+$"),
 		base.DXDatabaseTypeSQLServer:  regexp.MustCompile("^[a-zA-Z@#_][a-zA-Z0-9@#_$]*$"),
 		base.DXDatabaseTypeOracle:     regexp.MustCompile("^[a-zA-Z][a-zA-Z0-9_$#]*$"),
+		base.DXDatabaseTypeSQLite:     regexp.MustCompile("^[a-zA-Z_][a-zA-Z0-9_$]*$"),
 	}
 
 	// QuoteCharacters defines the start and end quote characters for different databases
@@ -40,6 +41,10 @@ var (
 			Start: []rune{'"'},
 			End:   []rune{'"'},
 		},
+		base.DXDatabaseTypeSQLite: {
+			Start: []rune{'"'},
+			End:   []rune{'"'},
+		},
 	}
 
 	// BUG-BE-174 FIX: pre-compiled at init instead of per-call
@@ -70,6 +75,8 @@ var (
 		base.DXDatabaseTypeSQLServer:  128,
 		base.DXDatabaseTypeOracle:     128,
 		base.DXDatabaseTypeMariaDB:    64,
+		// SQLite has no limit of its own; keep names portable to the other engines
+		base.DXDatabaseTypeSQLite: 128,
 	}
 )
 
@@ -89,6 +96,8 @@ func isReservedKeyword(dialect base.DXDatabaseType, word string) bool {
 		c = sqlServerKeywords
 	case base.DXDatabaseTypeOracle:
 		c = oracleKeywords
+	case base.DXDatabaseTypeSQLite:
+		c = sqliteKeywords
 	default:
 		panic("unhandled default case")
 	}
@@ -115,6 +124,8 @@ func isKeywordCanBeUseAsFieldNameDirectly(dialect base.DXDatabaseType, word stri
 		c = sqlServerKeywordsCanBeUsedAsFieldNameDirectly
 	case base.DXDatabaseTypeOracle:
 		c = oracleKeywordsCanBeUsedAsFieldNameDirectly
+	case base.DXDatabaseTypeSQLite:
+		c = sqliteKeywordsCanBeUsedAsFieldNameDirectly
 	default:
 		panic("unhandled default case")
 	}
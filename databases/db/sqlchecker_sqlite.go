@@ -0,0 +1,121 @@
+package db
+
+var (
+	// sqliteKeywords is the SQLite keyword list (https://sqlite.org/lang_keywords.html)
+	sqliteKeywords = map[string]bool{
+		"ABORT": true, "ACTION": true, "ADD": true, "AFTER": true,
+		"ALL": true, "ALTER": true, "ALWAYS": true, "ANALYZE": true,
+		"AND": true, "AS": true, "ASC": true, "ATTACH": true,
+		"AUTOINCREMENT": true, "BEFORE": true, "BEGIN": true, "BETWEEN": true,
+		"BY": true, "CASCADE": true, "CASE": true, "CAST": true,
+		"CHECK": true, "COLLATE": true, "COLUMN": true, "COMMIT": true,
+		"CONFLICT": true, "CONSTRAINT": true, "CREATE": true, "CROSS": true,
+		"CURRENT": true, "CURRENT_DATE": true, "CURRENT_TIME": true, "CURRENT_TIMESTAMP": true,
+		"DATABASE": true, "DEFAULT": true, "DEFERRABLE": true, "DEFERRED": true,
+		"DELETE": true, "DESC": true, "DETACH": true, "DISTINCT": true,
+		"DO": true, "DROP": true, "EACH": true, "ELSE": true,
+		"END": true, "ESCAPE": true, "EXCEPT": true, "EXCLUDE": true,
+		"EXCLUSIVE": true, "EXISTS": true, "EXPLAIN": true, "FAIL": true,
+		"FILTER": true, "FIRST": true, "FOLLOWING": true, "FOR": true,
+		"FOREIGN": true, "FROM": true, "FULL": true, "GENERATED": true,
+		"GLOB": true, "GROUP": true, "GROUPS": true, "HAVING": true,
+		"IF": true, "IGNORE": true, "IMMEDIATE": true, "IN": true,
+		"INDEX": true, "INDEXED": true, "INITIALLY": true, "INNER": true,
+		"INSERT": true, "INSTEAD": true, "INTERSECT": true, "INTO": true,
+		"IS": true, "ISNULL": true, "JOIN": true, "KEY": true,
+		"LAST": true, "LEFT": true, "LIKE": true, "LIMIT": true,
+		"MATCH": true, "MATERIALIZED": true, "NATURAL": true, "NO": true,
+		"NOT": true, "NOTHING": true, "NOTNULL": true, "NULL": true,
+		"NULLS": true, "OF": true, "OFFSET": true, "ON": true,
+		"OR": true, "ORDER": true, "OTHERS": true, "OUTER": true,
+		"OVER": true, "PARTITION": true, "PLAN": true, "PRAGMA": true,
+		"PRECEDING": true, "PRIMARY": true, "QUERY": true, "RAISE": true,
+		"RANGE": true, "RECURSIVE": true, "REFERENCES": true, "REGEXP": true,
+		"REINDEX": true, "RELEASE": true, "RENAME": true, "REPLACE": true,
+		"RESTRICT": true, "RETURNING": true, "RIGHT": true, "ROLLBACK": true,
+		"ROW": true, "ROWS": true, "SAVEPOINT": true, "SELECT": true,
+		"SET": true, "TABLE": true, "TEMP": true, "TEMPORARY": true,
+		"THEN": true, "TIES": true, "TO": true, "TRANSACTION": true,
+		"TRIGGER": true, "UNBOUNDED": true, "UNION": true, "UNIQUE": true,
+		"UPDATE": true, "USING": true, "VACUUM": true, "VALUES": true,
+		"VIEW": true, "VIRTUAL": true, "WHEN": true, "WHERE": true,
+		"WINDOW": true, "WITH": true, "WITHOUT": true,
+	}
+
+	// sqliteKeywordsCanBeUsedAsFieldNameDirectly contains the SQLite keywords the parser
+	// falls back to treating as plain identifiers (the %fallback ID list in parse.y), so
+	// they can be used as column/table names without double quotes.
+	sqliteKeywordsCanBeUsedAsFieldNameDirectly = map[string]bool{
+		"ABORT":        true,
+		"ACTION":       true,
+		"AFTER":        true,
+		"ALWAYS":       true,
+		"ANALYZE":      true,
+		"ASC":          true,
+		"ATTACH":       true,
+		"BEFORE":       true,
+		"BEGIN":        true,
+		"BY":           true,
+		"CASCADE":      true,
+		"CAST":         true,
+		"COLUMN":       true,
+		"CONFLICT":     true,
+		"CURRENT":      true,
+		"DATABASE":     true,
+		"DEFERRED":     true,
+		"DESC":         true,
+		"DETACH":       true,
+		"DO":           true,
+		"EACH":         true,
+		"END":          true,
+		"EXCLUDE":      true,
+		"EXCLUSIVE":    true,
+		"EXPLAIN":      true,
+		"FAIL":         true,
+		"FIRST":        true,
+		"FOLLOWING":    true,
+		"FOR":          true,
+		"GENERATED":    true,
+		"GROUPS":       true,
+		"IF":           true,
+		"IGNORE":       true,
+		"IMMEDIATE":    true,
+		"INITIALLY":    true,
+		"INSTEAD":      true,
+		"KEY":          true,
+		"LAST":         true,
+		"MATCH":        true,
+		"MATERIALIZED": true,
+		"NO":           true,
+		"NULLS":        true,
+		"OF":           true,
+		"OFFSET":       true,
+		"OTHERS":       true,
+		"PARTITION":    true,
+		"PLAN":         true,
+		"PRAGMA":       true,
+		"PRECEDING":    true,
+		"QUERY":        true,
+		"RAISE":        true,
+		"RANGE":        true,
+		"RECURSIVE":    true,
+		"REINDEX":      true,
+		"RELEASE":      true,
+		"RENAME":       true,
+		"REPLACE":      true,
+		"RESTRICT":     true,
+		"ROLLBACK":     true,
+		"ROW":          true,
+		"ROWS":         true,
+		"SAVEPOINT":    true,
+		"TEMP":         true,
+		"TIES":         true,
+		"TRIGGER":      true,
+		"UNBOUNDED":    true,
+		"VACUUM":       true,
+		"VIEW":         true,
+		"VIRTUAL":      true,
+		"WITH":         true,
+		"WITHOUT":      true,
+	}
+)
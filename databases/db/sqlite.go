@@ -0,0 +1,117 @@
+package db
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/sha256"
+	"database/sql/driver"
+	"fmt"
+
+	"github.com/donnyhardyanto/dxlib/errors"
+	dxAes "github.com/donnyhardyanto/dxlib/utils/crypto/aes"
+	"modernc.org/sqlite"
+)
+
+// SQLite has no crypto functions and no session variables. The encryption expressions
+// emitted for it call the Go functions registered below on the pure-Go driver, so the
+// encryption runs application-side, in this process; session keys live in a TEMP table
+// created on every new connection.
+const (
+	SQLiteSessionContextTable = "dx_session_context"
+
+	SQLiteFunctionEncrypt = "dx_encrypt"
+	SQLiteFunctionDecrypt = "dx_decrypt"
+	SQLiteFunctionSHA256  = "dx_sha256"
+)
+
+func init() {
+	// Encryption uses a random IV, so it must not be deterministic
+	sqlite.MustRegisterScalarFunction(SQLiteFunctionEncrypt, 2, sqliteEncrypt)
+	sqlite.MustRegisterDeterministicScalarFunction(SQLiteFunctionDecrypt, 2, sqliteDecrypt)
+	sqlite.MustRegisterDeterministicScalarFunction(SQLiteFunctionSHA256, 1, sqliteSHA256)
+	sqlite.RegisterConnectionHook(func(conn sqlite.ExecQuerierContext, _ string) error {
+		_, err := conn.ExecContext(context.Background(),
+			fmt.Sprintf("CREATE TEMP TABLE IF NOT EXISTS %s (key TEXT PRIMARY KEY, value TEXT)", SQLiteSessionContextTable), nil)
+		return err
+	})
+}
+
+// SQLiteSetSessionKeySQL is the statement storing a session key (first arg) and its
+// value (second arg) for the current connection.
+var SQLiteSetSessionKeySQL = fmt.Sprintf("INSERT INTO temp.%s (key, value) VALUES (?, ?) ON CONFLICT (key) DO UPDATE SET value = excluded.value", SQLiteSessionContextTable)
+
+// sqliteBytes returns a TEXT or BLOB argument as bytes; ok is false for NULL.
+func sqliteBytes(v driver.Value) (b []byte, ok bool, err error) {
+	switch v := v.(type) {
+	case nil:
+		return nil, false, nil
+	case []byte:
+		return v, true, nil
+	case string:
+		return []byte(v), true, nil
+	case int64, float64, bool:
+		return []byte(fmt.Sprint(v)), true, nil
+	default:
+		return nil, false, errors.Errorf("SQLITE_FUNCTION_UNSUPPORTED_ARGUMENT_TYPE:%T", v)
+	}
+}
+
+// sqliteCipherKey derives the AES-256 key from a session key passphrase.
+func sqliteCipherKey(v driver.Value) ([]byte, error) {
+	passphrase, ok, err := sqliteBytes(v)
+	if err != nil {
+		return nil, err
+	}
+	if !ok || len(passphrase) == 0 {
+		return nil, errors.New("SQLITE_ENCRYPTION_KEY_NOT_SET")
+	}
+	key := sha256.Sum256(passphrase)
+	return key[:], nil
+}
+
+// sqliteEncrypt is dx_encrypt(plaintext, passphrase): AES-256-CBC with a random IV.
+func sqliteEncrypt(_ *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+	data, ok, err := sqliteBytes(args[0])
+	if err != nil || !ok {
+		return nil, err
+	}
+	key, err := sqliteCipherKey(args[1])
+	if err != nil {
+		return nil, err
+	}
+	return dxAes.EncryptAES(key, data)
+}
+
+// sqliteDecrypt is dx_decrypt(ciphertext, passphrase), returning TEXT.
+func sqliteDecrypt(_ *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+	data, ok, err := sqliteBytes(args[0])
+	if err != nil || !ok {
+		return nil, err
+	}
+	key, err := sqliteCipherKey(args[1])
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < 2*aes.BlockSize || len(data)%aes.BlockSize != 0 {
+		return nil, errors.New("SQLITE_DECRYPT_INVALID_CIPHERTEXT")
+	}
+	plain, err := dxAes.DecryptAES(key, data)
+	if err != nil {
+		return nil, err
+	}
+	plain, err = dxAes.RemovePad(plain)
+	if err != nil {
+		return nil, errors.Wrap(err, "SQLITE_DECRYPT_WRONG_KEY_OR_CORRUPT")
+	}
+	return string(plain), nil
+}
+
+// sqliteSHA256 is dx_sha256(value), returning the BLOB digest like PostgreSQL's digest().
+func sqliteSHA256(_ *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+	data, ok, err := sqliteBytes(args[0])
+	if err != nil || !ok {
+		return nil, err
+	}
+	sum := sha256.Sum256(data)
+	return sum[:], nil
+}
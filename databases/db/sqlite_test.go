@@ -0,0 +1,102 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/donnyhardyanto/dxlib/base"
+	"github.com/donnyhardyanto/dxlib/utils"
+	"github.com/jmoiron/sqlx"
+)
+
+func openSQLiteTestDB(t *testing.T) *sqlx.DB {
+	t.Helper()
+	db, err := sqlx.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// One connection: every pooled ":memory:" connection is its own database
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = db.Close() })
+	_, err = db.Exec(`CREATE TABLE "app.items" (id INTEGER PRIMARY KEY AUTOINCREMENT, code TEXT NOT NULL UNIQUE, name TEXT, secret BLOB)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func TestSQLiteInsertUpsertCountDelete(t *testing.T) {
+	ctx := context.Background()
+	db := openSQLiteTestDB(t)
+
+	_, returning, err := Insert(ctx, db, "app.items", utils.JSON{"code": "A", "name": "first"}, []string{"id"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(returning["id"]) != "1" {
+		t.Fatalf("RETURNING id = %v, want 1", returning["id"])
+	}
+
+	_, id, isInsert, err := Upsert(ctx, db, "app.items", utils.JSON{"code": "A", "name": "renamed"}, utils.JSON{"name": "renamed"}, utils.JSON{"code": "A"}, "id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != 1 || isInsert {
+		t.Fatalf("upsert of existing row: id=%d isInsert=%t", id, isInsert)
+	}
+	_, id, isInsert, err = Upsert(ctx, db, "app.items", utils.JSON{"code": "B", "name": "second"}, utils.JSON{"name": "second"}, utils.JSON{"code": "B"}, "id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != 2 || !isInsert {
+		t.Fatalf("upsert of new row: id=%d isInsert=%t", id, isInsert)
+	}
+
+	_, rows, err := Select(ctx, db, "app.items", nil, []string{"id", "name"}, utils.JSON{"code": "A"}, nil, nil, nil, nil, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 1 || rows[0]["name"] != "renamed" {
+		t.Fatalf("select = %v", rows)
+	}
+
+	if _, _, err = Delete(ctx, db, "app.items", utils.JSON{"code": "A"}, nil); err != nil {
+		t.Fatal(err)
+	}
+	count, err := Count(ctx, db, "app.items", "", nil, nil, nil, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 1 {
+		t.Fatalf("count = %d, want 1", count)
+	}
+}
+
+func TestSQLiteApplicationSideEncryption(t *testing.T) {
+	db := openSQLiteTestDB(t)
+	if _, err := db.Exec(SQLiteSetSessionKeySQL, "app.key", "passphrase"); err != nil {
+		t.Fatal(err)
+	}
+	dbType := base.DXDatabaseTypeSQLite
+	_, err := db.Exec(fmt.Sprintf(`INSERT INTO "app.items" (code, secret) VALUES ('A', %s(?, %s))`,
+		SQLiteFunctionEncrypt, SessionKeyExpression(dbType, "app.key")), "top secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var plain string
+	if err = db.Get(&plain, `SELECT `+DecryptExpression(dbType, "secret", "app.key")+` FROM "app.items"`); err != nil {
+		t.Fatal(err)
+	}
+	if plain != "top secret" {
+		t.Fatalf("decrypted = %q", plain)
+	}
+	var stored []byte
+	if err = db.Get(&stored, `SELECT secret FROM "app.items"`); err != nil {
+		t.Fatal(err)
+	}
+	if string(stored) == "top secret" {
+		t.Fatal("secret stored in plain text")
+	}
+}
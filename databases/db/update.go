@@ -161,8 +161,8 @@ func Update(ctx context.Context, db *sqlx.DB, tableName string, setFieldNameValu
 
 	// Handle databases-specific UPDATE with RETURNING
 	switch driverName {
-	case "postgres", "mariadb":
-		// PostgreSQL and MariaDB support RETURNING clause
+	case "postgres", "mariadb", "sqlite":
+		// PostgreSQL, MariaDB and SQLite (3.35+) support RETURNING clause
 		baseSQL := strings.Join([]string{
 			"UPDATE",
 			tableName,
@@ -364,8 +364,8 @@ func TxUpdate(ctx context.Context, tx *sqlx.Tx, tableName string, setFieldValues
 
 	// Handle databases-specific UPDATE with RETURNING
 	switch driverName {
-	case "postgres", "mariadb":
-		// PostgreSQL and MariaDB support RETURNING clause
+	case "postgres", "mariadb", "sqlite":
+		// PostgreSQL, MariaDB and SQLite (3.35+) support RETURNING clause
 		baseSQL := strings.Join([]string{
 			"UPDATE",
 			tableName,
@@ -22,17 +22,20 @@ import (
 //   - MariaDB / MySQL            : INSERT ... ON DUPLICATE KEY UPDATE ..., id = LAST_INSERT_ID(id)
 //   - SQL Server                 : MERGE ... WITH (HOLDLOCK) ... OUTPUT inserted.id, $action
 //   - Oracle                     : PL/SQL block - try INSERT ... EXCEPTION WHEN DUP_VAL_ON_INDEX THEN UPDATE
+//   - SQLite                     : UPDATE ... RETURNING id, else INSERT ... ON CONFLICT DO NOTHING, else UPDATE again
 //
 // PRECONDITION: whereKeys columns MUST have a UNIQUE (or PRIMARY KEY) constraint.
 //   - PostgreSQL / SQLite : missing constraint -> statement rejected with clear error
 //   - SQL Server / Oracle : missing constraint -> caller responsibility; MERGE syntax still runs
 //   - MariaDB / MySQL     : missing constraint -> SILENT duplicate insert (DB-level foot-gun)
 //
-// Affects exactly ONE row per call (never multi-row). Single SQL statement, single round-trip.
+// Affects exactly ONE row per call (never multi-row). Single SQL statement, single round-trip
+// (SQLite: up to three statements, see above).
 // No SELECT-then-INSERT-or-UPDATE race window.
 //
 // Returns:
-//   - sql.Result : non-nil for MariaDB and Oracle paths; nil for PG / MSSQL paths (which use QueryRows)
+//   - sql.Result : non-nil for MariaDB and Oracle paths; nil for PG / MSSQL / SQLite paths (which use
+//     QueryRows) — use id and isInsert there, never result.RowsAffected()
 //   - id         : newly-inserted row id OR existing row id on update
 //   - isInsert   : true if a new row was inserted, false if an existing row was updated
 //
@@ -77,6 +80,8 @@ func Upsert(
 		return execUpsertSQLServer(ctx, db, nil, tableName, insertCols, updateCols, whereCols, mergedArgs, idField)
 	case base.DXDatabaseTypeOracle:
 		return execUpsertOracle(ctx, db, nil, tableName, insertCols, updateCols, whereCols, mergedArgs, idField)
+	case base.DXDatabaseTypeSQLite:
+		return execUpsertSQLite(ctx, db, nil, tableName, insertCols, updateCols, whereCols, mergedArgs, idField)
 	default:
 		return nil, 0, false, errors.Errorf("unsupported databases driver: %s", db.DriverName())
 	}
@@ -118,6 +123,8 @@ func TxUpsert(
 		return execUpsertSQLServer(ctx, nil, tx, tableName, insertCols, updateCols, whereCols, mergedArgs, idField)
 	case base.DXDatabaseTypeOracle:
 		return execUpsertOracle(ctx, nil, tx, tableName, insertCols, updateCols, whereCols, mergedArgs, idField)
+	case base.DXDatabaseTypeSQLite:
+		return execUpsertSQLite(ctx, nil, tx, tableName, insertCols, updateCols, whereCols, mergedArgs, idField)
 	default:
 		return nil, 0, false, errors.Errorf("unsupported databases driver: %s", tx.DriverName())
 	}
@@ -461,3 +468,81 @@ func execUpsertOracle(
 	isInsert := outAction == 1
 	return result, outID, isInsert, nil
 }
+
+// ---------- SQLite ----------
+
+// SQLite has no way to tell from one ON CONFLICT ... DO UPDATE statement whether the row
+// was inserted, so the update is tried first and, when no row matched, the insert with
+// DO NOTHING. An insert losing a race to another writer returns no row and the update
+// is retried. Updating first also keeps existing rows from burning AUTOINCREMENT ids.
+func buildUpsertSQLiteSQL(tableName string, insertCols, updateCols, whereCols []string, idField string) (insertSQL string, updateSQL string) {
+	valueRefs := make([]string, len(insertCols))
+	for i, c := range insertCols {
+		valueRefs[i] = ":" + c
+	}
+	updateAssigns := make([]string, len(updateCols))
+	for i, c := range updateCols {
+		updateAssigns[i] = fmt.Sprintf("%s = :%s", c, c)
+	}
+	whereConds := make([]string, len(whereCols))
+	for i, c := range whereCols {
+		whereConds[i] = fmt.Sprintf("%s = :%s", c, c)
+	}
+	insertSQL = fmt.Sprintf(
+		`INSERT INTO %s (%s) VALUES (%s) ON CONFLICT (%s) DO NOTHING RETURNING %s`,
+		tableName,
+		strings.Join(insertCols, ", "),
+		strings.Join(valueRefs, ", "),
+		strings.Join(whereCols, ", "),
+		idField,
+	)
+	updateSQL = fmt.Sprintf(
+		`UPDATE %s SET %s WHERE %s RETURNING %s`,
+		tableName,
+		strings.Join(updateAssigns, ", "),
+		strings.Join(whereConds, " AND "),
+		idField,
+	)
+	return insertSQL, updateSQL
+}
+
+func execUpsertSQLite(
+	ctx context.Context,
+	db *sqlx.DB, tx *sqlx.Tx,
+	tableName string, insertCols, updateCols, whereCols []string,
+	args utils.JSON, idField string,
+) (sql.Result, int64, bool, error) {
+	insertSQL, updateSQL := buildUpsertSQLiteSQL(tableName, insertCols, updateCols, whereCols, idField)
+
+	queryRows := func(sqlStmt string) ([]utils.JSON, error) {
+		if tx != nil {
+			_, rows, err := TxQueryRows(ctx, tx, nil, sqlStmt, args)
+			return rows, err
+		}
+		_, rows, err := QueryRows(ctx, db, nil, sqlStmt, args)
+		return rows, err
+	}
+
+	isInsert := false
+	rows, err := queryRows(updateSQL)
+	if err == nil && len(rows) == 0 {
+		isInsert = true
+		rows, err = queryRows(insertSQL)
+		if err == nil && len(rows) == 0 {
+			isInsert = false
+			rows, err = queryRows(updateSQL)
+		}
+	}
+	if err != nil {
+		return nil, 0, false, errors.Wrap(err, "sqlite upsert failed")
+	}
+	if len(rows) == 0 {
+		return nil, 0, false, errors.New("sqlite upsert returned no rows")
+	}
+
+	id, err := utilsJson.GetInt64(rows[0], idField)
+	if err != nil {
+		return nil, 0, false, errors.Wrapf(err, "sqlite upsert failed to read %s", idField)
+	}
+	return nil, id, isInsert, nil
+}
@@ -26,15 +26,22 @@ import (
 // behavior change). MariaDB has NO schema layer: the "schema" is virtual, carried
 // as a SINGLE quoted identifier `schema.table` (the dot is part of the table
 // name), so a dotted name is collapsed into one backtick-quoted identifier.
-// Idempotent (already-backtick-quoted or bare names pass through).
+// SQLite has no schemas either and gets the same treatment with double quotes.
+// Idempotent (already-quoted or bare names pass through).
 func QualifyTableNameForExec(dbType base.DXDatabaseType, tableName string) string {
-	if dbType != base.DXDatabaseTypeMariaDB {
+	quote := ""
+	switch dbType {
+	case base.DXDatabaseTypeMariaDB:
+		quote = "`"
+	case base.DXDatabaseTypeSQLite:
+		quote = `"`
+	default:
 		return tableName
 	}
-	if tableName == "" || strings.HasPrefix(tableName, "`") || !strings.Contains(tableName, ".") {
+	if tableName == "" || strings.HasPrefix(tableName, quote) || !strings.Contains(tableName, ".") {
 		return tableName
 	}
-	return "`" + strings.ReplaceAll(tableName, "`", "``") + "`"
+	return quote + strings.ReplaceAll(tableName, quote, quote+quote) + quote
 }
 
 // DbDriverFormatIdentifier formats an identifier (column/table name) according to databases requirements
@@ -53,9 +60,10 @@ func DbDriverFormatIdentifier(driverName string, identifier string) string {
 	case "db2", "sqlserver", "mariadb":
 		// Use uppercase for all case-insensitive databases for consistency
 		return strings.ToUpper(identifier)
-	case "postgres":
-		// PostgreSQL is case-sensitive but folds unquoted identifiers to lowercase
-		return identifier // Keep as-is for PostgreSQL
+	case "postgres", "sqlite":
+		// PostgreSQL is case-sensitive but folds unquoted identifiers to lowercase;
+		// SQLite matches identifiers case-insensitively
+		return identifier // Keep as-is for PostgreSQL and SQLite
 	default:
 		return identifier
 	}
@@ -197,7 +205,7 @@ func DbDriverFormatOrderByFieldName(driverName string, fieldName string, directi
 	}
 
 	switch driverName {
-	case "oracle", "postgres", "mariadb", "db2":
+	case "oracle", "postgres", "mariadb", "sqlite", "db2":
 		// These databases either support NULLS FIRST/LAST syntax directly
 		// or just need standard ORDER BY syntax
 		return fieldName + " " + validDirection, nil
@@ -431,6 +439,17 @@ func DBDriverGenerateLimitOffsetClause(driverName string, limitAsInt64, offsetAs
 		// Always include OFFSET clause
 		effectiveLimitOffsetClause += " offset " + strconv.FormatInt(offsetAsInt64, 10)
 
+	case "sqlite":
+		// SQLite needs a LIMIT before OFFSET; a negative limit means no limit
+		if hasLimit && limitAsInt64 > 0 {
+			effectiveLimitOffsetClause = " limit " + strconv.FormatInt(limitAsInt64, 10)
+		} else {
+			effectiveLimitOffsetClause = " limit -1"
+		}
+
+		// Always include OFFSET clause
+		effectiveLimitOffsetClause += " offset " + strconv.FormatInt(offsetAsInt64, 10)
+
 	default:
 		return "", "", errors.New("UNKNOWN_DATABASE_TYPE:" + driverName)
 	}
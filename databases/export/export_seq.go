@@ -0,0 +1,164 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"iter"
+
+	"github.com/donnyhardyanto/dxlib/databases/db"
+	"github.com/donnyhardyanto/dxlib/errors"
+	"github.com/donnyhardyanto/dxlib/language"
+	"github.com/donnyhardyanto/dxlib/utils"
+	"github.com/xuri/excelize/v2"
+)
+
+const XLSX ExportFormat = "xlsx"
+
+// exportSeqFlushRows is how often CSV output is flushed to the writer.
+const exportSeqFlushRows = 1000
+
+// ContentType returns the MIME type written by ExportSeqToWriter for format.
+func ContentType(format ExportFormat) (string, error) {
+	switch format {
+	case CSV:
+		return "text/csv", nil
+	case XLS, XLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", nil
+	default:
+		return "", errors.Errorf("unsupported export format: %s", format)
+	}
+}
+
+// ExportSeqToWriter writes the rows of a sequence to w as they are yielded, so an
+// export never holds the whole result set. CSV is flushed to w every
+// exportSeqFlushRows rows (and w.Flush is called when w has one, e.g. an
+// http.ResponseWriter). XLSX rows go through excelize's StreamWriter, which spills to
+// a temporary file, and the workbook is written to w once the sequence ends: an error
+// from the sequence then leaves w untouched.
+func ExportSeqToWriter(w io.Writer, rowsInfo *db.DXDatabaseTableRowsInfo, rows iter.Seq2[utils.JSON, error], opts ExportOptions) (count int64, err error) {
+	if opts.DateFormat == "" {
+		opts.DateFormat = "2006-01-02 15:04:05"
+	}
+	switch opts.Format {
+	case CSV:
+		return exportSeqToCSV(w, rowsInfo, rows, opts)
+	case XLS, XLSX:
+		return exportSeqToXLSX(w, rowsInfo, rows, opts)
+	default:
+		return 0, errors.Errorf("unsupported export format: %s", opts.Format)
+	}
+}
+
+func exportSeqToCSV(w io.Writer, rowsInfo *db.DXDatabaseTableRowsInfo, rows iter.Seq2[utils.JSON, error], opts ExportOptions) (count int64, err error) {
+	writer := csv.NewWriter(w)
+	flusher, _ := w.(interface{ Flush() })
+	flush := func() error {
+		writer.Flush()
+		if err := writer.Error(); err != nil {
+			return errors.Errorf("failed to write CSV: %+v", err)
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+		return nil
+	}
+
+	loc := resolveTimezone(opts.Timezone)
+
+	headers := make([]string, len(rowsInfo.Columns))
+	for i, col := range rowsInfo.Columns {
+		headers[i] = language.Translate(col, opts.Language, opts.TranslateFallback)
+	}
+	if err := writer.Write(headers); err != nil {
+		return 0, errors.Errorf("failed to write CSV headers: %+v", err)
+	}
+
+	record := make([]string, len(rowsInfo.Columns))
+	for row, err := range rows {
+		if err != nil {
+			_ = flush()
+			return count, err
+		}
+		for i, col := range rowsInfo.Columns {
+			record[i] = formatValue(row[col], opts.DateFormat, loc)
+		}
+		if err := writer.Write(record); err != nil {
+			return count, errors.Errorf("failed to write CSV record: %+v", err)
+		}
+		count++
+		if count%exportSeqFlushRows == 0 {
+			if err := flush(); err != nil {
+				return count, err
+			}
+		}
+	}
+	return count, flush()
+}
+
+func exportSeqToXLSX(w io.Writer, rowsInfo *db.DXDatabaseTableRowsInfo, rows iter.Seq2[utils.JSON, error], opts ExportOptions) (count int64, err error) {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	sheetName := opts.SheetName
+	if sheetName == "" {
+		sheetName = "Sheet1"
+	}
+	if sheetName != "Sheet1" {
+		if err := f.SetSheetName("Sheet1", sheetName); err != nil {
+			return 0, errors.Errorf("failed to name sheet: %+v", err)
+		}
+	}
+	sw, err := f.NewStreamWriter(sheetName)
+	if err != nil {
+		return 0, errors.Errorf("failed to create Excel stream writer: %+v", err)
+	}
+	if len(rowsInfo.Columns) > 0 {
+		if err := sw.SetColWidth(1, len(rowsInfo.Columns), 15); err != nil {
+			return 0, errors.Errorf("failed to set column width: %+v", err)
+		}
+	}
+
+	loc := resolveTimezone(opts.Timezone)
+
+	headers := make([]any, len(rowsInfo.Columns))
+	for i, col := range rowsInfo.Columns {
+		headers[i] = language.Translate(col, opts.Language, opts.TranslateFallback)
+	}
+	var headerOpts []excelize.RowOpts
+	style, err := f.NewStyle(&excelize.Style{
+		Font:      &excelize.Font{Bold: true},
+		Alignment: &excelize.Alignment{Horizontal: "center"},
+		Fill:      excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{"#E0EBF5"}},
+	})
+	if err == nil {
+		headerOpts = append(headerOpts, excelize.RowOpts{StyleID: style})
+	}
+	if err := sw.SetRow("A1", headers, headerOpts...); err != nil {
+		return 0, errors.Errorf("failed to write header: %+v", err)
+	}
+
+	values := make([]any, len(rowsInfo.Columns))
+	for row, err := range rows {
+		if err != nil {
+			return count, err
+		}
+		for i, col := range rowsInfo.Columns {
+			values[i] = formatValue(row[col], opts.DateFormat, loc)
+		}
+		cellName, err := excelize.CoordinatesToCellName(1, int(count)+2)
+		if err != nil {
+			return count, errors.Errorf("invalid cell coordinates: %+v", err)
+		}
+		if err := sw.SetRow(cellName, values); err != nil {
+			return count, errors.Errorf("failed to write row: %+v", err)
+		}
+		count++
+	}
+	if err := sw.Flush(); err != nil {
+		return count, errors.Errorf("failed to flush Excel stream: %+v", err)
+	}
+	if _, err := f.WriteTo(w); err != nil {
+		return count, errors.Errorf("failed to write Excel: %+v", err)
+	}
+	return count, nil
+}
@@ -0,0 +1,52 @@
+package export
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/donnyhardyanto/dxlib/databases/db"
+	"github.com/donnyhardyanto/dxlib/errors"
+	"github.com/donnyhardyanto/dxlib/utils"
+	"github.com/xuri/excelize/v2"
+)
+
+func TestExportSeqToWriter(t *testing.T) {
+	rowsInfo := &db.DXDatabaseTableRowsInfo{Columns: []string{"code", "name"}}
+	rows := func(yield func(utils.JSON, error) bool) {
+		for _, row := range []utils.JSON{{"code": 1, "name": "a,b"}, {"code": 2, "name": nil}} {
+			if !yield(row, nil) {
+				return
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	count, err := ExportSeqToWriter(&buf, rowsInfo, rows, ExportOptions{Format: CSV})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "code,name\n1,\"a,b\"\n2,\n"; count != 2 || buf.String() != want {
+		t.Errorf("csv = %q (%d rows), want %q", buf.String(), count, want)
+	}
+
+	buf.Reset()
+	if _, err = ExportSeqToWriter(&buf, rowsInfo, rows, ExportOptions{Format: XLSX}); err != nil {
+		t.Fatal(err)
+	}
+	f, err := excelize.OpenReader(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if v, _ := f.GetCellValue("Sheet1", "B2"); v != "a,b" {
+		t.Errorf("B2 = %q, want a,b", v)
+	}
+
+	failing := func(yield func(utils.JSON, error) bool) {
+		yield(nil, errors.New("boom"))
+	}
+	buf.Reset()
+	if _, err = ExportSeqToWriter(&buf, rowsInfo, failing, ExportOptions{Format: XLSX}); err == nil || buf.Len() != 0 {
+		t.Errorf("err = %v, %d bytes written; want the sequence error and no output", err, buf.Len())
+	}
+}
@@ -2,6 +2,7 @@ package models
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"strings"
 
@@ -44,12 +45,20 @@ func parseOracleKey(key string) (namespace string, attribute string) {
 	return
 }
 
+// SQLite has no session variables: databases/db keeps them in a TEMP table it creates on
+// every connection.
+const (
+	sqliteSetSessionConfigSQL = "INSERT INTO temp.dx_session_context (key, value) VALUES (?, ?) ON CONFLICT (key) DO UPDATE SET value = excluded.value"
+	sqliteGetSessionConfigSQL = "SELECT value FROM temp.dx_session_context WHERE key = ?"
+)
+
 // BuildSetSessionConfigSQL generates a parameterized SQL query to set a session-level configuration variable.
 // Returns the SQL string with placeholders and the corresponding args slice.
 // PostgreSQL: SELECT set_config($1, $2, false)
 // SQL Server: EXEC sp_set_session_context @key = @p1, @value = @p2
 // Oracle: BEGIN DBMS_SESSION.SET_CONTEXT(:1, :2, :3); END;
 // MariaDB/MySQL: SET @varName = ? (varName is derived from validated key, not user data)
+// SQLite: INSERT INTO temp.dx_session_context ... (the TEMP table databases/db creates per connection)
 func BuildSetSessionConfigSQL(dbType base.DXDatabaseType, key string, value string) (string, []any) {
 	switch dbType {
 	case base.DXDatabaseTypePostgreSQL:
@@ -63,6 +72,8 @@ func BuildSetSessionConfigSQL(dbType base.DXDatabaseType, key string, value stri
 		// MySQL/MariaDB user variable names cannot be parameterized; varName is derived from validated key
 		varName := strings.ReplaceAll(key, ".", "_")
 		return fmt.Sprintf("SET @%s = ?", varName), []any{value}
+	case base.DXDatabaseTypeSQLite:
+		return sqliteSetSessionConfigSQL, []any{key, value}
 	default:
 		return "-- Unknown databases type for setting", nil
 	}
@@ -74,6 +85,7 @@ func BuildSetSessionConfigSQL(dbType base.DXDatabaseType, key string, value stri
 // SQL Server: SESSION_CONTEXT(N'key')
 // Oracle: SYS_CONTEXT('APP_CTX', 'key')
 // MariaDB/MySQL: @key
+// SQLite: (SELECT value FROM temp.dx_session_context WHERE key = 'key')
 func BuildGetSessionConfigExpr(dbType base.DXDatabaseType, key string) (string, error) {
 	if err := ValidateSessionConfigKey(key); err != nil {
 		return "", fmt.Errorf("BuildGetSessionConfigExpr: %w", err)
@@ -89,6 +101,8 @@ func BuildGetSessionConfigExpr(dbType base.DXDatabaseType, key string) (string,
 	case base.DXDatabaseTypeMariaDB:
 		varName := strings.ReplaceAll(key, ".", "_")
 		return fmt.Sprintf("@%s", varName), nil
+	case base.DXDatabaseTypeSQLite:
+		return fmt.Sprintf("(SELECT value FROM temp.dx_session_context WHERE key = '%s')", key), nil
 	default:
 		return fmt.Sprintf("'%s'", key), nil
 	}
@@ -100,6 +114,7 @@ func BuildGetSessionConfigExpr(dbType base.DXDatabaseType, key string) (string,
 // SQL Server: SELECT CAST(SESSION_CONTEXT(@p1) AS NVARCHAR(MAX))
 // Oracle: SELECT SYS_CONTEXT(:1, :2) FROM DUAL
 // MariaDB/MySQL: SELECT @varName (varName is derived from validated key, not user data)
+// SQLite: SELECT value FROM temp.dx_session_context WHERE key = ?
 func BuildGetSessionConfigSQL(dbType base.DXDatabaseType, key string) (string, []any) {
 	switch dbType {
 	case base.DXDatabaseTypePostgreSQL:
@@ -113,6 +128,8 @@ func BuildGetSessionConfigSQL(dbType base.DXDatabaseType, key string) (string, [
 		// MySQL/MariaDB user variable names cannot be parameterized; varName is derived from validated key
 		varName := strings.ReplaceAll(key, ".", "_")
 		return fmt.Sprintf("SELECT @%s", varName), nil
+	case base.DXDatabaseTypeSQLite:
+		return sqliteGetSessionConfigSQL, []any{key}
 	default:
 		return "-- Unknown databases type for getting", nil
 	}
@@ -155,6 +172,9 @@ func SetSessionConfig(db *sql.DB, dbType base.DXDatabaseType, key string, value
 		query := fmt.Sprintf("SET @%s = ?", varName)
 		_, err := db.Exec(query, value)
 		return err
+	case base.DXDatabaseTypeSQLite:
+		_, err := db.Exec(sqliteSetSessionConfigSQL, key, value)
+		return err
 	default:
 		return fmt.Errorf("unsupported databases type for SetSessionConfig: %v", dbType)
 	}
@@ -188,6 +208,11 @@ func GetSessionConfig(db *sql.DB, dbType base.DXDatabaseType, key string) (strin
 		varName := strings.ReplaceAll(key, ".", "_")
 		query := fmt.Sprintf("SELECT @%s", varName)
 		err = db.QueryRow(query).Scan(&value)
+	case base.DXDatabaseTypeSQLite:
+		err = db.QueryRow(sqliteGetSessionConfigSQL, key).Scan(&value)
+		if errors.Is(err, sql.ErrNoRows) {
+			err = nil
+		}
 	default:
 		return "", fmt.Errorf("unsupported databases type for GetSessionConfig: %v", dbType)
 	}
@@ -234,6 +259,8 @@ func BuildUIDDefaultExpr(dbType base.DXDatabaseType) string {
 		return types.UIDDefaultExprOracle
 	case base.DXDatabaseTypeMariaDB:
 		return types.UIDDefaultExprMariaDB
+	case base.DXDatabaseTypeSQLite:
+		return types.UIDDefaultExprSQLite
 	default:
 		return types.UIDDefaultExprPostgreSQL
 	}
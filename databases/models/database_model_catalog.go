@@ -0,0 +1,1042 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/donnyhardyanto/dxlib/base"
+)
+
+// ============================================================================
+// ModelDBCatalog - what a live database actually contains, read from its catalog
+// (pg_catalog / information_schema / sys / ALL_* views) for comparison with a ModelDB.
+// Names keep the catalog's case; map keys are lowercase ("schema.name" for relations).
+// ============================================================================
+
+type ModelDBCatalogColumn struct {
+	Name       string
+	Ordinal    int
+	Type       string // as reported by the catalog, e.g. "character varying(255)"
+	IsNullable bool
+	IsIdentity bool   // IDENTITY / AUTO_INCREMENT / serial
+	Default    string // DEFAULT expression as reported by the catalog, "" when none
+}
+
+type ModelDBCatalogIndex struct {
+	Name         string
+	Columns      []string
+	IsUnique     bool
+	IsPrimaryKey bool
+	IsConstraint bool // backs a PRIMARY KEY / UNIQUE / FOREIGN KEY constraint; managed with the table
+}
+
+type ModelDBCatalogForeignKey struct {
+	Name                string
+	Columns             []string
+	ReferencedSchema    string
+	ReferencedTable     string
+	ReferencedColumns   []string
+	OnDelete            string // referential action, e.g. "CASCADE"; "" reads as NO ACTION
+	OnUpdate            string
+	IsDeferrable        bool
+	IsInitiallyDeferred bool
+}
+
+// ModelDBCatalogObject is a relation without further detail (views, materialized views).
+type ModelDBCatalogObject struct {
+	Schema        string
+	Name          string
+	QualifiedName string // quoted for DDL
+	Definition    string // views only: the SELECT the view is defined by
+}
+
+type ModelDBCatalogTable struct {
+	ModelDBCatalogObject
+	Columns  map[string]*ModelDBCatalogColumn
+	Indexes  map[string]*ModelDBCatalogIndex
+	Triggers map[string]string // lowercase -> trigger name
+
+	ForeignKeys      []*ModelDBCatalogForeignKey
+	CheckConstraints map[string]string // lowercase -> constraint name; named CHECK constraints only
+}
+
+type ModelDBCatalog struct {
+	DBType            base.DXDatabaseType
+	Schemas           map[string]bool
+	Tables            map[string]*ModelDBCatalogTable
+	Views             map[string]ModelDBCatalogObject
+	MaterializedViews map[string]ModelDBCatalogObject
+}
+
+func catalogKey(schema, name string) string {
+	return strings.ToLower(schema) + "." + strings.ToLower(name)
+}
+
+func (c *ModelDBCatalog) table(schema, name, qualifiedName string) *ModelDBCatalogTable {
+	key := catalogKey(schema, name)
+	t, ok := c.Tables[key]
+	if !ok {
+		t = &ModelDBCatalogTable{
+			ModelDBCatalogObject: ModelDBCatalogObject{Schema: schema, Name: name, QualifiedName: qualifiedName},
+			Columns:              map[string]*ModelDBCatalogColumn{},
+			Indexes:              map[string]*ModelDBCatalogIndex{},
+			Triggers:             map[string]string{},
+			CheckConstraints:     map[string]string{},
+		}
+		c.Tables[key] = t
+	}
+	return t
+}
+
+// relation names a relation of a real (non-MariaDB) schema.
+func (c *ModelDBCatalog) relation(schema, name string) ModelDBCatalogObject {
+	return ModelDBCatalogObject{Schema: schema, Name: name, QualifiedName: qualifiedTableName(c.DBType, schema, name)}
+}
+
+func (c *ModelDBCatalog) addView(schema, name, definition string) {
+	o := c.relation(schema, name)
+	o.Definition = strings.TrimSuffix(strings.TrimSpace(definition), ";")
+	c.Views[catalogKey(schema, name)] = o
+}
+
+func (c *ModelDBCatalog) namedTable(schema, name string) *ModelDBCatalogTable {
+	return c.table(schema, name, qualifiedTableName(c.DBType, schema, name))
+}
+
+func (t *ModelDBCatalogTable) addIndexColumn(index string, isUnique, isPrimaryKey, isConstraint bool, column string) {
+	idx, ok := t.Indexes[strings.ToLower(index)]
+	if !ok {
+		idx = &ModelDBCatalogIndex{Name: index, IsUnique: isUnique, IsPrimaryKey: isPrimaryKey, IsConstraint: isConstraint || isPrimaryKey}
+		t.Indexes[strings.ToLower(index)] = idx
+	}
+	idx.Columns = append(idx.Columns, strings.ToLower(column))
+}
+
+func (t *ModelDBCatalogTable) addForeignKeyColumn(name, column, referencedSchema, referencedTable, referencedColumn string) *ModelDBCatalogForeignKey {
+	var fk *ModelDBCatalogForeignKey
+	for _, existing := range t.ForeignKeys {
+		if existing.Name == name {
+			fk = existing
+		}
+	}
+	if fk == nil {
+		fk = &ModelDBCatalogForeignKey{Name: name, ReferencedSchema: referencedSchema, ReferencedTable: referencedTable}
+		t.ForeignKeys = append(t.ForeignKeys, fk)
+	}
+	fk.Columns = append(fk.Columns, strings.ToLower(column))
+	fk.ReferencedColumns = append(fk.ReferencedColumns, strings.ToLower(referencedColumn))
+	return fk
+}
+
+// postgreSQLForeignKeyActions maps pg_constraint.confdeltype / confupdtype to the action
+var postgreSQLForeignKeyActions = map[string]string{
+	"a": "NO ACTION",
+	"r": "RESTRICT",
+	"c": "CASCADE",
+	"n": "SET NULL",
+	"d": "SET DEFAULT",
+}
+
+// foreignKey returns the foreign key named name (any case), nil when there is none.
+func (t *ModelDBCatalogTable) foreignKey(name string) *ModelDBCatalogForeignKey {
+	for _, fk := range t.ForeignKeys {
+		if strings.EqualFold(fk.Name, name) {
+			return fk
+		}
+	}
+	return nil
+}
+
+// PrimaryKey returns the primary key columns, nil when the table has none.
+func (t *ModelDBCatalogTable) PrimaryKey() []string {
+	for _, idx := range t.Indexes {
+		if idx.IsPrimaryKey {
+			return idx.Columns
+		}
+	}
+	return nil
+}
+
+// ReadModelDBCatalog reads the tables, columns, indexes, foreign keys, check constraints,
+// triggers, views and materialized views of schemaNames. On MariaDB and SQLite a schema is virtual
+// (a "schema.table" name in the current database, see qualifiedTableName) and is read back the same way.
+func ReadModelDBCatalog(ctx context.Context, db *sql.DB, dbType base.DXDatabaseType, schemaNames []string) (*ModelDBCatalog, error) {
+	c := &ModelDBCatalog{
+		DBType:            dbType,
+		Schemas:           map[string]bool{},
+		Tables:            map[string]*ModelDBCatalogTable{},
+		Views:             map[string]ModelDBCatalogObject{},
+		MaterializedViews: map[string]ModelDBCatalogObject{},
+	}
+	var err error
+	switch dbType {
+	case base.DXDatabaseTypePostgreSQL:
+		for _, schemaName := range schemaNames {
+			if err = c.readPostgreSQL(ctx, db, schemaName); err != nil {
+				return nil, err
+			}
+		}
+	case base.DXDatabaseTypeSQLServer:
+		for _, schemaName := range schemaNames {
+			if err = c.readSQLServer(ctx, db, schemaName); err != nil {
+				return nil, err
+			}
+		}
+	case base.DXDatabaseTypeOracle:
+		for _, schemaName := range schemaNames {
+			if err = c.readOracle(ctx, db, schemaName); err != nil {
+				return nil, err
+			}
+		}
+	case base.DXDatabaseTypeMariaDB:
+		err = c.readMariaDB(ctx, db, schemaNames)
+	case base.DXDatabaseTypeSQLite:
+		err = c.readSQLite(ctx, db, schemaNames)
+	default:
+		return nil, fmt.Errorf("ReadModelDBCatalog: unsupported databases type: %v", dbType)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// catalogQuery runs query and calls scan for every row.
+func catalogQuery(ctx context.Context, db *sql.DB, query string, args []any, scan func(rows *sql.Rows) error) error {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("ReadModelDBCatalog: %w", err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+	for rows.Next() {
+		if err = scan(rows); err != nil {
+			return fmt.Errorf("ReadModelDBCatalog: %w", err)
+		}
+	}
+	return rows.Err()
+}
+
+func (c *ModelDBCatalog) readPostgreSQL(ctx context.Context, db *sql.DB, schemaName string) error {
+	args := []any{schemaName}
+	err := catalogQuery(ctx, db, "SELECT nspname FROM pg_catalog.pg_namespace WHERE nspname = $1", args, func(rows *sql.Rows) error {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return err
+		}
+		c.Schemas[strings.ToLower(name)] = true
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	err = catalogQuery(ctx, db, `SELECT c.relname, a.attname, a.attnum, format_type(a.atttypid, a.atttypmod), a.attnotnull,
+  a.attidentity <> '', COALESCE(pg_get_expr(d.adbin, d.adrelid), '')
+FROM pg_catalog.pg_attribute a
+JOIN pg_catalog.pg_class c ON c.oid = a.attrelid
+JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+LEFT JOIN pg_catalog.pg_attrdef d ON d.adrelid = a.attrelid AND d.adnum = a.attnum
+WHERE c.relkind IN ('r', 'p') AND a.attnum > 0 AND NOT a.attisdropped AND n.nspname = $1`, args, func(rows *sql.Rows) error {
+		var table, column, columnType, columnDefault string
+		var ordinal int
+		var notNull, isIdentity bool
+		if err := rows.Scan(&table, &column, &ordinal, &columnType, &notNull, &isIdentity, &columnDefault); err != nil {
+			return err
+		}
+		// serial columns are a sequence default.
+		if strings.HasPrefix(columnDefault, "nextval(") {
+			isIdentity, columnDefault = true, ""
+		}
+		c.namedTable(schemaName, table).Columns[strings.ToLower(column)] = &ModelDBCatalogColumn{
+			Name: column, Ordinal: ordinal, Type: columnType, IsNullable: !notNull, IsIdentity: isIdentity, Default: columnDefault,
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	err = catalogQuery(ctx, db, `SELECT t.relname, i.relname, ix.indisunique, ix.indisprimary,
+  EXISTS (SELECT 1 FROM pg_catalog.pg_constraint con WHERE con.conindid = ix.indexrelid),
+  pg_get_indexdef(ix.indexrelid, k.n, true)
+FROM pg_catalog.pg_index ix
+JOIN pg_catalog.pg_class i ON i.oid = ix.indexrelid
+JOIN pg_catalog.pg_class t ON t.oid = ix.indrelid
+JOIN pg_catalog.pg_namespace n ON n.oid = t.relnamespace
+CROSS JOIN LATERAL generate_series(1, ix.indnkeyatts) AS k(n)
+WHERE t.relkind IN ('r', 'p') AND n.nspname = $1
+ORDER BY t.relname, i.relname, k.n`, args, func(rows *sql.Rows) error {
+		var table, index, column string
+		var isUnique, isPrimaryKey, isConstraint bool
+		if err := rows.Scan(&table, &index, &isUnique, &isPrimaryKey, &isConstraint, &column); err != nil {
+			return err
+		}
+		c.namedTable(schemaName, table).addIndexColumn(index, isUnique, isPrimaryKey, isConstraint, strings.Trim(column, `"`))
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	err = catalogQuery(ctx, db, `SELECT con.conname, t.relname, a.attname, rn.nspname, rt.relname, ra.attname,
+  con.confdeltype, con.confupdtype, con.condeferrable, con.condeferred
+FROM pg_catalog.pg_constraint con
+JOIN pg_catalog.pg_class t ON t.oid = con.conrelid
+JOIN pg_catalog.pg_namespace n ON n.oid = t.relnamespace
+JOIN pg_catalog.pg_class rt ON rt.oid = con.confrelid
+JOIN pg_catalog.pg_namespace rn ON rn.oid = rt.relnamespace
+CROSS JOIN LATERAL unnest(con.conkey, con.confkey) WITH ORDINALITY AS k(col, refcol, n)
+JOIN pg_catalog.pg_attribute a ON a.attrelid = con.conrelid AND a.attnum = k.col
+JOIN pg_catalog.pg_attribute ra ON ra.attrelid = con.confrelid AND ra.attnum = k.refcol
+WHERE con.contype = 'f' AND n.nspname = $1
+ORDER BY t.relname, con.conname, k.n`, args, func(rows *sql.Rows) error {
+		var name, table, column, referencedSchema, referencedTable, referencedColumn, onDelete, onUpdate string
+		var isDeferrable, isInitiallyDeferred bool
+		if err := rows.Scan(&name, &table, &column, &referencedSchema, &referencedTable, &referencedColumn,
+			&onDelete, &onUpdate, &isDeferrable, &isInitiallyDeferred); err != nil {
+			return err
+		}
+		fk := c.namedTable(schemaName, table).addForeignKeyColumn(name, column, referencedSchema, referencedTable, referencedColumn)
+		fk.OnDelete, fk.OnUpdate = postgreSQLForeignKeyActions[onDelete], postgreSQLForeignKeyActions[onUpdate]
+		fk.IsDeferrable, fk.IsInitiallyDeferred = isDeferrable, isInitiallyDeferred
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	err = catalogQuery(ctx, db, `SELECT t.relname, con.conname
+FROM pg_catalog.pg_constraint con
+JOIN pg_catalog.pg_class t ON t.oid = con.conrelid
+JOIN pg_catalog.pg_namespace n ON n.oid = t.relnamespace
+WHERE con.contype = 'c' AND t.relkind IN ('r', 'p') AND n.nspname = $1`, args, func(rows *sql.Rows) error {
+		var table, constraint string
+		if err := rows.Scan(&table, &constraint); err != nil {
+			return err
+		}
+		c.namedTable(schemaName, table).CheckConstraints[strings.ToLower(constraint)] = constraint
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	err = catalogQuery(ctx, db, `SELECT c.relname, t.tgname
+FROM pg_catalog.pg_trigger t
+JOIN pg_catalog.pg_class c ON c.oid = t.tgrelid
+JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+WHERE NOT t.tgisinternal AND n.nspname = $1`, args, func(rows *sql.Rows) error {
+		var table, trigger string
+		if err := rows.Scan(&table, &trigger); err != nil {
+			return err
+		}
+		c.namedTable(schemaName, table).Triggers[strings.ToLower(trigger)] = trigger
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	err = catalogQuery(ctx, db, "SELECT viewname, definition FROM pg_catalog.pg_views WHERE schemaname = $1", args, func(rows *sql.Rows) error {
+		var view, definition string
+		if err := rows.Scan(&view, &definition); err != nil {
+			return err
+		}
+		c.addView(schemaName, view, definition)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return catalogQuery(ctx, db, "SELECT matviewname FROM pg_catalog.pg_matviews WHERE schemaname = $1", args, func(rows *sql.Rows) error {
+		var view string
+		if err := rows.Scan(&view); err != nil {
+			return err
+		}
+		c.MaterializedViews[catalogKey(schemaName, view)] = c.relation(schemaName, view)
+		return nil
+	})
+}
+
+func (c *ModelDBCatalog) readSQLServer(ctx context.Context, db *sql.DB, schemaName string) error {
+	args := []any{schemaName}
+	err := catalogQuery(ctx, db, "SELECT name FROM sys.schemas WHERE name = @p1", args, func(rows *sql.Rows) error {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return err
+		}
+		c.Schemas[strings.ToLower(name)] = true
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	err = catalogQuery(ctx, db, `SELECT t.name, c.name, c.column_id, ty.name, c.max_length, c.precision, c.scale, c.is_nullable,
+  c.is_identity, COALESCE(OBJECT_DEFINITION(c.default_object_id), '')
+FROM sys.columns c
+JOIN sys.tables t ON t.object_id = c.object_id
+JOIN sys.schemas s ON s.schema_id = t.schema_id
+JOIN sys.types ty ON ty.user_type_id = c.user_type_id
+WHERE s.name = @p1`, args, func(rows *sql.Rows) error {
+		var table, column, typeName, columnDefault string
+		var ordinal, maxLength, precision, scale int
+		var isNullable, isIdentity bool
+		if err := rows.Scan(&table, &column, &ordinal, &typeName, &maxLength, &precision, &scale, &isNullable, &isIdentity, &columnDefault); err != nil {
+			return err
+		}
+		c.namedTable(schemaName, table).Columns[strings.ToLower(column)] = &ModelDBCatalogColumn{
+			Name:       column,
+			Ordinal:    ordinal,
+			Type:       sqlServerColumnType(typeName, maxLength, precision, scale),
+			IsNullable: isNullable,
+			IsIdentity: isIdentity,
+			Default:    columnDefault,
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	err = catalogQuery(ctx, db, `SELECT t.name, i.name, i.is_unique, i.is_primary_key, i.is_unique_constraint, c.name
+FROM sys.indexes i
+JOIN sys.tables t ON t.object_id = i.object_id
+JOIN sys.schemas s ON s.schema_id = t.schema_id
+JOIN sys.index_columns ic ON ic.object_id = i.object_id AND ic.index_id = i.index_id AND ic.is_included_column = 0
+JOIN sys.columns c ON c.object_id = ic.object_id AND c.column_id = ic.column_id
+WHERE i.name IS NOT NULL AND s.name = @p1
+ORDER BY t.name, i.name, ic.key_ordinal`, args, func(rows *sql.Rows) error {
+		var table, index, column string
+		var isUnique, isPrimaryKey, isConstraint bool
+		if err := rows.Scan(&table, &index, &isUnique, &isPrimaryKey, &isConstraint, &column); err != nil {
+			return err
+		}
+		c.namedTable(schemaName, table).addIndexColumn(index, isUnique, isPrimaryKey, isConstraint, column)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	err = catalogQuery(ctx, db, `SELECT fk.name, t.name, pc.name, rs.name, rt.name, rc.name,
+  fk.delete_referential_action_desc, fk.update_referential_action_desc
+FROM sys.foreign_keys fk
+JOIN sys.foreign_key_columns fkc ON fkc.constraint_object_id = fk.object_id
+JOIN sys.tables t ON t.object_id = fk.parent_object_id
+JOIN sys.schemas s ON s.schema_id = t.schema_id
+JOIN sys.columns pc ON pc.object_id = fkc.parent_object_id AND pc.column_id = fkc.parent_column_id
+JOIN sys.tables rt ON rt.object_id = fk.referenced_object_id
+JOIN sys.schemas rs ON rs.schema_id = rt.schema_id
+JOIN sys.columns rc ON rc.object_id = fkc.referenced_object_id AND rc.column_id = fkc.referenced_column_id
+WHERE s.name = @p1
+ORDER BY t.name, fk.name, fkc.constraint_column_id`, args, func(rows *sql.Rows) error {
+		var name, table, column, referencedSchema, referencedTable, referencedColumn, onDelete, onUpdate string
+		if err := rows.Scan(&name, &table, &column, &referencedSchema, &referencedTable, &referencedColumn, &onDelete, &onUpdate); err != nil {
+			return err
+		}
+		fk := c.namedTable(schemaName, table).addForeignKeyColumn(name, column, referencedSchema, referencedTable, referencedColumn)
+		// NO_ACTION, CASCADE, SET_NULL, SET_DEFAULT
+		fk.OnDelete, fk.OnUpdate = strings.ReplaceAll(onDelete, "_", " "), strings.ReplaceAll(onUpdate, "_", " ")
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	err = catalogQuery(ctx, db, `SELECT t.name, cc.name
+FROM sys.check_constraints cc
+JOIN sys.tables t ON t.object_id = cc.parent_object_id
+JOIN sys.schemas s ON s.schema_id = t.schema_id
+WHERE s.name = @p1`, args, func(rows *sql.Rows) error {
+		var table, constraint string
+		if err := rows.Scan(&table, &constraint); err != nil {
+			return err
+		}
+		c.namedTable(schemaName, table).CheckConstraints[strings.ToLower(constraint)] = constraint
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	err = catalogQuery(ctx, db, `SELECT t.name, tr.name
+FROM sys.triggers tr
+JOIN sys.tables t ON t.object_id = tr.parent_id
+JOIN sys.schemas s ON s.schema_id = t.schema_id
+WHERE s.name = @p1`, args, func(rows *sql.Rows) error {
+		var table, trigger string
+		if err := rows.Scan(&table, &trigger); err != nil {
+			return err
+		}
+		c.namedTable(schemaName, table).Triggers[strings.ToLower(trigger)] = trigger
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Materialized views are indexed views on SQL Server, so they are read as views.
+	return catalogQuery(ctx, db, `SELECT v.name, COALESCE(OBJECT_DEFINITION(v.object_id), '')
+FROM sys.views v JOIN sys.schemas s ON s.schema_id = v.schema_id WHERE s.name = @p1`, args, func(rows *sql.Rows) error {
+		var view, definition string
+		if err := rows.Scan(&view, &definition); err != nil {
+			return err
+		}
+		// OBJECT_DEFINITION is the whole CREATE VIEW statement.
+		c.addView(schemaName, view, sqlServerViewHeader.ReplaceAllString(definition, ""))
+		return nil
+	})
+}
+
+var sqlServerViewHeader = regexp.MustCompile(`(?is)^\s*create\s+view\s+.+?\s+as\s+`)
+
+func sqlServerColumnType(typeName string, maxLength, precision, scale int) string {
+	switch strings.ToLower(typeName) {
+	case "nvarchar", "nchar":
+		if maxLength < 0 {
+			return typeName + "(max)"
+		}
+		return fmt.Sprintf("%s(%d)", typeName, maxLength/2)
+	case "varchar", "char", "varbinary", "binary":
+		if maxLength < 0 {
+			return typeName + "(max)"
+		}
+		return fmt.Sprintf("%s(%d)", typeName, maxLength)
+	case "decimal", "numeric":
+		return fmt.Sprintf("%s(%d,%d)", typeName, precision, scale)
+	case "datetime2", "datetimeoffset", "time":
+		return fmt.Sprintf("%s(%d)", typeName, scale)
+	default:
+		return typeName
+	}
+}
+
+func (c *ModelDBCatalog) readOracle(ctx context.Context, db *sql.DB, schemaName string) error {
+	// Oracle folds unquoted names to uppercase and quoteIdent creates uppercase objects.
+	args := []any{strings.ToUpper(schemaName)}
+	err := catalogQuery(ctx, db, "SELECT username FROM all_users WHERE username = :1", args, func(rows *sql.Rows) error {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return err
+		}
+		c.Schemas[strings.ToLower(name)] = true
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	err = catalogQuery(ctx, db, `SELECT c.table_name, c.column_name, c.column_id, c.data_type, c.char_length, c.data_precision, c.data_scale, c.nullable,
+  c.identity_column, c.data_default
+FROM all_tab_columns c
+JOIN all_tables t ON t.owner = c.owner AND t.table_name = c.table_name
+WHERE c.owner = :1`, args, func(rows *sql.Rows) error {
+		var table, column, dataType, nullable, identityColumn string
+		var ordinal int
+		var charLength, precision, scale sql.NullInt64
+		var columnDefault sql.NullString
+		if err := rows.Scan(&table, &column, &ordinal, &dataType, &charLength, &precision, &scale, &nullable, &identityColumn, &columnDefault); err != nil {
+			return err
+		}
+		isIdentity := identityColumn == "YES"
+		if isIdentity {
+			// The default of an identity column is its sequence.
+			columnDefault.String = ""
+		}
+		c.namedTable(schemaName, table).Columns[strings.ToLower(column)] = &ModelDBCatalogColumn{
+			Name:       column,
+			Ordinal:    ordinal,
+			Type:       oracleColumnType(dataType, charLength, precision, scale),
+			IsNullable: nullable == "Y",
+			IsIdentity: isIdentity,
+			Default:    strings.TrimSpace(columnDefault.String),
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	err = catalogQuery(ctx, db, `SELECT i.table_name, i.index_name, i.uniqueness,
+  (SELECT COUNT(*) FROM all_constraints k WHERE k.owner = i.owner AND k.index_name = i.index_name AND k.constraint_type = 'P'),
+  (SELECT COUNT(*) FROM all_constraints k WHERE k.owner = i.owner AND k.index_name = i.index_name),
+  c.column_name
+FROM all_indexes i
+JOIN all_ind_columns c ON c.index_owner = i.owner AND c.index_name = i.index_name
+WHERE i.table_owner = :1 AND i.index_type <> 'LOB'
+ORDER BY i.table_name, i.index_name, c.column_position`, args, func(rows *sql.Rows) error {
+		var table, index, uniqueness, column string
+		var primaryKeyCount, constraintCount int64
+		if err := rows.Scan(&table, &index, &uniqueness, &primaryKeyCount, &constraintCount, &column); err != nil {
+			return err
+		}
+		c.namedTable(schemaName, table).addIndexColumn(index, uniqueness == "UNIQUE", primaryKeyCount > 0, constraintCount > 0, column)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	err = catalogQuery(ctx, db, `SELECT k.constraint_name, k.table_name, kc.column_name, r.owner, r.table_name, rc.column_name,
+  k.delete_rule, k.deferrable, k.deferred
+FROM all_constraints k
+JOIN all_cons_columns kc ON kc.owner = k.owner AND kc.constraint_name = k.constraint_name
+JOIN all_constraints r ON r.owner = k.r_owner AND r.constraint_name = k.r_constraint_name
+JOIN all_cons_columns rc ON rc.owner = r.owner AND rc.constraint_name = r.constraint_name AND rc.position = kc.position
+WHERE k.constraint_type = 'R' AND k.owner = :1
+ORDER BY k.table_name, k.constraint_name, kc.position`, args, func(rows *sql.Rows) error {
+		var name, table, column, referencedSchema, referencedTable, referencedColumn, onDelete, deferrable, deferred string
+		if err := rows.Scan(&name, &table, &column, &referencedSchema, &referencedTable, &referencedColumn, &onDelete, &deferrable, &deferred); err != nil {
+			return err
+		}
+		fk := c.namedTable(schemaName, table).addForeignKeyColumn(name, column, referencedSchema, referencedTable, referencedColumn)
+		// Oracle has no ON UPDATE
+		fk.OnDelete, fk.OnUpdate = onDelete, "NO ACTION"
+		fk.IsDeferrable, fk.IsInitiallyDeferred = deferrable == "DEFERRABLE", deferred == "DEFERRED"
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// NOT NULL columns are system-named check constraints too; only named ones are read.
+	err = catalogQuery(ctx, db, `SELECT k.table_name, k.constraint_name
+FROM all_constraints k
+JOIN all_tables t ON t.owner = k.owner AND t.table_name = k.table_name
+WHERE k.constraint_type = 'C' AND k.generated = 'USER NAME' AND k.owner = :1`, args, func(rows *sql.Rows) error {
+		var table, constraint string
+		if err := rows.Scan(&table, &constraint); err != nil {
+			return err
+		}
+		c.namedTable(schemaName, table).CheckConstraints[strings.ToLower(constraint)] = constraint
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	err = catalogQuery(ctx, db, "SELECT table_name, trigger_name FROM all_triggers WHERE owner = :1 AND table_name IS NOT NULL", args, func(rows *sql.Rows) error {
+		var table, trigger string
+		if err := rows.Scan(&table, &trigger); err != nil {
+			return err
+		}
+		c.namedTable(schemaName, table).Triggers[strings.ToLower(trigger)] = trigger
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	err = catalogQuery(ctx, db, "SELECT view_name, text FROM all_views WHERE owner = :1", args, func(rows *sql.Rows) error {
+		var view string
+		var definition sql.NullString
+		if err := rows.Scan(&view, &definition); err != nil {
+			return err
+		}
+		c.addView(schemaName, view, definition.String)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return catalogQuery(ctx, db, "SELECT mview_name FROM all_mviews WHERE owner = :1", args, func(rows *sql.Rows) error {
+		var view string
+		if err := rows.Scan(&view); err != nil {
+			return err
+		}
+		key := catalogKey(schemaName, view)
+		c.MaterializedViews[key] = c.relation(schemaName, view)
+		// The container table of a materialized view is listed in ALL_TABLES too.
+		delete(c.Tables, key)
+		return nil
+	})
+}
+
+func oracleColumnType(dataType string, charLength, precision, scale sql.NullInt64) string {
+	switch strings.ToUpper(dataType) {
+	case "VARCHAR2", "NVARCHAR2", "CHAR", "NCHAR":
+		return fmt.Sprintf("%s(%d)", dataType, charLength.Int64)
+	case "NUMBER":
+		switch {
+		case precision.Valid && scale.Valid && scale.Int64 != 0:
+			return fmt.Sprintf("NUMBER(%d,%d)", precision.Int64, scale.Int64)
+		case precision.Valid:
+			return fmt.Sprintf("NUMBER(%d)", precision.Int64)
+		case scale.Valid && scale.Int64 == 0:
+			return "INTEGER"
+		default:
+			return "NUMBER"
+		}
+	default:
+		return dataType
+	}
+}
+
+// mariaDBCatalogObject names a relation read from information_schema. A relation of
+// the current database carries its virtual schema in its name ("schema.table"); a
+// relation of another database (unquoted "schema.view" DDL lands there) uses that
+// database as schema.
+func mariaDBCatalogObject(currentDatabase, tableSchema, tableName string) ModelDBCatalogObject {
+	if tableSchema != currentDatabase {
+		return ModelDBCatalogObject{
+			Schema:        tableSchema,
+			Name:          tableName,
+			QualifiedName: quoteIdent(base.DXDatabaseTypeMariaDB, tableSchema) + "." + quoteIdent(base.DXDatabaseTypeMariaDB, tableName),
+		}
+	}
+	o := ModelDBCatalogObject{Name: tableName, QualifiedName: quoteIdent(base.DXDatabaseTypeMariaDB, tableName)}
+	if i := strings.Index(tableName, "."); i >= 0 {
+		o.Schema, o.Name = tableName[:i], tableName[i+1:]
+	}
+	return o
+}
+
+func (c *ModelDBCatalog) readMariaDB(ctx context.Context, db *sql.DB, schemaNames []string) error {
+	var currentDatabase string
+	if err := db.QueryRowContext(ctx, "SELECT DATABASE()").Scan(&currentDatabase); err != nil {
+		return fmt.Errorf("ReadModelDBCatalog: %w", err)
+	}
+	wanted := map[string]bool{}
+	for _, schemaName := range schemaNames {
+		wanted[strings.ToLower(schemaName)] = true
+		// Virtual schemas always exist; there is nothing to create.
+		c.Schemas[strings.ToLower(schemaName)] = true
+	}
+	table := func(tableSchema, tableName string) *ModelDBCatalogTable {
+		o := mariaDBCatalogObject(currentDatabase, tableSchema, tableName)
+		if !wanted[strings.ToLower(o.Schema)] {
+			return nil
+		}
+		return c.table(o.Schema, o.Name, o.QualifiedName)
+	}
+	systemSchemas := "('mysql', 'information_schema', 'performance_schema', 'sys')"
+
+	err := catalogQuery(ctx, db, `SELECT c.TABLE_SCHEMA, c.TABLE_NAME, c.COLUMN_NAME, c.ORDINAL_POSITION, c.COLUMN_TYPE, c.IS_NULLABLE, c.EXTRA, c.COLUMN_DEFAULT
+FROM information_schema.COLUMNS c
+JOIN information_schema.TABLES t ON t.TABLE_SCHEMA = c.TABLE_SCHEMA AND t.TABLE_NAME = c.TABLE_NAME
+WHERE t.TABLE_TYPE = 'BASE TABLE' AND c.TABLE_SCHEMA NOT IN `+systemSchemas, nil, func(rows *sql.Rows) error {
+		var tableSchema, tableName, column, columnType, isNullable, extra string
+		var ordinal int
+		var columnDefault sql.NullString
+		if err := rows.Scan(&tableSchema, &tableName, &column, &ordinal, &columnType, &isNullable, &extra, &columnDefault); err != nil {
+			return err
+		}
+		// MariaDB reports "no default" of a nullable column as the expression NULL.
+		if columnDefault.String == "NULL" {
+			columnDefault.String = ""
+		}
+		if t := table(tableSchema, tableName); t != nil {
+			t.Columns[strings.ToLower(column)] = &ModelDBCatalogColumn{
+				Name: column, Ordinal: ordinal, Type: columnType, IsNullable: isNullable == "YES",
+				IsIdentity: strings.Contains(strings.ToLower(extra), "auto_increment"), Default: columnDefault.String,
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	err = catalogQuery(ctx, db, `SELECT s.TABLE_SCHEMA, s.TABLE_NAME, s.INDEX_NAME, s.NON_UNIQUE, tc.CONSTRAINT_NAME IS NOT NULL, s.COLUMN_NAME
+FROM information_schema.STATISTICS s
+LEFT JOIN information_schema.TABLE_CONSTRAINTS tc
+  ON tc.TABLE_SCHEMA = s.TABLE_SCHEMA AND tc.TABLE_NAME = s.TABLE_NAME AND tc.CONSTRAINT_NAME = s.INDEX_NAME
+WHERE s.TABLE_SCHEMA NOT IN `+systemSchemas+`
+ORDER BY s.TABLE_SCHEMA, s.TABLE_NAME, s.INDEX_NAME, s.SEQ_IN_INDEX`, nil, func(rows *sql.Rows) error {
+		var tableSchema, tableName, index, column string
+		var nonUnique, isConstraint bool
+		if err := rows.Scan(&tableSchema, &tableName, &index, &nonUnique, &isConstraint, &column); err != nil {
+			return err
+		}
+		if t := table(tableSchema, tableName); t != nil {
+			t.addIndexColumn(index, !nonUnique, index == "PRIMARY", isConstraint, column)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	err = catalogQuery(ctx, db, `SELECT k.CONSTRAINT_NAME, k.TABLE_SCHEMA, k.TABLE_NAME, k.COLUMN_NAME, k.REFERENCED_TABLE_SCHEMA, k.REFERENCED_TABLE_NAME,
+  k.REFERENCED_COLUMN_NAME, rc.DELETE_RULE, rc.UPDATE_RULE
+FROM information_schema.KEY_COLUMN_USAGE k
+JOIN information_schema.REFERENTIAL_CONSTRAINTS rc
+  ON rc.CONSTRAINT_SCHEMA = k.TABLE_SCHEMA AND rc.TABLE_NAME = k.TABLE_NAME AND rc.CONSTRAINT_NAME = k.CONSTRAINT_NAME
+WHERE k.REFERENCED_TABLE_NAME IS NOT NULL AND k.TABLE_SCHEMA NOT IN `+systemSchemas+`
+ORDER BY k.TABLE_SCHEMA, k.TABLE_NAME, k.CONSTRAINT_NAME, k.ORDINAL_POSITION`, nil, func(rows *sql.Rows) error {
+		var name, tableSchema, tableName, column, referencedTableSchema, referencedTableName, referencedColumn, onDelete, onUpdate string
+		if err := rows.Scan(&name, &tableSchema, &tableName, &column, &referencedTableSchema, &referencedTableName, &referencedColumn,
+			&onDelete, &onUpdate); err != nil {
+			return err
+		}
+		if t := table(tableSchema, tableName); t != nil {
+			referenced := mariaDBCatalogObject(currentDatabase, referencedTableSchema, referencedTableName)
+			fk := t.addForeignKeyColumn(name, column, referenced.Schema, referenced.Name, referencedColumn)
+			fk.OnDelete, fk.OnUpdate = onDelete, onUpdate
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	err = catalogQuery(ctx, db, `SELECT CONSTRAINT_SCHEMA, TABLE_NAME, CONSTRAINT_NAME FROM information_schema.CHECK_CONSTRAINTS
+WHERE CONSTRAINT_SCHEMA NOT IN `+systemSchemas, nil, func(rows *sql.Rows) error {
+		var tableSchema, tableName, constraint string
+		if err := rows.Scan(&tableSchema, &tableName, &constraint); err != nil {
+			return err
+		}
+		if t := table(tableSchema, tableName); t != nil {
+			t.CheckConstraints[strings.ToLower(constraint)] = constraint
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	err = catalogQuery(ctx, db, `SELECT EVENT_OBJECT_SCHEMA, EVENT_OBJECT_TABLE, TRIGGER_NAME FROM information_schema.TRIGGERS
+WHERE EVENT_OBJECT_SCHEMA NOT IN `+systemSchemas, nil, func(rows *sql.Rows) error {
+		var tableSchema, tableName, trigger string
+		if err := rows.Scan(&tableSchema, &tableName, &trigger); err != nil {
+			return err
+		}
+		if t := table(tableSchema, tableName); t != nil {
+			t.Triggers[strings.ToLower(trigger)] = trigger
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Materialized views are emulated as tables on MariaDB and are read as tables.
+	return catalogQuery(ctx, db, "SELECT TABLE_SCHEMA, TABLE_NAME, VIEW_DEFINITION FROM information_schema.VIEWS WHERE TABLE_SCHEMA NOT IN "+systemSchemas, nil,
+		func(rows *sql.Rows) error {
+			var tableSchema, tableName, definition string
+			if err := rows.Scan(&tableSchema, &tableName, &definition); err != nil {
+				return err
+			}
+			if o := mariaDBCatalogObject(currentDatabase, tableSchema, tableName); wanted[strings.ToLower(o.Schema)] {
+				o.Definition = strings.TrimSpace(definition)
+				c.Views[catalogKey(o.Schema, o.Name)] = o
+			}
+			return nil
+		})
+}
+
+// sqliteNamedConstraint matches the named FOREIGN KEY and CHECK table constraints of a
+// CREATE TABLE statement; SQLite keeps constraint names nowhere else.
+var sqliteNamedConstraint = regexp.MustCompile(`(?is)\bCONSTRAINT\s+("(?:[^"]|"")+"|[\w$]+)\s+(?:FOREIGN\s+KEY\s*\(([^)]*)\)|(CHECK)\b)`)
+
+// sqliteViewSelect matches the head of a CREATE VIEW statement up to its SELECT.
+var sqliteViewSelect = regexp.MustCompile(`(?is)^\s*CREATE\s+(?:TEMP\w*\s+)?VIEW\s+(?:IF\s+NOT\s+EXISTS\s+)?("(?:[^"]|"")+"|\S+)\s+AS\s+`)
+
+var (
+	sqliteDeferrable        = regexp.MustCompile(`(?i)\b(NOT\s+)?DEFERRABLE\b`)
+	sqliteInitiallyDeferred = regexp.MustCompile(`(?i)\bINITIALLY\s+DEFERRED\b`)
+)
+
+// sqliteConstraintClauseRest returns s up to the end of the table constraint it continues:
+// the first comma or closing parenthesis outside parentheses and quotes.
+func sqliteConstraintClauseRest(s string) string {
+	depth := 0
+	var quote rune
+	for i, r := range s {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'' || r == '`':
+			quote = r
+		case r == '(':
+			depth++
+		case r == ')' && depth == 0, r == ',' && depth == 0:
+			return s[:i]
+		case r == ')':
+			depth--
+		}
+	}
+	return s
+}
+
+func sqliteUnquote(identifier string) string {
+	identifier = strings.TrimSpace(identifier)
+	if len(identifier) >= 2 && identifier[0] == '"' && identifier[len(identifier)-1] == '"' {
+		return strings.ReplaceAll(identifier[1:len(identifier)-1], `""`, `"`)
+	}
+	return identifier
+}
+
+// sqliteCatalogObject names a relation read from sqlite_master; its virtual schema is
+// part of its name ("schema.table").
+func sqliteCatalogObject(name string) ModelDBCatalogObject {
+	o := ModelDBCatalogObject{Name: name, QualifiedName: quoteIdent(base.DXDatabaseTypeSQLite, name)}
+	if i := strings.Index(name, "."); i >= 0 {
+		o.Schema, o.Name = name[:i], name[i+1:]
+	}
+	return o
+}
+
+func (c *ModelDBCatalog) readSQLite(ctx context.Context, db *sql.DB, schemaNames []string) error {
+	wanted := map[string]bool{}
+	for _, schemaName := range schemaNames {
+		wanted[strings.ToLower(schemaName)] = true
+		// Virtual schemas always exist; there is nothing to create.
+		c.Schemas[strings.ToLower(schemaName)] = true
+	}
+	table := func(name string) *ModelDBCatalogTable {
+		o := sqliteCatalogObject(name)
+		if !wanted[strings.ToLower(o.Schema)] {
+			return nil
+		}
+		return c.table(o.Schema, o.Name, o.QualifiedName)
+	}
+	userTables := "m.type = 'table' AND m.name NOT LIKE 'sqlite_%'"
+
+	// The primary key is read from the columns: an INTEGER PRIMARY KEY is the rowid and has no index.
+	primaryKeys := map[*ModelDBCatalogTable][]string{}
+	err := catalogQuery(ctx, db, `SELECT m.name, p.name, p.cid, p.type, p."notnull", p.dflt_value, p.pk,
+  (SELECT count(*) FROM pragma_table_info(m.name) k WHERE k.pk > 0)
+FROM sqlite_master m, pragma_table_info(m.name) p
+WHERE `+userTables+`
+ORDER BY m.name, p.pk`, nil, func(rows *sql.Rows) error {
+		var tableName, column, columnType string
+		var cid, pk, pkCount int
+		var notNull bool
+		var columnDefault sql.NullString
+		if err := rows.Scan(&tableName, &column, &cid, &columnType, &notNull, &columnDefault, &pk, &pkCount); err != nil {
+			return err
+		}
+		if t := table(tableName); t != nil {
+			t.Columns[strings.ToLower(column)] = &ModelDBCatalogColumn{
+				Name: column, Ordinal: cid + 1, Type: columnType, IsNullable: !notNull && pk == 0,
+				IsIdentity: pk == 1 && pkCount == 1 && strings.EqualFold(columnType, "INTEGER"), Default: columnDefault.String,
+			}
+			if pk > 0 {
+				primaryKeys[t] = append(primaryKeys[t], column)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for t, columns := range primaryKeys {
+		for _, column := range columns {
+			t.addIndexColumn("PRIMARY", true, true, true, column)
+		}
+	}
+
+	err = catalogQuery(ctx, db, `SELECT m.name, il.name, il."unique", il.origin, COALESCE(ii.name, '')
+FROM sqlite_master m, pragma_index_list(m.name) il, pragma_index_info(il.name) ii
+WHERE `+userTables+` AND il.origin <> 'pk'
+ORDER BY m.name, il.name, ii.seqno`, nil, func(rows *sql.Rows) error {
+		var tableName, index, origin, column string
+		var isUnique bool
+		if err := rows.Scan(&tableName, &index, &isUnique, &origin, &column); err != nil {
+			return err
+		}
+		if t := table(tableName); t != nil {
+			// origin "u": the index of a UNIQUE constraint
+			t.addIndexColumn(index, isUnique, false, origin != "c", column)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	createStatements := map[*ModelDBCatalogTable]string{}
+	foreignKeys := map[*ModelDBCatalogForeignKey]*ModelDBCatalogTable{}
+	err = catalogQuery(ctx, db, `SELECT m.name, m.sql, f.id, f."table", f."from", COALESCE(f."to", ''), f.on_delete, f.on_update
+FROM sqlite_master m, pragma_foreign_key_list(m.name) f
+WHERE `+userTables+`
+ORDER BY m.name, f.id, f.seq`, nil, func(rows *sql.Rows) error {
+		var tableName, createStatement, referencedTableName, column, referencedColumn, onDelete, onUpdate string
+		var id int
+		if err := rows.Scan(&tableName, &createStatement, &id, &referencedTableName, &column, &referencedColumn, &onDelete, &onUpdate); err != nil {
+			return err
+		}
+		if t := table(tableName); t != nil {
+			createStatements[t] = createStatement
+			referenced := sqliteCatalogObject(referencedTableName)
+			// Named below from the CREATE TABLE statement when the constraint has a name.
+			fk := t.addForeignKeyColumn(fmt.Sprintf("fk_%d", id), column, referenced.Schema, referenced.Name, referencedColumn)
+			fk.OnDelete, fk.OnUpdate = onDelete, onUpdate
+			foreignKeys[fk] = t
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for fk, t := range foreignKeys {
+		statement := createStatements[t]
+		for _, m := range sqliteNamedConstraint.FindAllStringSubmatchIndex(statement, -1) {
+			if m[6] >= 0 {
+				continue
+			}
+			var columns []string
+			for _, column := range strings.Split(statement[m[4]:m[5]], ",") {
+				columns = append(columns, strings.ToLower(sqliteUnquote(column)))
+			}
+			if strings.Join(columns, ",") == strings.Join(fk.Columns, ",") {
+				fk.Name = sqliteUnquote(statement[m[2]:m[3]])
+				// Deferrability is only in the rest of the constraint clause.
+				clause := sqliteConstraintClauseRest(statement[m[1]:])
+				deferrable := sqliteDeferrable.FindStringSubmatch(clause)
+				fk.IsDeferrable = deferrable != nil && deferrable[1] == ""
+				fk.IsInitiallyDeferred = sqliteInitiallyDeferred.MatchString(clause)
+			}
+		}
+	}
+
+	err = catalogQuery(ctx, db, "SELECT m.name, m.sql FROM sqlite_master m WHERE "+userTables, nil, func(rows *sql.Rows) error {
+		var tableName, createStatement string
+		if err := rows.Scan(&tableName, &createStatement); err != nil {
+			return err
+		}
+		if t := table(tableName); t != nil {
+			for _, m := range sqliteNamedConstraint.FindAllStringSubmatch(createStatement, -1) {
+				if m[3] != "" {
+					constraint := sqliteUnquote(m[1])
+					t.CheckConstraints[strings.ToLower(constraint)] = constraint
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Materialized views are emulated as tables on SQLite and are read as tables.
+	return catalogQuery(ctx, db, "SELECT type, name, tbl_name, COALESCE(sql, '') FROM sqlite_master WHERE type IN ('trigger', 'view')", nil,
+		func(rows *sql.Rows) error {
+			var objectType, name, tableName, definition string
+			if err := rows.Scan(&objectType, &name, &tableName, &definition); err != nil {
+				return err
+			}
+			if objectType == "trigger" {
+				if t := table(tableName); t != nil {
+					t.Triggers[strings.ToLower(name)] = name
+				}
+				return nil
+			}
+			if o := sqliteCatalogObject(name); wanted[strings.ToLower(o.Schema)] {
+				o.Definition = strings.TrimSuffix(strings.TrimSpace(sqliteViewSelect.ReplaceAllString(definition, "")), ";")
+				c.Views[catalogKey(o.Schema, o.Name)] = o
+			}
+			return nil
+		})
+}
@@ -0,0 +1,412 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/donnyhardyanto/dxlib/base"
+)
+
+// ============================================================================
+// ModelDBForeignKey / ModelDBCheckConstraint - Table constraint entities
+// ============================================================================
+
+// ModelDBForeignKeyAction is the referential action of ON DELETE / ON UPDATE
+type ModelDBForeignKeyAction string
+
+const (
+	ModelDBForeignKeyActionNoAction   ModelDBForeignKeyAction = "NO ACTION"
+	ModelDBForeignKeyActionRestrict   ModelDBForeignKeyAction = "RESTRICT"
+	ModelDBForeignKeyActionCascade    ModelDBForeignKeyAction = "CASCADE"
+	ModelDBForeignKeyActionSetNull    ModelDBForeignKeyAction = "SET NULL"
+	ModelDBForeignKeyActionSetDefault ModelDBForeignKeyAction = "SET DEFAULT"
+)
+
+// ModelDBForeignKey is a named, table-level (possibly composite) foreign key. Unlike
+// ModelDBField.References it carries the referential actions and deferrability.
+type ModelDBForeignKey struct {
+	ModelDBEntity
+	Columns           []string
+	References        string // Referenced table in format "schema.table"
+	ReferencedColumns []string
+	OnDelete          ModelDBForeignKeyAction // empty for the database default (NO ACTION)
+	OnUpdate          ModelDBForeignKeyAction // empty for the database default (NO ACTION)
+	Deferrable        bool                    // DEFERRABLE (PostgreSQL, Oracle, SQLite)
+	InitiallyDeferred bool                    // INITIALLY DEFERRED, checked at commit
+
+	// Set by ModelDB.Init
+	ResolvedReferencedTable  *ModelDBTable
+	ResolvedReferencedFields []*ModelDBField
+
+	// Owner reference - the table this foreign key is on
+	OwnerTable *ModelDBTable
+}
+
+// NewModelDBForeignKey creates a foreign key from columns of table to referencedColumns
+// of the "schema.table" references
+func NewModelDBForeignKey(table *ModelDBTable, name string, columns []string, references string, referencedColumns []string) *ModelDBForeignKey {
+	fk := &ModelDBForeignKey{
+		ModelDBEntity: ModelDBEntity{
+			Name:   name,
+			Type:   ModelDBEntityTypeForeignKey,
+			Schema: table.Schema,
+		},
+		Columns:           columns,
+		References:        references,
+		ReferencedColumns: referencedColumns,
+		OwnerTable:        table,
+	}
+	table.ForeignKeys = append(table.ForeignKeys, fk)
+	return fk
+}
+
+// SetOnDelete sets the ON DELETE action
+func (fk *ModelDBForeignKey) SetOnDelete(action ModelDBForeignKeyAction) *ModelDBForeignKey {
+	fk.OnDelete = action
+	return fk
+}
+
+// SetOnUpdate sets the ON UPDATE action
+func (fk *ModelDBForeignKey) SetOnUpdate(action ModelDBForeignKeyAction) *ModelDBForeignKey {
+	fk.OnUpdate = action
+	return fk
+}
+
+// SetDeferrable makes the foreign key DEFERRABLE, INITIALLY DEFERRED when initiallyDeferred
+func (fk *ModelDBForeignKey) SetDeferrable(initiallyDeferred bool) *ModelDBForeignKey {
+	fk.Deferrable = true
+	fk.InitiallyDeferred = initiallyDeferred
+	return fk
+}
+
+// CreateDDL generates the table-level CONSTRAINT clause of the foreign key. Actions and
+// deferrability the engine does not support are an error rather than silently dropped.
+func (fk *ModelDBForeignKey) CreateDDL(dbType base.DXDatabaseType) (string, error) {
+	parts := strings.Split(fk.References, ".")
+	if len(parts) != 2 {
+		return "", fmt.Errorf("foreign key %s references '%s', want 'schema.table'", fk.Name, fk.References)
+	}
+	referencedTableName := parts[1]
+	if fk.ResolvedReferencedTable != nil {
+		referencedTableName = fk.ResolvedReferencedTable.TableName()
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("CONSTRAINT %s FOREIGN KEY (%s) REFERENCES %s (%s)",
+		quoteIdent(dbType, fk.Name), quoteIdentList(dbType, fk.Columns),
+		qualifiedTableName(dbType, parts[0], referencedTableName), quoteIdentList(dbType, fk.ReferencedColumns)))
+
+	onDelete, err := fk.actionForDBType(dbType, "DELETE", fk.OnDelete)
+	if err != nil {
+		return "", err
+	}
+	if onDelete != "" {
+		sb.WriteString(" ON DELETE " + onDelete)
+	}
+	onUpdate, err := fk.actionForDBType(dbType, "UPDATE", fk.OnUpdate)
+	if err != nil {
+		return "", err
+	}
+	if onUpdate != "" {
+		sb.WriteString(" ON UPDATE " + onUpdate)
+	}
+
+	if fk.Deferrable || fk.InitiallyDeferred {
+		switch dbType {
+		case base.DXDatabaseTypePostgreSQL, base.DXDatabaseTypePostgresSQLV2, base.DXDatabaseTypeOracle, base.DXDatabaseTypeSQLite:
+			if fk.InitiallyDeferred {
+				sb.WriteString(" DEFERRABLE INITIALLY DEFERRED")
+			} else {
+				sb.WriteString(" DEFERRABLE INITIALLY IMMEDIATE")
+			}
+		default:
+			return "", fmt.Errorf("foreign key %s: deferrable constraints are not supported on %s", fk.Name, dbType.String())
+		}
+	}
+	return sb.String(), nil
+}
+
+// actionForDBType renders a referential action for the engine, empty for the default
+func (fk *ModelDBForeignKey) actionForDBType(dbType base.DXDatabaseType, event string, action ModelDBForeignKeyAction) (string, error) {
+	switch action {
+	case "":
+		return "", nil
+	case ModelDBForeignKeyActionNoAction, ModelDBForeignKeyActionRestrict, ModelDBForeignKeyActionCascade,
+		ModelDBForeignKeyActionSetNull, ModelDBForeignKeyActionSetDefault:
+	default:
+		return "", fmt.Errorf("foreign key %s: unknown ON %s action '%s'", fk.Name, event, action)
+	}
+
+	switch dbType {
+	case base.DXDatabaseTypeOracle:
+		// Oracle: no ON UPDATE at all, and ON DELETE only CASCADE / SET NULL; the
+		// default (omitted) already behaves as NO ACTION
+		if action == ModelDBForeignKeyActionNoAction || action == ModelDBForeignKeyActionRestrict {
+			return "", nil
+		}
+		if event == "DELETE" && (action == ModelDBForeignKeyActionCascade || action == ModelDBForeignKeyActionSetNull) {
+			return string(action), nil
+		}
+	case base.DXDatabaseTypeSQLServer:
+		// SQL Server has no RESTRICT; its NO ACTION is checked immediately, which is the same
+		if action == ModelDBForeignKeyActionRestrict {
+			return string(ModelDBForeignKeyActionNoAction), nil
+		}
+		return string(action), nil
+	case base.DXDatabaseTypeMariaDB:
+		// InnoDB parses SET DEFAULT but rejects it
+		if action != ModelDBForeignKeyActionSetDefault {
+			return string(action), nil
+		}
+	default: // PostgreSQL, SQLite
+		return string(action), nil
+	}
+	return "", fmt.Errorf("foreign key %s: ON %s %s is not supported on %s", fk.Name, event, action, dbType.String())
+}
+
+// ModelDBCheckConstraint is a named, table-level CHECK constraint
+type ModelDBCheckConstraint struct {
+	ModelDBEntity
+	Expression         string                         // default boolean SQL expression (used when ExpressionByDBType not specified)
+	ExpressionByDBType map[base.DXDatabaseType]string // database-specific expressions
+
+	// Owner reference - the table this constraint is on
+	OwnerTable *ModelDBTable
+}
+
+// NewModelDBCheckConstraint creates a CHECK constraint on table
+func NewModelDBCheckConstraint(table *ModelDBTable, name string, expression string) *ModelDBCheckConstraint {
+	c := &ModelDBCheckConstraint{
+		ModelDBEntity: ModelDBEntity{
+			Name:   name,
+			Type:   ModelDBEntityTypeCheckConstraint,
+			Schema: table.Schema,
+		},
+		Expression: expression,
+		OwnerTable: table,
+	}
+	table.CheckConstraints = append(table.CheckConstraints, c)
+	return c
+}
+
+// SetExpressionByDBType overrides the expression for one database type
+func (c *ModelDBCheckConstraint) SetExpressionByDBType(dbType base.DXDatabaseType, expression string) *ModelDBCheckConstraint {
+	if c.ExpressionByDBType == nil {
+		c.ExpressionByDBType = map[base.DXDatabaseType]string{}
+	}
+	c.ExpressionByDBType[dbType] = expression
+	return c
+}
+
+// CreateDDL generates the table-level CONSTRAINT clause of the check constraint
+func (c *ModelDBCheckConstraint) CreateDDL(dbType base.DXDatabaseType) (string, error) {
+	expr := c.Expression
+	if dbExpr, ok := c.ExpressionByDBType[dbType]; ok && dbExpr != "" {
+		expr = dbExpr
+	}
+	if strings.TrimSpace(expr) == "" {
+		return "", fmt.Errorf("check constraint %s has no expression for %s", c.Name, dbType.String())
+	}
+	return fmt.Sprintf("CONSTRAINT %s CHECK (%s)", quoteIdent(dbType, c.Name), expr), nil
+}
+
+// constraintsDDL generates the table-level constraint clauses of CREATE TABLE
+func (t *ModelDBTable) constraintsDDL(dbType base.DXDatabaseType) ([]string, error) {
+	var clauses []string
+	for _, fk := range t.ForeignKeys {
+		if fk.isAddedAfterCreate(dbType) {
+			continue
+		}
+		clause, err := fk.CreateDDL(dbType)
+		if err != nil {
+			return nil, err
+		}
+		clauses = append(clauses, clause)
+	}
+	for _, c := range t.CheckConstraints {
+		clause, err := c.CreateDDL(dbType)
+		if err != nil {
+			return nil, err
+		}
+		clauses = append(clauses, clause)
+	}
+	return clauses, nil
+}
+
+// isAddedAfterCreate reports whether fk references a table created after its own table
+// (see creationRank), as in a cycle: CREATE TABLE cannot reference a table that does not
+// exist yet, so ModelDB.CreateDDL and ModelDB.Diff add such a foreign key with ALTER TABLE
+// once all tables exist (see AddForeignKeysDDL). SQLite only checks a reference when rows
+// change and cannot add a constraint later, so it keeps every foreign key in CREATE TABLE.
+func (fk *ModelDBForeignKey) isAddedAfterCreate(dbType base.DXDatabaseType) bool {
+	referenced := fk.ResolvedReferencedTable
+	if dbType == base.DXDatabaseTypeSQLite || referenced == nil || fk.OwnerTable == nil || referenced == fk.OwnerTable {
+		return false
+	}
+	ownerSchema, ownerTable := fk.OwnerTable.creationRank()
+	referencedSchema, referencedTable := referenced.creationRank()
+	return referencedSchema > ownerSchema || (referencedSchema == ownerSchema && referencedTable > ownerTable)
+}
+
+// creationRank returns the position of the schema of t and of t in it in the order tables
+// are created: schemas by Order, then tables by Order, ties in declaration order.
+func (t *ModelDBTable) creationRank() (schemaRank int, tableRank int) {
+	if t.Schema == nil {
+		return 0, 0
+	}
+	if t.Schema.DB != nil {
+		schemas := make([]*ModelDBSchema, len(t.Schema.DB.Schemas))
+		copy(schemas, t.Schema.DB.Schemas)
+		sort.SliceStable(schemas, func(i, j int) bool {
+			return schemas[i].Order < schemas[j].Order
+		})
+		schemaRank = slices.Index(schemas, t.Schema)
+	}
+	tables := make([]*ModelDBTable, len(t.Schema.Tables))
+	copy(tables, t.Schema.Tables)
+	sort.SliceStable(tables, func(i, j int) bool {
+		return tables[i].Order < tables[j].Order
+	})
+	return schemaRank, slices.Index(tables, t)
+}
+
+// AddForeignKeysDDL generates the ALTER TABLE statements adding the foreign keys of t that
+// CREATE TABLE leaves out (see isAddedAfterCreate); run them after all tables are created.
+func (t *ModelDBTable) AddForeignKeysDDL(dbType base.DXDatabaseType) (string, error) {
+	var sb strings.Builder
+	for _, fk := range t.ForeignKeys {
+		if !fk.isAddedAfterCreate(dbType) {
+			continue
+		}
+		clause, err := fk.CreateDDL(dbType)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString(addConstraintDDL(dbType, t, clause))
+	}
+	return sb.String(), nil
+}
+
+func addConstraintDDL(dbType base.DXDatabaseType, t *ModelDBTable, clause string) string {
+	return fmt.Sprintf("ALTER TABLE %s ADD %s;\n", qualifiedTableName(dbType, t.schemaName(), t.TableName()), clause)
+}
+
+// resolveForeignKey resolves the referenced table and columns of fk and checks that both
+// column lists exist and match, and that the referenced columns are a key of their table
+func (d *ModelDB) resolveForeignKey(table *ModelDBTable, fk *ModelDBForeignKey) error {
+	if fk.Name == "" {
+		return fmt.Errorf("ModelDB.Init: %s has a foreign key without a name", table.FullName())
+	}
+	if len(fk.Columns) == 0 || len(fk.Columns) != len(fk.ReferencedColumns) {
+		return fmt.Errorf("ModelDB.Init: %s foreign key '%s' has %d columns referencing %d columns",
+			table.FullName(), fk.Name, len(fk.Columns), len(fk.ReferencedColumns))
+	}
+	for _, column := range fk.Columns {
+		if _, ok := table.Fields[column]; !ok {
+			return fmt.Errorf("ModelDB.Init: %s foreign key '%s' column '%s' not found",
+				table.FullName(), fk.Name, column)
+		}
+	}
+
+	referencedTable := d.resolveTableReference(fk.References)
+	if referencedTable == nil {
+		return fmt.Errorf("ModelDB.Init: %s foreign key '%s' references '%s' not found",
+			table.FullName(), fk.Name, fk.References)
+	}
+	referencedFields := make([]*ModelDBField, 0, len(fk.ReferencedColumns))
+	for _, column := range fk.ReferencedColumns {
+		field, ok := referencedTable.Fields[column]
+		if !ok {
+			return fmt.Errorf("ModelDB.Init: %s foreign key '%s' references '%s.%s' not found",
+				table.FullName(), fk.Name, fk.References, column)
+		}
+		referencedFields = append(referencedFields, field)
+	}
+	if !referencedTable.isKey(fk.ReferencedColumns) {
+		return fmt.Errorf("ModelDB.Init: %s foreign key '%s' references (%s) of '%s', which is not a primary key or unique",
+			table.FullName(), fk.Name, strings.Join(fk.ReferencedColumns, ", "), fk.References)
+	}
+
+	fk.ResolvedReferencedTable = referencedTable
+	fk.ResolvedReferencedFields = referencedFields
+	return nil
+}
+
+// resolveTableReference resolves a reference string "schema.table" to a *ModelDBTable pointer.
+// Returns nil if not found.
+func (d *ModelDB) resolveTableReference(reference string) *ModelDBTable {
+	parts := strings.Split(reference, ".")
+	if len(parts) != 2 {
+		return nil
+	}
+	for _, schema := range d.Schemas {
+		if schema.Name != parts[0] {
+			continue
+		}
+		for _, table := range schema.Tables {
+			if table.Name == parts[1] {
+				return table
+			}
+		}
+	}
+	return nil
+}
+
+// isKey reports whether columns, in any order, are the primary key, a UNIQUE column or
+// the columns of a unique index of the table - what a foreign key may reference
+func (t *ModelDBTable) isKey(columns []string) bool {
+	set := map[string]bool{}
+	for _, column := range columns {
+		set[column] = true
+	}
+	sameColumns := func(other []string) bool {
+		if len(other) != len(set) || len(columns) != len(set) {
+			return false
+		}
+		for _, o := range other {
+			if !set[o] {
+				return false
+			}
+		}
+		return true
+	}
+
+	var primaryKey []string
+	for name, field := range t.Fields {
+		if field.IsPrimaryKey {
+			primaryKey = append(primaryKey, name)
+		}
+	}
+	if sameColumns(primaryKey) {
+		return true
+	}
+	if len(columns) == 1 {
+		if field, ok := t.Fields[columns[0]]; ok && field.IsUnique {
+			return true
+		}
+	}
+	for _, idx := range t.Indexes {
+		if !idx.IsUnique || idx.Where != "" {
+			continue
+		}
+		names := make([]string, 0, len(idx.Columns))
+		for _, c := range idx.Columns {
+			names = append(names, c.Name)
+		}
+		if sameColumns(names) {
+			return true
+		}
+	}
+	return false
+}
+
+// quoteIdentList quotes and comma-joins column names
+func quoteIdentList(dbType base.DXDatabaseType, names []string) string {
+	quoted := make([]string, 0, len(names))
+	for _, name := range names {
+		quoted = append(quoted, quoteIdent(dbType, name))
+	}
+	return strings.Join(quoted, ", ")
+}
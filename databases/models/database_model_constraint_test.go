@@ -0,0 +1,132 @@
+package models
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/donnyhardyanto/dxlib/base"
+	"github.com/donnyhardyanto/dxlib/types"
+)
+
+func TestModelDBForeignKeyAndCheckConstraint(t *testing.T) {
+	db := NewModelDB("test", nil)
+	schema := NewModelDBSchema(db, "app", 1)
+	bigint := types.DataType{TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "BIGINT"}}
+	NewModelDBTable(schema, "order_lines", 1, map[string]*ModelDBField{
+		"order_id": {Order: 1, Type: bigint, IsPrimaryKey: true},
+		"line_no":  {Order: 2, Type: bigint, IsPrimaryKey: true},
+	}, ModelDBTDEConfig{})
+	shipments := NewModelDBTable(schema, "shipments", 2, map[string]*ModelDBField{
+		"id":       {Order: 1, Type: bigint, IsPrimaryKey: true},
+		"order_id": {Order: 2, Type: bigint},
+		"line_no":  {Order: 3, Type: bigint},
+		"quantity": {Order: 4, Type: bigint, IsNotNull: true},
+	}, ModelDBTDEConfig{})
+	fk := NewModelDBForeignKey(shipments, "shipments_line_fkey", []string{"order_id", "line_no"}, "app.order_lines", []string{"line_no", "order_id"}).
+		SetOnDelete(ModelDBForeignKeyActionCascade).
+		SetOnUpdate(ModelDBForeignKeyActionRestrict).
+		SetDeferrable(true)
+	NewModelDBCheckConstraint(shipments, "shipments_quantity_positive", "quantity > 0")
+
+	if err := db.Init(); err != nil {
+		t.Fatal(err)
+	}
+	if fk.ResolvedReferencedTable == nil || len(fk.ResolvedReferencedFields) != 2 {
+		t.Fatalf("foreign key not resolved: %+v", fk)
+	}
+
+	ddl, err := shipments.CreateDDL(base.DXDatabaseTypePostgreSQL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`CONSTRAINT "shipments_line_fkey" FOREIGN KEY ("order_id", "line_no") REFERENCES "app"."order_lines" ("line_no", "order_id") ON DELETE CASCADE ON UPDATE RESTRICT DEFERRABLE INITIALLY DEFERRED`,
+		`CONSTRAINT "shipments_quantity_positive" CHECK (quantity > 0)`,
+	} {
+		if !strings.Contains(ddl, want) {
+			t.Errorf("DDL:\n%s\nmissing %s", ddl, want)
+		}
+	}
+
+	if _, err = shipments.CreateDDL(base.DXDatabaseTypeMariaDB); err == nil {
+		t.Error("a deferrable foreign key must fail on MariaDB")
+	}
+	fk.Deferrable, fk.InitiallyDeferred = false, false
+	ddl, err = shipments.CreateDDL(base.DXDatabaseTypeSQLServer)
+	if err != nil || !strings.Contains(ddl, "ON DELETE CASCADE ON UPDATE NO ACTION") {
+		t.Errorf("SQL Server DDL:\n%s\n%v", ddl, err)
+	}
+	if ddl, err = shipments.CreateDDL(base.DXDatabaseTypeOracle); err != nil || strings.Contains(ddl, "ON UPDATE") {
+		t.Errorf("ON UPDATE RESTRICT is the default on Oracle and must be omitted:\n%s\n%v", ddl, err)
+	}
+	fk.SetOnUpdate(ModelDBForeignKeyActionCascade)
+	if _, err = shipments.CreateDDL(base.DXDatabaseTypeOracle); err == nil {
+		t.Error("ON UPDATE CASCADE must fail on Oracle")
+	}
+
+	fk.ReferencedColumns = []string{"order_id", "order_id"}
+	if err = db.Init(); err == nil {
+		t.Error("a foreign key to a non-key must fail")
+	}
+	fk.ReferencedColumns = []string{"order_id"}
+	if err = db.Init(); err == nil {
+		t.Error("mismatched column counts must fail")
+	}
+}
+
+func TestModelDBForeignKeyToLaterTable(t *testing.T) {
+	db := NewModelDB("test", nil)
+	schema := NewModelDBSchema(db, "app", 1)
+	bigint := types.DataType{TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "BIGINT"}}
+	orders := NewModelDBTable(schema, "orders", 1, map[string]*ModelDBField{
+		"id":          {Order: 1, Type: bigint, IsPrimaryKey: true},
+		"customer_id": {Order: 2, Type: bigint},
+	}, ModelDBTDEConfig{})
+	NewModelDBTable(schema, "customers", 2, map[string]*ModelDBField{
+		"id": {Order: 1, Type: bigint, IsPrimaryKey: true},
+	}, ModelDBTDEConfig{})
+	NewModelDBForeignKey(orders, "orders_customer_fkey", []string{"customer_id"}, "app.customers", []string{"id"})
+	if err := db.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	addFK := `ALTER TABLE "app"."orders" ADD CONSTRAINT "orders_customer_fkey" FOREIGN KEY ("customer_id") REFERENCES "app"."customers" ("id");` + "\n"
+	ddl, err := db.CreateDDL(base.DXDatabaseTypePostgreSQL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Count(ddl, "orders_customer_fkey") != 1 || !strings.HasSuffix(ddl, addFK) {
+		t.Errorf("a foreign key to a table created later must be added after CREATE TABLE:\n%s", ddl)
+	}
+
+	catalog := &ModelDBCatalog{
+		DBType:            base.DXDatabaseTypePostgreSQL,
+		Schemas:           map[string]bool{"app": true},
+		Tables:            map[string]*ModelDBCatalogTable{},
+		Views:             map[string]ModelDBCatalogObject{},
+		MaterializedViews: map[string]ModelDBCatalogObject{},
+	}
+	diff, err := db.Diff(base.DXDatabaseTypePostgreSQL, catalog)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var kinds []string
+	for _, c := range diff.Changes {
+		kinds = append(kinds, string(c.Kind)+" "+c.Object)
+	}
+	want := []string{
+		"create_table app.orders",
+		"create_table app.customers",
+		"add_foreign_key orders_customer_fkey",
+	}
+	if strings.Join(kinds, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("changes:\n%s\nwant:\n%s", strings.Join(kinds, "\n"), strings.Join(want, "\n"))
+	}
+	if diff.Changes[2].DDL != addFK {
+		t.Errorf("add DDL:\n%s", diff.Changes[2].DDL)
+	}
+
+	if ddl, err = orders.CreateDDL(base.DXDatabaseTypeSQLite); err != nil || !strings.Contains(ddl, "orders_customer_fkey") {
+		t.Errorf("SQLite keeps the foreign key in CREATE TABLE:\n%s\n%v", ddl, err)
+	}
+}
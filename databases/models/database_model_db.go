@@ -51,9 +51,10 @@ func (d *ModelDB) GetExtensions(dbType base.DXDatabaseType) []string {
 	return d.Extensions[dbType]
 }
 
-// Init resolves all field References to ResolvedReferenceField pointers.
+// Init resolves all field References to ResolvedReferenceField pointers and the
+// referenced tables and columns of all foreign keys.
 // Tables are processed in Order to ensure referenced tables are resolved first.
-// Returns an error if any References string cannot be resolved.
+// Returns an error if any reference cannot be resolved or a foreign key is invalid.
 func (d *ModelDB) Init() error {
 	// Collect all tables from all schemas
 	var allTables []*ModelDBTable
@@ -78,6 +79,16 @@ func (d *ModelDB) Init() error {
 				field.ResolvedReferenceField = resolvedField
 			}
 		}
+		for _, fk := range table.ForeignKeys {
+			if err := d.resolveForeignKey(table, fk); err != nil {
+				return err
+			}
+		}
+		for _, c := range table.CheckConstraints {
+			if c.Name == "" {
+				return fmt.Errorf("ModelDB.Init: %s has a check constraint without a name", table.FullName())
+			}
+		}
 	}
 	return nil
 }
@@ -146,6 +157,12 @@ func (d *ModelDB) CreateDDL(dbType base.DXDatabaseType) (string, error) {
 				sb.WriteString(fmt.Sprintf("-- Oracle feature/package: %s (ensure enabled by DBA)\n", feature))
 			}
 			sb.WriteString("\n")
+		case base.DXDatabaseTypeSQLite:
+			// SQLite: extensions are loaded per connection by the driver, not by DDL
+			for _, ext := range extensions {
+				sb.WriteString(fmt.Sprintf("-- SQLite extension: %s (load it on the connection)\n", ext))
+			}
+			sb.WriteString("\n")
 		default:
 			panic("unhandled default case")
 		}
@@ -167,5 +184,16 @@ func (d *ModelDB) CreateDDL(dbType base.DXDatabaseType) (string, error) {
 		sb.WriteString("\n")
 	}
 
+	// Foreign keys to tables created after their own (see ModelDBTable.AddForeignKeysDDL)
+	for _, schema := range orderedSchemas {
+		for _, table := range schema.Tables {
+			s, err := table.AddForeignKeysDDL(dbType)
+			if err != nil {
+				return "", err
+			}
+			sb.WriteString(s)
+		}
+	}
+
 	return sb.String(), nil
 }
@@ -0,0 +1,828 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/donnyhardyanto/dxlib/base"
+)
+
+// ============================================================================
+// ModelDBSchemaDiff - DDL that brings a live database (ModelDBCatalog) in line with a ModelDB
+// ============================================================================
+
+type ModelDBSchemaChangeKind string
+
+const (
+	ModelDBSchemaChangeCreateSchema           ModelDBSchemaChangeKind = "create_schema"
+	ModelDBSchemaChangeCreateTable            ModelDBSchemaChangeKind = "create_table"
+	ModelDBSchemaChangeDropTable              ModelDBSchemaChangeKind = "drop_table"
+	ModelDBSchemaChangeAddColumn              ModelDBSchemaChangeKind = "add_column"
+	ModelDBSchemaChangeDropColumn             ModelDBSchemaChangeKind = "drop_column"
+	ModelDBSchemaChangeAlterColumnType        ModelDBSchemaChangeKind = "alter_column_type"
+	ModelDBSchemaChangeAlterColumnNullability ModelDBSchemaChangeKind = "alter_column_nullability"
+	ModelDBSchemaChangeCreateIndex            ModelDBSchemaChangeKind = "create_index"
+	ModelDBSchemaChangeRecreateIndex          ModelDBSchemaChangeKind = "recreate_index"
+	ModelDBSchemaChangeDropIndex              ModelDBSchemaChangeKind = "drop_index"
+	ModelDBSchemaChangeAddForeignKey          ModelDBSchemaChangeKind = "add_foreign_key"
+	ModelDBSchemaChangeRecreateForeignKey     ModelDBSchemaChangeKind = "recreate_foreign_key"
+	ModelDBSchemaChangeAddCheckConstraint     ModelDBSchemaChangeKind = "add_check_constraint"
+	ModelDBSchemaChangeCreateTrigger          ModelDBSchemaChangeKind = "create_trigger"
+	ModelDBSchemaChangeDropTrigger            ModelDBSchemaChangeKind = "drop_trigger"
+	ModelDBSchemaChangeCreateView             ModelDBSchemaChangeKind = "create_view"
+	ModelDBSchemaChangeDropView               ModelDBSchemaChangeKind = "drop_view"
+	ModelDBSchemaChangeCreateMaterializedView ModelDBSchemaChangeKind = "create_materialized_view"
+	ModelDBSchemaChangeDropMaterializedView   ModelDBSchemaChangeKind = "drop_materialized_view"
+)
+
+// ModelDBSchemaChange is one step of a diff. IsDestructive marks steps that drop an
+// object or column or change a column type: they can lose data and are only applied
+// when explicitly allowed. IsUnsupported marks steps the engine cannot make in place
+// (e.g. a column type change on SQLite, which needs a table rebuild): they have no
+// DDL and are only reported.
+type ModelDBSchemaChange struct {
+	Kind          ModelDBSchemaChangeKind
+	Object        string // schema.table[.column], or the index/trigger/view name
+	Detail        string // e.g. "varchar(100) -> varchar(255)"
+	DDL           string
+	IsDestructive bool
+	IsUnsupported bool
+}
+
+// ModelDBSchemaDiff holds the changes in execution order: schemas, drops of dependent
+// objects, tables, columns, then indexes, constraints, triggers, views and materialized views.
+type ModelDBSchemaDiff struct {
+	DBType  base.DXDatabaseType
+	Changes []*ModelDBSchemaChange
+}
+
+func (d *ModelDBSchemaDiff) HasChanges() bool {
+	return len(d.Changes) > 0
+}
+
+// Destructive returns the changes that need an explicit opt-in.
+func (d *ModelDBSchemaDiff) Destructive() []*ModelDBSchemaChange {
+	var r []*ModelDBSchemaChange
+	for _, c := range d.Changes {
+		if c.IsDestructive {
+			r = append(r, c)
+		}
+	}
+	return r
+}
+
+// Unsupported returns the changes the engine cannot make in place.
+func (d *ModelDBSchemaDiff) Unsupported() []*ModelDBSchemaChange {
+	var r []*ModelDBSchemaChange
+	for _, c := range d.Changes {
+		if c.IsUnsupported {
+			r = append(r, c)
+		}
+	}
+	return r
+}
+
+// Statements returns the DDL of the changes in order, leaving out unsupported ones and
+// destructive ones unless allowDestructive.
+func (d *ModelDBSchemaDiff) Statements(allowDestructive bool) []string {
+	var r []string
+	for _, c := range d.Changes {
+		if c.IsUnsupported || c.IsDestructive && !allowDestructive {
+			continue
+		}
+		r = append(r, c.DDL)
+	}
+	return r
+}
+
+// DDL returns Statements as one script.
+func (d *ModelDBSchemaDiff) DDL(allowDestructive bool) string {
+	return strings.Join(d.Statements(allowDestructive), "")
+}
+
+// Diff compares the model against catalog (see ReadModelDBCatalog) and returns the DDL
+// that makes the database match the model. Columns are compared by name, normalized
+// type and nullability; defaults, trigger bodies and view definitions are not compared,
+// only whether the object exists. Indexes backing PRIMARY KEY/UNIQUE constraints are
+// left alone. The foreign keys and check constraints of the model are compared by name
+// (foreign keys also by columns, referenced table, actions and deferrability); constraints the
+// model does not name, e.g. those of ModelDBField.References, are left alone.
+func (d *ModelDB) Diff(dbType base.DXDatabaseType, catalog *ModelDBCatalog) (*ModelDBSchemaDiff, error) {
+	var (
+		createSchemas, dropViews, dropTableObjects, createTables, addColumns, alterColumns,
+		dropColumns, dropTables, createIndexes, addConstraints, createTriggers, createViews []*ModelDBSchemaChange
+	)
+
+	schemas := make([]*ModelDBSchema, len(d.Schemas))
+	copy(schemas, d.Schemas)
+	sort.SliceStable(schemas, func(i, j int) bool {
+		return schemas[i].Order < schemas[j].Order
+	})
+
+	modelTables := map[string]bool{}
+	modelViews := map[string]bool{}
+	modelMaterializedViews := map[string]bool{}
+	for _, s := range schemas {
+		for _, t := range s.Tables {
+			modelTables[catalogKey(s.Name, t.TableName())] = true
+		}
+		for _, v := range s.Views {
+			modelViews[catalogKey(s.Name, v.Name)] = true
+		}
+		for _, mv := range s.MaterializedViews {
+			modelMaterializedViews[catalogKey(s.Name, mv.Name)] = true
+		}
+	}
+
+	for _, s := range schemas {
+		if !catalog.Schemas[strings.ToLower(s.Name)] {
+			if ddl := s.createSchemaDDL(dbType); ddl != "" {
+				createSchemas = append(createSchemas, &ModelDBSchemaChange{Kind: ModelDBSchemaChangeCreateSchema, Object: s.Name, DDL: ddl})
+			}
+		}
+
+		tables := make([]*ModelDBTable, len(s.Tables))
+		copy(tables, s.Tables)
+		sort.SliceStable(tables, func(i, j int) bool {
+			return tables[i].Order < tables[j].Order
+		})
+
+		for _, t := range tables {
+			ct, exists := catalog.Tables[catalogKey(s.Name, t.TableName())]
+			if !exists {
+				ddl, err := t.CreateDDL(dbType)
+				if err != nil {
+					return nil, err
+				}
+				createTables = append(createTables, &ModelDBSchemaChange{Kind: ModelDBSchemaChangeCreateTable, Object: t.FullTableName(), DDL: ddl})
+				// Foreign keys CREATE TABLE leaves out, added once all tables exist
+				for _, fk := range t.ForeignKeys {
+					if !fk.isAddedAfterCreate(dbType) {
+						continue
+					}
+					clause, err := fk.CreateDDL(dbType)
+					if err != nil {
+						return nil, err
+					}
+					addConstraints = append(addConstraints, &ModelDBSchemaChange{
+						Kind: ModelDBSchemaChangeAddForeignKey, Object: fk.Name, DDL: addConstraintDDL(dbType, t, clause),
+					})
+				}
+			} else {
+				add, alter, drop := t.diffColumns(dbType, ct)
+				addColumns = append(addColumns, add...)
+				alterColumns = append(alterColumns, alter...)
+				dropColumns = append(dropColumns, drop...)
+
+				constraints, err := t.diffConstraints(dbType, ct)
+				if err != nil {
+					return nil, err
+				}
+				addConstraints = append(addConstraints, constraints...)
+			}
+
+			drops, creates, err := t.diffIndexes(dbType, ct)
+			if err != nil {
+				return nil, err
+			}
+			dropTableObjects = append(dropTableObjects, drops...)
+			createIndexes = append(createIndexes, creates...)
+
+			drops, creates, err = t.diffTriggers(dbType, ct)
+			if err != nil {
+				return nil, err
+			}
+			dropTableObjects = append(dropTableObjects, drops...)
+			createTriggers = append(createTriggers, creates...)
+		}
+
+		views := make([]*ModelDBView, len(s.Views))
+		copy(views, s.Views)
+		sort.SliceStable(views, func(i, j int) bool {
+			return views[i].Order < views[j].Order
+		})
+		for _, v := range views {
+			if _, exists := catalog.Views[catalogKey(s.Name, v.Name)]; exists {
+				continue
+			}
+			ddl, err := v.CreateDDL(dbType)
+			if err != nil {
+				return nil, err
+			}
+			createViews = append(createViews, &ModelDBSchemaChange{Kind: ModelDBSchemaChangeCreateView, Object: v.FullViewName(), DDL: ddl})
+		}
+
+		mvs := make([]*ModelDBMaterializedView, len(s.MaterializedViews))
+		copy(mvs, s.MaterializedViews)
+		sort.SliceStable(mvs, func(i, j int) bool {
+			return mvs[i].Order < mvs[j].Order
+		})
+		for _, mv := range mvs {
+			if catalog.hasMaterializedView(dbType, s.Name, mv.Name) {
+				continue
+			}
+			ddl, err := mv.CreateDDL(dbType)
+			if err != nil {
+				return nil, err
+			}
+			for _, idx := range mv.Indexes {
+				idxDDL, err := idx.CreateDDL(dbType)
+				if err != nil {
+					return nil, err
+				}
+				ddl += idxDDL
+			}
+			createViews = append(createViews, &ModelDBSchemaChange{Kind: ModelDBSchemaChangeCreateMaterializedView, Object: mv.FullMaterializedViewName(), DDL: ddl})
+		}
+	}
+
+	// Relations of the model's schemas that the model does not know about.
+	for _, key := range sortedCatalogKeys(catalog.Views) {
+		// SQL Server materialized views are indexed views.
+		if modelViews[key] || modelMaterializedViews[key] {
+			continue
+		}
+		o := catalog.Views[key]
+		dropViews = append(dropViews, &ModelDBSchemaChange{
+			Kind: ModelDBSchemaChangeDropView, Object: o.Schema + "." + o.Name, IsDestructive: true,
+			DDL: fmt.Sprintf("DROP VIEW %s;\n", o.QualifiedName),
+		})
+	}
+	for _, key := range sortedCatalogKeys(catalog.MaterializedViews) {
+		if modelMaterializedViews[key] {
+			continue
+		}
+		o := catalog.MaterializedViews[key]
+		dropViews = append(dropViews, &ModelDBSchemaChange{
+			Kind: ModelDBSchemaChangeDropMaterializedView, Object: o.Schema + "." + o.Name, IsDestructive: true,
+			DDL: fmt.Sprintf("DROP MATERIALIZED VIEW %s;\n", o.QualifiedName),
+		})
+	}
+	tableKeys := make([]string, 0, len(catalog.Tables))
+	for key := range catalog.Tables {
+		tableKeys = append(tableKeys, key)
+	}
+	sort.Strings(tableKeys)
+	for _, key := range tableKeys {
+		// MariaDB and SQLite materialized views are tables.
+		if modelTables[key] || modelMaterializedViews[key] {
+			continue
+		}
+		ct := catalog.Tables[key]
+		dropTables = append(dropTables, &ModelDBSchemaChange{
+			Kind: ModelDBSchemaChangeDropTable, Object: ct.Schema + "." + ct.Name, IsDestructive: true,
+			DDL: fmt.Sprintf("DROP TABLE %s;\n", ct.QualifiedName),
+		})
+	}
+
+	diff := &ModelDBSchemaDiff{DBType: dbType}
+	for _, changes := range [][]*ModelDBSchemaChange{
+		createSchemas, dropViews, dropTableObjects, createTables, addColumns, alterColumns,
+		dropColumns, dropTables, createIndexes, addConstraints, createTriggers, createViews,
+	} {
+		diff.Changes = append(diff.Changes, changes...)
+	}
+	return diff, nil
+}
+
+func sortedCatalogKeys(m map[string]ModelDBCatalogObject) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// hasMaterializedView looks the view up where CreateDDL puts it per engine.
+func (c *ModelDBCatalog) hasMaterializedView(dbType base.DXDatabaseType, schema, name string) bool {
+	key := catalogKey(schema, name)
+	switch dbType {
+	case base.DXDatabaseTypeSQLServer:
+		_, ok := c.Views[key]
+		return ok
+	case base.DXDatabaseTypeMariaDB, base.DXDatabaseTypeSQLite:
+		_, ok := c.Tables[key]
+		return ok
+	default:
+		_, ok := c.MaterializedViews[key]
+		return ok
+	}
+}
+
+func (t *ModelDBTable) diffColumns(dbType base.DXDatabaseType, ct *ModelDBCatalogTable) (add, alter, drop []*ModelDBSchemaChange) {
+	tableName := qualifiedTableName(dbType, t.schemaName(), t.TableName())
+
+	for _, fieldName := range t.getOrderedFields() {
+		field := t.Fields[fieldName]
+		object := t.FullTableName() + "." + fieldName
+		column, exists := ct.Columns[strings.ToLower(fieldName)]
+		if !exists {
+			add = append(add, &ModelDBSchemaChange{
+				Kind: ModelDBSchemaChangeAddColumn, Object: object,
+				DDL: addColumnDDL(dbType, tableName, t.fieldToDDL(fieldName, *field, dbType)),
+			})
+			continue
+		}
+		// Computed columns have no type of their own on SQL Server, and their
+		// nullability follows the expression.
+		if field.IsGenerated {
+			continue
+		}
+
+		modelType := field.Type.TypeByDatabaseType[dbType]
+		notNull := field.IsNotNull || field.IsPrimaryKey
+		typeChanged := modelType != "" && normalizeColumnType(dbType, modelType) != normalizeColumnType(dbType, column.Type)
+		nullabilityChanged := notNull == column.IsNullable
+		if !typeChanged && !nullabilityChanged {
+			continue
+		}
+
+		columnType := strings.TrimSpace(columnTypeDecorations.ReplaceAllString(modelType, ""))
+		if columnType == "" {
+			columnType = column.Type
+		}
+		col := quoteIdent(dbType, fieldName)
+		nullSpec := "NULL"
+		if notNull {
+			nullSpec = "NOT NULL"
+		}
+
+		switch dbType {
+		case base.DXDatabaseTypeSQLite:
+			// SQLite cannot alter a column; the table has to be rebuilt.
+			if typeChanged {
+				alter = append(alter, &ModelDBSchemaChange{
+					Kind: ModelDBSchemaChangeAlterColumnType, Object: object, IsDestructive: true, IsUnsupported: true,
+					Detail: column.Type + " -> " + columnType + " (rebuild the table)",
+				})
+			}
+			if nullabilityChanged {
+				alter = append(alter, &ModelDBSchemaChange{
+					Kind: ModelDBSchemaChangeAlterColumnNullability, Object: object, IsUnsupported: true,
+					Detail: nullSpec + " (rebuild the table)",
+				})
+			}
+			continue
+		case base.DXDatabaseTypeMariaDB, base.DXDatabaseTypeSQLServer:
+			// Type and nullability are restated together.
+			spec := fmt.Sprintf("%s %s", col, columnType)
+			if dbType == base.DXDatabaseTypeMariaDB {
+				if defaultValue := t.getDefaultValueForDBType(*field, dbType); defaultValue != "" {
+					spec += " DEFAULT " + defaultValue
+				}
+			}
+			ddl := fmt.Sprintf("ALTER TABLE %s MODIFY COLUMN %s %s;\n", tableName, spec, nullSpec)
+			if dbType == base.DXDatabaseTypeSQLServer {
+				ddl = fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s %s;\n", tableName, spec, nullSpec)
+			}
+			if typeChanged {
+				alter = append(alter, &ModelDBSchemaChange{
+					Kind: ModelDBSchemaChangeAlterColumnType, Object: object, IsDestructive: true,
+					Detail: column.Type + " -> " + columnType, DDL: ddl,
+				})
+			} else {
+				alter = append(alter, &ModelDBSchemaChange{
+					Kind: ModelDBSchemaChangeAlterColumnNullability, Object: object, Detail: nullSpec, DDL: ddl,
+				})
+			}
+			continue
+		}
+
+		if typeChanged {
+			ddl := fmt.Sprintf("ALTER TABLE %s MODIFY (%s %s);\n", tableName, col, columnType)
+			if dbType != base.DXDatabaseTypeOracle {
+				ddl = fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s TYPE %s USING %s::%s;\n", tableName, col, columnType, col, columnType)
+			}
+			alter = append(alter, &ModelDBSchemaChange{
+				Kind: ModelDBSchemaChangeAlterColumnType, Object: object, IsDestructive: true,
+				Detail: column.Type + " -> " + columnType, DDL: ddl,
+			})
+		}
+		if nullabilityChanged {
+			ddl := fmt.Sprintf("ALTER TABLE %s MODIFY (%s %s);\n", tableName, col, nullSpec)
+			if dbType != base.DXDatabaseTypeOracle {
+				action := "DROP NOT NULL"
+				if notNull {
+					action = "SET NOT NULL"
+				}
+				ddl = fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s %s;\n", tableName, col, action)
+			}
+			alter = append(alter, &ModelDBSchemaChange{
+				Kind: ModelDBSchemaChangeAlterColumnNullability, Object: object, Detail: nullSpec, DDL: ddl,
+			})
+		}
+	}
+
+	columnNames := make([]string, 0, len(ct.Columns))
+	for name := range ct.Columns {
+		columnNames = append(columnNames, name)
+	}
+	sort.Strings(columnNames)
+	for _, name := range columnNames {
+		if t.hasField(name) || t.isFullTextVectorColumn(name) {
+			continue
+		}
+		column := ct.Columns[name]
+		drop = append(drop, &ModelDBSchemaChange{
+			Kind: ModelDBSchemaChangeDropColumn, Object: t.FullTableName() + "." + column.Name, IsDestructive: true,
+			DDL: fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s;\n", tableName, quoteIdent(dbType, column.Name)),
+		})
+	}
+	return add, alter, drop
+}
+
+func addColumnDDL(dbType base.DXDatabaseType, tableName, columnDDL string) string {
+	switch dbType {
+	case base.DXDatabaseTypeSQLServer:
+		return fmt.Sprintf("ALTER TABLE %s ADD %s;\n", tableName, columnDDL)
+	case base.DXDatabaseTypeOracle:
+		return fmt.Sprintf("ALTER TABLE %s ADD (%s);\n", tableName, columnDDL)
+	default: // PostgreSQL, MariaDB, SQLite
+		return fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s;\n", tableName, columnDDL)
+	}
+}
+
+func (t *ModelDBTable) schemaName() string {
+	if t.Schema != nil {
+		return t.Schema.Name
+	}
+	return ""
+}
+
+func (t *ModelDBTable) hasField(lowerName string) bool {
+	for fieldName := range t.Fields {
+		if strings.ToLower(fieldName) == lowerName {
+			return true
+		}
+	}
+	return false
+}
+
+// diffIndexes compares the explicit indexes of t. ct is nil when the table is created
+// by this diff.
+func (t *ModelDBTable) diffIndexes(dbType base.DXDatabaseType, ct *ModelDBCatalogTable) (drops, creates []*ModelDBSchemaChange, err error) {
+	expected := map[string]bool{}
+	indexes := make([]*ModelDBIndex, len(t.Indexes))
+	copy(indexes, t.Indexes)
+	sort.SliceStable(indexes, func(i, j int) bool {
+		return indexes[i].Order < indexes[j].Order
+	})
+
+	for _, idx := range indexes {
+		key := strings.ToLower(idx.Name)
+		expected[key] = true
+		if idx.IsFullText && ct != nil && (dbType == base.DXDatabaseTypeSQLServer || dbType == base.DXDatabaseTypeSQLite) {
+			// Not readable back from the catalog (SQL Server) or not created (SQLite)
+			continue
+		}
+		ddl, err := idx.CreateDDL(dbType)
+		if err != nil {
+			return nil, nil, err
+		}
+		var existing *ModelDBCatalogIndex
+		if ct != nil {
+			existing = ct.Indexes[key]
+		}
+		switch {
+		case existing == nil:
+			creates = append(creates, &ModelDBSchemaChange{Kind: ModelDBSchemaChangeCreateIndex, Object: idx.Name, DDL: ddl})
+		case !existing.matches(idx):
+			creates = append(creates, &ModelDBSchemaChange{
+				Kind: ModelDBSchemaChangeRecreateIndex, Object: idx.Name, IsDestructive: true,
+				Detail: fmt.Sprintf("(%s) -> (%s)", strings.Join(existing.Columns, ", "), strings.Join(idx.columnNames(), ", ")),
+				DDL:    dropIndexDDL(dbType, ct, existing) + ddl,
+			})
+		}
+	}
+	if ct == nil {
+		return drops, creates, nil
+	}
+
+	// SQL Server nullable UNIQUE columns get a filtered index instead of a constraint.
+	for fieldName, field := range t.Fields {
+		if field.IsUnique && !field.IsPrimaryKey && !field.IsNotNull {
+			expected[strings.ToLower(t.TableName()+"_"+fieldName+"_uidx")] = true
+		}
+	}
+
+	names := make([]string, 0, len(ct.Indexes))
+	for key := range ct.Indexes {
+		names = append(names, key)
+	}
+	sort.Strings(names)
+	for _, key := range names {
+		existing := ct.Indexes[key]
+		if expected[key] || existing.IsConstraint {
+			continue
+		}
+		drops = append(drops, &ModelDBSchemaChange{
+			Kind: ModelDBSchemaChangeDropIndex, Object: existing.Name, IsDestructive: true,
+			DDL: dropIndexDDL(dbType, ct, existing),
+		})
+	}
+	return drops, creates, nil
+}
+
+func (i *ModelDBIndex) columnNames() []string {
+	names := make([]string, 0, len(i.Columns))
+	for _, col := range i.Columns {
+		names = append(names, strings.ToLower(col.Name))
+	}
+	return names
+}
+
+func (c *ModelDBCatalogIndex) matches(idx *ModelDBIndex) bool {
+	// The catalog holds the tsvector expression or text index columns, not the model columns
+	if idx.IsFullText {
+		return true
+	}
+	if c.IsUnique != idx.IsUnique {
+		return false
+	}
+	return strings.Join(c.Columns, ",") == strings.Join(idx.columnNames(), ",")
+}
+
+func dropIndexDDL(dbType base.DXDatabaseType, ct *ModelDBCatalogTable, idx *ModelDBCatalogIndex) string {
+	switch dbType {
+	case base.DXDatabaseTypeSQLServer, base.DXDatabaseTypeMariaDB:
+		return fmt.Sprintf("DROP INDEX %s ON %s;\n", quoteIdent(dbType, idx.Name), ct.QualifiedName)
+	case base.DXDatabaseTypeSQLite:
+		// Named without the virtual schema, see ModelDBIndex.createSQLiteDDL
+		return fmt.Sprintf("DROP INDEX %s;\n", quoteIdent(dbType, idx.Name))
+	default: // PostgreSQL, Oracle: indexes live in the schema
+		return fmt.Sprintf("DROP INDEX %s;\n", qualifiedTableName(dbType, ct.Schema, idx.Name))
+	}
+}
+
+// diffConstraints adds the foreign keys and check constraints of t missing from the existing
+// table ct, and recreates the foreign keys whose columns, referenced table, actions or
+// deferrability changed.
+func (t *ModelDBTable) diffConstraints(dbType base.DXDatabaseType, ct *ModelDBCatalogTable) (changes []*ModelDBSchemaChange, err error) {
+	tableName := qualifiedTableName(dbType, t.schemaName(), t.TableName())
+
+	for _, fk := range t.ForeignKeys {
+		clause, err := fk.CreateDDL(dbType)
+		if err != nil {
+			return nil, err
+		}
+		ddl := addConstraintDDL(dbType, t, clause)
+		existing := ct.foreignKey(fk.Name)
+		switch {
+		case existing == nil:
+			changes = append(changes, &ModelDBSchemaChange{Kind: ModelDBSchemaChangeAddForeignKey, Object: fk.Name, DDL: ddl})
+		case !existing.matches(dbType, fk):
+			dropDDL := fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT %s;\n", tableName, quoteIdent(dbType, existing.Name))
+			if dbType == base.DXDatabaseTypeMariaDB {
+				dropDDL = fmt.Sprintf("ALTER TABLE %s DROP FOREIGN KEY %s;\n", tableName, quoteIdent(dbType, existing.Name))
+			}
+			changes = append(changes, &ModelDBSchemaChange{
+				Kind: ModelDBSchemaChangeRecreateForeignKey, Object: fk.Name, IsDestructive: true,
+				Detail: fmt.Sprintf("(%s) %s.%s ON DELETE %s ON UPDATE %s -> (%s) %s ON DELETE %s ON UPDATE %s",
+					strings.Join(existing.Columns, ", "), existing.ReferencedSchema, existing.ReferencedTable,
+					foreignKeyAction(dbType, existing.OnDelete), foreignKeyAction(dbType, existing.OnUpdate),
+					strings.Join(fk.Columns, ", "), fk.References, foreignKeyAction(dbType, string(fk.OnDelete)), foreignKeyAction(dbType, string(fk.OnUpdate))),
+				DDL: dropDDL + ddl,
+			})
+		}
+	}
+
+	for _, c := range t.CheckConstraints {
+		if _, exists := ct.CheckConstraints[strings.ToLower(c.Name)]; exists {
+			continue
+		}
+		clause, err := c.CreateDDL(dbType)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, &ModelDBSchemaChange{
+			Kind: ModelDBSchemaChangeAddCheckConstraint, Object: c.Name,
+			DDL: fmt.Sprintf("ALTER TABLE %s ADD %s;\n", tableName, clause),
+		})
+	}
+
+	// SQLite cannot add or drop a constraint of an existing table; the table has to be rebuilt.
+	if dbType == base.DXDatabaseTypeSQLite {
+		for _, c := range changes {
+			c.IsUnsupported, c.DDL = true, ""
+			c.Detail = strings.TrimSpace(c.Detail + " (rebuild the table)")
+		}
+	}
+	return changes, nil
+}
+
+func (c *ModelDBCatalogForeignKey) matches(dbType base.DXDatabaseType, fk *ModelDBForeignKey) bool {
+	referencedSchema, referencedTable, _ := strings.Cut(fk.References, ".")
+	if fk.ResolvedReferencedTable != nil {
+		referencedTable = fk.ResolvedReferencedTable.TableName()
+	}
+	if !strings.EqualFold(c.ReferencedSchema, referencedSchema) || !strings.EqualFold(c.ReferencedTable, referencedTable) {
+		return false
+	}
+	if strings.Join(c.Columns, ",") != strings.ToLower(strings.Join(fk.Columns, ",")) ||
+		strings.Join(c.ReferencedColumns, ",") != strings.ToLower(strings.Join(fk.ReferencedColumns, ",")) {
+		return false
+	}
+	// The actions as CreateDDL renders them for the engine, which already accepted them
+	onDelete, _ := fk.actionForDBType(dbType, "DELETE", fk.OnDelete)
+	onUpdate, _ := fk.actionForDBType(dbType, "UPDATE", fk.OnUpdate)
+	if foreignKeyAction(dbType, c.OnDelete) != foreignKeyAction(dbType, onDelete) ||
+		foreignKeyAction(dbType, c.OnUpdate) != foreignKeyAction(dbType, onUpdate) {
+		return false
+	}
+	return c.IsDeferrable == (fk.Deferrable || fk.InitiallyDeferred) && c.IsInitiallyDeferred == fk.InitiallyDeferred
+}
+
+// foreignKeyAction normalizes a referential action for comparison: "" is the default
+// NO ACTION, and MariaDB's RESTRICT is the same as its NO ACTION.
+func foreignKeyAction(dbType base.DXDatabaseType, action string) string {
+	action = strings.ToUpper(strings.TrimSpace(action))
+	if action == "" || (dbType == base.DXDatabaseTypeMariaDB && action == string(ModelDBForeignKeyActionRestrict)) {
+		return string(ModelDBForeignKeyActionNoAction)
+	}
+	return action
+}
+
+// diffTriggers compares the triggers of t by name. ct is nil when the table is created
+// by this diff.
+func (t *ModelDBTable) diffTriggers(dbType base.DXDatabaseType, ct *ModelDBCatalogTable) (drops, creates []*ModelDBSchemaChange, err error) {
+	expected := map[string]bool{}
+	triggers := make([]*ModelDBTrigger, len(t.Triggers))
+	copy(triggers, t.Triggers)
+	sort.SliceStable(triggers, func(i, j int) bool {
+		return triggers[i].Order < triggers[j].Order
+	})
+
+	for _, trigger := range triggers {
+		exists := ct != nil
+		for _, name := range trigger.namesForDBType(dbType) {
+			key := strings.ToLower(name)
+			expected[key] = true
+			if ct != nil {
+				if _, ok := ct.Triggers[key]; !ok {
+					exists = false
+				}
+			}
+		}
+		if exists {
+			continue
+		}
+		ddl := ""
+		// The trigger function is created or replaced with it.
+		if trigger.ExecuteFunction != nil {
+			if ddl, err = trigger.ExecuteFunction.CreateDDL(dbType); err != nil {
+				return nil, nil, err
+			}
+		}
+		triggerDDL, err := trigger.CreateDDL(dbType)
+		if err != nil {
+			return nil, nil, err
+		}
+		creates = append(creates, &ModelDBSchemaChange{Kind: ModelDBSchemaChangeCreateTrigger, Object: trigger.Name, DDL: ddl + triggerDDL})
+	}
+
+	// The row change trigger of Notify is not in t.Triggers; CreateDDL creates it with the table.
+	notifyDDL, err := t.NotifyTriggerDDL(dbType)
+	if err != nil {
+		return nil, nil, err
+	}
+	if notifyDDL != "" {
+		key := strings.ToLower(t.NotifyTriggerName())
+		expected[key] = true
+		if ct != nil {
+			if _, exists := ct.Triggers[key]; !exists {
+				creates = append(creates, &ModelDBSchemaChange{Kind: ModelDBSchemaChangeCreateTrigger, Object: t.NotifyTriggerName(), DDL: notifyDDL})
+			}
+		}
+	}
+	if ct == nil {
+		return drops, creates, nil
+	}
+
+	names := make([]string, 0, len(ct.Triggers))
+	for key := range ct.Triggers {
+		names = append(names, key)
+	}
+	sort.Strings(names)
+	for _, key := range names {
+		if expected[key] {
+			continue
+		}
+		name := ct.Triggers[key]
+		var ddl string
+		switch dbType {
+		case base.DXDatabaseTypePostgreSQL, base.DXDatabaseTypePostgresSQLV2:
+			ddl = fmt.Sprintf("DROP TRIGGER %s ON %s;\n", quoteIdent(dbType, name), ct.QualifiedName)
+		case base.DXDatabaseTypeMariaDB, base.DXDatabaseTypeSQLite: // trigger names are global, not per table
+			ddl = fmt.Sprintf("DROP TRIGGER %s;\n", quoteIdent(dbType, name))
+		default: // SQL Server, Oracle: triggers live in the schema
+			ddl = fmt.Sprintf("DROP TRIGGER %s;\n", qualifiedTableName(dbType, ct.Schema, name))
+		}
+		drops = append(drops, &ModelDBSchemaChange{Kind: ModelDBSchemaChangeDropTrigger, Object: name, IsDestructive: true, DDL: ddl})
+	}
+	return drops, creates, nil
+}
+
+// ============================================================================
+// Column type normalization: the model's type (types.DataType.TypeByDatabaseType)
+// and the catalog's spelling of it compare equal after normalizeColumnType.
+// ============================================================================
+
+// columnTypeDecorations are parts of a model type that are not part of the column type.
+var columnTypeDecorations = regexp.MustCompile(`(?i)\s+(identity\s*\(\s*\d+\s*,\s*\d+\s*\)|auto_increment|generated\s+(always|by\s+default)(\s+on\s+null)?\s+as\s+identity|srid\s+\d+)`)
+
+var columnTypeSpaces = regexp.MustCompile(`\s+`)
+
+var columnTypeAliases = map[base.DXDatabaseType]map[string]string{
+	base.DXDatabaseTypePostgreSQL: {
+		"integer":           "int",
+		"int4":              "int",
+		"serial":            "int",
+		"serial4":           "int",
+		"int8":              "bigint",
+		"bigserial":         "bigint",
+		"serial8":           "bigint",
+		"int2":              "smallint",
+		"smallserial":       "smallint",
+		"bool":              "boolean",
+		"float8":            "double precision",
+		"float4":            "real",
+		"decimal":           "numeric",
+		"character varying": "varchar",
+		"character":         "char",
+		"timestamptz":       "timestamp with time zone",
+		"timestamp":         "timestamp without time zone",
+		"time":              "time without time zone",
+		"timetz":            "time with time zone",
+	},
+	base.DXDatabaseTypeMariaDB: {
+		"integer":          "int",
+		"numeric":          "decimal",
+		"real":             "double",
+		"double precision": "double",
+		"json":             "longtext",
+	},
+	base.DXDatabaseTypeSQLServer: {
+		"integer":          "int",
+		"numeric":          "decimal",
+		"double precision": "float",
+		"rowversion":       "timestamp",
+	},
+	base.DXDatabaseTypeOracle: {
+		"varchar":  "varchar2",
+		"int":      "integer",
+		"smallint": "integer",
+	},
+}
+
+// normalizeColumnType reduces a column type to a canonical lowercase spelling so that
+// e.g. "VARCHAR(255)" and "character varying(255)" compare equal on PostgreSQL.
+func normalizeColumnType(dbType base.DXDatabaseType, columnType string) string {
+	s := columnTypeDecorations.ReplaceAllString(columnType, "")
+	s = strings.ToLower(strings.TrimSpace(columnTypeSpaces.ReplaceAllString(s, " ")))
+	s = strings.ReplaceAll(strings.ReplaceAll(s, ", ", ","), " (", "(")
+
+	array := ""
+	if strings.HasSuffix(s, "[]") {
+		s, array = strings.TrimSuffix(s, "[]"), "[]"
+	}
+	name, args := s, ""
+	if i := strings.Index(s, "("); i >= 0 {
+		if j := strings.Index(s[i:], ")"); j >= 0 {
+			name = strings.TrimSpace(s[:i] + s[i+j+1:])
+			args = s[i : i+j+1]
+		}
+	}
+	if alias, ok := columnTypeAliases[dbType][name]; ok {
+		name = alias
+	}
+
+	switch dbType {
+	case base.DXDatabaseTypeMariaDB:
+		switch name {
+		case "boolean", "bool":
+			name, args = "tinyint", "(1)"
+		case "int", "bigint", "smallint", "mediumint":
+			// Display widths are not part of the type.
+			args = ""
+		}
+	case base.DXDatabaseTypeSQLServer:
+		if (name == "datetime2" || name == "datetimeoffset" || name == "time") && args == "(7)" {
+			args = ""
+		}
+	case base.DXDatabaseTypeOracle:
+		args = strings.NewReplacer(" char)", ")", " byte)", ")").Replace(args)
+		if strings.HasPrefix(name, "timestamp") && args == "(6)" {
+			args = ""
+		}
+		if name == "number" && strings.HasSuffix(args, ",0)") {
+			args = strings.TrimSuffix(args, ",0)") + ")"
+		}
+		if name == "number" && args == "(38)" {
+			name, args = "integer", ""
+		}
+	}
+	return name + args + array
+}
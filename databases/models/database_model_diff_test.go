@@ -0,0 +1,311 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+
+	"github.com/donnyhardyanto/dxlib/base"
+	"github.com/donnyhardyanto/dxlib/types"
+	_ "modernc.org/sqlite"
+)
+
+func TestNormalizeColumnType(t *testing.T) {
+	cases := []struct {
+		dbType      base.DXDatabaseType
+		model, live string
+	}{
+		{base.DXDatabaseTypePostgreSQL, "VARCHAR(1024)", "character varying(1024)"},
+		{base.DXDatabaseTypePostgreSQL, "BIGSERIAL", "bigint"},
+		{base.DXDatabaseTypePostgreSQL, "TIMESTAMP WITH TIME ZONE", "timestamp with time zone"},
+		{base.DXDatabaseTypePostgreSQL, "geometry(Point, 4326)", "geometry(Point,4326)"},
+		{base.DXDatabaseTypePostgreSQL, "BIGINT[]", "bigint[]"},
+		{base.DXDatabaseTypeMariaDB, "BIGINT AUTO_INCREMENT", "bigint(20)"},
+		{base.DXDatabaseTypeMariaDB, "BOOLEAN", "tinyint(1)"},
+		{base.DXDatabaseTypeMariaDB, "JSON", "longtext"},
+		{base.DXDatabaseTypeSQLServer, "BIGINT IDENTITY(1,1)", "bigint"},
+		{base.DXDatabaseTypeSQLServer, "DATETIMEOFFSET", "datetimeoffset(7)"},
+		{base.DXDatabaseTypeSQLServer, "NVARCHAR(MAX)", "nvarchar(max)"},
+		{base.DXDatabaseTypeOracle, "NUMBER(19) GENERATED BY DEFAULT AS IDENTITY", "NUMBER(19)"},
+		{base.DXDatabaseTypeOracle, "VARCHAR(1024)", "VARCHAR2(1024)"},
+		{base.DXDatabaseTypeOracle, "TIMESTAMP WITH TIME ZONE", "TIMESTAMP(6) WITH TIME ZONE"},
+	}
+	for _, c := range cases {
+		if m, l := normalizeColumnType(c.dbType, c.model), normalizeColumnType(c.dbType, c.live); m != l {
+			t.Errorf("%s: %q -> %q, %q -> %q; want equal", c.dbType.String(), c.model, m, c.live, l)
+		}
+	}
+	if normalizeColumnType(base.DXDatabaseTypePostgreSQL, "VARCHAR(100)") == normalizeColumnType(base.DXDatabaseTypePostgreSQL, "character varying(255)") {
+		t.Error("varchar lengths must differ")
+	}
+}
+
+func TestModelDBDiff(t *testing.T) {
+	db := NewModelDB("test", nil)
+	schema := NewModelDBSchema(db, "app", 1)
+	varchar := func(n string) types.DataType {
+		return types.DataType{TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "VARCHAR(" + n + ")"}}
+	}
+	NewModelDBTable(schema, "users", 1, map[string]*ModelDBField{
+		"id":    {Order: 1, Type: types.DataType{TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "BIGSERIAL"}}, IsPrimaryKey: true},
+		"name":  {Order: 2, Type: varchar("255"), IsNotNull: true},
+		"email": {Order: 3, Type: varchar("255")},
+	}, ModelDBTDEConfig{})
+	NewModelDBTable(schema, "roles", 2, map[string]*ModelDBField{
+		"id": {Order: 1, Type: varchar("64"), IsPrimaryKey: true},
+	}, ModelDBTDEConfig{})
+
+	catalog := &ModelDBCatalog{
+		DBType:            base.DXDatabaseTypePostgreSQL,
+		Schemas:           map[string]bool{"app": true},
+		Tables:            map[string]*ModelDBCatalogTable{},
+		Views:             map[string]ModelDBCatalogObject{},
+		MaterializedViews: map[string]ModelDBCatalogObject{},
+	}
+	users := catalog.namedTable("app", "users")
+	users.Columns["id"] = &ModelDBCatalogColumn{Name: "id", Type: "bigint"}
+	users.Columns["name"] = &ModelDBCatalogColumn{Name: "name", Type: "character varying(100)", IsNullable: true}
+	users.Columns["legacy"] = &ModelDBCatalogColumn{Name: "legacy", Type: "text", IsNullable: true}
+	users.addIndexColumn("users_pkey", true, true, true, "id")
+	users.addIndexColumn("users_legacy_idx", false, false, false, "legacy")
+	catalog.namedTable("app", "old_audit")
+
+	diff, err := db.Diff(base.DXDatabaseTypePostgreSQL, catalog)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var kinds []string
+	for _, c := range diff.Changes {
+		kinds = append(kinds, string(c.Kind)+" "+c.Object)
+	}
+	want := []string{
+		"drop_index users_legacy_idx",
+		"create_table app.roles",
+		"add_column app.users.email",
+		"alter_column_type app.users.name",
+		"alter_column_nullability app.users.name",
+		"drop_column app.users.legacy",
+		"drop_table app.old_audit",
+	}
+	if strings.Join(kinds, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("changes:\n%s\nwant:\n%s", strings.Join(kinds, "\n"), strings.Join(want, "\n"))
+	}
+
+	safe := diff.DDL(false)
+	if strings.Contains(safe, "DROP") || strings.Contains(safe, " TYPE ") {
+		t.Errorf("destructive DDL without opt-in:\n%s", safe)
+	}
+	if !strings.Contains(safe, `ALTER TABLE "app"."users" ALTER COLUMN "name" SET NOT NULL;`) {
+		t.Errorf("missing SET NOT NULL:\n%s", safe)
+	}
+	if len(diff.Destructive()) != 4 {
+		t.Errorf("destructive = %d, want 4", len(diff.Destructive()))
+	}
+}
+
+func TestModelDBCatalogGoSource(t *testing.T) {
+	catalog := &ModelDBCatalog{
+		DBType:            base.DXDatabaseTypePostgreSQL,
+		Schemas:           map[string]bool{"app": true},
+		Tables:            map[string]*ModelDBCatalogTable{},
+		Views:             map[string]ModelDBCatalogObject{},
+		MaterializedViews: map[string]ModelDBCatalogObject{},
+	}
+	orders := catalog.namedTable("app", "orders")
+	orders.Columns["id"] = &ModelDBCatalogColumn{Name: "id", Type: "bigint", Ordinal: 1, IsIdentity: true}
+	orders.Columns["user_id"] = &ModelDBCatalogColumn{Name: "user_id", Type: "bigint", Ordinal: 2}
+	orders.Columns["status"] = &ModelDBCatalogColumn{Name: "status", Type: "character varying(20)", Ordinal: 3, Default: "'new'::character varying"}
+	orders.Columns["shape"] = &ModelDBCatalogColumn{Name: "shape", Type: "polygon", Ordinal: 4, IsNullable: true}
+	orders.addIndexColumn("orders_pkey", true, true, true, "id")
+	orders.addIndexColumn("orders_status_idx", false, false, false, "status")
+	orders.addForeignKeyColumn("orders_user_id_fkey", "user_id", "app", "users", "id")
+	users := catalog.namedTable("app", "users")
+	users.Columns["id"] = &ModelDBCatalogColumn{Name: "id", Type: "bigint", Ordinal: 1, IsIdentity: true}
+	users.Columns["created_at"] = &ModelDBCatalogColumn{Name: "created_at", Type: "timestamp with time zone", Ordinal: 2, Default: "now()"}
+	users.addIndexColumn("users_pkey", true, true, true, "id")
+
+	src, issues, err := catalog.GoSource("schema", "Model", "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`tableAppOrders := models.NewModelDBTable(schemaApp, "orders", 20,`,
+		`"id":         {Order: 1, Type: types.DataTypeBigSerial, IsPrimaryKey: true},`,
+		`"user_id": {Order: 2, Type: types.DataTypeInt64, IsNotNull: true, References: "app.users.id"},`,
+		`"status":  {Order: 3, Type: types.DataTypeString20, IsNotNull: true, DefaultValue: "new"},`,
+		`base.DXDatabaseTypePostgreSQL: "polygon"`,
+		`DefaultValue: "now()"`,
+		`models.NewModelDBIndexForTable(tableAppOrders, "orders_status_idx", 1, []models.ModelDBIndexColumn{{Name: "status"}}, false)`,
+	} {
+		if !strings.Contains(string(src), want) {
+			t.Errorf("missing %q in:\n%s", want, src)
+		}
+	}
+	if len(issues) != 1 || issues[0].Type != "polygon" {
+		t.Errorf("issues = %+v, want the polygon column", issues)
+	}
+}
+
+func TestModelDBDiffConstraints(t *testing.T) {
+	db := NewModelDB("test", nil)
+	schema := NewModelDBSchema(db, "app", 1)
+	bigint := types.DataType{TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "BIGINT"}}
+	NewModelDBTable(schema, "customers", 1, map[string]*ModelDBField{
+		"id": {Order: 1, Type: bigint, IsPrimaryKey: true},
+	}, ModelDBTDEConfig{})
+	orders := NewModelDBTable(schema, "orders", 2, map[string]*ModelDBField{
+		"id":          {Order: 1, Type: bigint, IsPrimaryKey: true},
+		"customer_id": {Order: 2, Type: bigint},
+		"quantity":    {Order: 3, Type: bigint},
+	}, ModelDBTDEConfig{})
+	NewModelDBForeignKey(orders, "orders_customer_fkey", []string{"customer_id"}, "app.customers", []string{"id"}).
+		SetOnDelete(ModelDBForeignKeyActionCascade)
+	NewModelDBCheckConstraint(orders, "orders_quantity_positive", "quantity > 0")
+	NewModelDBCheckConstraint(orders, "orders_id_positive", "id > 0")
+	if err := db.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	catalog := &ModelDBCatalog{
+		DBType:            base.DXDatabaseTypePostgreSQL,
+		Schemas:           map[string]bool{"app": true},
+		Tables:            map[string]*ModelDBCatalogTable{},
+		Views:             map[string]ModelDBCatalogObject{},
+		MaterializedViews: map[string]ModelDBCatalogObject{},
+	}
+	for _, name := range []string{"customers", "orders"} {
+		ct := catalog.namedTable("app", name)
+		ct.Columns["id"] = &ModelDBCatalogColumn{Name: "id", Type: "bigint"}
+		ct.addIndexColumn(name+"_pkey", true, true, true, "id")
+	}
+	ct := catalog.Tables["app.orders"]
+	ct.Columns["customer_id"] = &ModelDBCatalogColumn{Name: "customer_id", Type: "bigint", IsNullable: true}
+	ct.Columns["quantity"] = &ModelDBCatalogColumn{Name: "quantity", Type: "bigint", IsNullable: true}
+	ct.addForeignKeyColumn("orders_customer_fkey", "id", "app", "customers", "id")
+	ct.addForeignKeyColumn("orders_legacy_fkey", "quantity", "app", "customers", "id")
+	ct.CheckConstraints["orders_id_positive"] = "orders_id_positive"
+
+	diff, err := db.Diff(base.DXDatabaseTypePostgreSQL, catalog)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var kinds []string
+	for _, c := range diff.Changes {
+		kinds = append(kinds, string(c.Kind)+" "+c.Object)
+	}
+	want := []string{
+		"recreate_foreign_key orders_customer_fkey",
+		"add_check_constraint orders_quantity_positive",
+	}
+	if strings.Join(kinds, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("changes:\n%s\nwant:\n%s", strings.Join(kinds, "\n"), strings.Join(want, "\n"))
+	}
+	if ddl := diff.Changes[0].DDL; !diff.Changes[0].IsDestructive ||
+		ddl != `ALTER TABLE "app"."orders" DROP CONSTRAINT "orders_customer_fkey";`+"\n"+
+			`ALTER TABLE "app"."orders" ADD CONSTRAINT "orders_customer_fkey" FOREIGN KEY ("customer_id") REFERENCES "app"."customers" ("id") ON DELETE CASCADE;`+"\n" {
+		t.Errorf("recreate DDL:\n%s", ddl)
+	}
+	if ddl := diff.DDL(false); ddl != `ALTER TABLE "app"."orders" ADD CONSTRAINT "orders_quantity_positive" CHECK (quantity > 0);`+"\n" {
+		t.Errorf("safe DDL:\n%s", ddl)
+	}
+
+	ct.ForeignKeys = nil
+	fk := ct.addForeignKeyColumn("orders_customer_fkey", "customer_id", "app", "customers", "id")
+	if diff, err = db.Diff(base.DXDatabaseTypePostgreSQL, catalog); err != nil || len(diff.Changes) != 2 ||
+		diff.Changes[0].Kind != ModelDBSchemaChangeRecreateForeignKey {
+		t.Fatalf("a foreign key whose ON DELETE changed must be recreated: %+v, %v", diff, err)
+	}
+	fk.OnDelete, fk.OnUpdate = "CASCADE", "NO ACTION"
+	if diff, err = db.Diff(base.DXDatabaseTypePostgreSQL, catalog); err != nil || len(diff.Changes) != 1 {
+		t.Fatalf("a matching foreign key must be left alone: %+v, %v", diff, err)
+	}
+	fk.IsDeferrable = true
+	if diff, err = db.Diff(base.DXDatabaseTypePostgreSQL, catalog); err != nil || len(diff.Changes) != 2 {
+		t.Fatalf("a foreign key that is no longer deferrable must be recreated: %+v, %v", diff, err)
+	}
+}
+
+// The model created on SQLite reads back from its catalog without differences
+func TestModelDBCatalogSQLite(t *testing.T) {
+	db := NewModelDB("test", nil)
+	schema := NewModelDBSchema(db, "app", 1)
+	integer := types.DataType{TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypeSQLite: "INTEGER"}}
+	text := types.DataType{TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypeSQLite: "VARCHAR(255)"}}
+	customers := NewModelDBTable(schema, "customers", 1, map[string]*ModelDBField{
+		"id":    {Order: 1, Type: integer, IsPrimaryKey: true},
+		"email": {Order: 2, Type: text, IsNotNull: true, IsUnique: true},
+	}, ModelDBTDEConfig{})
+	NewModelDBIndexForTable(customers, "customers_email_idx", 1, []ModelDBIndexColumn{{Name: "email"}}, false)
+	orders := NewModelDBTable(schema, "orders", 2, map[string]*ModelDBField{
+		"id":          {Order: 1, Type: integer, IsPrimaryKey: true},
+		"customer_id": {Order: 2, Type: integer},
+		"quantity":    {Order: 3, Type: integer},
+	}, ModelDBTDEConfig{})
+	NewModelDBForeignKey(orders, "orders_customer_fkey", []string{"customer_id"}, "app.customers", []string{"id"}).
+		SetOnDelete(ModelDBForeignKeyActionCascade).SetDeferrable(true)
+	NewModelDBCheckConstraint(orders, "orders_quantity_positive", "quantity > 0")
+	if err := db.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	connection, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = connection.Close()
+	}()
+	connection.SetMaxOpenConns(1)
+	for _, table := range []*ModelDBTable{customers, orders} {
+		ddl, err := table.CreateDDL(base.DXDatabaseTypeSQLite)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = connection.Exec(ddl); err != nil {
+			t.Fatalf("%v\n%s", err, ddl)
+		}
+	}
+	ddl, err := customers.Indexes[0].CreateDDL(base.DXDatabaseTypeSQLite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = connection.Exec(ddl + `CREATE INDEX "orders_legacy_idx" ON "app.orders" ("quantity");`); err != nil {
+		t.Fatal(err)
+	}
+
+	catalog, err := ReadModelDBCatalog(context.Background(), connection, base.DXDatabaseTypeSQLite, []string{"app"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ct := catalog.Tables["app.orders"]
+	if ct == nil || len(ct.Columns) != 3 || !ct.Columns["id"].IsIdentity || ct.Columns["id"].IsNullable ||
+		strings.Join(ct.PrimaryKey(), ",") != "id" {
+		t.Fatalf("orders = %+v", ct)
+	}
+	if fk := ct.foreignKey("orders_customer_fkey"); fk == nil || fk.ReferencedSchema != "app" || fk.ReferencedTable != "customers" ||
+		fk.OnDelete != "CASCADE" || !fk.IsDeferrable || !fk.IsInitiallyDeferred {
+		t.Fatalf("foreign keys = %+v", ct.ForeignKeys)
+	}
+	if ct.CheckConstraints["orders_quantity_positive"] == "" {
+		t.Fatalf("check constraints = %v", ct.CheckConstraints)
+	}
+
+	diff, err := db.Diff(base.DXDatabaseTypeSQLite, catalog)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diff.Changes) != 1 || diff.Changes[0].Kind != ModelDBSchemaChangeDropIndex ||
+		diff.Changes[0].DDL != `DROP INDEX "orders_legacy_idx";`+"\n" {
+		t.Fatalf("changes = %+v", diff.Changes)
+	}
+
+	orders.Fields["quantity"].Type = text
+	orders.Fields["quantity"].IsNotNull = true
+	if diff, err = db.Diff(base.DXDatabaseTypeSQLite, catalog); err != nil {
+		t.Fatal(err)
+	}
+	if len(diff.Unsupported()) != 2 || strings.Contains(diff.DDL(true), "ALTER COLUMN") {
+		t.Fatalf("a column change must be reported as unsupported on SQLite: %+v\n%s", diff.Changes, diff.DDL(true))
+	}
+}
@@ -13,6 +13,8 @@ const (
 	ModelDBEntityTypeFunction
 	ModelDBEntityTypeIndex
 	ModelDBEntityTypeTrigger
+	ModelDBEntityTypeForeignKey
+	ModelDBEntityTypeCheckConstraint
 )
 
 // ============================================================================
@@ -91,6 +91,10 @@ func (f *ModelDBFunction) CreateDDL(dbType base.DXDatabaseType) (string, error)
 		return f.createMariaDBDDL(), nil
 	case base.DXDatabaseTypeOracle:
 		return f.createOracleDDL(), nil
+	case base.DXDatabaseTypeSQLite:
+		// SQLite has no stored functions; a trigger inlines BodyByDB[SQLite] of its
+		// ExecuteFunction instead (see ModelDBTrigger.createSQLiteDDL)
+		return "", nil
 	default:
 		return "", fmt.Errorf("unsupported databases type: %v", dbType)
 	}
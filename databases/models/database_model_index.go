@@ -42,6 +42,13 @@ type ModelDBIndex struct {
 	Concurrent  bool               // CREATE INDEX CONCURRENTLY (PostgreSQL)
 	IfNotExists bool               // CREATE INDEX IF NOT EXISTS
 
+	// Full-text index (NewModelDBFullTextIndexForTable)
+	IsFullText           bool
+	FullTextConfig       string // PostgreSQL text search configuration, default "simple"
+	FullTextVectorColumn string // PostgreSQL: generated tsvector column indexed instead of the expression
+	FullTextKeyIndexName string // SQL Server: unique single-column index the full-text index is keyed on
+	FullTextCatalog      string // SQL Server: full-text catalog, empty for the default catalog
+
 	// Owner reference - either Table or MaterializedView (set by NewDBIndex*)
 	OwnerTable            *ModelDBTable
 	OwnerMaterializedView *ModelDBMaterializedView
@@ -147,6 +154,9 @@ func (i *ModelDBIndex) quotedColumns(dbType base.DXDatabaseType, withNulls bool)
 
 // CreateDDL generates DDL script for the index based on databases type
 func (i *ModelDBIndex) CreateDDL(dbType base.DXDatabaseType) (string, error) {
+	if i.IsFullText {
+		return i.createFullTextDDL(dbType)
+	}
 	switch dbType {
 	case base.DXDatabaseTypePostgreSQL:
 		return i.createPostgreSQLDDL(), nil
@@ -156,6 +166,8 @@ func (i *ModelDBIndex) CreateDDL(dbType base.DXDatabaseType) (string, error) {
 		return i.createMariaDBDDL(), nil
 	case base.DXDatabaseTypeOracle:
 		return i.createOracleDDL(), nil
+	case base.DXDatabaseTypeSQLite:
+		return i.createSQLiteDDL(), nil
 	default:
 		return "", fmt.Errorf("unsupported databases type: %v", dbType)
 	}
@@ -306,3 +318,34 @@ func (i *ModelDBIndex) createOracleDDL() string {
 
 	return sb.String()
 }
+
+func (i *ModelDBIndex) createSQLiteDDL() string {
+	var sb strings.Builder
+
+	sb.WriteString("CREATE ")
+	if i.IsUnique {
+		sb.WriteString("UNIQUE ")
+	}
+	sb.WriteString("INDEX ")
+	if i.IfNotExists {
+		sb.WriteString("IF NOT EXISTS ")
+	}
+	sb.WriteString(quoteIdent(base.DXDatabaseTypeSQLite, i.Name))
+	sb.WriteString(" ON ")
+	sb.WriteString(i.qualifiedOwnerName(base.DXDatabaseTypeSQLite))
+
+	// Columns (SQLite has no NULLS FIRST/LAST in index definitions)
+	sb.WriteString(" (")
+	sb.WriteString(i.quotedColumns(base.DXDatabaseTypeSQLite, false))
+	sb.WriteString(")")
+
+	// Where clause for partial index
+	if i.Where != "" {
+		sb.WriteString(" WHERE ")
+		sb.WriteString(i.Where)
+	}
+
+	sb.WriteString(";\n")
+
+	return sb.String()
+}
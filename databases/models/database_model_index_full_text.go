@@ -0,0 +1,145 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/donnyhardyanto/dxlib/base"
+)
+
+// Full-text indexes back builder.FullTextSearchDef searches:
+//   - PostgreSQL: GIN index on to_tsvector(config, ...) or on a generated tsvector column
+//   - MariaDB: FULLTEXT index on the columns
+//   - SQL Server: FULLTEXT INDEX keyed on a unique index (created with the table only: SQL Server
+//     full-text indexes have no name, so the schema diff cannot find them again)
+//   - Oracle: Oracle Text CONTEXT index on the first column, with a MULTI_COLUMN_DATASTORE for more
+//   - SQLite: not supported (searches fall back to LIKE)
+
+// NewModelDBFullTextIndexForTable creates a full-text index over columns of table
+func NewModelDBFullTextIndexForTable(table *ModelDBTable, name string, order int, columns []string) *ModelDBIndex {
+	indexColumns := make([]ModelDBIndexColumn, len(columns))
+	for i, c := range columns {
+		indexColumns[i] = ModelDBIndexColumn{Name: c}
+	}
+	idx := NewModelDBIndexForTable(table, name, order, indexColumns, false)
+	idx.IsFullText = true
+	idx.Method = ModelDBIndexMethodGIN
+	return idx
+}
+
+// SetFullTextConfig sets the PostgreSQL text search configuration (default "simple")
+func (i *ModelDBIndex) SetFullTextConfig(config string) *ModelDBIndex {
+	i.FullTextConfig = config
+	return i
+}
+
+// SetFullTextVectorColumn makes the PostgreSQL index use a generated tsvector column, added by CreateDDL
+func (i *ModelDBIndex) SetFullTextVectorColumn(columnName string) *ModelDBIndex {
+	i.FullTextVectorColumn = columnName
+	return i
+}
+
+// SetFullTextKey sets the SQL Server unique index the full-text index is keyed on, and its catalog
+// (empty for the default catalog)
+func (i *ModelDBIndex) SetFullTextKey(keyIndexName string, catalog string) *ModelDBIndex {
+	i.FullTextKeyIndexName = keyIndexName
+	i.FullTextCatalog = catalog
+	return i
+}
+
+func (i *ModelDBIndex) fullTextConfig() string {
+	if i.FullTextConfig == "" {
+		return "simple"
+	}
+	return i.FullTextConfig
+}
+
+// fullTextVectorExpression must stay the same as builder.FullTextVectorExpression, or PostgreSQL
+// searches without a vector column cannot use the expression index.
+func (i *ModelDBIndex) fullTextVectorExpression() string {
+	parts := make([]string, len(i.Columns))
+	for n, col := range i.Columns {
+		parts[n] = "coalesce(" + quoteIdent(base.DXDatabaseTypePostgreSQL, col.Name) + ", '')"
+	}
+	return "to_tsvector('" + i.fullTextConfig() + "', " + strings.Join(parts, " || ' ' || ") + ")"
+}
+
+// CreateFullTextVectorColumnDDL generates the PostgreSQL generated tsvector column of the index,
+// empty when it has none or on other databases
+func (i *ModelDBIndex) CreateFullTextVectorColumnDDL(dbType base.DXDatabaseType) string {
+	if !i.IsFullText || i.FullTextVectorColumn == "" || dbType != base.DXDatabaseTypePostgreSQL {
+		return ""
+	}
+	return fmt.Sprintf("ALTER TABLE %s ADD COLUMN IF NOT EXISTS %s tsvector GENERATED ALWAYS AS (%s) STORED;\n",
+		i.qualifiedOwnerName(dbType), quoteIdent(dbType, i.FullTextVectorColumn), i.fullTextVectorExpression())
+}
+
+func (i *ModelDBIndex) createFullTextDDL(dbType base.DXDatabaseType) (string, error) {
+	if len(i.Columns) == 0 {
+		return "", fmt.Errorf("full-text index %s has no columns", i.Name)
+	}
+	var sb strings.Builder
+	switch dbType {
+	case base.DXDatabaseTypePostgreSQL:
+		sb.WriteString(i.CreateFullTextVectorColumnDDL(dbType))
+		sb.WriteString("CREATE INDEX ")
+		if i.Concurrent {
+			sb.WriteString("CONCURRENTLY ")
+		}
+		if i.IfNotExists {
+			sb.WriteString("IF NOT EXISTS ")
+		}
+		sb.WriteString(quoteIdent(dbType, i.Name))
+		sb.WriteString(" ON ")
+		sb.WriteString(i.qualifiedOwnerName(dbType))
+		sb.WriteString(" USING GIN (")
+		if i.FullTextVectorColumn != "" {
+			sb.WriteString(quoteIdent(dbType, i.FullTextVectorColumn))
+		} else {
+			sb.WriteString(i.fullTextVectorExpression())
+		}
+		sb.WriteString(");\n")
+	case base.DXDatabaseTypeMariaDB:
+		fmt.Fprintf(&sb, "CREATE FULLTEXT INDEX %s ON %s (%s);\n", quoteIdent(dbType, i.Name), i.qualifiedOwnerName(dbType), i.quotedColumns(dbType, false))
+	case base.DXDatabaseTypeSQLServer:
+		if i.FullTextKeyIndexName == "" {
+			return "", fmt.Errorf("full-text index %s needs a key index on SQL Server (SetFullTextKey)", i.Name)
+		}
+		fmt.Fprintf(&sb, "CREATE FULLTEXT INDEX ON %s (%s) KEY INDEX %s", i.qualifiedOwnerName(dbType), i.quotedColumns(dbType, false), quoteIdent(dbType, i.FullTextKeyIndexName))
+		if i.FullTextCatalog != "" {
+			sb.WriteString(" ON ")
+			sb.WriteString(quoteIdent(dbType, i.FullTextCatalog))
+		}
+		sb.WriteString(" WITH CHANGE_TRACKING AUTO;\n")
+	case base.DXDatabaseTypeOracle:
+		parameters := "SYNC (ON COMMIT)"
+		if len(i.Columns) > 1 {
+			// One CONTEXT index covers all columns through a datastore preference
+			preference := strings.ToUpper(i.Name) + "_DS"
+			columnNames := make([]string, len(i.Columns))
+			for n, col := range i.Columns {
+				columnNames[n] = strings.ToUpper(col.Name)
+			}
+			fmt.Fprintf(&sb, "BEGIN\n    CTX_DDL.CREATE_PREFERENCE('%s', 'MULTI_COLUMN_DATASTORE');\n    CTX_DDL.SET_ATTRIBUTE('%s', 'COLUMNS', '%s');\nEND;\n/\n",
+				preference, preference, strings.Join(columnNames, ", "))
+			parameters = "DATASTORE " + preference + " " + parameters
+		}
+		fmt.Fprintf(&sb, "CREATE INDEX %s ON %s (%s) INDEXTYPE IS CTXSYS.CONTEXT PARAMETERS ('%s');\n",
+			quoteIdent(dbType, i.Name), i.qualifiedOwnerName(dbType), quoteIdent(dbType, i.Columns[0].Name), parameters)
+	case base.DXDatabaseTypeSQLite:
+		fmt.Fprintf(&sb, "-- SQLite: full-text index %s not supported, searches fall back to LIKE\n", i.Name)
+	default:
+		return "", fmt.Errorf("unsupported databases type: %v", dbType)
+	}
+	return sb.String(), nil
+}
+
+// isFullTextVectorColumn reports whether lowerName is the generated tsvector column of a full-text index
+func (t *ModelDBTable) isFullTextVectorColumn(lowerName string) bool {
+	for _, idx := range t.Indexes {
+		if idx.IsFullText && strings.ToLower(idx.FullTextVectorColumn) == lowerName {
+			return true
+		}
+	}
+	return false
+}
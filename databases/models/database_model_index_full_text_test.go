@@ -0,0 +1,53 @@
+package models
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/donnyhardyanto/dxlib/base"
+)
+
+func TestModelDBFullTextIndexDDL(t *testing.T) {
+	db := NewModelDB("test", nil)
+	schema := NewModelDBSchema(db, "app", 1)
+	table := NewModelDBTable(schema, "articles", 1, map[string]*ModelDBField{}, ModelDBTDEConfig{})
+	idx := NewModelDBFullTextIndexForTable(table, "articles_fts", 1, []string{"title", "body"})
+
+	cases := []struct {
+		dbType base.DXDatabaseType
+		want   []string
+	}{
+		{base.DXDatabaseTypePostgreSQL, []string{`USING GIN (to_tsvector('simple', coalesce("title", '') || ' ' || coalesce("body", '')))`}},
+		{base.DXDatabaseTypeMariaDB, []string{"CREATE FULLTEXT INDEX `articles_fts` ON `app.articles` (`title`, `body`);"}},
+		{base.DXDatabaseTypeOracle, []string{"CTX_DDL.SET_ATTRIBUTE('ARTICLES_FTS_DS', 'COLUMNS', 'TITLE, BODY');",
+			`ON "APP"."ARTICLES" ("TITLE") INDEXTYPE IS CTXSYS.CONTEXT PARAMETERS ('DATASTORE ARTICLES_FTS_DS SYNC (ON COMMIT)');`}},
+	}
+	for _, c := range cases {
+		ddl, err := idx.CreateDDL(c.dbType)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, want := range c.want {
+			if !strings.Contains(ddl, want) {
+				t.Errorf("%s DDL:\n%s\nmissing %s", c.dbType.String(), ddl, want)
+			}
+		}
+	}
+
+	if _, err := idx.CreateDDL(base.DXDatabaseTypeSQLServer); err == nil {
+		t.Error("SQL Server full-text index without a key index must fail")
+	}
+	ddl, _ := idx.SetFullTextKey("articles_pkey", "").CreateDDL(base.DXDatabaseTypeSQLServer)
+	if !strings.Contains(ddl, "CREATE FULLTEXT INDEX ON [app].[articles] ([title], [body]) KEY INDEX [articles_pkey]") {
+		t.Errorf("SQL Server DDL: %s", ddl)
+	}
+
+	ddl, _ = idx.SetFullTextConfig("english").SetFullTextVectorColumn("search_vector").CreateDDL(base.DXDatabaseTypePostgreSQL)
+	if !strings.Contains(ddl, `ADD COLUMN IF NOT EXISTS "search_vector" tsvector GENERATED ALWAYS AS (to_tsvector('english',`) ||
+		!strings.Contains(ddl, `USING GIN ("search_vector")`) {
+		t.Errorf("PostgreSQL vector column DDL: %s", ddl)
+	}
+	if !table.isFullTextVectorColumn("search_vector") {
+		t.Error("the generated vector column must not be diffed as an unknown column")
+	}
+}
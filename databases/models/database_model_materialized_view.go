@@ -229,6 +229,15 @@ func (mv *ModelDBMaterializedView) FullMaterializedViewName() string {
 	return mv.Name
 }
 
+// sqliteTableName is the quoted name of the table emulating the view on SQLite.
+func (mv *ModelDBMaterializedView) sqliteTableName() string {
+	schemaName := ""
+	if mv.Schema != nil {
+		schemaName = mv.Schema.Name
+	}
+	return qualifiedTableName(base.DXDatabaseTypeSQLite, schemaName, mv.Name)
+}
+
 // CreateDDL generates the CREATE MATERIALIZED VIEW DDL statement
 func (mv *ModelDBMaterializedView) CreateDDL(dbType base.DXDatabaseType) (string, error) {
 	// Get the SELECT SQL - either from RawSQL or build from fields
@@ -300,6 +309,21 @@ func (mv *ModelDBMaterializedView) CreateDDL(dbType base.DXDatabaseType) (string
 				strings.Join(mv.UniqueIndexColumns, ", "))
 		}
 
+	case base.DXDatabaseTypeSQLite:
+		// SQLite has no materialized views either: a table, named like the other
+		// virtual-schema tables
+		fmt.Fprintf(&sb, "-- SQLite: Materialized view emulated as table %s\n", mv.FullMaterializedViewName())
+		fmt.Fprintf(&sb, "CREATE TABLE %s AS\n", mv.sqliteTableName())
+		sb.WriteString(selectSQL)
+		sb.WriteString(";\n")
+		if len(mv.UniqueIndexColumns) > 0 {
+			indexName := fmt.Sprintf("idx_%s_pk", mv.Name)
+			fmt.Fprintf(&sb, "\nCREATE UNIQUE INDEX %s ON %s (%s);\n",
+				indexName,
+				mv.sqliteTableName(),
+				strings.Join(mv.UniqueIndexColumns, ", "))
+		}
+
 	default:
 		return "", fmt.Errorf("unsupported databases type for materialized view: %v", dbType)
 	}
@@ -532,6 +556,8 @@ func (mv *ModelDBMaterializedView) DropDDL(dbType base.DXDatabaseType) string {
 		return fmt.Sprintf("DROP MATERIALIZED VIEW %s;\n", mv.FullMaterializedViewName())
 	case base.DXDatabaseTypeMariaDB:
 		return fmt.Sprintf("DROP TABLE IF EXISTS %s;\n", mv.FullMaterializedViewName())
+	case base.DXDatabaseTypeSQLite:
+		return fmt.Sprintf("DROP TABLE IF EXISTS %s;\n", mv.sqliteTableName())
 	default:
 		return fmt.Sprintf("DROP MATERIALIZED VIEW IF EXISTS %s;\n", mv.FullMaterializedViewName())
 	}
@@ -554,6 +580,13 @@ func (mv *ModelDBMaterializedView) RefreshDDL(dbType base.DXDatabaseType, concur
 			return "", err
 		}
 		return fmt.Sprintf("TRUNCATE TABLE %s;\nINSERT INTO %s %s;\n", mv.FullMaterializedViewName(), mv.FullMaterializedViewName(), selectSQL), nil
+	case base.DXDatabaseTypeSQLite:
+		// SQLite has no TRUNCATE; an unqualified DELETE is optimized into one
+		selectSQL, err := mv.buildSelectSQL(dbType)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("DELETE FROM %s;\nINSERT INTO %s %s;\n", mv.sqliteTableName(), mv.sqliteTableName(), selectSQL), nil
 	default:
 		return fmt.Sprintf("-- Refresh materialized view %s\n", mv.FullMaterializedViewName()), nil
 	}
@@ -0,0 +1,91 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/donnyhardyanto/dxlib/base"
+)
+
+// ModelDBNotify makes a PostgreSQL table announce every inserted, updated and deleted row with
+// pg_notify on Channel, for subscribers of databases.DXDatabase.Subscribe. The payload is a
+// JSON ModelDBRowChange carrying the IdFieldName and, when set, UtagFieldName of the row.
+type ModelDBNotify struct {
+	Channel       string
+	IdFieldName   string
+	UtagFieldName string
+}
+
+// ModelDBRowChange is the payload of a row change notification
+type ModelDBRowChange struct {
+	Table     string  `json:"table"` // schema.table
+	Operation string  `json:"op"`    // INSERT, UPDATE or DELETE
+	Id        any     `json:"id"`
+	Utag      string  `json:"utag,omitempty"` // the utag column as text
+	At        float64 `json:"at"`             // when the row changed, Unix seconds
+}
+
+// SetNotify makes the table announce its row changes on channel (see ModelDBNotify)
+func (t *ModelDBTable) SetNotify(channel string, idFieldName string, utagFieldName string) *ModelDBTable {
+	t.Notify = &ModelDBNotify{
+		Channel:       channel,
+		IdFieldName:   idFieldName,
+		UtagFieldName: utagFieldName,
+	}
+	return t
+}
+
+// NotifyTriggerName returns the name of the row change trigger of the table and of its function
+func (t *ModelDBTable) NotifyTriggerName() string {
+	return t.TableName() + "_notify"
+}
+
+// NotifyTriggerDDL generates the PostgreSQL trigger function and the AFTER ... FOR EACH ROW
+// trigger that announce the row changes of a table with Notify. Other databases have no
+// pg_notify and get an empty script.
+func (t *ModelDBTable) NotifyTriggerDDL(dbType base.DXDatabaseType) (string, error) {
+	if t.Notify == nil || dbType != base.DXDatabaseTypePostgreSQL {
+		return "", nil
+	}
+	n := t.Notify
+	if n.Channel == "" {
+		return "", fmt.Errorf("notify channel of table %s is empty", t.FullTableName())
+	}
+	fieldNames := []string{n.IdFieldName}
+	if n.UtagFieldName != "" {
+		fieldNames = append(fieldNames, n.UtagFieldName)
+	}
+	for _, fieldName := range fieldNames {
+		if _, ok := t.Fields[fieldName]; !ok {
+			return "", fmt.Errorf("notify field %s not found in table %s", fieldName, t.FullTableName())
+		}
+	}
+
+	schemaName := ""
+	if t.Schema != nil {
+		schemaName = t.Schema.Name
+	}
+	tableName := qualifiedTableName(dbType, schemaName, t.TableName())
+	functionName := qualifiedTableName(dbType, schemaName, t.NotifyTriggerName())
+
+	payload := []string{
+		"'table', TG_TABLE_SCHEMA || '.' || TG_TABLE_NAME",
+		"'op', TG_OP",
+		"'id', r." + quoteIdent(dbType, n.IdFieldName),
+	}
+	if n.UtagFieldName != "" {
+		// as text, whatever the column type, to decode into ModelDBRowChange.Utag
+		payload = append(payload, "'utag', r."+quoteIdent(dbType, n.UtagFieldName)+"::text")
+	}
+	payload = append(payload, "'at', extract(epoch FROM clock_timestamp())")
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("CREATE OR REPLACE FUNCTION %s()\n    RETURNS TRIGGER AS\n$$\n", functionName))
+	sb.WriteString("DECLARE\n    r RECORD;\nBEGIN\n")
+	sb.WriteString("    IF TG_OP = 'DELETE' THEN\n        r := OLD;\n    ELSE\n        r := NEW;\n    END IF;\n")
+	sb.WriteString(fmt.Sprintf("    PERFORM pg_notify('%s', json_build_object(%s)::text);\n", strings.ReplaceAll(n.Channel, "'", "''"), strings.Join(payload, ", ")))
+	sb.WriteString("    RETURN NULL;\nEND;\n$$ LANGUAGE plpgsql;\n")
+	sb.WriteString(fmt.Sprintf("CREATE TRIGGER %s\n    AFTER INSERT OR UPDATE OR DELETE\n    ON %s\n    FOR EACH ROW\n    EXECUTE FUNCTION %s();\n",
+		quoteIdent(dbType, t.NotifyTriggerName()), tableName, functionName))
+	return sb.String(), nil
+}
@@ -0,0 +1,112 @@
+package models
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/donnyhardyanto/dxlib/base"
+	"github.com/donnyhardyanto/dxlib/types"
+)
+
+func TestModelDBTableNotifyTriggerDDL(t *testing.T) {
+	schema := NewModelDBSchema(NewModelDB("test", nil), "app", 1)
+	table := NewModelDBTable(schema, "orders", 1, map[string]*ModelDBField{
+		"id":   {Order: 1, Type: types.DataTypeBigSerial, IsPrimaryKey: true},
+		"utag": {Order: 2, Type: types.DataTypeString255},
+	}, ModelDBTDEConfig{}).SetNotify("order_changes", "id", "utag")
+
+	ddl, err := table.CreateDDL(base.DXDatabaseTypePostgreSQL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`CREATE OR REPLACE FUNCTION "app"."orders_notify"()`,
+		`PERFORM pg_notify('order_changes', json_build_object('table', TG_TABLE_SCHEMA || '.' || TG_TABLE_NAME, 'op', TG_OP, 'id', r."id", 'utag', r."utag"::text, 'at', extract(epoch FROM clock_timestamp()))::text);`,
+		`AFTER INSERT OR UPDATE OR DELETE`,
+		`EXECUTE FUNCTION "app"."orders_notify"();`,
+	} {
+		if !strings.Contains(ddl, want) {
+			t.Errorf("DDL:\n%s\nmissing %s", ddl, want)
+		}
+	}
+
+	if ddl, err = table.CreateDDL(base.DXDatabaseTypeMariaDB); err != nil || strings.Contains(ddl, "pg_notify") {
+		t.Fatalf("MariaDB DDL:\n%s\nerr = %v", ddl, err)
+	}
+	if _, err = table.SetNotify("order_changes", "id", "version").CreateDDL(base.DXDatabaseTypePostgreSQL); err == nil {
+		t.Fatal("an unknown utag field must fail")
+	}
+}
+
+func TestModelDBDiffNotifyTrigger(t *testing.T) {
+	db := NewModelDB("test", nil)
+	schema := NewModelDBSchema(db, "app", 1)
+	bigint := types.DataType{TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "BIGINT"}}
+	NewModelDBTable(schema, "orders", 1, map[string]*ModelDBField{
+		"id": {Order: 1, Type: bigint, IsPrimaryKey: true},
+	}, ModelDBTDEConfig{}).SetNotify("order_changes", "id", "")
+
+	catalog := &ModelDBCatalog{
+		DBType:            base.DXDatabaseTypePostgreSQL,
+		Schemas:           map[string]bool{"app": true},
+		Tables:            map[string]*ModelDBCatalogTable{},
+		Views:             map[string]ModelDBCatalogObject{},
+		MaterializedViews: map[string]ModelDBCatalogObject{},
+	}
+	orders := catalog.namedTable("app", "orders")
+	orders.Columns["id"] = &ModelDBCatalogColumn{Name: "id", Type: "bigint"}
+	orders.addIndexColumn("orders_pkey", true, true, true, "id")
+
+	diff, err := db.Diff(base.DXDatabaseTypePostgreSQL, catalog)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diff.Changes) != 1 || diff.Changes[0].Kind != ModelDBSchemaChangeCreateTrigger || diff.Changes[0].Object != "orders_notify" ||
+		!strings.Contains(diff.Changes[0].DDL, `CREATE TRIGGER "orders_notify"`) {
+		t.Fatalf("a missing notify trigger must be created: %+v", diff.Changes)
+	}
+
+	orders.Triggers["orders_notify"] = "orders_notify"
+	if diff, err = db.Diff(base.DXDatabaseTypePostgreSQL, catalog); err != nil || diff.HasChanges() {
+		t.Fatalf("an existing notify trigger must be left alone: %+v, %v", diff, err)
+	}
+}
+
+func TestModelDBDiffDropTrigger(t *testing.T) {
+	db := NewModelDB("test", nil)
+	schema := NewModelDBSchema(db, "app", 1)
+	bigint := types.DataType{TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "BIGINT"}}
+	NewModelDBTable(schema, "orders", 1, map[string]*ModelDBField{
+		"id": {Order: 1, Type: bigint, IsPrimaryKey: true},
+	}, ModelDBTDEConfig{})
+
+	for dbType, want := range map[base.DXDatabaseType]string{
+		base.DXDatabaseTypePostgresSQLV2: `DROP TRIGGER "orders_audit" ON "app"."orders";` + "\n",
+		base.DXDatabaseTypeSQLite:        `DROP TRIGGER "orders_audit";` + "\n",
+	} {
+		catalog := &ModelDBCatalog{
+			DBType:            dbType,
+			Schemas:           map[string]bool{"app": true},
+			Tables:            map[string]*ModelDBCatalogTable{},
+			Views:             map[string]ModelDBCatalogObject{},
+			MaterializedViews: map[string]ModelDBCatalogObject{},
+		}
+		orders := catalog.namedTable("app", "orders")
+		orders.Columns["id"] = &ModelDBCatalogColumn{Name: "id", Type: "bigint"}
+		orders.Triggers["orders_audit"] = "orders_audit"
+
+		diff, err := db.Diff(dbType, catalog)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var ddl string
+		for _, c := range diff.Changes {
+			if c.Kind == ModelDBSchemaChangeDropTrigger {
+				ddl += c.DDL
+			}
+		}
+		if ddl != want {
+			t.Errorf("%s: drop DDL %q, want %q", dbType, ddl, want)
+		}
+	}
+}
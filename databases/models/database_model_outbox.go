@@ -0,0 +1,43 @@
+package models
+
+import (
+	"github.com/donnyhardyanto/dxlib/types"
+)
+
+// Transactional outbox: an event is written to the outbox table in the transaction that makes
+// the change it announces (databases.DXDatabaseTx.Publish), and a relay
+// (databases.DXOutboxRelay) delivers it once that transaction has committed. Events that keep
+// failing are moved to the dead-letter table.
+
+// OutboxDeadLetterTableName returns the dead-letter table of the outbox table outboxTableName
+func OutboxDeadLetterTableName(outboxTableName string) string {
+	return outboxTableName + "_dead_letter"
+}
+
+// NewModelDBOutboxTables creates the outbox table name and its dead-letter table in schema.
+// The relay finds due events through the index on (delivered_at, next_attempt_at).
+func NewModelDBOutboxTables(schema *ModelDBSchema, name string, order int) (outbox *ModelDBTable, deadLetter *ModelDBTable) {
+	outbox = NewModelDBTable(schema, name, order, map[string]*ModelDBField{
+		"id":              {Order: 1, Type: types.DataTypeBigSerial, IsPrimaryKey: true},
+		"topic":           {Order: 2, Type: types.DataTypeString255, IsNotNull: true},
+		"payload":         {Order: 3, Type: types.DataTypeJSON, IsNotNull: true},
+		"created_at":      {Order: 4, Type: types.DataTypeISO8601, IsNotNull: true},
+		"attempt_count":   {Order: 5, Type: types.DataTypeInt, IsNotNull: true, DefaultValue: 0},
+		"next_attempt_at": {Order: 6, Type: types.DataTypeISO8601, IsNotNull: true},
+		"last_error":      {Order: 7, Type: types.DataTypeString},
+		"delivered_at":    {Order: 8, Type: types.DataTypeISO8601},
+	}, ModelDBTDEConfig{})
+	NewModelDBIndexForTable(outbox, name+"_due", 1, []ModelDBIndexColumn{{Name: "delivered_at"}, {Name: "next_attempt_at"}}, false)
+
+	deadLetter = NewModelDBTable(schema, OutboxDeadLetterTableName(name), order+1, map[string]*ModelDBField{
+		"id":            {Order: 1, Type: types.DataTypeBigSerial, IsPrimaryKey: true},
+		"outbox_id":     {Order: 2, Type: types.DataTypeInt64, IsNotNull: true},
+		"topic":         {Order: 3, Type: types.DataTypeString255, IsNotNull: true},
+		"payload":       {Order: 4, Type: types.DataTypeJSON, IsNotNull: true},
+		"created_at":    {Order: 5, Type: types.DataTypeISO8601, IsNotNull: true},
+		"attempt_count": {Order: 6, Type: types.DataTypeInt, IsNotNull: true},
+		"last_error":    {Order: 7, Type: types.DataTypeString},
+		"dead_at":       {Order: 8, Type: types.DataTypeISO8601, IsNotNull: true},
+	}, ModelDBTDEConfig{})
+	return outbox, deadLetter
+}
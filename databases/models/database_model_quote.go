@@ -25,7 +25,7 @@ func quoteIdent(dbType base.DXDatabaseType, id string) string {
 		// UPPERCASE objects or nothing resolves. Quoting (vs bare) keeps reserved
 		// words like UID usable as column names.
 		return `"` + strings.ReplaceAll(strings.ToUpper(id), `"`, `""`) + `"`
-	default: // PostgreSQL
+	default: // PostgreSQL, SQLite
 		return `"` + strings.ReplaceAll(id, `"`, `""`) + `"`
 	}
 }
@@ -77,11 +77,12 @@ func renderDefaultForDBType(dbType base.DXDatabaseType, field ModelDBField, v an
 //     quoted identifier `schema.table` (the dot is part of the table name),
 //     living inside a normally-created database whose name is unrelated to the
 //     schema (so `CREATE DATABASE mydb` + many virtual schemas can coexist).
+//   - SQLite: no schema layer either; same single-identifier scheme, "schema.table".
 func qualifiedTableName(dbType base.DXDatabaseType, schema, table string) string {
 	if schema == "" {
 		return quoteIdent(dbType, table)
 	}
-	if dbType == base.DXDatabaseTypeMariaDB {
+	if dbType == base.DXDatabaseTypeMariaDB || dbType == base.DXDatabaseTypeSQLite {
 		return quoteIdent(dbType, schema+"."+table)
 	}
 	return quoteIdent(dbType, schema) + "." + quoteIdent(dbType, table)
@@ -0,0 +1,462 @@
+package models
+
+import (
+	"fmt"
+	"go/format"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/donnyhardyanto/dxlib/base"
+	"github.com/donnyhardyanto/dxlib/types"
+)
+
+// ============================================================================
+// Reverse engineering - Go source that builds the ModelDB of an existing database
+// ============================================================================
+
+// ModelDBReverseIssue is something GoSource could not express in the model. The source
+// still compiles; the object needs a manual look.
+type ModelDBReverseIssue struct {
+	Object string // schema.table[.column], or the view/trigger name
+	Type   string // catalog column type, for unmapped types
+	Reason string
+}
+
+type reverseDataType struct {
+	goName     string
+	dataType   types.DataType
+	isIdentity bool
+}
+
+// reverseDataTypes are tried in order; the first whose TypeByDatabaseType normalizes to
+// the column type wins, so narrower and more common types come first.
+var reverseDataTypes = []reverseDataType{
+	{"DataTypeBigSerial", types.DataTypeBigSerial, true},
+	{"DataTypeSerial", types.DataTypeSerial, true},
+	{"DataTypeInt64", types.DataTypeInt64, false},
+	{"DataTypeInt32", types.DataTypeInt32, false},
+	{"DataTypeString1", types.DataTypeString1, false},
+	{"DataTypeString5", types.DataTypeString5, false},
+	{"DataTypeString10", types.DataTypeString10, false},
+	{"DataTypeString20", types.DataTypeString20, false},
+	{"DataTypeString30", types.DataTypeString30, false},
+	{"DataTypeString50", types.DataTypeString50, false},
+	{"DataTypeString100", types.DataTypeString100, false},
+	{"DataTypeString255", types.DataTypeString255, false},
+	{"DataTypeString256", types.DataTypeString256, false},
+	{"DataTypeString500", types.DataTypeString500, false},
+	{"DataTypeString512", types.DataTypeString512, false},
+	{"DataTypeString1024", types.DataTypeString1024, false},
+	{"DataTypeString2048", types.DataTypeString2048, false},
+	{"DataTypeString8096", types.DataTypeString8096, false},
+	{"DataTypeString32768", types.DataTypeString32768, false},
+	{"DataTypeMoney", types.DataTypeMoney, false},
+	{"DataTypeDecimal", types.DataTypeDecimal, false},
+	{"DataTypeFloat32", types.DataTypeFloat32, false},
+	{"DataTypeFloat64", types.DataTypeFloat64, false},
+	{"DataTypeBool", types.DataTypeBool, false},
+	{"DataTypeISO8601", types.DataTypeISO8601, false},
+	{"DataTypeDate", types.DataTypeDate, false},
+	{"DataTypeTime", types.DataTypeTime, false},
+	{"DataTypeJSON", types.DataTypeJSON, false},
+	{"DataTypeArrayString", types.DataTypeArrayString, false},
+	{"DataTypeArrayInt64", types.DataTypeArrayInt64, false},
+	{"DataTypeBlob", types.DataTypeBlob, false},
+	{"DataTypeGeometryPoint", types.DataTypeGeometryPoint, false},
+}
+
+var databaseTypeGoNames = map[base.DXDatabaseType]string{
+	base.DXDatabaseTypePostgreSQL: "base.DXDatabaseTypePostgreSQL",
+	base.DXDatabaseTypeSQLServer:  "base.DXDatabaseTypeSQLServer",
+	base.DXDatabaseTypeMariaDB:    "base.DXDatabaseTypeMariaDB",
+	base.DXDatabaseTypeOracle:     "base.DXDatabaseTypeOracle",
+	base.DXDatabaseTypeSQLite:     "base.DXDatabaseTypeSQLite",
+}
+
+// reverseDataTypeFor returns the DataType of a column, nil when no DataType matches.
+func reverseDataTypeFor(dbType base.DXDatabaseType, column *ModelDBCatalogColumn) *reverseDataType {
+	columnType := normalizeColumnType(dbType, column.Type)
+	for _, isIdentity := range []bool{column.IsIdentity, false} {
+		for i := range reverseDataTypes {
+			candidate := &reverseDataTypes[i]
+			if candidate.isIdentity != isIdentity {
+				continue
+			}
+			if normalizeColumnType(dbType, candidate.dataType.TypeByDatabaseType[dbType]) == columnType {
+				return candidate
+			}
+		}
+	}
+	return nil
+}
+
+// reverseSource accumulates the generated function body.
+type reverseSource struct {
+	catalog   *ModelDBCatalog
+	sb        strings.Builder
+	issues    []ModelDBReverseIssue
+	usesBase  bool
+	usesTypes bool
+	idents    map[string]bool
+}
+
+// GoSource generates a Go file in packageName with a function funcName that builds the
+// ModelDB of the catalog: schemas, tables (columns, primary/foreign keys, unique
+// columns, defaults), indexes and views. Columns map to the types.DataType whose
+// TypeByDatabaseType matches; unmapped ones get an inline DataType for the source
+// engine and are returned as issues, like anything else the model cannot express.
+// View bodies are copied as raw SQL and stay engine specific.
+func (c *ModelDBCatalog) GoSource(packageName, funcName, dbName string) ([]byte, []ModelDBReverseIssue, error) {
+	if _, ok := databaseTypeGoNames[c.DBType]; !ok {
+		return nil, nil, fmt.Errorf("GoSource: unsupported databases type: %v", c.DBType)
+	}
+	r := &reverseSource{catalog: c, idents: map[string]bool{}}
+
+	tablesBySchema := map[string][]*ModelDBCatalogTable{}
+	viewsBySchema := map[string][]ModelDBCatalogObject{}
+	schemaNames := map[string]string{}
+	for _, t := range c.sortedTables() {
+		key := strings.ToLower(t.Schema)
+		tablesBySchema[key] = append(tablesBySchema[key], t)
+		schemaNames[key] = t.Schema
+	}
+	for _, key := range sortedCatalogKeys(c.Views) {
+		v := c.Views[key]
+		viewsBySchema[strings.ToLower(v.Schema)] = append(viewsBySchema[strings.ToLower(v.Schema)], v)
+		schemaNames[strings.ToLower(v.Schema)] = v.Schema
+	}
+	for _, key := range sortedCatalogKeys(c.MaterializedViews) {
+		mv := c.MaterializedViews[key]
+		r.issue(mv.Schema+"."+mv.Name, "", "materialized view not generated: add it with NewModelDBMaterializedViewRawSQL")
+	}
+	for schemaKey := range c.Schemas {
+		if _, ok := schemaNames[schemaKey]; !ok {
+			schemaNames[schemaKey] = schemaKey
+		}
+	}
+	schemaKeys := make([]string, 0, len(schemaNames))
+	for key := range schemaNames {
+		schemaKeys = append(schemaKeys, key)
+	}
+	sort.Strings(schemaKeys)
+
+	fmt.Fprintf(&r.sb, "\tdb := models.NewModelDB(%s, nil)\n", strconv.Quote(dbName))
+	for i, schemaKey := range schemaKeys {
+		schemaName := r.name(schemaNames[schemaKey])
+		tables, views := tablesBySchema[schemaKey], viewsBySchema[schemaKey]
+		r.sb.WriteString("\n")
+		if len(tables) == 0 && len(views) == 0 {
+			fmt.Fprintf(&r.sb, "\tmodels.NewModelDBSchema(db, %s, %d)\n", strconv.Quote(schemaName), i+1)
+			continue
+		}
+		schemaVar := r.ident("schema", schemaName)
+		fmt.Fprintf(&r.sb, "\t%s := models.NewModelDBSchema(db, %s, %d)\n", schemaVar, strconv.Quote(schemaName), i+1)
+		for j, t := range tables {
+			r.table(schemaVar, t, (j+1)*10)
+		}
+		for _, v := range views {
+			r.view(schemaVar, v)
+		}
+	}
+	r.sb.WriteString("\n\treturn db\n")
+
+	var src strings.Builder
+	fmt.Fprintf(&src, "// Generated by ModelDBCatalog.GoSource from a %s database; review before use.\n\n", c.DBType.String())
+	fmt.Fprintf(&src, "package %s\n\nimport (\n", packageName)
+	if r.usesBase {
+		src.WriteString("\t\"github.com/donnyhardyanto/dxlib/base\"\n")
+	}
+	src.WriteString("\t\"github.com/donnyhardyanto/dxlib/databases/models\"\n")
+	if r.usesTypes {
+		src.WriteString("\t\"github.com/donnyhardyanto/dxlib/types\"\n")
+	}
+	src.WriteString(")\n\n")
+	fmt.Fprintf(&src, "func %s() *models.ModelDB {\n%s}\n", funcName, r.sb.String())
+
+	formatted, err := format.Source([]byte(src.String()))
+	if err != nil {
+		return nil, r.issues, fmt.Errorf("GoSource: %w", err)
+	}
+	return formatted, r.issues, nil
+}
+
+// sortedTables orders tables so that referenced tables come before the tables that
+// reference them (CreateDDL runs in that order), by name otherwise.
+func (c *ModelDBCatalog) sortedTables() []*ModelDBCatalogTable {
+	keys := make([]string, 0, len(c.Tables))
+	for key := range c.Tables {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var sorted []*ModelDBCatalogTable
+	state := map[string]int{} // 1 visiting, 2 done
+	var visit func(key string)
+	visit = func(key string) {
+		t, ok := c.Tables[key]
+		if !ok || state[key] != 0 {
+			return
+		}
+		state[key] = 1
+		for _, fk := range t.ForeignKeys {
+			visit(catalogKey(fk.ReferencedSchema, fk.ReferencedTable))
+		}
+		state[key] = 2
+		sorted = append(sorted, t)
+	}
+	for _, key := range keys {
+		visit(key)
+	}
+	return sorted
+}
+
+func (r *reverseSource) issue(object, columnType, reason string) {
+	r.issues = append(r.issues, ModelDBReverseIssue{Object: object, Type: columnType, Reason: reason})
+}
+
+// name is how a catalog name is spelled in the model: Oracle folds unquoted names to
+// uppercase and quoteIdent uppercases them again, so the model uses lowercase.
+func (r *reverseSource) name(s string) string {
+	if r.catalog.DBType == base.DXDatabaseTypeOracle {
+		return strings.ToLower(s)
+	}
+	return s
+}
+
+// ident returns a unique Go identifier such as tableAppUsers.
+func (r *reverseSource) ident(prefix string, parts ...string) string {
+	var sb strings.Builder
+	sb.WriteString(prefix)
+	for _, part := range parts {
+		upper := true
+		for _, ch := range part {
+			if !unicode.IsLetter(ch) && !unicode.IsDigit(ch) {
+				upper = true
+				continue
+			}
+			if upper {
+				ch = unicode.ToUpper(ch)
+				upper = false
+			}
+			sb.WriteRune(ch)
+		}
+	}
+	ident := sb.String()
+	for i := 2; r.idents[ident]; i++ {
+		ident = fmt.Sprintf("%s%d", sb.String(), i)
+	}
+	r.idents[ident] = true
+	return ident
+}
+
+func (r *reverseSource) table(schemaVar string, t *ModelDBCatalogTable, order int) {
+	dbType := r.catalog.DBType
+	object := t.Schema + "." + t.Name
+	tableName := r.name(t.Name)
+
+	primaryKey := t.PrimaryKey()
+	if len(primaryKey) > 1 {
+		r.issue(object, "", "composite primary key generated as NOT NULL columns with a unique index")
+	}
+	uniqueColumns := map[string]bool{}
+	var indexes []*ModelDBCatalogIndex
+	for _, key := range sortedIndexKeys(t.Indexes) {
+		idx := t.Indexes[key]
+		switch {
+		case idx.IsPrimaryKey:
+			if len(idx.Columns) > 1 {
+				indexes = append(indexes, idx)
+			}
+		case idx.IsUnique && len(idx.Columns) == 1 && (idx.IsConstraint ||
+			(dbType == base.DXDatabaseTypeSQLServer && strings.EqualFold(idx.Name, t.Name+"_"+idx.Columns[0]+"_uidx"))):
+			// Regenerated by fieldToDDL / CreateDDL from IsUnique.
+			uniqueColumns[idx.Columns[0]] = true
+		case idx.IsConstraint && !idx.IsUnique:
+			// Backs a foreign key (MariaDB); created with it.
+		default:
+			indexes = append(indexes, idx)
+		}
+	}
+	references := map[string]string{}
+	for _, fk := range t.ForeignKeys {
+		if len(fk.Columns) != 1 {
+			r.issue(object, "", fmt.Sprintf("composite foreign key %s (%s) not generated", fk.Name, strings.Join(fk.Columns, ", ")))
+			continue
+		}
+		references[fk.Columns[0]] = r.name(fk.ReferencedSchema) + "." + r.name(fk.ReferencedTable) + "." + r.name(fk.ReferencedColumns[0])
+	}
+
+	columns := make([]*ModelDBCatalogColumn, 0, len(t.Columns))
+	for _, column := range t.Columns {
+		columns = append(columns, column)
+	}
+	sort.Slice(columns, func(i, j int) bool {
+		return columns[i].Ordinal < columns[j].Ordinal
+	})
+
+	var fields strings.Builder
+	for i, column := range columns {
+		lower := strings.ToLower(column.Name)
+		var attrs []string
+		attrs = append(attrs, fmt.Sprintf("Order: %d", i+1))
+
+		mapped := reverseDataTypeFor(dbType, column)
+		r.usesTypes = true
+		if mapped == nil {
+			r.usesBase = true
+			r.issue(object+"."+column.Name, column.Type, "no types.DataType matches; generated an inline DataType for "+dbType.String())
+			attrs = append(attrs, fmt.Sprintf("Type: types.DataType{TypeByDatabaseType: map[base.DXDatabaseType]string{%s: %s}}",
+				databaseTypeGoNames[dbType], strconv.Quote(column.Type)))
+		} else {
+			attrs = append(attrs, "Type: types."+mapped.goName)
+			if column.IsIdentity && !mapped.isIdentity {
+				r.issue(object+"."+column.Name, column.Type, "identity column mapped to a plain type; the model will not generate its values")
+			}
+		}
+		if len(primaryKey) == 1 && primaryKey[0] == lower {
+			attrs = append(attrs, "IsPrimaryKey: true")
+		} else if !column.IsNullable {
+			attrs = append(attrs, "IsNotNull: true")
+		}
+		if uniqueColumns[lower] {
+			attrs = append(attrs, "IsUnique: true")
+		}
+		if column.Default != "" && !column.IsIdentity {
+			if def := r.defaultValue(object+"."+column.Name, column, mapped); def != "" {
+				attrs = append(attrs, def)
+			}
+		}
+		if ref, ok := references[lower]; ok {
+			attrs = append(attrs, "References: "+strconv.Quote(ref))
+		}
+		fmt.Fprintf(&fields, "\t\t%s: {%s},\n", strconv.Quote(r.name(column.Name)), strings.Join(attrs, ", "))
+	}
+
+	for _, trigger := range sortedTriggerNames(t.Triggers) {
+		r.issue(object, "", "trigger "+trigger+" not generated: add it with NewModelDBTrigger")
+	}
+
+	tableVar := ""
+	if len(indexes) > 0 {
+		tableVar = r.ident("table", t.Schema, t.Name)
+		fmt.Fprintf(&r.sb, "\t%s := ", tableVar)
+	} else {
+		r.sb.WriteString("\t")
+	}
+	fmt.Fprintf(&r.sb, "models.NewModelDBTable(%s, %s, %d, map[string]*models.ModelDBField{\n%s\t}, models.ModelDBTDEConfig{})\n",
+		schemaVar, strconv.Quote(tableName), order, fields.String())
+
+	for i, idx := range indexes {
+		cols := make([]string, 0, len(idx.Columns))
+		for _, col := range idx.Columns {
+			cols = append(cols, fmt.Sprintf("{Name: %s}", strconv.Quote(r.name(col))))
+		}
+		fmt.Fprintf(&r.sb, "\tmodels.NewModelDBIndexForTable(%s, %s, %d, []models.ModelDBIndexColumn{%s}, %t)\n",
+			tableVar, strconv.Quote(r.name(idx.Name)), i+1, strings.Join(cols, ", "), idx.IsUnique)
+	}
+}
+
+func sortedIndexKeys(m map[string]*ModelDBCatalogIndex) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedTriggerNames(m map[string]string) []string {
+	names := make([]string, 0, len(m))
+	for _, name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (r *reverseSource) view(schemaVar string, v ModelDBCatalogObject) {
+	if v.Definition == "" {
+		r.issue(v.Schema+"."+v.Name, "", "view definition not readable; not generated")
+		return
+	}
+	body := "`" + v.Definition + "`"
+	if strings.Contains(v.Definition, "`") {
+		body = strconv.Quote(v.Definition)
+	}
+	fmt.Fprintf(&r.sb, "\tmodels.NewModelDBViewRawSQL(%s, %s, %s)\n", schemaVar, strconv.Quote(r.name(v.Name)), body)
+}
+
+var (
+	reverseDefaultCast    = regexp.MustCompile(`::[a-zA-Z ]+(\[\])?(\(\d+(,\s*\d+)?\))?$`)
+	reverseDefaultNumber  = regexp.MustCompile(`^-?\d+(\.\d+)?$`)
+	reverseDefaultLiteral = regexp.MustCompile(`^'((?:[^']|'')*)'$`)
+	reverseDefaultNow     = map[string]bool{
+		"now()": true, "current_timestamp": true, "current_timestamp()": true, "getdate()": true,
+		"sysdatetimeoffset()": true, "sysdatetime()": true, "systimestamp": true, "sysdate": true,
+	}
+)
+
+// defaultValue returns the ModelDBField attribute for a catalog DEFAULT: portable
+// literals and the current timestamp go to DefaultValue, other expressions to
+// DefaultValueByDBType of the source engine.
+func (r *reverseSource) defaultValue(object string, column *ModelDBCatalogColumn, mapped *reverseDataType) string {
+	def := strings.TrimSpace(column.Default)
+	// SQL Server wraps defaults in parentheses: ((0)), ('x'), (getdate()).
+	for strings.HasPrefix(def, "(") && strings.HasSuffix(def, ")") && balancedParentheses(def[1:len(def)-1]) {
+		def = strings.TrimSpace(def[1 : len(def)-1])
+	}
+	// PostgreSQL casts literals: 'x'::character varying, '0'::numeric.
+	for reverseDefaultCast.MatchString(def) {
+		def = reverseDefaultCast.ReplaceAllString(def, "")
+	}
+	def = strings.TrimPrefix(def, "N'")
+	if strings.HasPrefix(column.Default, "N'") || strings.Contains(column.Default, "(N'") {
+		def = "'" + def
+	}
+
+	isString := mapped != nil && (mapped.dataType.GoType == types.GoTypeString || mapped.dataType.GoType == types.GoTypeStringPointer)
+	isBool := mapped != nil && mapped.goName == "DataTypeBool"
+	lower := strings.ToLower(def)
+
+	switch {
+	case reverseDefaultNow[lower]:
+		return `DefaultValue: "now()"`
+	case isBool && (lower == "true" || lower == "1" || lower == "'1'"):
+		return "DefaultValue: true"
+	case isBool && (lower == "false" || lower == "0" || lower == "'0'"):
+		return "DefaultValue: false"
+	case reverseDefaultLiteral.MatchString(def):
+		value := strings.ReplaceAll(reverseDefaultLiteral.FindStringSubmatch(def)[1], "''", "'")
+		if isString || reverseDefaultNumber.MatchString(value) {
+			return "DefaultValue: " + strconv.Quote(value)
+		}
+	case reverseDefaultNumber.MatchString(def) && !isString:
+		return "DefaultValue: " + strconv.Quote(def)
+	}
+	if isString {
+		// String defaults are quoted as literals by the model (valueToSQLLiteral).
+		r.issue(object, column.Type, "default expression "+column.Default+" of a string column not generated")
+		return ""
+	}
+	r.usesBase = true
+	return fmt.Sprintf("DefaultValueByDBType: map[base.DXDatabaseType]any{%s: %s}", databaseTypeGoNames[r.catalog.DBType], strconv.Quote(def))
+}
+
+func balancedParentheses(s string) bool {
+	depth := 0
+	for _, ch := range s {
+		switch ch {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth < 0 {
+				return false
+			}
+		}
+	}
+	return depth == 0
+}
@@ -37,6 +37,19 @@ func NewModelDBSchema(db *ModelDB, name string, order int) *ModelDBSchema {
 	return schema
 }
 
+// createSchemaDDL returns the schema creation statement; empty on MariaDB and Oracle
+// (see CreateDDL).
+func (s *ModelDBSchema) createSchemaDDL(dbType base.DXDatabaseType) string {
+	switch dbType {
+	case base.DXDatabaseTypePostgreSQL:
+		return fmt.Sprintf("CREATE SCHEMA IF NOT EXISTS %s;\n", quoteIdent(dbType, s.Name))
+	case base.DXDatabaseTypeSQLServer:
+		return fmt.Sprintf("IF NOT EXISTS (SELECT * FROM sys.schemas WHERE name = '%s')\nBEGIN\n    EXEC('CREATE SCHEMA %s')\nEND;\n", s.Name, quoteIdent(dbType, s.Name))
+	default:
+		return ""
+	}
+}
+
 // CreateDDL generates DDL script for the schema and all its entities
 // Order: function -> table -> (index table) -> trigger table -> view -> materialized view -> index materialized view
 func (s *ModelDBSchema) CreateDDL(dbType base.DXDatabaseType) (string, error) {
@@ -44,10 +57,9 @@ func (s *ModelDBSchema) CreateDDL(dbType base.DXDatabaseType) (string, error) {
 
 	// Create schema statement
 	switch dbType {
-	case base.DXDatabaseTypePostgreSQL:
-		sb.WriteString(fmt.Sprintf("CREATE SCHEMA IF NOT EXISTS %s;\n\n", quoteIdent(dbType, s.Name)))
-	case base.DXDatabaseTypeSQLServer:
-		sb.WriteString(fmt.Sprintf("IF NOT EXISTS (SELECT * FROM sys.schemas WHERE name = '%s')\nBEGIN\n    EXEC('CREATE SCHEMA %s')\nEND;\n\n", s.Name, quoteIdent(dbType, s.Name)))
+	case base.DXDatabaseTypePostgreSQL, base.DXDatabaseTypeSQLServer:
+		sb.WriteString(s.createSchemaDDL(dbType))
+		sb.WriteString("\n")
 	case base.DXDatabaseTypeMariaDB:
 		// MariaDB has NO schema layer, and the schema name must NOT become a
 		// database name (the DB is created separately, unrelated name; many virtual
@@ -57,6 +69,9 @@ func (s *ModelDBSchema) CreateDDL(dbType base.DXDatabaseType) (string, error) {
 		// comment only — emitting a SQL comment is unsafe because callers that split
 		// the script on ';' would glue a dangling comment onto the next statement).
 		// Tables land in the connection's current database.
+	case base.DXDatabaseTypeSQLite:
+		// SQLite: same virtual schema as MariaDB. Its own "schemas" are attached
+		// database files, which the model does not manage.
 	case base.DXDatabaseTypeOracle:
 		// Oracle: schema == USER, provisioned by the admin (reset-databases
 		// CreateOracleUser) BEFORE this DDL runs — no schema-creation DDL emitted.
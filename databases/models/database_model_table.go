@@ -71,8 +71,12 @@ type ModelDBTable struct {
 	TDE               ModelDBTDEConfig // Database-specific TDE configuration
 	UseTableSuffix    bool
 	PhysicalTableName string
-	Indexes           []*ModelDBIndex   // Indexes on this table
-	Triggers          []*ModelDBTrigger // Triggers on this table
+	Indexes           []*ModelDBIndex           // Indexes on this table
+	Triggers          []*ModelDBTrigger         // Triggers on this table
+	ForeignKeys       []*ModelDBForeignKey      // Table-level foreign keys (see database_model_constraint.go)
+	CheckConstraints  []*ModelDBCheckConstraint // Table-level CHECK constraints
+	TenantFieldName   string                    // Tenant column of a multi-tenant table (see database_model_tenant.go)
+	Notify            *ModelDBNotify            // pg_notify of row changes (see database_model_notify.go)
 }
 
 // NewModelDBTable creates a new databases table and registers it with the schema.
@@ -157,6 +161,11 @@ func (t *ModelDBTable) CreateDDL(dbType base.DXDatabaseType) (string, error) {
 		colDef := t.fieldToDDL(fieldName, *field, dbType)
 		columns = append(columns, colDef)
 	}
+	constraints, err := t.constraintsDDL(dbType)
+	if err != nil {
+		return "", err
+	}
+	columns = append(columns, constraints...)
 
 	sb.WriteString("    " + strings.Join(columns, ",\n    "))
 	sb.WriteString("\n)")
@@ -166,6 +175,18 @@ func (t *ModelDBTable) CreateDDL(dbType base.DXDatabaseType) (string, error) {
 
 	sb.WriteString(";\n")
 
+	rls, err := t.TenantRLSPolicyDDL(dbType)
+	if err != nil {
+		return "", err
+	}
+	sb.WriteString(rls)
+
+	notify, err := t.NotifyTriggerDDL(dbType)
+	if err != nil {
+		return "", err
+	}
+	sb.WriteString(notify)
+
 	// SQL Server only: for each nullable UNIQUE column the inline UNIQUE was
 	// skipped (see fieldToDDL) — emit a FILTERED unique index so multiple NULLs
 	// are allowed while non-NULL values stay unique, matching PG/MariaDB/Oracle.
@@ -245,6 +266,12 @@ func (t *ModelDBTable) fieldToDDL(fieldName string, field ModelDBField, dbType b
 	// Add PRIMARY KEY constraint
 	if field.IsPrimaryKey {
 		sb.WriteString(" PRIMARY KEY")
+		// SQLite: an INTEGER PRIMARY KEY is the rowid; AUTOINCREMENT keeps ids from
+		// being reused, like SERIAL/IDENTITY on the other engines
+		if dbType == base.DXDatabaseTypeSQLite && (field.Type.APIParameterType == types.DataTypeSerial.APIParameterType ||
+			field.Type.APIParameterType == types.DataTypeBigSerial.APIParameterType) {
+			sb.WriteString(" AUTOINCREMENT")
+		}
 	}
 
 	// Add NOT NULL constraint
@@ -301,8 +328,8 @@ func (t *ModelDBTable) generatedColumnDDL(fieldName string, field ModelDBField,
 		// PostgreSQL: only STORED supported
 		return fmt.Sprintf("%s %s GENERATED ALWAYS AS (%s) STORED", fieldName, sqlType, expr)
 
-	case base.DXDatabaseTypeMariaDB:
-		// MySQL/MariaDB: STORED or VIRTUAL
+	case base.DXDatabaseTypeMariaDB, base.DXDatabaseTypeSQLite:
+		// MySQL/MariaDB/SQLite: STORED or VIRTUAL
 		storage := "VIRTUAL"
 		if field.IsStored {
 			storage = "STORED"
@@ -596,6 +623,9 @@ func (t *ModelDBTable) encryptExpression(dbType base.DXDatabaseType, argIndex in
 		return fmt.Sprintf("UTL_RAW.CAST_TO_RAW(%s)", placeholder)
 	case base.DXDatabaseTypeMariaDB:
 		return fmt.Sprintf("AES_ENCRYPT(%s, %s)", placeholder, keyExpr)
+	case base.DXDatabaseTypeSQLite:
+		// Application-side function registered on the SQLite driver by databases/db
+		return fmt.Sprintf("dx_encrypt(%s, %s)", placeholder, keyExpr)
 	default:
 		return placeholder
 	}
@@ -621,6 +651,8 @@ func (t *ModelDBTable) hashExpression(dbType base.DXDatabaseType, argIndex int,
 		return fmt.Sprintf("DBMS_CRYPTO.HASH(UTL_RAW.CAST_TO_RAW(%s), 4)", valueExpr) // 4 = SHA256
 	case base.DXDatabaseTypeMariaDB:
 		return fmt.Sprintf("SHA2(%s, 256)", valueExpr)
+	case base.DXDatabaseTypeSQLite:
+		return fmt.Sprintf("dx_sha256(%s)", valueExpr)
 	default:
 		return placeholder
 	}
@@ -653,6 +685,8 @@ func (t *ModelDBTable) keyExpression(dbType base.DXDatabaseType, source ModelDBK
 			return fmt.Sprintf("SYS_CONTEXT('USERENV', '%s')", value)
 		case base.DXDatabaseTypeMariaDB:
 			return fmt.Sprintf("@%s", value)
+		case base.DXDatabaseTypeSQLite:
+			return fmt.Sprintf("(SELECT value FROM temp.dx_session_context WHERE key = '%s')", value)
 		default:
 			return fmt.Sprintf("'%s'", value)
 		}
@@ -666,6 +700,8 @@ func (t *ModelDBTable) keyExpression(dbType base.DXDatabaseType, source ModelDBK
 			return fmt.Sprintf("SYS_CONTEXT('CLIENTCONTEXT', '%s')", value)
 		case base.DXDatabaseTypeMariaDB:
 			return fmt.Sprintf("@%s", value)
+		case base.DXDatabaseTypeSQLite:
+			return fmt.Sprintf("(SELECT value FROM temp.dx_session_context WHERE key = '%s')", value)
 		default:
 			return fmt.Sprintf("'%s'", value)
 		}
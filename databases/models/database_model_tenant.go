@@ -0,0 +1,55 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/donnyhardyanto/dxlib/base"
+)
+
+// Row-level multi-tenancy: the rows of a table with a TenantFieldName belong to the tenant in
+// that column. The tables package scopes queries to it in the application; on PostgreSQL the
+// table also gets a row-level security policy that compares it with TenantSessionConfigKey,
+// so rows of other tenants stay invisible to SQL that bypasses the tables package.
+
+// TenantSessionConfigKey is the session config row-level security policies read the tenant from
+const TenantSessionConfigKey = "app.tenant_id"
+
+// SetTenantFieldName makes fieldName the tenant column of the table
+func (t *ModelDBTable) SetTenantFieldName(fieldName string) *ModelDBTable {
+	t.TenantFieldName = fieldName
+	return t
+}
+
+// TenantRLSPolicyName returns the name of the row-level security policy of the table
+func (t *ModelDBTable) TenantRLSPolicyName() string {
+	return t.TableName() + "_tenant_isolation"
+}
+
+// TenantRLSPolicyDDL generates the PostgreSQL row-level security of a table with a
+// TenantFieldName: rows are visible and writable only while TenantSessionConfigKey holds their
+// tenant, and not at all while it is unset. FORCE applies the policy to the table owner too.
+// Other databases have no such policies and get an empty script.
+func (t *ModelDBTable) TenantRLSPolicyDDL(dbType base.DXDatabaseType) (string, error) {
+	if t.TenantFieldName == "" || dbType != base.DXDatabaseTypePostgreSQL {
+		return "", nil
+	}
+	if _, ok := t.Fields[t.TenantFieldName]; !ok {
+		return "", fmt.Errorf("tenant field %s not found in table %s", t.TenantFieldName, t.FullTableName())
+	}
+	schemaName := ""
+	if t.Schema != nil {
+		schemaName = t.Schema.Name
+	}
+	tableName := qualifiedTableName(dbType, schemaName, t.TableName())
+	predicate := fmt.Sprintf("%s = NULLIF(current_setting('%s', true), '')::bigint", quoteIdent(dbType, t.TenantFieldName), TenantSessionConfigKey)
+	return fmt.Sprintf("ALTER TABLE %s ENABLE ROW LEVEL SECURITY;\n", tableName) +
+		fmt.Sprintf("ALTER TABLE %s FORCE ROW LEVEL SECURITY;\n", tableName) +
+		fmt.Sprintf("CREATE POLICY %s ON %s USING (%s) WITH CHECK (%s);\n", quoteIdent(dbType, t.TenantRLSPolicyName()), tableName, predicate, predicate), nil
+}
+
+// BuildSetTenantSessionConfigSQL generates the SQL that sets TenantSessionConfigKey to tenantId
+// for the session (see BuildSetSessionConfigSQL).
+func BuildSetTenantSessionConfigSQL(dbType base.DXDatabaseType, tenantId int64) (string, []any) {
+	return BuildSetSessionConfigSQL(dbType, TenantSessionConfigKey, strconv.FormatInt(tenantId, 10))
+}
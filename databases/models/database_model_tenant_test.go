@@ -0,0 +1,41 @@
+package models
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/donnyhardyanto/dxlib/base"
+	"github.com/donnyhardyanto/dxlib/types"
+)
+
+func TestModelDBTableTenantRLSPolicyDDL(t *testing.T) {
+	db := NewModelDB("test", nil)
+	schema := NewModelDBSchema(db, "app", 1)
+	bigint := types.DataType{TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "BIGINT"}}
+	table := NewModelDBTable(schema, "orders", 1, map[string]*ModelDBField{
+		"id":        {Order: 1, Type: bigint, IsPrimaryKey: true},
+		"tenant_id": {Order: 2, Type: bigint, IsNotNull: true},
+	}, ModelDBTDEConfig{}).SetTenantFieldName("tenant_id")
+
+	ddl, err := table.CreateDDL(base.DXDatabaseTypePostgreSQL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	predicate := `"tenant_id" = NULLIF(current_setting('app.tenant_id', true), '')::bigint`
+	for _, want := range []string{
+		`ALTER TABLE "app"."orders" ENABLE ROW LEVEL SECURITY;`,
+		`ALTER TABLE "app"."orders" FORCE ROW LEVEL SECURITY;`,
+		`CREATE POLICY "orders_tenant_isolation" ON "app"."orders" USING (` + predicate + `) WITH CHECK (` + predicate + `);`,
+	} {
+		if !strings.Contains(ddl, want) {
+			t.Errorf("DDL:\n%s\nmissing %s", ddl, want)
+		}
+	}
+
+	if s, args := BuildSetTenantSessionConfigSQL(base.DXDatabaseTypePostgreSQL, 42); s != "SELECT set_config($1, $2, false)" || args[0] != TenantSessionConfigKey || args[1] != "42" {
+		t.Fatalf("set tenant = %s %v", s, args)
+	}
+	if _, err = table.SetTenantFieldName("tenant").CreateDDL(base.DXDatabaseTypePostgreSQL); err == nil {
+		t.Fatal("an unknown tenant field must fail")
+	}
+}
@@ -119,6 +119,8 @@ func (t *ModelDBTrigger) CreateDDL(dbType base.DXDatabaseType) (string, error) {
 		return t.createMariaDBDDL(), nil
 	case base.DXDatabaseTypeOracle:
 		return t.createOracleDDL(), nil
+	case base.DXDatabaseTypeSQLite:
+		return t.createSQLiteDDL()
 	default:
 		return "", fmt.Errorf("unsupported databases type: %v", dbType)
 	}
@@ -229,6 +231,60 @@ func (t *ModelDBTrigger) createMariaDBDDL() string {
 	return sb.String()
 }
 
+// namesForDBType returns the names CreateDDL gives the trigger: on SQLite a trigger with
+// several events is one trigger per event, named <name>_<event>.
+func (t *ModelDBTrigger) namesForDBType(dbType base.DXDatabaseType) []string {
+	if dbType != base.DXDatabaseTypeSQLite || len(t.Events) <= 1 {
+		return []string{t.Name}
+	}
+	names := make([]string, 0, len(t.Events))
+	for _, event := range t.Events {
+		names = append(names, fmt.Sprintf("%s_%s", t.Name, strings.ToLower(string(event))))
+	}
+	return names
+}
+
+// createSQLiteDDL generates one trigger per event. SQLite triggers cannot call a
+// function, so the body is the ExecuteFunction's BodyByDB[SQLite] statements.
+func (t *ModelDBTrigger) createSQLiteDDL() (string, error) {
+	var body string
+	if t.ExecuteFunction != nil && t.ExecuteFunction.BodyByDB != nil {
+		body = t.ExecuteFunction.BodyByDB[base.DXDatabaseTypeSQLite]
+	}
+	if body == "" {
+		return "", fmt.Errorf("trigger %s: SQLite needs the trigger body in ExecuteFunction.BodyByDB[SQLite]", t.Name)
+	}
+
+	var sb strings.Builder
+	names := t.namesForDBType(base.DXDatabaseTypeSQLite)
+	for i, event := range t.Events {
+		name := names[i]
+		sb.WriteString("CREATE TRIGGER IF NOT EXISTS ")
+		sb.WriteString(name)
+		sb.WriteString("\n")
+		sb.WriteString(string(t.Timing))
+		sb.WriteString(" ")
+		sb.WriteString(string(event))
+		if event == ModelDBTriggerEventUpdate && len(t.UpdateColumns) > 0 {
+			sb.WriteString(" OF " + strings.Join(t.UpdateColumns, ", "))
+		}
+		sb.WriteString("\nON ")
+		sb.WriteString(qualifiedTableName(base.DXDatabaseTypeSQLite, t.OwnerTable.schemaName(), t.OwnerTable.TableName()))
+		// SQLite only has row-level triggers
+		sb.WriteString("\nFOR EACH ROW\n")
+		if t.When != "" {
+			sb.WriteString("WHEN " + t.When + "\n")
+		}
+		sb.WriteString("BEGIN\n")
+		sb.WriteString(body)
+		sb.WriteString("\nEND;\n")
+		if i < len(t.Events)-1 {
+			sb.WriteString("\n")
+		}
+	}
+	return sb.String(), nil
+}
+
 func (t *ModelDBTrigger) createOracleDDL() string {
 	var sb strings.Builder
 
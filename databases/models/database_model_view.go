@@ -529,6 +529,8 @@ func (v *ModelDBView) buildDecryptExpr(placeholder string, dbType base.DXDatabas
 		return fmt.Sprintf("CONVERT(VARCHAR(MAX), DecryptByPassPhrase(%s, %s))", keyExpr, encColumn), nil
 	case base.DXDatabaseTypeMariaDB:
 		return fmt.Sprintf("AES_DECRYPT(%s, %s)", encColumn, keyExpr), nil
+	case base.DXDatabaseTypeSQLite:
+		return fmt.Sprintf("dx_decrypt(%s, %s)", encColumn, keyExpr), nil
 	case base.DXDatabaseTypeOracle:
 		return fmt.Sprintf("UTL_RAW.CAST_TO_VARCHAR2(DBMS_CRYPTO.DECRYPT(%s, DBMS_CRYPTO.ENCRYPT_AES256 + DBMS_CRYPTO.CHAIN_CBC + DBMS_CRYPTO.PAD_PKCS5, UTL_RAW.CAST_TO_RAW(%s)))", encColumn, keyExpr), nil
 	default:
@@ -584,7 +586,7 @@ func (v *ModelDBView) DropDDL(dbType base.DXDatabaseType) string {
 		return fmt.Sprintf("IF OBJECT_ID('%s', 'V') IS NOT NULL DROP VIEW %s;\n", v.FullViewName(), v.FullViewName())
 	case base.DXDatabaseTypeOracle:
 		return fmt.Sprintf("DROP VIEW %s;\n", v.FullViewName())
-	default: // PostgreSQL, MariaDB
+	default: // PostgreSQL, MariaDB, SQLite
 		return fmt.Sprintf("DROP VIEW IF EXISTS %s;\n", v.FullViewName())
 	}
 }
@@ -592,8 +594,8 @@ func (v *ModelDBView) DropDDL(dbType base.DXDatabaseType) string {
 // CreateOrReplaceDDL generates CREATE OR REPLACE VIEW (where supported)
 func (v *ModelDBView) CreateOrReplaceDDL(dbType base.DXDatabaseType) (string, error) {
 	switch dbType {
-	case base.DXDatabaseTypeSQLServer:
-		// SQL Server doesn't support CREATE OR REPLACE, use DROP + CREATE
+	case base.DXDatabaseTypeSQLServer, base.DXDatabaseTypeSQLite:
+		// SQL Server and SQLite don't support CREATE OR REPLACE, use DROP + CREATE
 		drop := v.DropDDL(dbType)
 		create, err := v.CreateDDL(dbType)
 		if err != nil {
@@ -296,6 +296,11 @@ func load(path string) ([]string, error) {
 	return statements, nil
 }
 
+// Queries returns the loaded statements in order, each terminated by ';'
+func (s *SqlFile) Queries() []string {
+	return append([]string(nil), s.queries...)
+}
+
 // Exec executes SQL statements
 func (s *SqlFile) Exec(db *sql.DB) (res []sql.Result, err error) {
 	if db == nil {
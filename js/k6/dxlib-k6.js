@@ -25,6 +25,7 @@ const dxlib = {};
                 this.preKeyIndex = null;
                 this.sessionKey = null;
                 this.userId = null;
+                this.http = null; // k6: the 'k6/http' module of the script, so requests are measured and tagged
             }
 
             Clone() {
@@ -32,23 +33,43 @@ const dxlib = {};
             }
         }
 
-        async function api(client, url, jsonRequestData, asserted) {
+        async function api(client, url, jsonRequestData, asserted, method, tags) {
+            if ((method === null) || (method === undefined) || (method === "")) {
+                method = 'POST';
+            }
             if ((jsonRequestData === null) || (jsonRequestData === undefined) || (jsonRequestData === "")) {
                 jsonRequestData = {};
             }
             let bodyAsString = JSON.stringify(jsonRequestData);
+            if ((method === 'GET') || (method === 'DELETE')) {
+                let query = Object.keys(jsonRequestData)
+                    .map(k => `${encodeURIComponent(k)}=${encodeURIComponent(jsonRequestData[k])}`)
+                    .join('&');
+                if (query !== "") {
+                    url = url + '?' + query;
+                }
+                bodyAsString = null;
+            }
 
             let headers = {
                 'Content-Type': 'application/json',
             }
-            if (client.sessionKey !== "") {
+            if ((client.sessionKey !== null) && (client.sessionKey !== "")) {
                 headers["Authorization"] = `Bearer ${client.sessionKey}`;
             }
-            let response = await fetch(client.APIAddress + url, {
-                method: 'POST',
-                headers: headers,
-                body: bodyAsString,
-            });
+            let response;
+            if (client.http !== null) {
+                response = client.http.request(method, client.APIAddress + url, bodyAsString, {
+                    headers: headers,
+                    tags: tags,
+                });
+            } else {
+                response = await fetch(client.APIAddress + url, {
+                    method: method,
+                    headers: headers,
+                    body: bodyAsString,
+                });
+            }
             if (asserted) {
                 assertResponse(response);
             }
@@ -93,9 +114,18 @@ const dxlib = {};
             if (parameters !== "") {
                 headers["X-Var"] = JSON.stringify(parameters);
             }
-            let response = await http.post(client.APIAddress + url, fileContent, {
-                headers: headers,
-            });
+            let response;
+            if (client.http !== null) {
+                response = client.http.post(client.APIAddress + url, fileContent, {
+                    headers: headers,
+                });
+            } else {
+                response = await fetch(client.APIAddress + url, {
+                    method: 'POST',
+                    headers: headers,
+                    body: fileContent,
+                });
+            }
             if (asserted) {
                 assertResponse(response);
             }
@@ -3,9 +3,11 @@ package login_system
 import (
 	"context"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/donnyhardyanto/dxlib/api"
 	"github.com/donnyhardyanto/dxlib/databases"
 	dxlibLog "github.com/donnyhardyanto/dxlib/log"
 )
@@ -209,6 +211,40 @@ func (m *LoginSystemManager) InstanceGet(sessionKey string) (map[string]any, err
 	return nil, nil
 }
 
+// ContextWithSessionTenant returns ctx carrying the tenant of a session found by InstanceGet
+// (see databases.ContextWithTenantId), which scopes the queries of tables in tenancy mode.
+// ok is false for a session of no known tenant.
+func (m *LoginSystemManager) ContextWithSessionTenant(ctx context.Context, sessionKey string) (context.Context, bool) {
+	tenantIdVal, ok := m.SessionToTenant.Load(sessionKey)
+	if !ok {
+		return ctx, false
+	}
+	return databases.ContextWithTenantId(ctx, tenantIdVal.(int64)), true
+}
+
+// SessionTenantMiddleware is an API middleware (DXAPIEndPoint.Middlewares) that sets
+// aepr.Context to carry the tenant of the session of the "Authorization: Bearer" header
+// (see ContextWithSessionTenant), so endpoints reach tables in tenancy mode without wiring
+// the tenant by hand. Put it after the middleware that authenticates the session; a request
+// without a session key, or with one of no known tenant, is left unchanged.
+func (m *LoginSystemManager) SessionTenantMiddleware(aepr *api.DXAPIEndPointRequest) error {
+	sessionKey, ok := strings.CutPrefix(aepr.Request.Header.Get("Authorization"), "Bearer ")
+	sessionKey = strings.TrimSpace(sessionKey)
+	if !ok || sessionKey == "" {
+		return nil
+	}
+	if _, known := m.SessionToTenant.Load(sessionKey); !known {
+		// InstanceGet records the tenant of a session registered by another process
+		if _, err := m.InstanceGet(sessionKey); err != nil {
+			return err
+		}
+	}
+	if ctx, ok := m.ContextWithSessionTenant(aepr.Context, sessionKey); ok {
+		aepr.Context = ctx
+	}
+	return nil
+}
+
 // InstanceUnregister removes a session.
 // Routes via SessionToTenant map; falls back to scanning all instances.
 func (m *LoginSystemManager) InstanceUnregister(sessionKey string, reason TokenRemoveReasonType, reasonData any) error {
@@ -1,5 +1,10 @@
 package module
 
+import (
+	"github.com/donnyhardyanto/dxlib/databases"
+	"github.com/donnyhardyanto/dxlib/errors"
+)
+
 type DXModuleInterface interface {
 }
 
@@ -9,3 +14,29 @@ type DXModule struct {
 	DatabaseNameId         string
 	ReadOnlyDatabaseNameId string
 }
+
+// ApplyReadOnlyDatabase registers the ReadOnlyDatabaseNameId database as a read replica
+// of the DatabaseNameId database, so reads of the module's tables are routed to it (see
+// DXDatabase.AddReplica). Call it after both databases are registered in
+// databases.Manager and before connecting. A module without ReadOnlyDatabaseNameId, or
+// with the same name in both fields, is left as is.
+func (m *DXModule) ApplyReadOnlyDatabase() error {
+	if m.ReadOnlyDatabaseNameId == "" || m.ReadOnlyDatabaseNameId == m.DatabaseNameId {
+		return nil
+	}
+	d, ok := databases.Manager.Databases[m.DatabaseNameId]
+	if !ok {
+		return errors.Errorf("MODULE_DATABASE_NOT_FOUND:%s:%s", m.NameId, m.DatabaseNameId)
+	}
+	rd, ok := databases.Manager.Databases[m.ReadOnlyDatabaseNameId]
+	if !ok {
+		return errors.Errorf("MODULE_READ_ONLY_DATABASE_NOT_FOUND:%s:%s", m.NameId, m.ReadOnlyDatabaseNameId)
+	}
+	for _, r := range d.Replicas {
+		if r.Database == rd {
+			return nil
+		}
+	}
+	d.AddReplica(rd)
+	return nil
+}
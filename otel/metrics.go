@@ -10,6 +10,11 @@ var (
 	HTTPRequestCount    metric.Int64Counter
 	DBQueryDuration     metric.Float64Histogram
 	DBQueryCount        metric.Int64Counter
+	DBTxRetryCount      metric.Int64Counter
+	DBSlotWaitDuration  metric.Float64Histogram
+	DBSlotTimeoutCount  metric.Int64Counter
+	DBNotificationLag   metric.Float64Histogram
+	DBNotificationCount metric.Int64Counter
 	RedisOpDuration     metric.Float64Histogram
 	RedisOpCount        metric.Int64Counter
 	HTTPClientDuration  metric.Float64Histogram
@@ -51,6 +56,43 @@ func InitMetrics() error {
 		return err
 	}
 
+	DBTxRetryCount, err = meter.Int64Counter("db.client.transaction.retry.count",
+		metric.WithDescription("Total number of database transactions retried after a serialization failure or deadlock"),
+	)
+	if err != nil {
+		return err
+	}
+
+	DBSlotWaitDuration, err = meter.Float64Histogram("db.client.concurrency.wait_time",
+		metric.WithUnit("s"),
+		metric.WithDescription("Time spent waiting for a database concurrency slot"),
+	)
+	if err != nil {
+		return err
+	}
+
+	DBSlotTimeoutCount, err = meter.Int64Counter("db.client.concurrency.timeout.count",
+		metric.WithDescription("Total number of database concurrency slot acquisitions that timed out"),
+	)
+	if err != nil {
+		return err
+	}
+
+	DBNotificationLag, err = meter.Float64Histogram("db.client.notification.lag",
+		metric.WithUnit("s"),
+		metric.WithDescription("Time between a row change and the delivery of its LISTEN/NOTIFY notification"),
+	)
+	if err != nil {
+		return err
+	}
+
+	DBNotificationCount, err = meter.Int64Counter("db.client.notification.count",
+		metric.WithDescription("Total number of LISTEN/NOTIFY notifications received"),
+	)
+	if err != nil {
+		return err
+	}
+
 	RedisOpDuration, err = meter.Float64Histogram("redis.client.operation.duration",
 		metric.WithUnit("s"),
 		metric.WithDescription("Duration of Redis operations"),
@@ -0,0 +1,26 @@
+package redis
+
+import (
+	"context"
+
+	"github.com/donnyhardyanto/dxlib/errors"
+	"github.com/go-redis/redis/v8"
+)
+
+// StreamAdd appends an entry to the stream and returns its ID. With maxLen > 0 the stream is
+// trimmed to about maxLen entries.
+func (r *DXRedis) StreamAdd(ctx context.Context, stream string, values map[string]any, maxLen int64) (id string, err error) {
+	ctx, endOtel := r.redisOtelStart(ctx, "XADD")
+	defer func() { endOtel(err) }()
+
+	id, err = r.Connection.XAdd(ctx, &redis.XAddArgs{
+		Stream: stream,
+		MaxLen: maxLen,
+		Approx: maxLen > 0,
+		Values: values,
+	}).Result()
+	if err != nil {
+		return "", errors.Wrapf(err, "Cannot add to Redis %s stream %s", r.NameId, stream)
+	}
+	return id, nil
+}
@@ -272,6 +272,81 @@ func (tqb *TableSelectQueryBuilder) SearchLike(value string, fieldNames ...strin
 	return tqb
 }
 
+// SearchFullText adds a dialect-native full-text search condition (wrapper for base method).
+// def comes from the table definition, so its fields are not checked against the whitelists.
+func (tqb *TableSelectQueryBuilder) SearchFullText(value string, def builder.FullTextSearchDef) *TableSelectQueryBuilder {
+	tqb.SelectQueryBuilder.SearchFullText(value, def)
+	return tqb
+}
+
+// OrderByFullTextRank orders by full-text relevance, most relevant first (wrapper for base method)
+func (tqb *TableSelectQueryBuilder) OrderByFullTextRank(value string, def builder.FullTextSearchDef) *TableSelectQueryBuilder {
+	tqb.SelectQueryBuilder.OrderByFullTextRank(value, def)
+	return tqb
+}
+
+// Exists adds EXISTS (sub) (wrapper for base method)
+func (tqb *TableSelectQueryBuilder) Exists(sub *builder.SelectQueryBuilder) *TableSelectQueryBuilder {
+	tqb.SelectQueryBuilder.Exists(sub)
+	return tqb
+}
+
+// NotExists adds NOT EXISTS (sub) (wrapper for base method)
+func (tqb *TableSelectQueryBuilder) NotExists(sub *builder.SelectQueryBuilder) *TableSelectQueryBuilder {
+	tqb.SelectQueryBuilder.NotExists(sub)
+	return tqb
+}
+
+// InSubquery adds field IN (sub) with field validation
+func (tqb *TableSelectQueryBuilder) InSubquery(fieldName string, sub *builder.SelectQueryBuilder) *TableSelectQueryBuilder {
+	tqb.CheckFieldExist(fieldName)
+	if tqb.Error != nil {
+		return tqb
+	}
+	tqb.SelectQueryBuilder.InSubquery(fieldName, sub)
+	return tqb
+}
+
+// NotInSubquery adds field NOT IN (sub) with field validation
+func (tqb *TableSelectQueryBuilder) NotInSubquery(fieldName string, sub *builder.SelectQueryBuilder) *TableSelectQueryBuilder {
+	tqb.CheckFieldExist(fieldName)
+	if tqb.Error != nil {
+		return tqb
+	}
+	tqb.SelectQueryBuilder.NotInSubquery(fieldName, sub)
+	return tqb
+}
+
+// Union appends UNION other (wrapper for base method)
+func (tqb *TableSelectQueryBuilder) Union(other *builder.SelectQueryBuilder) *TableSelectQueryBuilder {
+	tqb.SelectQueryBuilder.Union(other)
+	return tqb
+}
+
+// UnionAll appends UNION ALL other (wrapper for base method)
+func (tqb *TableSelectQueryBuilder) UnionAll(other *builder.SelectQueryBuilder) *TableSelectQueryBuilder {
+	tqb.SelectQueryBuilder.UnionAll(other)
+	return tqb
+}
+
+// Intersect appends INTERSECT other (wrapper for base method)
+func (tqb *TableSelectQueryBuilder) Intersect(other *builder.SelectQueryBuilder) *TableSelectQueryBuilder {
+	tqb.SelectQueryBuilder.Intersect(other)
+	return tqb
+}
+
+// Except appends EXCEPT other, MINUS on Oracle (wrapper for base method)
+func (tqb *TableSelectQueryBuilder) Except(other *builder.SelectQueryBuilder) *TableSelectQueryBuilder {
+	tqb.SelectQueryBuilder.Except(other)
+	return tqb
+}
+
+// SelectWindow adds a window function select field (wrapper for base method)
+func (tqb *TableSelectQueryBuilder) SelectWindow(def builder.WindowFunctionDef) *TableSelectQueryBuilder {
+	tqb.SelectQueryBuilder.SelectWindow(def)
+	return tqb
+}
+
 // In adds field IN (values) condition with field validation
 func (tqb *TableSelectQueryBuilder) In(fieldName string, values any) *TableSelectQueryBuilder {
 	tqb.CheckFieldExist(fieldName)
@@ -541,6 +616,7 @@ func (tqb *TableSelectQueryBuilder) NotIn(fieldName string, values any) *TableSe
 //
 // PostgreSQL: uses native array operators (= ANY or &&)
 // MariaDB / MySQL: uses JSON_CONTAINS with a JSON array literal
+// SQLite: arrays are JSON TEXT, matched through json_each
 // SQL Server / Oracle: falls back to LIKE-based membership check on the serialised JSON string
 func (tqb *TableSelectQueryBuilder) ArrayContainsAnyInt64(fieldName string, values []int64) *TableSelectQueryBuilder {
 	if len(values) == 0 {
@@ -567,6 +643,9 @@ func (tqb *TableSelectQueryBuilder) ArrayContainsAnyInt64(fieldName string, valu
 			jsonChecks = append(jsonChecks, fmt.Sprintf("JSON_CONTAINS(%s, CAST(%s AS JSON))", tqb.QuoteIdentifier(fieldName), v))
 		}
 		tqb.Conditions = append(tqb.Conditions, "("+strings.Join(jsonChecks, " OR ")+")")
+	case base.DXDatabaseTypeSQLite:
+		tqb.Conditions = append(tqb.Conditions, fmt.Sprintf("EXISTS (SELECT 1 FROM json_each(%s) WHERE value IN (%s))",
+			tqb.QuoteIdentifier(fieldName), strings.Join(strVals, ", ")))
 	case base.DXDatabaseTypeSQLServer:
 		// SQL Server: [ is a special character in LIKE patterns (character class).
 		// Use CHARINDEX on the stripped inner content to avoid unclosed-bracket errors.
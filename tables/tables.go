@@ -1,6 +1,8 @@
 package tables
 
 import (
+	"fmt"
+
 	"github.com/donnyhardyanto/dxlib/api"
 	"github.com/donnyhardyanto/dxlib/databases"
 	"github.com/donnyhardyanto/dxlib/databases/db"
@@ -428,18 +430,50 @@ type PagingResult struct {
 	Rows       []utils.JSON
 	TotalRows  int64
 	TotalPages int64
+	// TotalRowsStrategy produced TotalRows; with IsTotalRowsCapped there are more than TotalRows rows
+	TotalRowsStrategy DXPagingCountStrategy
+	IsTotalRowsCapped bool
+	// IsTotalRowsUnknown is set when the count was skipped (cursor paging without is_total_rows_included)
+	IsTotalRowsUnknown bool
+	// IsCursorPaging is set by DoCursorPagingWithSelectQueryBuilder; an empty cursor means there is no such page
+	IsCursorPaging bool
+	NextCursor     string
+	PrevCursor     string
 }
 
 // ToResponseJSON converts PagingResult to the standard JSON response format
 func (pr *PagingResult) ToResponseJSON() utils.JSON {
+	list := utils.JSON{
+		"rows":       pr.Rows,
+		"total_rows": pr.TotalRows,
+		"total_page": pr.TotalPages,
+		"rows_info":  pr.RowsInfo,
+	}
+	if pr.IsTotalRowsUnknown {
+		list["total_rows"] = nil
+		list["total_page"] = nil
+	} else {
+		list["total_rows_strategy"] = DXPagingCountStrategyExact
+		if pr.TotalRowsStrategy != "" {
+			list["total_rows_strategy"] = pr.TotalRowsStrategy
+		}
+		if pr.IsTotalRowsCapped {
+			list["total_rows_label"] = fmt.Sprintf("%d+", pr.TotalRows)
+		}
+	}
+	if pr.IsCursorPaging {
+		list["next_cursor"] = nil
+		list["prev_cursor"] = nil
+		if pr.NextCursor != "" {
+			list["next_cursor"] = pr.NextCursor
+		}
+		if pr.PrevCursor != "" {
+			list["prev_cursor"] = pr.PrevCursor
+		}
+	}
 	return utils.JSON{
 		"data": utils.JSON{
-			"list": utils.JSON{
-				"rows":       pr.Rows,
-				"total_rows": pr.TotalRows,
-				"total_page": pr.TotalPages,
-				"rows_info":  pr.RowsInfo,
-			},
+			"list": list,
 		},
 	}
 }
@@ -46,6 +46,10 @@ func (t *DXRawTable) TxInsertAuto(
 		encryptionColumns := t.convertEncryptionColumnDefsForWrite(data)
 		return t.TxInsertWithEncryption(dtx, data, encryptionColumns, returningFieldNames)
 	}
+	data, err := t.tenantInsertData(dtx.Ctx, data)
+	if err != nil {
+		return nil, nil, err
+	}
 	return dtx.Insert(dtx.Ctx, t.GetFullTableName(), data, returningFieldNames)
 }
 
@@ -60,6 +64,11 @@ func (t *DXRawTable) TxCheckValidationUniqueFieldNameGroupsForInsert(dtx *databa
 			k[f] = val
 		}
 
+		k, err := t.tenantWhere(dtx.Ctx, k)
+		if err != nil {
+			return err
+		}
+
 		// Ensure c and err are properly captured
 		c, err := db.TxCount(dtx.Ctx, dtx.Tx, t.GetFullTableName(), "", k, nil, nil, nil, "")
 		if err != nil {
@@ -84,6 +93,11 @@ func (t *DXRawTable) TxCheckValidationUniqueFieldNameGroupsForUpdate(dtx *databa
 			k[f] = val
 		}
 
+		k, err := t.tenantWhere(dtx.Ctx, k)
+		if err != nil {
+			return err
+		}
+
 		_, d, err := db.TxSelect(dtx.Ctx, dtx.Tx, t.GetFullTableName(), nil, []string{t.FieldNameForRowId}, k, nil, nil, nil, nil, nil, nil, nil)
 		if err != nil {
 			return err
@@ -119,6 +133,9 @@ func (t *DXRawTable) InsertAuto(
 	if err = t.EnsureDatabase(); err != nil {
 		return nil, nil, err
 	}
+	if data, err = t.tenantInsertData(ctx, data); err != nil {
+		return nil, nil, err
+	}
 
 	if !t.HasEncryptionConfig() {
 		// No encryption at all, no transaction needed
@@ -30,6 +30,13 @@ func (t *DXRawTable) TxUpdateAuto(
 		encryptionColumns := t.convertEncryptionColumnDefsForWrite(data)
 		return t.TxUpdateWithEncryption(dtx, data, encryptionColumns, where, returningFieldNames)
 	}
+	if err := t.checkTenantUpdateData(data); err != nil {
+		return nil, nil, err
+	}
+	where, err := t.tenantWhere(dtx.Ctx, where)
+	if err != nil {
+		return nil, nil, err
+	}
 	return dtx.Update(dtx.Ctx, t.GetFullTableName(), data, where, returningFieldNames)
 }
 
@@ -44,6 +51,12 @@ func (t *DXRawTable) UpdateAuto(
 	if err = t.EnsureDatabase(); err != nil {
 		return nil, nil, err
 	}
+	if err = t.checkTenantUpdateData(data); err != nil {
+		return nil, nil, err
+	}
+	if where, err = t.tenantWhere(ctx, where); err != nil {
+		return nil, nil, err
+	}
 
 	if !t.HasEncryptionConfig() {
 		// No encryption at all, no transaction needed
@@ -67,7 +67,11 @@ func placeholder(dbType base.DXDatabaseType, index int) string {
 
 // encryptExpression returns databases-specific encryption SQL expression
 func encryptExpression(dbType base.DXDatabaseType, argIndex int, sessionKey string) string {
-	ph := placeholder(dbType, argIndex)
+	return encryptPlaceholderExpression(dbType, placeholder(dbType, argIndex), sessionKey)
+}
+
+// encryptPlaceholderExpression is encryptExpression for an already formatted bind placeholder
+func encryptPlaceholderExpression(dbType base.DXDatabaseType, ph string, sessionKey string) string {
 	keyExpr := db.SessionKeyExpression(dbType, sessionKey)
 
 	switch dbType {
@@ -79,6 +83,9 @@ func encryptExpression(dbType base.DXDatabaseType, argIndex int, sessionKey stri
 		return fmt.Sprintf("UTL_RAW.CAST_TO_RAW(%s)", ph)
 	case base.DXDatabaseTypeMariaDB:
 		return fmt.Sprintf("AES_ENCRYPT(%s, %s)", ph, keyExpr)
+	case base.DXDatabaseTypeSQLite:
+		// Encrypted by the application-side function registered on the SQLite driver
+		return fmt.Sprintf("%s(%s, %s)", db.SQLiteFunctionEncrypt, ph, keyExpr)
 	default:
 		return ph
 	}
@@ -86,7 +93,11 @@ func encryptExpression(dbType base.DXDatabaseType, argIndex int, sessionKey stri
 
 // hashExpression returns databases-specific hash SQL expression with optional salt
 func hashExpression(dbType base.DXDatabaseType, argIndex int, saltSessionKey string) string {
-	ph := placeholder(dbType, argIndex)
+	return hashPlaceholderExpression(dbType, placeholder(dbType, argIndex), saltSessionKey)
+}
+
+// hashPlaceholderExpression is hashExpression for an already formatted bind placeholder
+func hashPlaceholderExpression(dbType base.DXDatabaseType, ph string, saltSessionKey string) string {
 
 	valueExpr := ph
 	if saltSessionKey != "" {
@@ -103,6 +114,8 @@ func hashExpression(dbType base.DXDatabaseType, argIndex int, saltSessionKey str
 		return fmt.Sprintf("DBMS_CRYPTO.HASH(UTL_RAW.CAST_TO_RAW(%s), 4)", valueExpr)
 	case base.DXDatabaseTypeMariaDB:
 		return fmt.Sprintf("SHA2(%s, 256)", valueExpr)
+	case base.DXDatabaseTypeSQLite:
+		return fmt.Sprintf("%s(%s)", db.SQLiteFunctionSHA256, valueExpr)
 	default:
 		return ph
 	}
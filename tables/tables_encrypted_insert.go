@@ -27,6 +27,10 @@ func (t *DXRawTable) TxInsertWithEncryption(
 	if err := t.EnsureDatabase(); err != nil {
 		return nil, nil, err
 	}
+	data, err := t.tenantInsertData(dtx.Ctx, data)
+	if err != nil {
+		return nil, nil, err
+	}
 
 	dbType := t.Database.DatabaseType
 
@@ -26,8 +26,11 @@ func (t *DXRawTable) SelectWithEncryption(ctx context.Context, l *log.DXLog, fie
 	if err = t.EnsureDatabase(); err != nil {
 		return nil, nil, err
 	}
+	if forUpdatePart == true {
+		ctx = databases.ContextWithPrimary(ctx)
+	}
 
-	txErr := t.Database.Tx(ctx, l, databases.LevelReadCommitted, func(dtx *databases.DXDatabaseTx) error {
+	txErr := t.Database.ReadTx(ctx, l, databases.LevelReadCommitted, func(dtx *databases.DXDatabaseTx) error {
 		rowsInfo, rows, err = t.TxSelectWithEncryption(dtx, fieldNames, encryptionColumns, where, joinSQLPart, orderBy, limit, forUpdatePart)
 		return err
 	})
@@ -145,7 +148,7 @@ func (t *DXRawTable) PagingWithEncryption(
 		return nil, err
 	}
 
-	txErr := t.Database.Tx(ctx, l, databases.LevelReadCommitted, func(dtx *databases.DXDatabaseTx) error {
+	txErr := t.Database.ReadTx(ctx, l, databases.LevelReadCommitted, func(dtx *databases.DXDatabaseTx) error {
 		pagingResult, err = t.TxPagingWithEncryption(dtx, columns, encryptionColumns, whereClause, whereArgs, orderBy, rowPerPage, pageIndex)
 		return err
 	})
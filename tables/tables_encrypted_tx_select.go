@@ -30,6 +30,10 @@ func (t *DXRawTable) TxSelectWithEncryption(dtx *databases.DXDatabaseTx, fieldNa
 		return nil, nil, err
 	}
 
+	where, err := t.tenantWhere(dtx.Ctx, where)
+	if err != nil {
+		return nil, nil, err
+	}
 	return executeEncryptedSelect(dtx.Ctx, dtx, tableName, t.FieldTypeMapping, dbType, fieldNames, encryptionColumns, where, joinSQLPart, orderBy, limit, forUpdatePart)
 }
 
@@ -52,6 +56,10 @@ func (t *DXRawTable) TxSelectOneWithEncryption(dtx *databases.DXDatabaseTx, fiel
 		return nil, nil, err
 	}
 
+	where, err := t.tenantWhere(dtx.Ctx, where)
+	if err != nil {
+		return nil, nil, err
+	}
 	rowsInfo, rows, err := executeEncryptedSelect(dtx.Ctx, dtx, tableName, t.FieldTypeMapping, dbType, fieldNames, encryptionColumns, where, joinSQLPart, orderBy, 1, forUpdatePart)
 	if err != nil {
 		return rowsInfo, nil, err
@@ -163,6 +171,10 @@ func (t *DXRawTable) TxPagingWithEncryption(
 		return nil, err
 	}
 
+	whereClause, whereArgs, err := t.tenantWhereClause(dtx.Ctx, dbType, whereClause, whereArgs)
+	if err != nil {
+		return nil, err
+	}
 	return executeEncryptedPaging(dtx.Ctx, dtx, t.ListViewNameId, dbType, columns, encryptionColumns, whereClause, whereArgs, orderBy, rowPerPage, pageIndex)
 }
 
@@ -29,6 +29,13 @@ func (t *DXRawTable) TxUpdateWithEncryption(
 	if err := t.EnsureDatabase(); err != nil {
 		return nil, nil, err
 	}
+	if err := t.checkTenantUpdateData(data); err != nil {
+		return nil, nil, err
+	}
+	where, err := t.tenantWhere(dtx.Ctx, where)
+	if err != nil {
+		return nil, nil, err
+	}
 
 	dbType := t.Database.DatabaseType
 
@@ -13,11 +13,13 @@ import (
 	"github.com/donnyhardyanto/dxlib/databases"
 	"github.com/donnyhardyanto/dxlib/databases/db"
 	"github.com/donnyhardyanto/dxlib/databases/db/query"
+	"github.com/donnyhardyanto/dxlib/databases/db/query/builder"
 	"github.com/donnyhardyanto/dxlib/databases/export"
 	"github.com/donnyhardyanto/dxlib/databases/models"
 	"github.com/donnyhardyanto/dxlib/errors"
 	"github.com/donnyhardyanto/dxlib/language"
 	"github.com/donnyhardyanto/dxlib/log"
+	"github.com/donnyhardyanto/dxlib/redis"
 	tableQueryBuilder "github.com/donnyhardyanto/dxlib/tables/query_builder"
 	"github.com/donnyhardyanto/dxlib/types"
 	"github.com/donnyhardyanto/dxlib/utils"
@@ -58,6 +60,20 @@ type DXRawTable struct {
 	OrderByFieldNames               []string
 	FilterableFieldNames            []string // Whitelist of fields that can be filtered via filter_key_values
 	DownloadableOrderByFieldNames   []string // If set, used for SELECT in download. Falls back to OrderByFieldNames if nil.
+
+	// Total row count of paging lists, see DXPagingCountStrategy (empty = exact)
+	PagingCountStrategy     DXPagingCountStrategy
+	PagingCountCap          int64          // capped: maximum rows counted (0 = DefaultPagingCountCap)
+	PagingCountCacheRedis   *redis.DXRedis // cached: where counts are kept
+	PagingCountCacheSeconds int            // cached: seconds a count is kept (0 = DefaultPagingCountCacheSeconds)
+
+	// FullTextSearch makes search_text a full-text search (ordered by relevance without order_by)
+	// instead of LIKE over SearchTextFieldNames; nil keeps LIKE
+	FullTextSearch *builder.FullTextSearchDef
+
+	// TenantFieldName turns on tenancy mode: queries are scoped to the tenant of the context
+	// (see tables_raw_table_tenancy.go); empty = off
+	TenantFieldName string
 }
 
 // EnsureDatabase ensures databases connection is initialized
@@ -129,11 +145,19 @@ func (t *DXRawTable) Delete(ctx context.Context, l *log.DXLog, where utils.JSON,
 	if err := t.EnsureDatabase(); err != nil {
 		return nil, nil, err
 	}
+	where, err := t.tenantWhere(ctx, where)
+	if err != nil {
+		return nil, nil, err
+	}
 	return t.Database.Delete(ctx, t.GetFullTableName(), where, returningFieldNames)
 }
 
 // TxDelete deletes within a transaction
 func (t *DXRawTable) TxDelete(dtx *databases.DXDatabaseTx, where utils.JSON, returningFieldNames []string) (sql.Result, []utils.JSON, error) {
+	where, err := t.tenantWhere(dtx.Ctx, where)
+	if err != nil {
+		return nil, nil, err
+	}
 	return dtx.TxDelete(dtx.Ctx, t.GetFullTableName(), where, returningFieldNames)
 }
 
@@ -218,12 +242,42 @@ func (t *DXRawTable) Upsert(ctx context.Context, l *log.DXLog, data utils.JSON,
 	if err := t.EnsureDatabase(); err != nil {
 		return nil, 0, false, err
 	}
+	if err := t.checkTenantUpdateData(data); err != nil {
+		return nil, 0, false, err
+	}
+	where, err := t.tenantWhere(ctx, where)
+	if err != nil {
+		return nil, 0, false, err
+	}
 	insertData := utilsJson.DeepMerge2(data, where)
+	if dtx := databases.TxFromContext(ctx, t.Database); dtx != nil {
+		return db.TxUpsert(ctx, dtx.Tx, t.GetFullTableName(), insertData, data, where, t.FieldNameForRowId)
+	}
+	if t.Database.IsTenantSessionTxNeeded(ctx) {
+		var (
+			result   sql.Result
+			id       int64
+			isInsert bool
+		)
+		err := t.Database.Tx(ctx, l, databases.LevelReadCommitted, func(dtx *databases.DXDatabaseTx) (err error) {
+			result, id, isInsert, err = db.TxUpsert(dtx.Ctx, dtx.Tx, t.GetFullTableName(), insertData, data, where, t.FieldNameForRowId)
+			return err
+		})
+		return result, id, isInsert, err
+	}
+	t.Database.MarkWritten(ctx)
 	return db.Upsert(ctx, t.Database.Connection, t.GetFullTableName(), insertData, data, where, t.FieldNameForRowId)
 }
 
 // TxUpsert is the transactional variant of Upsert. Same contract.
 func (t *DXRawTable) TxUpsert(dtx *databases.DXDatabaseTx, data utils.JSON, where utils.JSON) (sql.Result, int64, bool, error) {
+	if err := t.checkTenantUpdateData(data); err != nil {
+		return nil, 0, false, err
+	}
+	where, err := t.tenantWhere(dtx.Ctx, where)
+	if err != nil {
+		return nil, 0, false, err
+	}
 	insertData := utilsJson.DeepMerge2(data, where)
 	return db.TxUpsert(dtx.Ctx, dtx.Tx, t.GetFullTableName(), insertData, data, where, t.FieldNameForRowId)
 }
@@ -258,17 +312,56 @@ func (t *DXRawTable) NewTableDeleteQueryBuilder() *tableQueryBuilder.TableDelete
 	return tableQueryBuilder.NewTableDeleteQueryBuilder(t.GetDbType(), t)
 }
 
+// listOutFields returns the columns a list or download selects: OrderByFieldNames, with
+// a runtime decryption expression for encrypted columns the list view does not decrypt.
+func (t *DXRawTable) listOutFields(dtx *databases.DXDatabaseTx) []string {
+	// Use OrderByFieldNames to control which columns are returned
+	var outFields []string
+
+	if len(t.EncryptionColumnDefs) > 0 {
+		dbType := base.StringToDXDatabaseType(dtx.Tx.DriverName())
+		encryptionColumns := t.convertEncryptionColumnDefsForSelect()
+
+		// Process each field in OrderByFieldNames
+		for _, fieldName := range t.OrderByFieldNames {
+			added := false
+			// Check if this field needs runtime decryption
+			for _, col := range encryptionColumns {
+				if col.AliasName == fieldName && !col.ViewHasDecrypt {
+					// Field needs runtime decryption - add decryption expression
+					expr := db.DecryptExpression(dbType, col.FieldName, col.EncryptionKeyDef.SessionKey)
+					outFields = append(outFields, fmt.Sprintf("%s AS %s", expr, fieldName))
+					added = true
+					break
+				}
+			}
+			if !added {
+				// Field doesn't need runtime decryption - add as-is
+				outFields = append(outFields, fieldName)
+			}
+		}
+	} else {
+		// No encryption - use OrderByFieldNames directly
+		outFields = t.OrderByFieldNames
+	}
+
+	return outFields
+}
+
 // DoPagingWithSelectQueryBuilder executes a paging query using SelectQueryBuilder (core implementation).
 // Supports EncryptionColumnDefs and EncryptionKeyDefs for encrypted tables.
-// Uses CountWithSelectQueryBuilder2 for total count and SelectWithSelectQueryBuilder2 for rows.
+// Counts with the table's PagingCountStrategy and uses SelectWithSelectQueryBuilder2 for rows.
 func (t *DXRawTable) DoPagingWithSelectQueryBuilder(ctx context.Context, l *log.DXLog, qb *tableQueryBuilder.TableSelectQueryBuilder) (pagingResult *PagingResult, err error) {
 	if err = t.EnsureDatabase(); err != nil {
 		return nil, err
 	}
+	if err = t.scopeSelectBuilderToTenant(ctx, qb.SelectQueryBuilder); err != nil {
+		return nil, err
+	}
 	// Set source to list view name
 	qb.SourceName = t.GetListViewName()
 
-	txErr := t.Database.Tx(ctx, l, databases.LevelReadCommitted, func(dtx *databases.DXDatabaseTx) error {
+	txErr := t.Database.ReadTx(ctx, l, databases.LevelReadCommitted, func(dtx *databases.DXDatabaseTx) error {
 		// Set encryption session keys if needed
 		if len(t.EncryptionColumnDefs) > 0 || len(t.EncryptionKeyDefs) > 0 {
 			if err := t.TxSetAllEncryptionSessionKeys(dtx); err != nil {
@@ -277,50 +370,14 @@ func (t *DXRawTable) DoPagingWithSelectQueryBuilder(ctx context.Context, l *log.
 		}
 
 		// Count
-		totalRows, err := query.TxCountWithSelectQueryBuilder2(ctx, dtx, qb.SelectQueryBuilder)
+		count, err := t.txPagingCount(ctx, dtx, qb)
 		if err != nil {
 			return err
 		}
 
-		rowPerPage := qb.LimitValue
-		totalPages := int64(0)
-		if rowPerPage > 0 {
-			totalPages = (totalRows + rowPerPage - 1) / rowPerPage
-		}
-
 		// Only set OutFields if not already pre-set by caller (e.g. DoRequestSearchPagingDownload)
 		if len(qb.OutFields) == 0 {
-			// Use OrderByFieldNames to control which columns are returned
-			var outFields []string
-
-			if len(t.EncryptionColumnDefs) > 0 {
-				dbType := base.StringToDXDatabaseType(dtx.Tx.DriverName())
-				encryptionColumns := t.convertEncryptionColumnDefsForSelect()
-
-				// Process each field in OrderByFieldNames
-				for _, fieldName := range t.OrderByFieldNames {
-					added := false
-					// Check if this field needs runtime decryption
-					for _, col := range encryptionColumns {
-						if col.AliasName == fieldName && !col.ViewHasDecrypt {
-							// Field needs runtime decryption - add decryption expression
-							expr := db.DecryptExpression(dbType, col.FieldName, col.EncryptionKeyDef.SessionKey)
-							outFields = append(outFields, fmt.Sprintf("%s AS %s", expr, fieldName))
-							added = true
-							break
-						}
-					}
-					if !added {
-						// Field doesn't need runtime decryption - add as-is
-						outFields = append(outFields, fieldName)
-					}
-				}
-			} else {
-				// No encryption - use OrderByFieldNames directly
-				outFields = t.OrderByFieldNames
-			}
-
-			qb.OutFields = outFields
+			qb.OutFields = t.listOutFields(dtx)
 		}
 
 		// Select
@@ -330,11 +387,10 @@ func (t *DXRawTable) DoPagingWithSelectQueryBuilder(ctx context.Context, l *log.
 		}
 
 		pagingResult = &PagingResult{
-			RowsInfo:   rowsInfo,
-			Rows:       rows,
-			TotalRows:  totalRows,
-			TotalPages: totalPages,
+			RowsInfo: rowsInfo,
+			Rows:     rows,
 		}
+		count.apply(pagingResult, qb.LimitValue)
 		return nil
 	})
 	if txErr != nil {
@@ -343,6 +399,39 @@ func (t *DXRawTable) DoPagingWithSelectQueryBuilder(ctx context.Context, l *log.
 	return pagingResult, nil
 }
 
+// DoSelectSeqWithSelectQueryBuilder streams the rows of qb from the list view to fn
+// instead of materialising them (see query.TxSelectSeqWithSelectQueryBuilder2), with
+// the encryption session keys and OutFields of DoPagingWithSelectQueryBuilder. fn runs
+// inside the read transaction and must range over rows before returning. Once fn has
+// written output it cannot take back, it returns its error through
+// databases.TxNotRepeatable so the read is not run again.
+func (t *DXRawTable) DoSelectSeqWithSelectQueryBuilder(ctx context.Context, l *log.DXLog, qb *tableQueryBuilder.TableSelectQueryBuilder, batchSize int,
+	fn func(rowsInfo *db.DXDatabaseTableRowsInfo, rows db.DXDatabaseRowsSeq) error) (err error) {
+	if err = t.EnsureDatabase(); err != nil {
+		return err
+	}
+	if err = t.scopeSelectBuilderToTenant(ctx, qb.SelectQueryBuilder); err != nil {
+		return err
+	}
+	qb.SourceName = t.GetListViewName()
+
+	return t.Database.ReadTx(ctx, l, databases.LevelReadCommitted, func(dtx *databases.DXDatabaseTx) error {
+		if len(t.EncryptionColumnDefs) > 0 || len(t.EncryptionKeyDefs) > 0 {
+			if err := t.TxSetAllEncryptionSessionKeys(dtx); err != nil {
+				return err
+			}
+		}
+		if len(qb.OutFields) == 0 {
+			qb.OutFields = t.listOutFields(dtx)
+		}
+		rowsInfo, rows, err := query.TxSelectSeqWithSelectQueryBuilder2(ctx, dtx, qb.SelectQueryBuilder, t.FieldTypeMapping, batchSize)
+		if err != nil {
+			return err
+		}
+		return fn(rowsInfo, rows)
+	})
+}
+
 // PagingWithSelectQueryBuilder executes a paging query using TableSelectQueryBuilder.
 // Delegates to DoPagingWithSelectQueryBuilder.
 func (t *DXRawTable) PagingWithSelectQueryBuilder(ctx context.Context, l *log.DXLog, qb *tableQueryBuilder.TableSelectQueryBuilder) (*PagingResult, error) {
@@ -384,14 +473,12 @@ func (t *DXRawTable) DoApplyRequestSearchFilter(aepr *api.DXAPIEndPointRequest,
 	}
 
 	if searchText != "" {
-		searchFields := t.SearchTextFieldNames
-
 		// If search_columns provided, use those instead of defaults
 		if isSearchColumnsExist && len(searchColumns) > 0 {
-			searchFields = searchColumns
+			qb.SearchLike(searchText, searchColumns...)
+		} else {
+			t.applySearchText(qb, searchText)
 		}
-
-		qb.SearchLike(searchText, searchFields...)
 	}
 	if isFilterKeyValuesExist && filterKeyValues != nil {
 		if err := t.processFilterKeyValues(qb, filterKeyValues); err != nil {
@@ -409,9 +496,54 @@ func (t *DXRawTable) DoApplyRequestSearchFilter(aepr *api.DXAPIEndPointRequest,
 	return nil
 }
 
+// applySearchText filters qb by search_text: a full-text search when FullTextSearch is set,
+// otherwise LIKE over SearchTextFieldNames.
+func (t *DXRawTable) applySearchText(qb *tableQueryBuilder.TableSelectQueryBuilder, searchText string) {
+	if t.FullTextSearch != nil {
+		qb.SearchFullText(searchText, t.fullTextSearchDef())
+		return
+	}
+	qb.SearchLike(searchText, t.SearchTextFieldNames...)
+}
+
+// fullTextSearchDef is FullTextSearch with the SQL Server ranking table and key defaulted to the
+// table and FieldNameForRowId.
+func (t *DXRawTable) fullTextSearchDef() builder.FullTextSearchDef {
+	def := *t.FullTextSearch
+	if def.TableName == "" {
+		def.TableName = t.GetFullTableName()
+	}
+	if def.KeyFieldName == "" {
+		def.KeyFieldName = t.FieldNameForRowId
+	}
+	return def
+}
+
+// fullTextSearchText returns the request's search_text when it is searched with FullTextSearch
+// (not overridden by search_columns), otherwise "".
+func (t *DXRawTable) fullTextSearchText(aepr *api.DXAPIEndPointRequest) (string, error) {
+	if t.FullTextSearch == nil {
+		return "", nil
+	}
+	_, searchText, err := aepr.GetParameterValueAsString("search_text")
+	if err != nil {
+		return "", err
+	}
+	isSearchColumnsExist, searchColumns, err := aepr.GetParameterValueAsStrings("search_columns")
+	if err != nil {
+		return "", err
+	}
+	if isSearchColumnsExist && len(searchColumns) > 0 {
+		return "", nil
+	}
+	return searchText, nil
+}
+
 // DoRequestSearchPagingList executes paging with a pre-built TableSelectQueryBuilder and writes JSON response.
 // Parses row_per_page and page_index from the request. The caller is responsible for building the query builder
 // with all WHERE and ORDER BY conditions. Optional onResultList callback allows post-processing of rows.
+// When the request has a cursor parameter (empty for the first page), keyset paging is used instead of
+// page_index, see DoCursorPagingWithSelectQueryBuilder; is_total_rows_included opts in to the count.
 func (t *DXRawTable) DoRequestSearchPagingList(aepr *api.DXAPIEndPointRequest, qb *tableQueryBuilder.TableSelectQueryBuilder, onResultList OnResultList) error {
 	// Apply search_text, filter_key_values and the default is_deleted filter.
 	if err := t.DoApplyRequestSearchFilter(aepr, qb); err != nil {
@@ -433,17 +565,46 @@ func (t *DXRawTable) DoRequestSearchPagingList(aepr *api.DXAPIEndPointRequest, q
 		return err
 	}
 
+	isCursorExist, cursor, err := aepr.GetParameterValueAsString("cursor")
+	if err != nil {
+		return err
+	}
+
 	// Parse order_by into OrderBy calls with validation
 	qb.ParseOrderByFromArray(orderByArray)
 
-	qb.Limit(rowPerPage)
-	if pageIndex > 0 {
-		qb.Offset(pageIndex * rowPerPage)
+	// Without an order, full-text results come most relevant first (not in cursor mode,
+	// which only seeks on fields)
+	if !isCursorExist && len(qb.OrderByDefs) == 0 && len(qb.RawOrderBys) == 0 {
+		searchText, err := t.fullTextSearchText(aepr)
+		if err != nil {
+			return err
+		}
+		if searchText != "" {
+			qb.OrderByFullTextRank(searchText, t.fullTextSearchDef())
+		}
 	}
 
-	result, err := t.DoPagingWithSelectQueryBuilder(aepr.Context, &aepr.Log, qb)
-	if err != nil {
-		return err
+	var result *PagingResult
+	if isCursorExist {
+		_, isTotalRowsIncluded, err := aepr.GetParameterValueAsBool("is_total_rows_included")
+		if err != nil {
+			return err
+		}
+		result, err = t.DoCursorPagingWithSelectQueryBuilder(aepr.Context, &aepr.Log, qb, cursor, rowPerPage, isTotalRowsIncluded)
+		if err != nil {
+			return err
+		}
+	} else {
+		qb.Limit(rowPerPage)
+		if pageIndex > 0 {
+			qb.Offset(pageIndex * rowPerPage)
+		}
+
+		result, err = t.DoPagingWithSelectQueryBuilder(aepr.Context, &aepr.Log, qb)
+		if err != nil {
+			return err
+		}
 	}
 
 	if onResultList != nil {
@@ -479,7 +640,7 @@ func (t *DXRawTable) RequestSearchPagingDownload(aepr *api.DXAPIEndPointRequest)
 
 	qb := t.NewTableSelectQueryBuilder()
 	if searchText != "" {
-		qb.SearchLike(searchText, t.SearchTextFieldNames...)
+		t.applySearchText(qb, searchText)
 	}
 	if isFilterKeyValuesExist && filterKeyValues != nil {
 		err := t.processFilterKeyValues(qb, filterKeyValues)
@@ -553,7 +714,7 @@ func (t *DXRawTable) DoRequestSearchPagingDownload(aepr *api.DXAPIEndPointReques
 
 	// Apply search text filter
 	if searchText != "" {
-		qb.SearchLike(searchText, t.SearchTextFieldNames...)
+		t.applySearchText(qb, searchText)
 	}
 
 	// Apply filter_key_values
@@ -579,37 +740,11 @@ func (t *DXRawTable) DoRequestSearchPagingDownload(aepr *api.DXAPIEndPointReques
 		qb.Offset(pageIndex * rowPerPage)
 	}
 
-	// Pre-set OutFields to DownloadableOrderByFieldNames so DoPagingWithSelectQueryBuilder will not overwrite it
+	// Pre-set OutFields to DownloadableOrderByFieldNames so DoSelectSeqWithSelectQueryBuilder will not overwrite it
 	if len(t.DownloadableOrderByFieldNames) > 0 {
 		qb.OutFields = t.DownloadableOrderByFieldNames
 	}
 
-	pagingResult, err := t.DoPagingWithSelectQueryBuilder(aepr.Context, &aepr.Log, qb)
-	if err != nil {
-		return err
-	}
-
-	// Filter out "id" and "uid" fields from pagingResult for download
-	excludeFields := map[string]bool{"id": true, "uid": true}
-
-	// Filter RowsInfo.Columns (column metadata)
-	if pagingResult.RowsInfo != nil && len(pagingResult.RowsInfo.Columns) > 0 {
-		filteredColumns := make([]string, 0, len(pagingResult.RowsInfo.Columns))
-		for _, columnName := range pagingResult.RowsInfo.Columns {
-			if !excludeFields[columnName] {
-				filteredColumns = append(filteredColumns, columnName)
-			}
-		}
-		pagingResult.RowsInfo.Columns = filteredColumns
-
-		// Filter each row to remove excluded fields
-		for i := range pagingResult.Rows {
-			for fieldName := range excludeFields {
-				delete(pagingResult.Rows[i], fieldName)
-			}
-		}
-	}
-
 	// Resolve timezone from request, default to Asia/Jakarta (WIB)
 	_, timezone, _ := aepr.GetParameterValueAsString("timezone")
 	if timezone == "" {
@@ -625,9 +760,7 @@ func (t *DXRawTable) DoRequestSearchPagingDownload(aepr *api.DXAPIEndPointReques
 		Language:          lang,
 		TranslateFallback: fallback,
 	}
-
-	// Get file as stream
-	data, contentType, err := export.ExportToStream(pagingResult.RowsInfo, pagingResult.Rows, opts)
+	contentType, err := export.ContentType(opts.Format)
 	if err != nil {
 		return err
 	}
@@ -643,26 +776,56 @@ func (t *DXRawTable) DoRequestSearchPagingDownload(aepr *api.DXAPIEndPointReques
 	default:
 	}
 
-	// Set response headers
-	filename := fmt.Sprintf("export_%s_%s.%s", t.GetFullTableName(), time.Now().Format("20060102_150405"), format)
+	// Rows are streamed from the database into the file, and the file into the
+	// response; the headers go out with the first byte, so a query error before
+	// that still gets a regular error response.
+	w := &downloadResponseWriter{
+		aepr:        aepr,
+		filename:    fmt.Sprintf("export_%s_%s.%s", t.GetFullTableName(), time.Now().Format("20060102_150405"), format),
+		contentType: contentType,
+	}
+	return t.DoSelectSeqWithSelectQueryBuilder(aepr.Context, &aepr.Log, qb, 0, func(rowsInfo *db.DXDatabaseTableRowsInfo, rows db.DXDatabaseRowsSeq) error {
+		// Leave "id" and "uid" out of the download
+		columns := make([]string, 0, len(rowsInfo.Columns))
+		for _, columnName := range rowsInfo.Columns {
+			if columnName != "id" && columnName != "uid" {
+				columns = append(columns, columnName)
+			}
+		}
+		_, err := export.ExportSeqToWriter(w, &db.DXDatabaseTableRowsInfo{Columns: columns}, rows, opts)
+		if err != nil && aepr.ResponseHeaderSent {
+			// part of the file is out: running the query again would append a second file
+			return databases.TxNotRepeatable(err)
+		}
+		return err
+	})
+}
 
-	rw := *aepr.GetResponseWriter()
-	rw.Header().Set("Content-Type", contentType)
-	rw.Header().Set("Content-Length", fmt.Sprintf("%d", len(data)))
-	rw.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
-	rw.Header().Set("X-Content-Type-Options", "nosniff")
+// downloadResponseWriter sends the download headers on the first write.
+type downloadResponseWriter struct {
+	aepr        *api.DXAPIEndPointRequest
+	filename    string
+	contentType string
+}
 
-	rw.WriteHeader(http.StatusOK)
-	aepr.ResponseStatusCode = http.StatusOK
+func (w *downloadResponseWriter) Write(p []byte) (int, error) {
+	rw := *w.aepr.GetResponseWriter()
+	if !w.aepr.ResponseHeaderSent {
+		rw.Header().Set("Content-Type", w.contentType)
+		rw.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, w.filename))
+		rw.Header().Set("X-Content-Type-Options", "nosniff")
+		rw.WriteHeader(http.StatusOK)
+		w.aepr.ResponseStatusCode = http.StatusOK
+		w.aepr.ResponseHeaderSent = true
+		w.aepr.ResponseBodySent = true
+	}
+	return rw.Write(p)
+}
 
-	if _, err = rw.Write(data); err != nil {
-		return err
+func (w *downloadResponseWriter) Flush() {
+	if flusher, ok := (*w.aepr.GetResponseWriter()).(http.Flusher); ok {
+		flusher.Flush()
 	}
-
-	aepr.ResponseHeaderSent = true
-	aepr.ResponseBodySent = true
-
-	return nil
 }
 
 // OnResultList is a callback type for paging result processing
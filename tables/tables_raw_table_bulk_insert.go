@@ -0,0 +1,232 @@
+package tables
+
+import (
+	"context"
+	"slices"
+	"sort"
+
+	"github.com/donnyhardyanto/dxlib/databases"
+	"github.com/donnyhardyanto/dxlib/databases/db"
+	"github.com/donnyhardyanto/dxlib/errors"
+	"github.com/donnyhardyanto/dxlib/log"
+	"github.com/donnyhardyanto/dxlib/utils"
+)
+
+// DXRawTable Bulk Insert Methods
+// Rows are written with multi-row statements chunked by the dialect's parameter limit,
+// see db.TxBulkInsert and db.TxBulkUpsert. Every row must have the same fields.
+
+// TxBulkInsert inserts rows within a transaction. Data fields matching an EncryptionColumnDef's
+// DataFieldName are encrypted into FieldName (and hashed into HashFieldName).
+func (t *DXRawTable) TxBulkInsert(dtx *databases.DXDatabaseTx, rows []utils.JSON, returningFieldNames []string) (int64, []utils.JSON, error) {
+	if t.HasEncryptionConfig() {
+		if err := t.TxSetAllEncryptionSessionKeys(dtx); err != nil {
+			return 0, nil, err
+		}
+	}
+	rows, err := t.tenantInsertRows(dtx.Ctx, rows)
+	if err != nil {
+		return 0, nil, err
+	}
+	writeRows, columnExpressions := t.bulkWriteRows(rows)
+	return db.TxBulkInsert(dtx.Ctx, dtx.Tx, t.GetFullTableName(), writeRows, columnExpressions, returningFieldNames)
+}
+
+// BulkInsert inserts rows in one transaction; without encryption config PostgreSQL uses COPY
+// when nothing is returned (see DXDatabase.BulkInsert).
+func (t *DXRawTable) BulkInsert(ctx context.Context, l *log.DXLog, rows []utils.JSON, returningFieldNames []string) (affectedRows int64, returningRows []utils.JSON, err error) {
+	if err = t.EnsureDatabase(); err != nil {
+		return 0, nil, err
+	}
+	if !t.HasEncryptionConfig() {
+		if rows, err = t.tenantInsertRows(ctx, rows); err != nil {
+			return 0, nil, err
+		}
+		return t.Database.BulkInsert(ctx, t.GetFullTableName(), rows, returningFieldNames)
+	}
+	err = t.Database.Tx(ctx, l, databases.LevelReadCommitted, func(dtx *databases.DXDatabaseTx) error {
+		affectedRows, returningRows, err = t.TxBulkInsert(dtx, rows, returningFieldNames)
+		return err
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+	return affectedRows, returningRows, nil
+}
+
+// TxBulkUpsert inserts rows within a transaction, updating existing rows on keyFieldNames
+// conflicts. updateFieldNames nil updates every non-key field.
+func (t *DXRawTable) TxBulkUpsert(dtx *databases.DXDatabaseTx, rows []utils.JSON, keyFieldNames []string, updateFieldNames []string,
+	returningFieldNames []string) (int64, []utils.JSON, error) {
+	if t.HasEncryptionConfig() {
+		if err := t.TxSetAllEncryptionSessionKeys(dtx); err != nil {
+			return 0, nil, err
+		}
+	}
+	if t.TenantFieldName != "" && !slices.Contains(keyFieldNames, t.TenantFieldName) {
+		return 0, nil, errors.Errorf("TENANT_BULK_UPSERT_KEY_WITHOUT_TENANT_FIELD:%s.%s", t.GetFullTableName(), t.TenantFieldName)
+	}
+	rows, err := t.tenantInsertRows(dtx.Ctx, rows)
+	if err != nil {
+		return 0, nil, err
+	}
+	writeRows, columnExpressions := t.bulkWriteRows(rows)
+	return db.TxBulkUpsert(dtx.Ctx, dtx.Tx, t.GetFullTableName(), writeRows, columnExpressions, keyFieldNames,
+		t.bulkWriteFieldNames(updateFieldNames), returningFieldNames)
+}
+
+// BulkUpsert is TxBulkUpsert in its own transaction.
+func (t *DXRawTable) BulkUpsert(ctx context.Context, l *log.DXLog, rows []utils.JSON, keyFieldNames []string, updateFieldNames []string,
+	returningFieldNames []string) (affectedRows int64, returningRows []utils.JSON, err error) {
+	if err = t.EnsureDatabase(); err != nil {
+		return 0, nil, err
+	}
+	err = t.Database.Tx(ctx, l, databases.LevelReadCommitted, func(dtx *databases.DXDatabaseTx) error {
+		affectedRows, returningRows, err = t.TxBulkUpsert(dtx, rows, keyFieldNames, updateFieldNames, returningFieldNames)
+		return err
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+	return affectedRows, returningRows, nil
+}
+
+// bulkWriteRows copies rows, moving encrypted data fields to their FieldName/HashFieldName
+// columns, and returns the expressions encrypting and hashing those columns.
+func (t *DXRawTable) bulkWriteRows(rows []utils.JSON) ([]utils.JSON, db.BulkColumnExpressions) {
+	if len(t.EncryptionColumnDefs) == 0 {
+		return rows, nil
+	}
+	dbType := t.GetDbType()
+	columnExpressions := db.BulkColumnExpressions{}
+	writeRows := make([]utils.JSON, len(rows))
+	for i, row := range rows {
+		writeRow := make(utils.JSON, len(row))
+		for k, v := range row {
+			writeRow[k] = v
+		}
+		for _, def := range t.EncryptionColumnDefs {
+			value, exists := writeRow[def.DataFieldName]
+			if !exists {
+				continue
+			}
+			delete(writeRow, def.DataFieldName)
+			writeRow[def.FieldName] = value
+			if _, ok := columnExpressions[def.FieldName]; !ok {
+				sessionKey := def.EncryptionKeyDef.SessionKey
+				columnExpressions[def.FieldName] = func(ph string) string {
+					return encryptPlaceholderExpression(dbType, ph, sessionKey)
+				}
+			}
+			if def.HashFieldName != "" {
+				writeRow[def.HashFieldName] = value
+				if _, ok := columnExpressions[def.HashFieldName]; !ok {
+					saltSessionKey := def.HashSaltSessionKey
+					columnExpressions[def.HashFieldName] = func(ph string) string {
+						return hashPlaceholderExpression(dbType, ph, saltSessionKey)
+					}
+				}
+			}
+		}
+		writeRows[i] = writeRow
+	}
+	return writeRows, columnExpressions
+}
+
+// bulkWriteFieldNames maps encrypted data field names to their FieldName/HashFieldName columns.
+func (t *DXRawTable) bulkWriteFieldNames(fieldNames []string) []string {
+	if fieldNames == nil || len(t.EncryptionColumnDefs) == 0 {
+		return fieldNames
+	}
+	r := make([]string, 0, len(fieldNames))
+	for _, f := range fieldNames {
+		mapped := false
+		for _, def := range t.EncryptionColumnDefs {
+			if def.DataFieldName == f {
+				r = append(r, def.FieldName)
+				if def.HashFieldName != "" {
+					r = append(r, def.HashFieldName)
+				}
+				mapped = true
+				break
+			}
+		}
+		if !mapped {
+			r = append(r, f)
+		}
+	}
+	return r
+}
+
+// DXTable Bulk Insert Methods (with audit fields)
+
+// auditCreateFieldNames are left untouched when a bulk upsert updates an existing row.
+var auditCreateFieldNames = map[string]bool{
+	"is_deleted":             true,
+	"created_at":             true,
+	"created_by_user_id":     true,
+	"created_by_user_nameid": true,
+}
+
+// TxBulkInsert inserts rows within a transaction with audit fields
+func (t *DXTable) TxBulkInsert(dtx *databases.DXDatabaseTx, rows []utils.JSON, returningFieldNames []string) (int64, []utils.JSON, error) {
+	for _, row := range rows {
+		t.SetInsertAuditFields(nil, row)
+	}
+	return t.DXRawTable.TxBulkInsert(dtx, rows, returningFieldNames)
+}
+
+// BulkInsert inserts rows with audit fields
+func (t *DXTable) BulkInsert(ctx context.Context, l *log.DXLog, rows []utils.JSON, returningFieldNames []string) (int64, []utils.JSON, error) {
+	for _, row := range rows {
+		t.SetInsertAuditFields(nil, row)
+	}
+	return t.DXRawTable.BulkInsert(ctx, l, rows, returningFieldNames)
+}
+
+// TxBulkUpsert upserts rows within a transaction with audit fields. On the update path only
+// last_modified_* changes, like Upsert.
+func (t *DXTable) TxBulkUpsert(dtx *databases.DXDatabaseTx, rows []utils.JSON, keyFieldNames []string, updateFieldNames []string,
+	returningFieldNames []string) (int64, []utils.JSON, error) {
+	updateFieldNames = t.prepareBulkUpsertAudit(rows, keyFieldNames, updateFieldNames)
+	return t.DXRawTable.TxBulkUpsert(dtx, rows, keyFieldNames, updateFieldNames, returningFieldNames)
+}
+
+// BulkUpsert upserts rows with audit fields
+func (t *DXTable) BulkUpsert(ctx context.Context, l *log.DXLog, rows []utils.JSON, keyFieldNames []string, updateFieldNames []string,
+	returningFieldNames []string) (int64, []utils.JSON, error) {
+	updateFieldNames = t.prepareBulkUpsertAudit(rows, keyFieldNames, updateFieldNames)
+	return t.DXRawTable.BulkUpsert(ctx, l, rows, keyFieldNames, updateFieldNames, returningFieldNames)
+}
+
+// prepareBulkUpsertAudit sets the insert audit fields of rows and returns the update field names
+// without the create audit fields, including the last_modified_* ones.
+func (t *DXTable) prepareBulkUpsertAudit(rows []utils.JSON, keyFieldNames []string, updateFieldNames []string) []string {
+	for _, row := range rows {
+		t.SetInsertAuditFields(nil, row)
+	}
+	if len(rows) == 0 {
+		return updateFieldNames
+	}
+	isKey := map[string]bool{}
+	for _, k := range keyFieldNames {
+		isKey[k] = true
+	}
+	if updateFieldNames == nil {
+		updateFieldNames = []string{}
+		for k := range rows[0] {
+			if !isKey[k] && !auditCreateFieldNames[k] {
+				updateFieldNames = append(updateFieldNames, k)
+			}
+		}
+		sort.Strings(updateFieldNames)
+		return updateFieldNames
+	}
+	r := append([]string{}, updateFieldNames...)
+	for _, f := range []string{"last_modified_at", "last_modified_by_user_id", "last_modified_by_user_nameid"} {
+		if !slices.Contains(r, f) {
+			r = append(r, f)
+		}
+	}
+	return r
+}
@@ -0,0 +1,438 @@
+package tables
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/donnyhardyanto/dxlib/base"
+	"github.com/donnyhardyanto/dxlib/databases"
+	"github.com/donnyhardyanto/dxlib/databases/db"
+	"github.com/donnyhardyanto/dxlib/databases/db/query"
+	"github.com/donnyhardyanto/dxlib/databases/db/query/builder"
+	"github.com/donnyhardyanto/dxlib/errors"
+	"github.com/donnyhardyanto/dxlib/log"
+	"github.com/donnyhardyanto/dxlib/secure_memory"
+	tableQueryBuilder "github.com/donnyhardyanto/dxlib/tables/query_builder"
+	"github.com/donnyhardyanto/dxlib/utils"
+)
+
+// Keyset (cursor) paging
+// Instead of OFFSET, the next page is read with a seek predicate on the ORDER BY fields,
+// so deep pages cost the same as the first one and rows do not shift between pages when
+// rows are inserted. FieldNameForRowId is appended to the order as a unique tiebreaker.
+// Cursors are opaque tokens signed with HMAC-SHA256; they only carry the order values of
+// the boundary row and are bound to the list view and the order they were created for.
+
+// PagingCursorSecureMemoryKey is the secure memory key of the cursor signing key. When it is
+// not stored, a random per-process key is used, so cursors do not survive a restart and are
+// not accepted by other instances.
+var PagingCursorSecureMemoryKey = "PAGING_CURSOR_SIGNING_KEY"
+
+var pagingCursorFallbackKey = sync.OnceValue(func() []byte {
+	key := make([]byte, 32)
+	_, _ = rand.Read(key)
+	log.Log.Warnf("PAGING_CURSOR_SIGNING_KEY_NOT_IN_SECURE_MEMORY:%s, using a per-process random key", PagingCursorSecureMemoryKey)
+	return key
+})
+
+func pagingCursorSigningKey() []byte {
+	if secure_memory.Manager.Exists(PagingCursorSecureMemoryKey) {
+		key, err := secure_memory.Manager.Get(PagingCursorSecureMemoryKey)
+		if err == nil && len(key) > 0 {
+			return key
+		}
+	}
+	return pagingCursorFallbackKey()
+}
+
+// ErrInvalidPagingCursor is returned when a cursor is malformed, tampered with, or was
+// created for another list or order.
+type ErrInvalidPagingCursor struct {
+	Reason string
+}
+
+func (e *ErrInvalidPagingCursor) Error() string {
+	return fmt.Sprintf("INVALID_PAGING_CURSOR:%s", e.Reason)
+}
+
+func (e *ErrInvalidPagingCursor) DomainErrorCode() string {
+	return "INVALID_PAGING_CURSOR"
+}
+
+func (e *ErrInvalidPagingCursor) DomainErrorHTTPStatusCode() int {
+	return http.StatusBadRequest
+}
+
+func (e *ErrInvalidPagingCursor) DomainErrorResponseBody() utils.JSON {
+	return utils.JSON{
+		"reason":         "INVALID_PAGING_CURSOR",
+		"reason_message": "INVALID_PAGING_CURSOR",
+	}
+}
+
+func (e *ErrInvalidPagingCursor) DomainErrorLogDetails() string {
+	return e.Reason
+}
+
+// pagingCursor is the signed payload of a cursor token
+type pagingCursor struct {
+	Source     string      `json:"s"`
+	Order      string      `json:"o"`
+	IsBackward bool        `json:"b,omitempty"`
+	Values     [][2]string `json:"v"`
+}
+
+func encodePagingCursor(c pagingCursor) (string, error) {
+	payload, err := json.Marshal(c)
+	if err != nil {
+		return "", errors.Wrap(err, "PAGING_CURSOR_ENCODE_ERROR")
+	}
+	mac := hmac.New(sha256.New, pagingCursorSigningKey())
+	mac.Write(payload)
+	return base64.RawURLEncoding.EncodeToString(payload) + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil)), nil
+}
+
+func decodePagingCursor(token string) (c pagingCursor, err error) {
+	payloadPart, signaturePart, ok := strings.Cut(token, ".")
+	if !ok {
+		return c, &ErrInvalidPagingCursor{Reason: "MALFORMED"}
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(payloadPart)
+	if err != nil {
+		return c, &ErrInvalidPagingCursor{Reason: "MALFORMED"}
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(signaturePart)
+	if err != nil {
+		return c, &ErrInvalidPagingCursor{Reason: "MALFORMED"}
+	}
+	mac := hmac.New(sha256.New, pagingCursorSigningKey())
+	mac.Write(payload)
+	if !hmac.Equal(signature, mac.Sum(nil)) {
+		return c, &ErrInvalidPagingCursor{Reason: "BAD_SIGNATURE"}
+	}
+	if err = json.Unmarshal(payload, &c); err != nil {
+		return c, &ErrInvalidPagingCursor{Reason: "MALFORMED"}
+	}
+	return c, nil
+}
+
+// encodePagingCursorValue tags a row value with its type, so it binds back with the same type
+func encodePagingCursorValue(v any) [2]string {
+	switch x := v.(type) {
+	case nil:
+		return [2]string{"n", ""}
+	case bool:
+		return [2]string{"b", strconv.FormatBool(x)}
+	case int:
+		return [2]string{"i", strconv.FormatInt(int64(x), 10)}
+	case int32:
+		return [2]string{"i", strconv.FormatInt(int64(x), 10)}
+	case int64:
+		return [2]string{"i", strconv.FormatInt(x, 10)}
+	case float32:
+		return [2]string{"f", strconv.FormatFloat(float64(x), 'g', -1, 32)}
+	case float64:
+		return [2]string{"f", strconv.FormatFloat(x, 'g', -1, 64)}
+	case time.Time:
+		return [2]string{"t", x.Format(time.RFC3339Nano)}
+	case []byte:
+		// MariaDB returns text columns as []byte; bind them back as strings so the
+		// comparison uses the column collation
+		if utf8.Valid(x) {
+			return [2]string{"s", string(x)}
+		}
+		return [2]string{"x", base64.StdEncoding.EncodeToString(x)}
+	case string:
+		return [2]string{"s", x}
+	default:
+		return [2]string{"s", fmt.Sprint(x)}
+	}
+}
+
+func decodePagingCursorValue(tagged [2]string) (any, error) {
+	switch tagged[0] {
+	case "n":
+		return nil, nil
+	case "b":
+		return strconv.ParseBool(tagged[1])
+	case "i":
+		return strconv.ParseInt(tagged[1], 10, 64)
+	case "f":
+		return strconv.ParseFloat(tagged[1], 64)
+	case "t":
+		return time.Parse(time.RFC3339Nano, tagged[1])
+	case "x":
+		return base64.StdEncoding.DecodeString(tagged[1])
+	case "s":
+		return tagged[1], nil
+	default:
+		return nil, errors.Errorf("UNKNOWN_PAGING_CURSOR_VALUE_TYPE:%s", tagged[0])
+	}
+}
+
+// cursorSeekKey is one ORDER BY field of a seek predicate
+type cursorSeekKey struct {
+	Expression    string // quoted column or decrypt expression
+	Direction     string // ASC or DESC
+	NullPlacement string // FIRST, LAST or empty for the dialect default
+	IsNotNull     bool   // no IS NULL branch, e.g. for the row id tiebreaker
+}
+
+// isNullsSortedHigh reports whether the dialect sorts NULL as the largest value by default
+func isNullsSortedHigh(dbType base.DXDatabaseType) bool {
+	return dbType == base.DXDatabaseTypePostgreSQL || dbType == base.DXDatabaseTypeOracle
+}
+
+// cursorSeekCondition builds the predicate selecting the rows after values in the given
+// order, or before them when isBackward. It expands the row comparison
+// (a, b) > (va, vb) into (a > va) OR (a = va AND b > vb), so mixed directions and NULLs
+// work on every dialect.
+func cursorSeekCondition(dbType base.DXDatabaseType, keys []cursorSeekKey, values []any, isBackward bool) (string, map[string]any) {
+	params := map[string]any{}
+	bind := func(v any) string {
+		name := fmt.Sprintf("paging_cursor_%d", len(params))
+		params[name] = v
+		return ":" + name
+	}
+	var terms []string
+	var equals []string
+	for i, k := range keys {
+		isAsc := strings.ToUpper(k.Direction) != string(databases.DXOrderByDirectionDesc)
+		nullPlacement := strings.ToUpper(k.NullPlacement)
+		if nullPlacement == "" {
+			nullPlacement = string(databases.DXOrderByNullPlacementFirst)
+			if isAsc == isNullsSortedHigh(dbType) {
+				nullPlacement = string(databases.DXOrderByNullPlacementLast)
+			}
+		}
+		isNullsAfter := nullPlacement == string(databases.DXOrderByNullPlacementLast)
+		op := ">"
+		if isAsc == isBackward {
+			op = "<"
+		}
+		if isBackward {
+			isNullsAfter = !isNullsAfter
+		}
+
+		strict := ""
+		if values[i] == nil {
+			if !isNullsAfter {
+				strict = k.Expression + " IS NOT NULL"
+			}
+		} else if isNullsAfter && !k.IsNotNull {
+			strict = "(" + k.Expression + " " + op + " " + bind(values[i]) + " OR " + k.Expression + " IS NULL)"
+		} else {
+			strict = k.Expression + " " + op + " " + bind(values[i])
+		}
+		if strict != "" {
+			terms = append(terms, "("+strings.Join(append(slices.Clone(equals), strict), " AND ")+")")
+		}
+
+		if values[i] == nil {
+			equals = append(equals, k.Expression+" IS NULL")
+		} else {
+			equals = append(equals, k.Expression+" = "+bind(values[i]))
+		}
+	}
+	if len(terms) == 0 {
+		return "1=0", params
+	}
+	return "(" + strings.Join(terms, " OR ") + ")", params
+}
+
+// cursorOrderSignature identifies the order a cursor was created for
+func cursorOrderSignature(orderByDefs []builder.OrderByDef) string {
+	parts := make([]string, len(orderByDefs))
+	for i, o := range orderByDefs {
+		parts[i] = o.FieldName + " " + strings.ToUpper(o.Direction) + " " + strings.ToUpper(o.NullPlacement)
+	}
+	return strings.Join(parts, ",")
+}
+
+// DoCursorPagingWithSelectQueryBuilder reads the page of qb after (or before) cursor using
+// keyset paging; an empty cursor reads the first page. qb must only order by fields
+// (no raw ORDER BY). The total row count is only computed when isTotalRowsIncluded.
+func (t *DXRawTable) DoCursorPagingWithSelectQueryBuilder(ctx context.Context, l *log.DXLog, qb *tableQueryBuilder.TableSelectQueryBuilder, cursor string,
+	rowPerPage int64, isTotalRowsIncluded bool) (pagingResult *PagingResult, err error) {
+	if err = t.EnsureDatabase(); err != nil {
+		return nil, err
+	}
+	if qb.Error != nil {
+		return nil, qb.Error
+	}
+	if err = t.scopeSelectBuilderToTenant(ctx, qb.SelectQueryBuilder); err != nil {
+		return nil, err
+	}
+	if len(qb.RawOrderBys) > 0 {
+		return nil, errors.Errorf("CURSOR_PAGING_DOES_NOT_SUPPORT_RAW_ORDER_BY")
+	}
+	if rowPerPage <= 0 {
+		return nil, errors.Errorf("CURSOR_PAGING_INVALID_ROW_PER_PAGE:%d", rowPerPage)
+	}
+	rowIdFieldName := t.FieldNameForRowId
+	if rowIdFieldName == "" {
+		return nil, errors.Errorf("CURSOR_PAGING_REQUIRES_FIELD_NAME_FOR_ROW_ID:%s", t.GetFullTableName())
+	}
+	if !slices.ContainsFunc(qb.OrderByDefs, func(o builder.OrderByDef) bool { return o.FieldName == rowIdFieldName }) {
+		qb.OrderByDefs = append(qb.OrderByDefs, builder.OrderByDef{FieldName: rowIdFieldName, Direction: string(databases.DXOrderByDirectionAsc)})
+	}
+	orderByDefs := slices.Clone(qb.OrderByDefs)
+
+	qb.SourceName = t.GetListViewName()
+	orderSignature := cursorOrderSignature(orderByDefs)
+
+	var c pagingCursor
+	if cursor != "" {
+		c, err = decodePagingCursor(cursor)
+		if err != nil {
+			return nil, err
+		}
+		if c.Source != qb.SourceName || c.Order != orderSignature || len(c.Values) != len(orderByDefs) {
+			return nil, &ErrInvalidPagingCursor{Reason: "ORDER_OR_SOURCE_MISMATCH"}
+		}
+	}
+	values := make([]any, len(c.Values))
+	for i, tagged := range c.Values {
+		if values[i], err = decodePagingCursorValue(tagged); err != nil {
+			return nil, &ErrInvalidPagingCursor{Reason: err.Error()}
+		}
+	}
+
+	isMore := false
+	txErr := t.Database.ReadTx(ctx, l, databases.LevelReadCommitted, func(dtx *databases.DXDatabaseTx) error {
+		if len(t.EncryptionColumnDefs) > 0 || len(t.EncryptionKeyDefs) > 0 {
+			if err := t.TxSetAllEncryptionSessionKeys(dtx); err != nil {
+				return err
+			}
+		}
+
+		pagingResult = &PagingResult{IsCursorPaging: true, IsTotalRowsUnknown: !isTotalRowsIncluded}
+		// Count before the seek predicate, so it is the total of the whole list
+		if isTotalRowsIncluded {
+			count, err := t.txPagingCount(ctx, dtx, qb)
+			if err != nil {
+				return err
+			}
+			count.apply(pagingResult, rowPerPage)
+		}
+
+		dbType := base.StringToDXDatabaseType(dtx.Tx.DriverName())
+		if len(qb.OutFields) == 0 {
+			qb.OutFields = t.listOutFields(dtx)
+		}
+		encryptionColumns := t.convertEncryptionColumnDefsForSelect()
+		keys := make([]cursorSeekKey, len(orderByDefs))
+		for i, o := range orderByDefs {
+			keys[i] = cursorSeekKey{Expression: qb.QuoteIdentifier(o.FieldName), Direction: o.Direction, NullPlacement: o.NullPlacement,
+				IsNotNull: o.FieldName == rowIdFieldName}
+			for _, col := range encryptionColumns {
+				if col.AliasName == o.FieldName && !col.ViewHasDecrypt {
+					// The alias only exists in the select list, so compare the decrypted value
+					keys[i].Expression = db.DecryptExpression(dbType, col.FieldName, col.EncryptionKeyDef.SessionKey)
+					break
+				}
+			}
+			// The boundary row values are read from the result, so every order field must be selected
+			if len(qb.OutFields) > 0 && !slices.ContainsFunc(qb.OutFields, func(f string) bool {
+				return f == o.FieldName || strings.HasSuffix(f, " AS "+o.FieldName)
+			}) {
+				if keys[i].Expression != qb.QuoteIdentifier(o.FieldName) {
+					qb.OutFields = append(qb.OutFields, keys[i].Expression+" AS "+o.FieldName)
+				} else {
+					qb.OutFields = append(qb.OutFields, o.FieldName)
+				}
+			}
+		}
+
+		if cursor != "" {
+			condition, params := cursorSeekCondition(dbType, keys, values, c.IsBackward)
+			qb.AndWithParams(condition, params)
+		}
+		if c.IsBackward {
+			// Read backward from the cursor, then restore the requested order
+			for i := range qb.OrderByDefs {
+				if strings.ToUpper(qb.OrderByDefs[i].Direction) == string(databases.DXOrderByDirectionDesc) {
+					qb.OrderByDefs[i].Direction = string(databases.DXOrderByDirectionAsc)
+				} else {
+					qb.OrderByDefs[i].Direction = string(databases.DXOrderByDirectionDesc)
+				}
+				switch strings.ToUpper(qb.OrderByDefs[i].NullPlacement) {
+				case string(databases.DXOrderByNullPlacementFirst):
+					qb.OrderByDefs[i].NullPlacement = string(databases.DXOrderByNullPlacementLast)
+				case string(databases.DXOrderByNullPlacementLast):
+					qb.OrderByDefs[i].NullPlacement = string(databases.DXOrderByNullPlacementFirst)
+				}
+			}
+		}
+		// One extra row tells whether there is another page in the read direction
+		qb.LimitValue = rowPerPage + 1
+		qb.OffsetValue = 0
+
+		rowsInfo, rows, err := query.TxSelectWithSelectQueryBuilder2(ctx, dtx, qb.SelectQueryBuilder, t.FieldTypeMapping)
+		if err != nil {
+			return err
+		}
+		isMore = int64(len(rows)) > rowPerPage
+		if isMore {
+			rows = rows[:rowPerPage]
+		}
+		if c.IsBackward {
+			slices.Reverse(rows)
+		}
+		pagingResult.RowsInfo = rowsInfo
+		pagingResult.Rows = rows
+		return nil
+	})
+	if txErr != nil {
+		return nil, txErr
+	}
+
+	rows := pagingResult.Rows
+	if len(rows) == 0 {
+		return pagingResult, nil
+	}
+	newCursor := func(row utils.JSON, isBackward bool) (string, error) {
+		nc := pagingCursor{Source: qb.SourceName, Order: orderSignature, IsBackward: isBackward, Values: make([][2]string, len(orderByDefs))}
+		for i, o := range orderByDefs {
+			v, ok := row[o.FieldName]
+			if !ok {
+				// Oracle returns quoted-uppercase column names
+				for k, rv := range row {
+					if strings.EqualFold(k, o.FieldName) {
+						v, ok = rv, true
+						break
+					}
+				}
+			}
+			if !ok {
+				return "", errors.Errorf("CURSOR_PAGING_ORDER_FIELD_NOT_IN_ROW:%s", o.FieldName)
+			}
+			nc.Values[i] = encodePagingCursorValue(v)
+		}
+		return encodePagingCursor(nc)
+	}
+	// isMore is about the read direction; the other direction has rows whenever a cursor was given
+	if (c.IsBackward && isMore) || (!c.IsBackward && cursor != "") {
+		if pagingResult.PrevCursor, err = newCursor(rows[0], true); err != nil {
+			return nil, err
+		}
+	}
+	if (!c.IsBackward && isMore) || c.IsBackward {
+		if pagingResult.NextCursor, err = newCursor(rows[len(rows)-1], false); err != nil {
+			return nil, err
+		}
+	}
+	return pagingResult, nil
+}
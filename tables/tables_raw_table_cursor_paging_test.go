@@ -0,0 +1,64 @@
+package tables
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/donnyhardyanto/dxlib/base"
+)
+
+func TestPagingCursorRoundTripAndTamper(t *testing.T) {
+	at := time.Date(2026, 1, 2, 3, 4, 5, 6, time.UTC)
+	token, err := encodePagingCursor(pagingCursor{Source: "v_items", Order: "id ASC ", Values: [][2]string{
+		encodePagingCursorValue(int64(42)), encodePagingCursorValue(at), encodePagingCursorValue(nil), encodePagingCursorValue([]byte("abc")),
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := decodePagingCursor(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range []any{int64(42), at, nil, "abc"} {
+		got, err := decodePagingCursorValue(c.Values[i])
+		if err != nil || got != want {
+			t.Fatalf("value %d = %v (%v), want %v", i, got, err, want)
+		}
+	}
+
+	tampered := []byte(token)
+	tampered[3] ^= 1
+	var cursorErr *ErrInvalidPagingCursor
+	if _, err = decodePagingCursor(string(tampered)); !errors.As(err, &cursorErr) {
+		t.Fatalf("tampered cursor error = %v", err)
+	}
+}
+
+func TestCursorSeekCondition(t *testing.T) {
+	keys := []cursorSeekKey{{Expression: `"name"`, Direction: "ASC"}, {Expression: `"id"`, Direction: "ASC", IsNotNull: true}}
+	tests := []struct {
+		name       string
+		dbType     base.DXDatabaseType
+		values     []any
+		isBackward bool
+		want       string
+	}{
+		{"value, nulls last", base.DXDatabaseTypePostgreSQL, []any{"b", int64(7)}, false,
+			`((("name" > :paging_cursor_0 OR "name" IS NULL)) OR ("name" = :paging_cursor_1 AND "id" > :paging_cursor_2))`},
+		{"null, nulls last", base.DXDatabaseTypePostgreSQL, []any{nil, int64(7)}, false,
+			`(("name" IS NULL AND "id" > :paging_cursor_0))`},
+		{"null, nulls first", base.DXDatabaseTypeMariaDB, []any{nil, int64(7)}, false,
+			`(("name" IS NOT NULL) OR ("name" IS NULL AND "id" > :paging_cursor_0))`},
+		{"backward", base.DXDatabaseTypeMariaDB, []any{"b", int64(7)}, true,
+			`((("name" < :paging_cursor_0 OR "name" IS NULL)) OR ("name" = :paging_cursor_1 AND "id" < :paging_cursor_2))`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _ := cursorSeekCondition(tt.dbType, keys, tt.values, tt.isBackward)
+			if got != tt.want {
+				t.Errorf("cursorSeekCondition() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -20,10 +20,14 @@ func (t *DXRawTable) DeleteWithBuilder(ctx context.Context, l *log.DXLog, tqb *t
 	if tqb.Error != nil {
 		return nil, nil, tqb.Error
 	}
+	if err = t.scopeDeleteBuilderToTenant(ctx, tqb); err != nil {
+		return nil, nil, err
+	}
 
 	tqb.SourceName = t.GetFullTableName()
 
-	if len(t.EncryptionKeyDefs) > 0 || len(t.EncryptionColumnDefs) > 0 {
+	// The tenant session config of row-level security needs a transaction as well
+	if len(t.EncryptionKeyDefs) > 0 || len(t.EncryptionColumnDefs) > 0 || t.Database.IsTenantSessionTxNeeded(ctx) {
 		txErr := t.Database.Tx(ctx, l, databases.LevelReadCommitted, func(dtx *databases.DXDatabaseTx) error {
 			if err = t.TxSetAllEncryptionSessionKeys(dtx); err != nil {
 				return err
@@ -37,6 +41,10 @@ func (t *DXRawTable) DeleteWithBuilder(ctx context.Context, l *log.DXLog, tqb *t
 		return result, rows, nil
 	}
 
+	if dtx := databases.TxFromContext(ctx, t.Database); dtx != nil {
+		return query.TxDeleteWithDeleteQueryBuilder2(ctx, dtx, tqb.DeleteQueryBuilder)
+	}
+	t.Database.MarkWritten(ctx)
 	return query.DeleteWithDeleteQueryBuilder2(ctx, t.Database.Connection, tqb.DeleteQueryBuilder)
 }
 
@@ -45,6 +53,9 @@ func (t *DXRawTable) TxDeleteWithBuilder(dtx *databases.DXDatabaseTx, tqb *table
 	if tqb.Error != nil {
 		return nil, nil, tqb.Error
 	}
+	if err := t.scopeDeleteBuilderToTenant(dtx.Ctx, tqb); err != nil {
+		return nil, nil, err
+	}
 
 	tqb.SourceName = t.GetFullTableName()
 	return query.TxDeleteWithDeleteQueryBuilder2(dtx.Ctx, dtx, tqb.DeleteQueryBuilder)
@@ -14,6 +14,9 @@ func (t *DXRawTable) TxDeleteWithBuilderAuto(dtx *databases.DXDatabaseTx, tqb *t
 	if tqb.Error != nil {
 		return nil, nil, tqb.Error
 	}
+	if err := t.scopeDeleteBuilderToTenant(dtx.Ctx, tqb); err != nil {
+		return nil, nil, err
+	}
 
 	tqb.SourceName = t.GetFullTableName()
 
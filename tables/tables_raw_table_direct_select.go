@@ -18,6 +18,10 @@ func (t *DXRawTable) DirectSelect(ctx context.Context, l *log.DXLog, fieldNames
 	if err := t.EnsureDatabase(); err != nil {
 		return nil, nil, err
 	}
+	where, err := t.tenantWhere(ctx, where)
+	if err != nil {
+		return nil, nil, err
+	}
 	return t.Database.Select(ctx, t.GetFullTableName(), t.FieldTypeMapping, fieldNames, where, joinSQLPart, nil, nil, orderBy, limit, nil, forUpdatePart)
 }
 
@@ -27,6 +31,10 @@ func (t *DXRawTable) DirectSelectOne(ctx context.Context, l *log.DXLog, fieldNam
 	if err := t.EnsureDatabase(); err != nil {
 		return nil, nil, err
 	}
+	where, err := t.tenantWhere(ctx, where)
+	if err != nil {
+		return nil, nil, err
+	}
 	return t.Database.SelectOne(ctx, t.GetFullTableName(), t.FieldTypeMapping, fieldNames, where, joinSQLPart, nil, nil, orderBy, nil, nil)
 }
 
@@ -36,6 +44,10 @@ func (t *DXRawTable) DirectShouldSelectOne(ctx context.Context, l *log.DXLog, fi
 	if err := t.EnsureDatabase(); err != nil {
 		return nil, nil, err
 	}
+	where, err := t.tenantWhere(ctx, where)
+	if err != nil {
+		return nil, nil, err
+	}
 	return t.Database.ShouldSelectOne(ctx, t.GetFullTableName(), t.FieldTypeMapping, fieldNames, where, joinSQLPart, nil, nil, orderBy, nil, nil)
 }
 
@@ -123,5 +135,9 @@ func (t *DXRawTable) DirectCount(ctx context.Context, l *log.DXLog, where utils.
 	if err := t.EnsureDatabase(); err != nil {
 		return 0, err
 	}
+	where, err := t.tenantWhere(ctx, where)
+	if err != nil {
+		return 0, err
+	}
 	return t.Database.Count(ctx, t.GetFullTableName(), where, joinSQLPart)
 }
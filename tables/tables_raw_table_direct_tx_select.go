@@ -14,18 +14,30 @@ import (
 // TxDirectSelect returns multiple rows from the base table within a transaction
 func (t *DXRawTable) TxDirectSelect(dtx *databases.DXDatabaseTx, fieldNames []string, where utils.JSON, joinSQLPart any,
 	orderBy db.DXDatabaseTableFieldsOrderBy, limit any, forUpdatePart any) (*db.DXDatabaseTableRowsInfo, []utils.JSON, error) {
+	where, err := t.tenantWhere(dtx.Ctx, where)
+	if err != nil {
+		return nil, nil, err
+	}
 	return dtx.Select(dtx.Ctx, t.GetFullTableName(), t.FieldTypeMapping, fieldNames, where, joinSQLPart, nil, nil, orderBy, limit, nil, forUpdatePart)
 }
 
 // TxDirectSelectOne returns a single row from the base table within a transaction
 func (t *DXRawTable) TxDirectSelectOne(dtx *databases.DXDatabaseTx, fieldNames []string, where utils.JSON, joinSQLPart any,
 	orderBy db.DXDatabaseTableFieldsOrderBy, forUpdatePart any) (*db.DXDatabaseTableRowsInfo, utils.JSON, error) {
+	where, err := t.tenantWhere(dtx.Ctx, where)
+	if err != nil {
+		return nil, nil, err
+	}
 	return dtx.SelectOne(dtx.Ctx, t.GetFullTableName(), t.FieldTypeMapping, fieldNames, where, joinSQLPart, nil, nil, orderBy, nil, forUpdatePart)
 }
 
 // TxDirectShouldSelectOne returns a single row from the base table or error if not found within a transaction
 func (t *DXRawTable) TxDirectShouldSelectOne(dtx *databases.DXDatabaseTx, fieldNames []string, where utils.JSON, joinSQLPart any,
 	orderBy db.DXDatabaseTableFieldsOrderBy, forUpdatePart any) (*db.DXDatabaseTableRowsInfo, utils.JSON, error) {
+	where, err := t.tenantWhere(dtx.Ctx, where)
+	if err != nil {
+		return nil, nil, err
+	}
 	return dtx.ShouldSelectOne(dtx.Ctx, t.GetFullTableName(), t.FieldTypeMapping, fieldNames, where, joinSQLPart, nil, nil, orderBy, nil, forUpdatePart)
 }
 
@@ -17,11 +17,19 @@ func (t *DXRawTable) Insert(ctx context.Context, l *log.DXLog, data utils.JSON,
 	if err := t.EnsureDatabase(); err != nil {
 		return nil, nil, err
 	}
+	data, err := t.tenantInsertData(ctx, data)
+	if err != nil {
+		return nil, nil, err
+	}
 	return t.Database.Insert(ctx, t.GetFullTableName(), data, returningFieldNames)
 }
 
 // TxInsert inserts within a transaction
 func (t *DXRawTable) TxInsert(dtx *databases.DXDatabaseTx, data utils.JSON, returningFieldNames []string) (sql.Result, utils.JSON, error) {
+	data, err := t.tenantInsertData(dtx.Ctx, data)
+	if err != nil {
+		return nil, nil, err
+	}
 	return dtx.Insert(dtx.Ctx, t.GetFullTableName(), data, returningFieldNames)
 }
 
@@ -56,6 +64,10 @@ func (t *DXRawTable) DoInsert(aepr *api.DXAPIEndPointRequest, data utils.JSON) (
 		returningFields = append(returningFields, t.FieldNameForRowUid)
 	}
 
+	data, err := t.tenantInsertData(aepr.Context, data)
+	if err != nil {
+		return 0, err
+	}
 	_, returningValues, err := t.Database.Insert(aepr.Context, t.GetFullTableName(), data, returningFields)
 	if err != nil {
 		return 0, err
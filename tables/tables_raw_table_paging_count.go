@@ -0,0 +1,161 @@
+package tables
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"slices"
+	"time"
+
+	"github.com/donnyhardyanto/dxlib/databases"
+	"github.com/donnyhardyanto/dxlib/databases/db/query"
+	"github.com/donnyhardyanto/dxlib/errors"
+	"github.com/donnyhardyanto/dxlib/log"
+	tableQueryBuilder "github.com/donnyhardyanto/dxlib/tables/query_builder"
+	"github.com/donnyhardyanto/dxlib/utils"
+)
+
+// DXPagingCountStrategy selects how paging lists compute total_rows (DXRawTable.PagingCountStrategy)
+type DXPagingCountStrategy string
+
+const (
+	// DXPagingCountStrategyExact runs COUNT(*) on every request (the default)
+	DXPagingCountStrategyExact DXPagingCountStrategy = "exact"
+	// DXPagingCountStrategyEstimated reads the table statistics for unfiltered lists, and falls back to
+	// DXPagingCountStrategyCapped for filtered ones. The estimate includes soft-deleted rows.
+	DXPagingCountStrategyEstimated DXPagingCountStrategy = "estimated"
+	// DXPagingCountStrategyCapped counts at most PagingCountCap rows, reported as e.g. "10000+"
+	DXPagingCountStrategyCapped DXPagingCountStrategy = "capped"
+	// DXPagingCountStrategyCached keeps exact counts in PagingCountCacheRedis, keyed by the normalised
+	// filter, for PagingCountCacheSeconds
+	DXPagingCountStrategyCached DXPagingCountStrategy = "cached"
+)
+
+const (
+	DefaultPagingCountCap          = 10000
+	DefaultPagingCountCacheSeconds = 60
+)
+
+// pagingCount is a total row count and the strategy that produced it
+type pagingCount struct {
+	TotalRows int64
+	Strategy  DXPagingCountStrategy
+	IsCapped  bool
+}
+
+// apply sets the count fields of a PagingResult
+func (c pagingCount) apply(pr *PagingResult, rowPerPage int64) {
+	pr.TotalRows = c.TotalRows
+	pr.TotalRowsStrategy = c.Strategy
+	pr.IsTotalRowsCapped = c.IsCapped
+	pr.TotalPages = 0
+	if rowPerPage > 0 {
+		pr.TotalPages = (c.TotalRows + rowPerPage - 1) / rowPerPage
+	}
+}
+
+// txPagingCount counts the rows of qb with the table's PagingCountStrategy
+func (t *DXRawTable) txPagingCount(ctx context.Context, dtx *databases.DXDatabaseTx, qb *tableQueryBuilder.TableSelectQueryBuilder) (pagingCount, error) {
+	switch t.PagingCountStrategy {
+	case "", DXPagingCountStrategyExact:
+		return t.txExactPagingCount(ctx, dtx, qb)
+	case DXPagingCountStrategyEstimated:
+		if isUnfilteredPagingQuery(qb) {
+			totalRows, isAvailable, err := query.TxEstimateTableRowCount(ctx, dtx, t.GetFullTableName())
+			if err != nil {
+				return pagingCount{}, err
+			}
+			if isAvailable {
+				return pagingCount{TotalRows: totalRows, Strategy: DXPagingCountStrategyEstimated}, nil
+			}
+		}
+		return t.txCappedPagingCount(ctx, dtx, qb)
+	case DXPagingCountStrategyCapped:
+		return t.txCappedPagingCount(ctx, dtx, qb)
+	case DXPagingCountStrategyCached:
+		return t.txCachedPagingCount(ctx, dtx, qb)
+	default:
+		return pagingCount{}, errors.Errorf("UNKNOWN_PAGING_COUNT_STRATEGY:%s", t.PagingCountStrategy)
+	}
+}
+
+func (t *DXRawTable) txExactPagingCount(ctx context.Context, dtx *databases.DXDatabaseTx, qb *tableQueryBuilder.TableSelectQueryBuilder) (pagingCount, error) {
+	totalRows, err := query.TxCountWithSelectQueryBuilder2(ctx, dtx, qb.SelectQueryBuilder)
+	if err != nil {
+		return pagingCount{}, err
+	}
+	return pagingCount{TotalRows: totalRows, Strategy: DXPagingCountStrategyExact}, nil
+}
+
+func (t *DXRawTable) txCappedPagingCount(ctx context.Context, dtx *databases.DXDatabaseTx, qb *tableQueryBuilder.TableSelectQueryBuilder) (pagingCount, error) {
+	maxCount := t.PagingCountCap
+	if maxCount <= 0 {
+		maxCount = DefaultPagingCountCap
+	}
+	totalRows, isCapped, err := query.TxCappedCountWithSelectQueryBuilder2(ctx, dtx, qb.SelectQueryBuilder, maxCount)
+	if err != nil {
+		return pagingCount{}, err
+	}
+	return pagingCount{TotalRows: totalRows, Strategy: DXPagingCountStrategyCapped, IsCapped: isCapped}, nil
+}
+
+// txCachedPagingCount serves the count from Redis, counting exactly on a miss. Redis errors only
+// log a warning, so the list keeps working when the cache is down.
+func (t *DXRawTable) txCachedPagingCount(ctx context.Context, dtx *databases.DXDatabaseTx, qb *tableQueryBuilder.TableSelectQueryBuilder) (pagingCount, error) {
+	if t.PagingCountCacheRedis == nil {
+		log.Log.Warnf("PAGING_COUNT_CACHE_REDIS_NOT_SET:%s", t.GetFullTableName())
+		return t.txExactPagingCount(ctx, dtx, qb)
+	}
+	key, err := pagingCountCacheKey(qb)
+	if err != nil {
+		return pagingCount{}, err
+	}
+	cached, err := t.PagingCountCacheRedis.Get(ctx, key)
+	if err != nil {
+		log.Log.Warnf("PAGING_COUNT_CACHE_GET_ERROR:%s:%+v", key, err)
+	} else if totalRows, ok := cached["total_rows"].(float64); ok {
+		return pagingCount{TotalRows: int64(totalRows), Strategy: DXPagingCountStrategyCached}, nil
+	}
+
+	c, err := t.txExactPagingCount(ctx, dtx, qb)
+	if err != nil {
+		return pagingCount{}, err
+	}
+	seconds := t.PagingCountCacheSeconds
+	if seconds <= 0 {
+		seconds = DefaultPagingCountCacheSeconds
+	}
+	if err = t.PagingCountCacheRedis.Set(ctx, key, utils.JSON{"total_rows": c.TotalRows}, time.Duration(seconds)*time.Second); err != nil {
+		log.Log.Warnf("PAGING_COUNT_CACHE_SET_ERROR:%s:%+v", key, err)
+	}
+	return c, nil
+}
+
+// pagingCountCacheKey identifies the rows counted by qb: the source and its filter, with the
+// conditions sorted so the order in which filters were added does not matter.
+func pagingCountCacheKey(qb *tableQueryBuilder.TableSelectQueryBuilder) (string, error) {
+	conditions := slices.Clone(qb.Conditions)
+	slices.Sort(conditions)
+	filter, err := json.Marshal([]any{qb.SourceName, conditions, qb.Args, qb.Joins, qb.GroupByFields, qb.HavingConditions})
+	if err != nil {
+		return "", errors.Wrap(err, "PAGING_COUNT_CACHE_KEY_ERROR")
+	}
+	hash := sha256.Sum256(filter)
+	return "paging_count:" + qb.SourceName + ":" + hex.EncodeToString(hash[:]), nil
+}
+
+// isUnfilteredPagingQuery reports whether qb selects the whole source, apart from the default
+// is_deleted = false filter of DoApplyRequestSearchFilter.
+func isUnfilteredPagingQuery(qb *tableQueryBuilder.TableSelectQueryBuilder) bool {
+	if len(qb.Joins) > 0 || len(qb.CTEs) > 0 || len(qb.GroupByFields) > 0 || len(qb.HavingConditions) > 0 {
+		return false
+	}
+	isDeletedCondition := qb.QuoteIdentifier("is_deleted") + " = :is_deleted"
+	for _, condition := range qb.Conditions {
+		if condition != isDeletedCondition || qb.Args["is_deleted"] != false {
+			return false
+		}
+	}
+	return true
+}
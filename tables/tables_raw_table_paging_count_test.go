@@ -0,0 +1,43 @@
+package tables
+
+import "testing"
+
+func TestPagingCountCacheKeyAndUnfiltered(t *testing.T) {
+	table := &DXRawTable{TableNameDirect: "app.items", FilterableFieldNames: []string{"is_deleted", "status", "kind"}}
+
+	qb := table.NewTableSelectQueryBuilder()
+	qb.SourceName = "app.v_items"
+	qb.Eq("is_deleted", false)
+	if !isUnfilteredPagingQuery(qb) {
+		t.Fatal("the default is_deleted filter must count as unfiltered")
+	}
+	qb.Eq("status", "open").Eq("kind", "a")
+
+	reordered := table.NewTableSelectQueryBuilder()
+	reordered.SourceName = "app.v_items"
+	reordered.Eq("kind", "a").Eq("status", "open").Eq("is_deleted", false)
+	if isUnfilteredPagingQuery(reordered) {
+		t.Fatal("a status filter must not count as unfiltered")
+	}
+
+	key1, err := pagingCountCacheKey(qb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key2, err := pagingCountCacheKey(reordered)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reordered.Eq("status", "closed")
+	key3, _ := pagingCountCacheKey(reordered)
+	if key1 != key2 || key1 == key3 {
+		t.Fatalf("cache keys: %s %s %s", key1, key2, key3)
+	}
+
+	pr := &PagingResult{}
+	pagingCount{TotalRows: 10000, Strategy: DXPagingCountStrategyCapped, IsCapped: true}.apply(pr, 30)
+	list := pr.ToResponseJSON()["data"].(map[string]any)["list"].(map[string]any)
+	if list["total_rows_label"] != "10000+" || list["total_rows_strategy"] != DXPagingCountStrategyCapped || pr.TotalPages != 334 {
+		t.Fatalf("list = %v, total pages = %d", list, pr.TotalPages)
+	}
+}
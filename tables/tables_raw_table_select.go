@@ -19,12 +19,18 @@ func (t *DXRawTable) Select(ctx context.Context, l *log.DXLog, fieldNames []stri
 	if err = t.EnsureDatabase(); err != nil {
 		return nil, nil, err
 	}
+	if where, err = t.tenantWhere(ctx, where); err != nil {
+		return nil, nil, err
+	}
+	if forUpdatePart == true {
+		ctx = databases.ContextWithPrimary(ctx)
+	}
 	if len(t.EncryptionColumnDefs) > 0 {
 		encryptionColumns := t.convertEncryptionColumnDefsForSelect()
 		return t.SelectWithEncryption(ctx, l, fieldNames, encryptionColumns, where, joinSQLPart, orderBy, limit, forUpdatePart)
 	}
 	if len(t.EncryptionKeyDefs) > 0 {
-		txErr := t.Database.Tx(ctx, l, databases.LevelReadCommitted, func(dtx *databases.DXDatabaseTx) error {
+		txErr := t.Database.ReadTx(ctx, l, databases.LevelReadCommitted, func(dtx *databases.DXDatabaseTx) error {
 			if err = t.TxSetAllEncryptionSessionKeys(dtx); err != nil {
 				return err
 			}
@@ -45,12 +51,15 @@ func (t *DXRawTable) SelectOne(ctx context.Context, l *log.DXLog, fieldNames []s
 	if err = t.EnsureDatabase(); err != nil {
 		return nil, nil, err
 	}
+	if where, err = t.tenantWhere(ctx, where); err != nil {
+		return nil, nil, err
+	}
 	if len(t.EncryptionColumnDefs) > 0 {
 		encryptionColumns := t.convertEncryptionColumnDefsForSelect()
 		return t.SelectOneWithEncryption(ctx, l, fieldNames, encryptionColumns, where, joinSQLPart, orderBy)
 	}
 	if len(t.EncryptionKeyDefs) > 0 {
-		txErr := t.Database.Tx(ctx, l, databases.LevelReadCommitted, func(dtx *databases.DXDatabaseTx) error {
+		txErr := t.Database.ReadTx(ctx, l, databases.LevelReadCommitted, func(dtx *databases.DXDatabaseTx) error {
 			if err = t.TxSetAllEncryptionSessionKeys(dtx); err != nil {
 				return err
 			}
@@ -71,12 +80,15 @@ func (t *DXRawTable) ShouldSelectOne(ctx context.Context, l *log.DXLog, fieldNam
 	if err = t.EnsureDatabase(); err != nil {
 		return nil, nil, err
 	}
+	if where, err = t.tenantWhere(ctx, where); err != nil {
+		return nil, nil, err
+	}
 	if len(t.EncryptionColumnDefs) > 0 {
 		encryptionColumns := t.convertEncryptionColumnDefsForSelect()
 		return t.ShouldSelectOneWithEncryption(ctx, l, fieldNames, encryptionColumns, where, joinSQLPart, orderBy)
 	}
 	if len(t.EncryptionKeyDefs) > 0 {
-		txErr := t.Database.Tx(ctx, l, databases.LevelReadCommitted, func(dtx *databases.DXDatabaseTx) error {
+		txErr := t.Database.ReadTx(ctx, l, databases.LevelReadCommitted, func(dtx *databases.DXDatabaseTx) error {
 			if err = t.TxSetAllEncryptionSessionKeys(dtx); err != nil {
 				return err
 			}
@@ -203,6 +215,10 @@ func (t *DXRawTable) ShouldGetBy(ctx context.Context, l *log.DXLog, fieldName st
 // TxSelect returns multiple rows within a transaction
 func (t *DXRawTable) TxSelect(dtx *databases.DXDatabaseTx, fieldNames []string, where utils.JSON, joinSQLPart any,
 	orderBy db.DXDatabaseTableFieldsOrderBy, limit any, forUpdatePart any) (*db.DXDatabaseTableRowsInfo, []utils.JSON, error) {
+	where, err := t.tenantWhere(dtx.Ctx, where)
+	if err != nil {
+		return nil, nil, err
+	}
 	return dtx.Select(dtx.Ctx, t.GetListViewName(), t.FieldTypeMapping, fieldNames, where, joinSQLPart, nil, nil, orderBy, limit, nil, forUpdatePart)
 }
 
@@ -214,6 +230,10 @@ func (t *DXRawTable) TxSelectOne(dtx *databases.DXDatabaseTx, fieldNames []strin
 	if forUpdatePart != nil && forUpdatePart != false && forUpdatePart != "" {
 		tableName = t.GetFullTableName()
 	}
+	where, err := t.tenantWhere(dtx.Ctx, where)
+	if err != nil {
+		return nil, nil, err
+	}
 	return dtx.SelectOne(dtx.Ctx, tableName, t.FieldTypeMapping, fieldNames, where, joinSQLPart, nil, nil, orderBy, nil, forUpdatePart)
 }
 
@@ -225,6 +245,10 @@ func (t *DXRawTable) TxShouldSelectOne(dtx *databases.DXDatabaseTx, fieldNames [
 	if forUpdatePart != nil && forUpdatePart != false && forUpdatePart != "" {
 		tableName = t.GetFullTableName()
 	}
+	where, err := t.tenantWhere(dtx.Ctx, where)
+	if err != nil {
+		return nil, nil, err
+	}
 	return dtx.ShouldSelectOne(dtx.Ctx, tableName, t.FieldTypeMapping, fieldNames, where, joinSQLPart, nil, nil, orderBy, nil, forUpdatePart)
 }
 
@@ -325,8 +349,11 @@ func (t *DXRawTable) Count(ctx context.Context, l *log.DXLog, where utils.JSON,
 	if err = t.EnsureDatabase(); err != nil {
 		return 0, err
 	}
+	if where, err = t.tenantWhere(ctx, where); err != nil {
+		return 0, err
+	}
 	if len(t.EncryptionKeyDefs) > 0 || len(t.EncryptionColumnDefs) > 0 {
-		txErr := t.Database.Tx(ctx, l, databases.LevelReadCommitted, func(dtx *databases.DXDatabaseTx) error {
+		txErr := t.Database.ReadTx(ctx, l, databases.LevelReadCommitted, func(dtx *databases.DXDatabaseTx) error {
 			if err = t.TxSetAllEncryptionSessionKeys(dtx); err != nil {
 				return err
 			}
@@ -12,6 +12,7 @@ import (
 	"github.com/donnyhardyanto/dxlib/log"
 	tableQueryBuilder "github.com/donnyhardyanto/dxlib/tables/query_builder"
 	"github.com/donnyhardyanto/dxlib/utils"
+	"github.com/jmoiron/sqlx"
 )
 
 // prepareBuilderForSelect sets SourceName and handles encryption OutFields on the builder.
@@ -56,11 +57,18 @@ func (t *DXRawTable) SelectWithBuilder(ctx context.Context, l *log.DXLog, tqb *t
 	if tqb.Error != nil {
 		return nil, nil, tqb.Error
 	}
+	if err = t.scopeSelectBuilderToTenant(ctx, tqb.SelectQueryBuilder); err != nil {
+		return nil, nil, err
+	}
 
 	needsEncryptionTx := t.prepareBuilderForSelect(tqb)
+	if s, ok := tqb.ForUpdatePart.(string); ok && s != "" {
+		ctx = databases.ContextWithPrimary(ctx)
+	}
 
-	if needsEncryptionTx {
-		txErr := t.Database.Tx(ctx, l, databases.LevelReadCommitted, func(dtx *databases.DXDatabaseTx) error {
+	// The tenant session config of row-level security needs a transaction as well
+	if needsEncryptionTx || t.Database.IsTenantSessionTxNeeded(ctx) {
+		txErr := t.Database.ReadTx(ctx, l, databases.LevelReadCommitted, func(dtx *databases.DXDatabaseTx) error {
 			if err = t.TxSetAllEncryptionSessionKeys(dtx); err != nil {
 				return err
 			}
@@ -73,7 +81,17 @@ func (t *DXRawTable) SelectWithBuilder(ctx context.Context, l *log.DXLog, tqb *t
 		return rowsInfo, rows, nil
 	}
 
-	return query.SelectWithSelectQueryBuilder2(ctx, t.Database.Connection, tqb.SelectQueryBuilder, t.FieldTypeMapping)
+	if dtx := databases.TxFromContext(ctx, t.Database); dtx != nil {
+		return query.TxSelectWithSelectQueryBuilder2(ctx, dtx, tqb.SelectQueryBuilder, t.FieldTypeMapping)
+	}
+	err = t.Database.WithReadConnection(ctx, func(connection *sqlx.DB) (err error) {
+		rowsInfo, rows, err = query.SelectWithSelectQueryBuilder2(ctx, connection, tqb.SelectQueryBuilder, t.FieldTypeMapping)
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return rowsInfo, rows, nil
 }
 
 // SelectOneWithBuilder returns a single row using TableSelectQueryBuilder.
@@ -110,6 +128,9 @@ func (t *DXRawTable) TxSelectWithBuilder(dtx *databases.DXDatabaseTx, tqb *table
 	if tqb.Error != nil {
 		return nil, nil, tqb.Error
 	}
+	if err := t.scopeSelectBuilderToTenant(dtx.Ctx, tqb.SelectQueryBuilder); err != nil {
+		return nil, nil, err
+	}
 
 	tqb.SourceName = t.GetListViewName()
 	return query.TxSelectWithSelectQueryBuilder2(dtx.Ctx, dtx, tqb.SelectQueryBuilder, t.FieldTypeMapping)
@@ -152,11 +173,15 @@ func (t *DXRawTable) CountWithBuilder(ctx context.Context, l *log.DXLog, tqb *ta
 	if tqb.Error != nil {
 		return 0, tqb.Error
 	}
+	if err = t.scopeSelectBuilderToTenant(ctx, tqb.SelectQueryBuilder); err != nil {
+		return 0, err
+	}
 
 	tqb.SourceName = t.GetListViewName()
 
-	if len(t.EncryptionKeyDefs) > 0 || len(t.EncryptionColumnDefs) > 0 {
-		txErr := t.Database.Tx(ctx, l, databases.LevelReadCommitted, func(dtx *databases.DXDatabaseTx) error {
+	// The tenant session config of row-level security needs a transaction as well
+	if len(t.EncryptionKeyDefs) > 0 || len(t.EncryptionColumnDefs) > 0 || t.Database.IsTenantSessionTxNeeded(ctx) {
+		txErr := t.Database.ReadTx(ctx, l, databases.LevelReadCommitted, func(dtx *databases.DXDatabaseTx) error {
 			if err = t.TxSetAllEncryptionSessionKeys(dtx); err != nil {
 				return err
 			}
@@ -169,5 +194,15 @@ func (t *DXRawTable) CountWithBuilder(ctx context.Context, l *log.DXLog, tqb *ta
 		return count, nil
 	}
 
-	return query.CountWithSelectQueryBuilder2(ctx, t.Database.Connection, tqb.SelectQueryBuilder)
+	if dtx := databases.TxFromContext(ctx, t.Database); dtx != nil {
+		return query.TxCountWithSelectQueryBuilder2(ctx, dtx, tqb.SelectQueryBuilder)
+	}
+	err = t.Database.WithReadConnection(ctx, func(connection *sqlx.DB) (err error) {
+		count, err = query.CountWithSelectQueryBuilder2(ctx, connection, tqb.SelectQueryBuilder)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
 }
@@ -15,6 +15,9 @@ func (t *DXRawTable) TxSelectWithBuilderAuto(dtx *databases.DXDatabaseTx, tqb *t
 	if tqb.Error != nil {
 		return nil, nil, tqb.Error
 	}
+	if err := t.scopeSelectBuilderToTenant(dtx.Ctx, tqb.SelectQueryBuilder); err != nil {
+		return nil, nil, err
+	}
 
 	t.prepareBuilderForSelect(tqb)
 
@@ -60,6 +63,9 @@ func (t *DXRawTable) TxCountWithBuilderAuto(dtx *databases.DXDatabaseTx, tqb *ta
 	if tqb.Error != nil {
 		return 0, tqb.Error
 	}
+	if err := t.scopeSelectBuilderToTenant(dtx.Ctx, tqb.SelectQueryBuilder); err != nil {
+		return 0, err
+	}
 
 	tqb.SourceName = t.GetListViewName()
 
@@ -0,0 +1,148 @@
+package tables
+
+import (
+	"context"
+	"maps"
+	"strings"
+
+	"github.com/donnyhardyanto/dxlib/base"
+	"github.com/donnyhardyanto/dxlib/databases"
+	"github.com/donnyhardyanto/dxlib/databases/db/query/builder"
+	utils2 "github.com/donnyhardyanto/dxlib/databases/db/query/utils"
+	"github.com/donnyhardyanto/dxlib/errors"
+	tableQueryBuilder "github.com/donnyhardyanto/dxlib/tables/query_builder"
+	"github.com/donnyhardyanto/dxlib/utils"
+)
+
+// Tenancy mode: with TenantFieldName set, every select, count, update and delete of the table
+// is scoped to the tenant of the context (databases.ContextWithTenantId) and every insert gets
+// it as TenantFieldName. Queries through a context without a tenant are refused, as are
+// updates that try to move rows to another tenant. When the database sets the tenant session
+// config for row-level security (databases.DXDatabase.IsTenantSessionTxNeeded), the operations
+// made without a transaction run in one of their own, and bulk inserts do not use COPY.
+
+// tenantIdParamName is the builder parameter the tenant predicate binds
+const tenantIdParamName = "__tenant_id__"
+
+// contextTenantId returns the tenant queries through ctx are scoped to; ok is false when the
+// table is not in tenancy mode.
+func (t *DXRawTable) contextTenantId(ctx context.Context) (tenantId int64, ok bool, err error) {
+	if t.TenantFieldName == "" {
+		return 0, false, nil
+	}
+	tenantId, ok = databases.TenantIdFromContext(ctx)
+	if !ok {
+		return 0, false, errors.Errorf("TENANT_NOT_IN_CONTEXT:%s", t.GetFullTableName())
+	}
+	return tenantId, true, nil
+}
+
+// tenantWhere returns where with the tenant predicate added, without changing where
+func (t *DXRawTable) tenantWhere(ctx context.Context, where utils.JSON) (utils.JSON, error) {
+	tenantId, ok, err := t.contextTenantId(ctx)
+	if err != nil || !ok {
+		return where, err
+	}
+	scoped := maps.Clone(where)
+	if scoped == nil {
+		scoped = utils.JSON{}
+	}
+	scoped[t.TenantFieldName] = tenantId
+	return scoped, nil
+}
+
+// tenantInsertData returns data with the tenant set, without changing data
+func (t *DXRawTable) tenantInsertData(ctx context.Context, data utils.JSON) (utils.JSON, error) {
+	return t.tenantWhere(ctx, data)
+}
+
+// tenantInsertRows returns rows with the tenant set on each, without changing rows
+func (t *DXRawTable) tenantInsertRows(ctx context.Context, rows []utils.JSON) ([]utils.JSON, error) {
+	if t.TenantFieldName == "" {
+		return rows, nil
+	}
+	scoped := make([]utils.JSON, len(rows))
+	for i, row := range rows {
+		var err error
+		if scoped[i], err = t.tenantInsertData(ctx, row); err != nil {
+			return nil, err
+		}
+	}
+	return scoped, nil
+}
+
+// checkTenantUpdateData refuses an update that sets the tenant column
+func (t *DXRawTable) checkTenantUpdateData(data utils.JSON) error {
+	if t.TenantFieldName == "" {
+		return nil
+	}
+	if _, ok := data[t.TenantFieldName]; ok {
+		return errors.Errorf("TENANT_FIELD_NOT_UPDATABLE:%s.%s", t.GetFullTableName(), t.TenantFieldName)
+	}
+	return nil
+}
+
+// scopeConditionsToTenant adds the tenant predicate to the WHERE of a builder once; running
+// the builder again only rebinds the tenant.
+func (t *DXRawTable) scopeConditionsToTenant(ctx context.Context, dbType base.DXDatabaseType, conditions *[]string, args utils.JSON) error {
+	tenantId, ok, err := t.contextTenantId(ctx)
+	if err != nil || !ok {
+		return err
+	}
+	if _, isScoped := args[tenantIdParamName]; !isScoped {
+		*conditions = append(*conditions, utils2.QuoteIdentifierByDbType(dbType, t.TenantFieldName)+" = :"+tenantIdParamName)
+	}
+	args[tenantIdParamName] = tenantId
+	return nil
+}
+
+// tenantWhereClause returns a raw WHERE clause, bound with named whereArgs, with the tenant
+// predicate added
+func (t *DXRawTable) tenantWhereClause(ctx context.Context, dbType base.DXDatabaseType, whereClause string, whereArgs utils.JSON) (string, utils.JSON, error) {
+	tenantId, ok, err := t.contextTenantId(ctx)
+	if err != nil || !ok {
+		return whereClause, whereArgs, err
+	}
+	predicate := utils2.QuoteIdentifierByDbType(dbType, t.TenantFieldName) + " = :" + tenantIdParamName
+	if strings.TrimSpace(whereClause) != "" {
+		predicate = "(" + whereClause + ") AND " + predicate
+	}
+	args := maps.Clone(whereArgs)
+	if args == nil {
+		args = utils.JSON{}
+	}
+	args[tenantIdParamName] = tenantId
+	return predicate, args, nil
+}
+
+// scopeSelectBuilderToTenant scopes a select builder to the tenant of ctx. The members of
+// set operations are rendered already and cannot be scoped, so they are refused.
+func (t *DXRawTable) scopeSelectBuilderToTenant(ctx context.Context, qb *builder.SelectQueryBuilder) error {
+	if t.TenantFieldName == "" {
+		return nil
+	}
+	if len(qb.SetOperations) > 0 {
+		return errors.Errorf("TENANT_SCOPE_SET_OPERATION_NOT_SUPPORTED:%s", t.GetFullTableName())
+	}
+	return t.scopeConditionsToTenant(ctx, qb.DbType, &qb.Conditions, qb.Args)
+}
+
+func (t *DXRawTable) scopeUpdateBuilderToTenant(ctx context.Context, tqb *tableQueryBuilder.TableUpdateQueryBuilder) error {
+	if err := t.checkTenantUpdateData(tqb.SetFields); err != nil {
+		return err
+	}
+	return t.scopeConditionsToTenant(ctx, tqb.DbType, &tqb.Conditions, tqb.Args)
+}
+
+func (t *DXRawTable) scopeDeleteBuilderToTenant(ctx context.Context, tqb *tableQueryBuilder.TableDeleteQueryBuilder) error {
+	return t.scopeConditionsToTenant(ctx, tqb.DbType, &tqb.Conditions, tqb.Args)
+}
+
+func (t *DXRawTable) scopeInsertBuilderToTenant(ctx context.Context, tqb *tableQueryBuilder.TableInsertQueryBuilder) error {
+	tenantId, ok, err := t.contextTenantId(ctx)
+	if err != nil || !ok {
+		return err
+	}
+	tqb.SetFields[t.TenantFieldName] = tenantId
+	return nil
+}
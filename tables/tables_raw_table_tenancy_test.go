@@ -0,0 +1,92 @@
+package tables
+
+import (
+	"context"
+	"testing"
+
+	"github.com/donnyhardyanto/dxlib/base"
+	"github.com/donnyhardyanto/dxlib/databases"
+	"github.com/donnyhardyanto/dxlib/log"
+	"github.com/donnyhardyanto/dxlib/utils"
+	"github.com/jmoiron/sqlx"
+	_ "modernc.org/sqlite"
+)
+
+func TestTenancyScopesQueries(t *testing.T) {
+	table := &DXRawTable{TableNameDirect: "app.orders", TenantFieldName: "tenant_id"}
+	ctx := databases.ContextWithTenantId(context.Background(), 42)
+
+	if _, err := table.tenantWhere(context.Background(), utils.JSON{"id": 1}); err == nil {
+		t.Fatal("a query without a tenant in its context must be refused")
+	}
+	where := utils.JSON{"id": 1}
+	scoped, err := table.tenantWhere(ctx, where)
+	if err != nil || scoped["tenant_id"] != int64(42) || len(where) != 1 {
+		t.Fatalf("scoped = %v, where = %v, err = %v", scoped, where, err)
+	}
+
+	qb := table.NewTableSelectQueryBuilder()
+	qb.And(`"status" = 'open'`)
+	for range 2 {
+		if err = table.scopeSelectBuilderToTenant(ctx, qb.SelectQueryBuilder); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(qb.Conditions) != 2 || qb.Conditions[1] != `"tenant_id" = :__tenant_id__` || qb.Args[tenantIdParamName] != int64(42) {
+		t.Fatalf("conditions = %v, args = %v", qb.Conditions, qb.Args)
+	}
+
+	iqb := table.NewTableInsertQueryBuilder()
+	if err = table.scopeInsertBuilderToTenant(ctx, iqb); err != nil || iqb.SetFields["tenant_id"] != int64(42) {
+		t.Fatalf("set fields = %v, err = %v", iqb.SetFields, err)
+	}
+
+	uqb := table.NewTableUpdateQueryBuilder()
+	uqb.SetFields["tenant_id"] = int64(7)
+	if err = table.scopeUpdateBuilderToTenant(ctx, uqb); err == nil {
+		t.Fatal("an update moving rows to another tenant must be refused")
+	}
+
+	if (&DXRawTable{}).checkTenantUpdateData(utils.JSON{"tenant_id": 7}) != nil {
+		t.Fatal("a table not in tenancy mode must not be scoped")
+	}
+}
+
+// A view filtered by the tenant session config stands in for a row-level security policy
+func TestTenancySessionConfigWithoutTx(t *testing.T) {
+	connection, err := sqlx.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	connection.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = connection.Close() })
+	for _, statement := range []string{
+		`CREATE TABLE orders (id INTEGER PRIMARY KEY, tenant_id INTEGER NOT NULL, status TEXT)`,
+		`CREATE TEMP VIEW v_orders AS SELECT * FROM orders WHERE tenant_id = CAST((SELECT value FROM temp.dx_session_context WHERE key = 'app.tenant_id') AS INTEGER)`,
+		`INSERT INTO orders (id, tenant_id, status) VALUES (1, 42, 'open'), (2, 7, 'open')`,
+	} {
+		if _, err = connection.Exec(statement); err != nil {
+			t.Fatal(err)
+		}
+	}
+	table := &DXRawTable{
+		TableNameDirect: "orders",
+		ListViewNameId:  "v_orders",
+		TenantFieldName: "tenant_id",
+		Database: &databases.DXDatabase{NameId: "tenancy", DatabaseType: base.DXDatabaseTypeSQLite, Connection: connection, Connected: true,
+			IsTenantSessionConfigEnabled: true},
+	}
+	ctx := databases.ContextWithTenantId(context.Background(), 42)
+
+	_, rows, err := table.Select(ctx, &log.Log, nil, utils.JSON{"status": "open"}, nil, nil, nil, nil)
+	if err != nil || len(rows) != 1 || rows[0]["id"] != int64(1) {
+		t.Fatalf("a select outside a transaction must see the tenant session config: rows = %v, err = %v", rows, err)
+	}
+	if _, err = connection.Exec(`DELETE FROM temp.dx_session_context`); err != nil {
+		t.Fatal(err)
+	}
+	count, err := table.Count(ctx, &log.Log, utils.JSON{"status": "open"}, nil)
+	if err != nil || count != 1 {
+		t.Fatalf("count = %d, err = %v", count, err)
+	}
+}
@@ -17,11 +17,25 @@ func (t *DXRawTable) Update(ctx context.Context, l *log.DXLog, data utils.JSON,
 	if err := t.EnsureDatabase(); err != nil {
 		return nil, nil, err
 	}
+	if err := t.checkTenantUpdateData(data); err != nil {
+		return nil, nil, err
+	}
+	where, err := t.tenantWhere(ctx, where)
+	if err != nil {
+		return nil, nil, err
+	}
 	return t.Database.Update(ctx, t.GetFullTableName(), data, where, returningFieldNames)
 }
 
 // TxUpdate updates within a transaction
 func (t *DXRawTable) TxUpdate(dtx *databases.DXDatabaseTx, data utils.JSON, where utils.JSON, returningFieldNames []string) (sql.Result, []utils.JSON, error) {
+	if err := t.checkTenantUpdateData(data); err != nil {
+		return nil, nil, err
+	}
+	where, err := t.tenantWhere(dtx.Ctx, where)
+	if err != nil {
+		return nil, nil, err
+	}
 	return dtx.Update(dtx.Ctx, t.GetFullTableName(), data, where, returningFieldNames)
 }
 
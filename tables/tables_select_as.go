@@ -0,0 +1,89 @@
+package tables
+
+import (
+	"context"
+
+	"github.com/donnyhardyanto/dxlib/base"
+	"github.com/donnyhardyanto/dxlib/databases"
+	"github.com/donnyhardyanto/dxlib/databases/db"
+	"github.com/donnyhardyanto/dxlib/log"
+	"github.com/donnyhardyanto/dxlib/utils"
+)
+
+// DXTableRowSelector is the select API shared by DXRawTable and the tables embedding it.
+// Taking the interface keeps DXTable's own Select (which hides soft-deleted rows) in
+// effect when a DXTable is passed to the typed selects below.
+type DXTableRowSelector interface {
+	GetDbType() base.DXDatabaseType
+	Select(ctx context.Context, l *log.DXLog, fieldNames []string, where utils.JSON, joinSQLPart any,
+		orderBy db.DXDatabaseTableFieldsOrderBy, limit any, forUpdatePart any) (*db.DXDatabaseTableRowsInfo, []utils.JSON, error)
+	SelectOne(ctx context.Context, l *log.DXLog, fieldNames []string, where utils.JSON, joinSQLPart any,
+		orderBy db.DXDatabaseTableFieldsOrderBy) (*db.DXDatabaseTableRowsInfo, utils.JSON, error)
+	ShouldSelectOne(ctx context.Context, l *log.DXLog, fieldNames []string, where utils.JSON, joinSQLPart any,
+		orderBy db.DXDatabaseTableFieldsOrderBy) (*db.DXDatabaseTableRowsInfo, utils.JSON, error)
+	TxSelect(dtx *databases.DXDatabaseTx, fieldNames []string, where utils.JSON, joinSQLPart any,
+		orderBy db.DXDatabaseTableFieldsOrderBy, limit any, forUpdatePart any) (*db.DXDatabaseTableRowsInfo, []utils.JSON, error)
+	TxSelectOne(dtx *databases.DXDatabaseTx, fieldNames []string, where utils.JSON, joinSQLPart any,
+		orderBy db.DXDatabaseTableFieldsOrderBy, forUpdatePart any) (*db.DXDatabaseTableRowsInfo, utils.JSON, error)
+	TxShouldSelectOne(dtx *databases.DXDatabaseTx, fieldNames []string, where utils.JSON, joinSQLPart any,
+		orderBy db.DXDatabaseTableFieldsOrderBy, forUpdatePart any) (*db.DXDatabaseTableRowsInfo, utils.JSON, error)
+}
+
+// SelectAs is Select mapping the rows into []T (see db.MapRow for the mapping rules).
+func SelectAs[T any](ctx context.Context, t DXTableRowSelector, l *log.DXLog, fieldNames []string, where utils.JSON, joinSQLPart any,
+	orderBy db.DXDatabaseTableFieldsOrderBy, limit any, forUpdatePart any) ([]T, error) {
+	_, rows, err := t.Select(ctx, l, fieldNames, where, joinSQLPart, orderBy, limit, forUpdatePart)
+	if err != nil {
+		return nil, err
+	}
+	return db.MapRows[T](t.GetDbType(), rows)
+}
+
+// SelectOneAs is SelectOne mapping the row into *T, nil when not found.
+func SelectOneAs[T any](ctx context.Context, t DXTableRowSelector, l *log.DXLog, fieldNames []string, where utils.JSON, joinSQLPart any,
+	orderBy db.DXDatabaseTableFieldsOrderBy) (*T, error) {
+	_, row, err := t.SelectOne(ctx, l, fieldNames, where, joinSQLPart, orderBy)
+	return mapTableRow[T](t, row, err)
+}
+
+// ShouldSelectOneAs is ShouldSelectOne mapping the row into *T.
+func ShouldSelectOneAs[T any](ctx context.Context, t DXTableRowSelector, l *log.DXLog, fieldNames []string, where utils.JSON, joinSQLPart any,
+	orderBy db.DXDatabaseTableFieldsOrderBy) (*T, error) {
+	_, row, err := t.ShouldSelectOne(ctx, l, fieldNames, where, joinSQLPart, orderBy)
+	return mapTableRow[T](t, row, err)
+}
+
+// TxSelectAs is TxSelect mapping the rows into []T.
+func TxSelectAs[T any](dtx *databases.DXDatabaseTx, t DXTableRowSelector, fieldNames []string, where utils.JSON, joinSQLPart any,
+	orderBy db.DXDatabaseTableFieldsOrderBy, limit any, forUpdatePart any) ([]T, error) {
+	_, rows, err := t.TxSelect(dtx, fieldNames, where, joinSQLPart, orderBy, limit, forUpdatePart)
+	if err != nil {
+		return nil, err
+	}
+	return db.MapRows[T](dtx.Database.DatabaseType, rows)
+}
+
+// TxSelectOneAs is TxSelectOne mapping the row into *T, nil when not found.
+func TxSelectOneAs[T any](dtx *databases.DXDatabaseTx, t DXTableRowSelector, fieldNames []string, where utils.JSON, joinSQLPart any,
+	orderBy db.DXDatabaseTableFieldsOrderBy, forUpdatePart any) (*T, error) {
+	_, row, err := t.TxSelectOne(dtx, fieldNames, where, joinSQLPart, orderBy, forUpdatePart)
+	return mapTableRow[T](t, row, err)
+}
+
+// TxShouldSelectOneAs is TxShouldSelectOne mapping the row into *T.
+func TxShouldSelectOneAs[T any](dtx *databases.DXDatabaseTx, t DXTableRowSelector, fieldNames []string, where utils.JSON, joinSQLPart any,
+	orderBy db.DXDatabaseTableFieldsOrderBy, forUpdatePart any) (*T, error) {
+	_, row, err := t.TxShouldSelectOne(dtx, fieldNames, where, joinSQLPart, orderBy, forUpdatePart)
+	return mapTableRow[T](t, row, err)
+}
+
+func mapTableRow[T any](t DXTableRowSelector, row utils.JSON, err error) (*T, error) {
+	if err != nil || row == nil {
+		return nil, err
+	}
+	r, err := db.MapRow[T](t.GetDbType(), row)
+	if err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
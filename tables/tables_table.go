@@ -162,6 +162,13 @@ func (t *DXTable) Upsert(ctx context.Context, l *log.DXLog, data utils.JSON, whe
 	if err := t.EnsureDatabase(); err != nil {
 		return nil, 0, false, err
 	}
+	if err := t.checkTenantUpdateData(data); err != nil {
+		return nil, 0, false, err
+	}
+	where, err := t.tenantWhere(ctx, where)
+	if err != nil {
+		return nil, 0, false, err
+	}
 
 	insertData := utilsJson.DeepMerge2(data, where)
 	t.SetInsertAuditFields(nil, insertData)
@@ -172,11 +179,34 @@ func (t *DXTable) Upsert(ctx context.Context, l *log.DXLog, data utils.JSON, whe
 	}
 	t.SetUpdateAuditFields(nil, updateData)
 
+	if dtx := databases.TxFromContext(ctx, t.Database); dtx != nil {
+		return db.TxUpsert(ctx, dtx.Tx, t.GetFullTableName(), insertData, updateData, where, t.FieldNameForRowId)
+	}
+	if t.Database.IsTenantSessionTxNeeded(ctx) {
+		var (
+			result   sql.Result
+			id       int64
+			isInsert bool
+		)
+		err := t.Database.Tx(ctx, l, databases.LevelReadCommitted, func(dtx *databases.DXDatabaseTx) (err error) {
+			result, id, isInsert, err = db.TxUpsert(dtx.Ctx, dtx.Tx, t.GetFullTableName(), insertData, updateData, where, t.FieldNameForRowId)
+			return err
+		})
+		return result, id, isInsert, err
+	}
+	t.Database.MarkWritten(ctx)
 	return db.Upsert(ctx, t.Database.Connection, t.GetFullTableName(), insertData, updateData, where, t.FieldNameForRowId)
 }
 
 // TxUpsert is the transactional variant of Upsert. Same audit semantics.
 func (t *DXTable) TxUpsert(dtx *databases.DXDatabaseTx, data utils.JSON, where utils.JSON) (sql.Result, int64, bool, error) {
+	if err := t.checkTenantUpdateData(data); err != nil {
+		return nil, 0, false, err
+	}
+	where, err := t.tenantWhere(dtx.Ctx, where)
+	if err != nil {
+		return nil, 0, false, err
+	}
 	insertData := utilsJson.DeepMerge2(data, where)
 	t.SetInsertAuditFields(nil, insertData)
 
@@ -266,7 +296,7 @@ func (t *DXTable) RequestSearchPagingDownload(aepr *api.DXAPIEndPointRequest) er
 
 	qb := t.DXRawTable.NewTableSelectQueryBuilder()
 	if searchText != "" {
-		qb.SearchLike(searchText, t.SearchTextFieldNames...)
+		t.applySearchText(qb, searchText)
 	}
 	if isFilterKeyValuesExist && filterKeyValues != nil {
 		err := t.processFilterKeyValues(qb, filterKeyValues)
@@ -282,6 +282,13 @@ func (t *DXTableAuditOnly) Upsert(ctx context.Context, l *log.DXLog, data utils.
 	if err := t.EnsureDatabase(); err != nil {
 		return nil, 0, err
 	}
+	if err := t.checkTenantUpdateData(data); err != nil {
+		return nil, 0, err
+	}
+	where, err := t.tenantWhere(ctx, where)
+	if err != nil {
+		return nil, 0, err
+	}
 
 	_, existing, err := t.DXRawTable.SelectOne(ctx, l, nil, where, nil, nil)
 	if err != nil {
@@ -306,6 +313,14 @@ func (t *DXTableAuditOnly) Upsert(ctx context.Context, l *log.DXLog, data utils.
 
 // TxUpsert inserts or updates within a transaction with audit fields
 func (t *DXTableAuditOnly) TxUpsert(dtx *databases.DXDatabaseTx, data utils.JSON, where utils.JSON) (sql.Result, int64, error) {
+	if err := t.checkTenantUpdateData(data); err != nil {
+		return nil, 0, err
+	}
+	where, err := t.tenantWhere(dtx.Ctx, where)
+	if err != nil {
+		return nil, 0, err
+	}
+
 	_, existing, err := t.DXRawTable.TxSelectOne(dtx, nil, where, nil, nil, nil)
 	if err != nil {
 		return nil, 0, err
@@ -136,6 +136,10 @@ const UIDDefaultExprOracle = "LOWER(TO_CHAR(ROUND((CAST(SYS_EXTRACT_UTC(SYSTIMES
 // UIDDefaultExprMariaDB is the MariaDB/MySQL UID default expression
 const UIDDefaultExprMariaDB = "CONCAT(HEX(FLOOR(UNIX_TIMESTAMP(NOW(6)) * 1000000)), REPLACE(UUID(), '-', ''))"
 
+// UIDDefaultExprSQLite is the SQLite UID default expression (parenthesized: SQLite only
+// accepts a function call as a DEFAULT inside parentheses)
+const UIDDefaultExprSQLite = "(lower(printf('%x', CAST(unixepoch('subsec') * 1000000 AS INTEGER))) || lower(hex(randomblob(16))))"
+
 // GenerateUID returns a collision-resistant, roughly time-sortable opaque id,
 // generated in the application (no database round-trip). It is the Go equivalent
 // of UIDDefaultExprPostgreSQL: lowercase hex of the current Unix time in
@@ -154,14 +158,14 @@ var (
 		APIParameterType:   APIParameterTypeEncryptedBlob,
 		JSONType:           JSONTypeString,
 		GoType:             GoTypeSliceByte,
-		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "BYTEA", base.DXDatabaseTypeSQLServer: "VARBINARY(MAX)", base.DXDatabaseTypeMariaDB: "LONGBLOB", base.DXDatabaseTypeOracle: "BLOB"},
+		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "BYTEA", base.DXDatabaseTypeSQLServer: "VARBINARY(MAX)", base.DXDatabaseTypeMariaDB: "LONGBLOB", base.DXDatabaseTypeOracle: "BLOB", base.DXDatabaseTypeSQLite: "BLOB"},
 	}
 	DataTypeBlob = DataType{
 		Description:        "Opaque binary payload (small inline files/images); native BLOB/BYTEA, base64 JSON string, []byte in Go. Large media belongs in object storage.",
 		APIParameterType:   APIParameterTypeBlob,
 		JSONType:           JSONTypeString,
 		GoType:             GoTypeSliceByte,
-		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "BYTEA", base.DXDatabaseTypeSQLServer: "VARBINARY(MAX)", base.DXDatabaseTypeMariaDB: "LONGBLOB", base.DXDatabaseTypeOracle: "BLOB"},
+		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "BYTEA", base.DXDatabaseTypeSQLServer: "VARBINARY(MAX)", base.DXDatabaseTypeMariaDB: "LONGBLOB", base.DXDatabaseTypeOracle: "BLOB", base.DXDatabaseTypeSQLite: "BLOB"},
 	}
 
 	DataTypeUID = DataType{
@@ -169,8 +173,8 @@ var (
 		APIParameterType:           APIParameterTypeString,
 		JSONType:                   JSONTypeString,
 		GoType:                     GoTypeString,
-		TypeByDatabaseType:         map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: VARCHAR1024, base.DXDatabaseTypeSQLServer: VARCHAR1024, base.DXDatabaseTypeMariaDB: VARCHAR1024, base.DXDatabaseTypeOracle: "VARCHAR2(1024)"},
-		DefaultValueByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: UIDDefaultExprPostgreSQL, base.DXDatabaseTypeSQLServer: UIDDefaultExprSQLServer, base.DXDatabaseTypeOracle: UIDDefaultExprOracle, base.DXDatabaseTypeMariaDB: UIDDefaultExprMariaDB},
+		TypeByDatabaseType:         map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: VARCHAR1024, base.DXDatabaseTypeSQLServer: VARCHAR1024, base.DXDatabaseTypeMariaDB: VARCHAR1024, base.DXDatabaseTypeOracle: "VARCHAR2(1024)", base.DXDatabaseTypeSQLite: VARCHAR1024},
+		DefaultValueByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: UIDDefaultExprPostgreSQL, base.DXDatabaseTypeSQLServer: UIDDefaultExprSQLServer, base.DXDatabaseTypeOracle: UIDDefaultExprOracle, base.DXDatabaseTypeMariaDB: UIDDefaultExprMariaDB, base.DXDatabaseTypeSQLite: UIDDefaultExprSQLite},
 	}
 
 	DataTypeString = DataType{
@@ -178,7 +182,7 @@ var (
 		APIParameterType:   APIParameterTypeString,
 		JSONType:           JSONTypeString,
 		GoType:             GoTypeString,
-		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: VARCHAR1024, base.DXDatabaseTypeSQLServer: VARCHAR1024, base.DXDatabaseTypeMariaDB: VARCHAR1024, base.DXDatabaseTypeOracle: VARCHAR1024},
+		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: VARCHAR1024, base.DXDatabaseTypeSQLServer: VARCHAR1024, base.DXDatabaseTypeMariaDB: VARCHAR1024, base.DXDatabaseTypeOracle: VARCHAR1024, base.DXDatabaseTypeSQLite: VARCHAR1024},
 	}
 
 	DataTypeProtectedString = DataType{
@@ -186,7 +190,7 @@ var (
 		APIParameterType:   APIParameterTypeProtectedString,
 		JSONType:           JSONTypeString,
 		GoType:             GoTypeString,
-		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: VARCHAR1024, base.DXDatabaseTypeSQLServer: VARCHAR1024, base.DXDatabaseTypeMariaDB: VARCHAR1024, base.DXDatabaseTypeOracle: VARCHAR1024},
+		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: VARCHAR1024, base.DXDatabaseTypeSQLServer: VARCHAR1024, base.DXDatabaseTypeMariaDB: VARCHAR1024, base.DXDatabaseTypeOracle: VARCHAR1024, base.DXDatabaseTypeSQLite: VARCHAR1024},
 	}
 
 	DataTypeProtectedSQLString = DataType{
@@ -194,7 +198,7 @@ var (
 		APIParameterType:   APIParameterTypeProtectedSQLString,
 		JSONType:           JSONTypeString,
 		GoType:             GoTypeString,
-		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: VARCHAR1024, base.DXDatabaseTypeSQLServer: VARCHAR1024, base.DXDatabaseTypeMariaDB: VARCHAR1024, base.DXDatabaseTypeOracle: VARCHAR1024},
+		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: VARCHAR1024, base.DXDatabaseTypeSQLServer: VARCHAR1024, base.DXDatabaseTypeMariaDB: VARCHAR1024, base.DXDatabaseTypeOracle: VARCHAR1024, base.DXDatabaseTypeSQLite: VARCHAR1024},
 	}
 
 	DataTypeProtectedNonEmptyString = DataType{
@@ -202,7 +206,7 @@ var (
 		APIParameterType:   APIParameterTypeProtectedNonEmptyString,
 		JSONType:           JSONTypeString,
 		GoType:             GoTypeString,
-		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: VARCHAR1024, base.DXDatabaseTypeSQLServer: VARCHAR1024, base.DXDatabaseTypeMariaDB: VARCHAR1024, base.DXDatabaseTypeOracle: VARCHAR1024},
+		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: VARCHAR1024, base.DXDatabaseTypeSQLServer: VARCHAR1024, base.DXDatabaseTypeMariaDB: VARCHAR1024, base.DXDatabaseTypeOracle: VARCHAR1024, base.DXDatabaseTypeSQLite: VARCHAR1024},
 	}
 
 	DataTypeNullableString = DataType{
@@ -210,7 +214,7 @@ var (
 		APIParameterType:   APIParameterTypeNullableString,
 		JSONType:           JSONTypeString,
 		GoType:             GoTypeStringPointer,
-		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: VARCHAR1024, base.DXDatabaseTypeSQLServer: VARCHAR1024, base.DXDatabaseTypeMariaDB: VARCHAR1024, base.DXDatabaseTypeOracle: VARCHAR1024},
+		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: VARCHAR1024, base.DXDatabaseTypeSQLServer: VARCHAR1024, base.DXDatabaseTypeMariaDB: VARCHAR1024, base.DXDatabaseTypeOracle: VARCHAR1024, base.DXDatabaseTypeSQLite: VARCHAR1024},
 	}
 
 	DataTypeNonEmptyString = DataType{
@@ -218,7 +222,7 @@ var (
 		APIParameterType:   APIParameterTypeNonEmptyString,
 		JSONType:           JSONTypeString,
 		GoType:             GoTypeString,
-		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: VARCHAR1024, base.DXDatabaseTypeSQLServer: VARCHAR1024, base.DXDatabaseTypeMariaDB: VARCHAR1024, base.DXDatabaseTypeOracle: VARCHAR1024},
+		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: VARCHAR1024, base.DXDatabaseTypeSQLServer: VARCHAR1024, base.DXDatabaseTypeMariaDB: VARCHAR1024, base.DXDatabaseTypeOracle: VARCHAR1024, base.DXDatabaseTypeSQLite: VARCHAR1024},
 	}
 
 	DataTypeEmail = DataType{
@@ -226,7 +230,7 @@ var (
 		APIParameterType:   APIParameterTypeEmail,
 		JSONType:           JSONTypeString,
 		GoType:             GoTypeString,
-		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: VARCHAR255, base.DXDatabaseTypeSQLServer: VARCHAR255, base.DXDatabaseTypeMariaDB: VARCHAR255, base.DXDatabaseTypeOracle: VARCHAR255},
+		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: VARCHAR255, base.DXDatabaseTypeSQLServer: VARCHAR255, base.DXDatabaseTypeMariaDB: VARCHAR255, base.DXDatabaseTypeOracle: VARCHAR255, base.DXDatabaseTypeSQLite: VARCHAR255},
 	}
 
 	DataTypePhoneNumber = DataType{
@@ -234,7 +238,7 @@ var (
 		APIParameterType:   APIParameterTypePhoneNumber,
 		JSONType:           JSONTypeString,
 		GoType:             GoTypeString,
-		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: VARCHAR255, base.DXDatabaseTypeSQLServer: VARCHAR255, base.DXDatabaseTypeMariaDB: VARCHAR255, base.DXDatabaseTypeOracle: VARCHAR255},
+		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: VARCHAR255, base.DXDatabaseTypeSQLServer: VARCHAR255, base.DXDatabaseTypeMariaDB: VARCHAR255, base.DXDatabaseTypeOracle: VARCHAR255, base.DXDatabaseTypeSQLite: VARCHAR255},
 	}
 
 	DataTypeNPWP = DataType{
@@ -242,7 +246,7 @@ var (
 		APIParameterType:   APIParameterTypeNPWP,
 		JSONType:           JSONTypeString,
 		GoType:             GoTypeString,
-		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: VARCHAR255, base.DXDatabaseTypeSQLServer: VARCHAR255, base.DXDatabaseTypeMariaDB: VARCHAR255, base.DXDatabaseTypeOracle: VARCHAR255},
+		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: VARCHAR255, base.DXDatabaseTypeSQLServer: VARCHAR255, base.DXDatabaseTypeMariaDB: VARCHAR255, base.DXDatabaseTypeOracle: VARCHAR255, base.DXDatabaseTypeSQLite: VARCHAR255},
 	}
 
 	// DataTypeID is an integer identifier / primary key — same underlying type as
@@ -253,7 +257,7 @@ var (
 		APIParameterType:   APIParameterTypeID,
 		JSONType:           JSONTypeNumber,
 		GoType:             GoTypeInt64,
-		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "BIGINT", base.DXDatabaseTypeSQLServer: "BIGINT", base.DXDatabaseTypeMariaDB: "BIGINT", base.DXDatabaseTypeOracle: "NUMBER(19)"},
+		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "BIGINT", base.DXDatabaseTypeSQLServer: "BIGINT", base.DXDatabaseTypeMariaDB: "BIGINT", base.DXDatabaseTypeOracle: "NUMBER(19)", base.DXDatabaseTypeSQLite: "INTEGER"},
 	}
 
 	DataTypeInt32 = DataType{
@@ -261,7 +265,7 @@ var (
 		APIParameterType:   APIParameterTypeInt32,
 		JSONType:           JSONTypeNumber,
 		GoType:             GoTypeInt32,
-		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "INT", base.DXDatabaseTypeSQLServer: "INT", base.DXDatabaseTypeMariaDB: "INT", base.DXDatabaseTypeOracle: "NUMBER(10)"},
+		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "INT", base.DXDatabaseTypeSQLServer: "INT", base.DXDatabaseTypeMariaDB: "INT", base.DXDatabaseTypeOracle: "NUMBER(10)", base.DXDatabaseTypeSQLite: "INTEGER"},
 	}
 
 	DataTypeInt32P = DataType{
@@ -269,7 +273,7 @@ var (
 		APIParameterType:   APIParameterTypeInt32P,
 		JSONType:           JSONTypeNumber,
 		GoType:             GoTypeInt32,
-		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "INT", base.DXDatabaseTypeSQLServer: "INT", base.DXDatabaseTypeMariaDB: "INT", base.DXDatabaseTypeOracle: "NUMBER(10)"},
+		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "INT", base.DXDatabaseTypeSQLServer: "INT", base.DXDatabaseTypeMariaDB: "INT", base.DXDatabaseTypeOracle: "NUMBER(10)", base.DXDatabaseTypeSQLite: "INTEGER"},
 	}
 
 	DataTypeInt32ZP = DataType{
@@ -277,7 +281,7 @@ var (
 		APIParameterType:   APIParameterTypeInt32ZP,
 		JSONType:           JSONTypeNumber,
 		GoType:             GoTypeInt32,
-		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "INT", base.DXDatabaseTypeSQLServer: "INT", base.DXDatabaseTypeMariaDB: "INT", base.DXDatabaseTypeOracle: "NUMBER(10)"},
+		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "INT", base.DXDatabaseTypeSQLServer: "INT", base.DXDatabaseTypeMariaDB: "INT", base.DXDatabaseTypeOracle: "NUMBER(10)", base.DXDatabaseTypeSQLite: "INTEGER"},
 	}
 
 	DataTypeNullableInt32 = DataType{
@@ -285,7 +289,7 @@ var (
 		APIParameterType:   APIParameterTypeNullableInt32,
 		JSONType:           JSONTypeNumber,
 		GoType:             GoTypeInt32,
-		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "INT", base.DXDatabaseTypeSQLServer: "INT", base.DXDatabaseTypeMariaDB: "INT", base.DXDatabaseTypeOracle: "NUMBER(10)"},
+		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "INT", base.DXDatabaseTypeSQLServer: "INT", base.DXDatabaseTypeMariaDB: "INT", base.DXDatabaseTypeOracle: "NUMBER(10)", base.DXDatabaseTypeSQLite: "INTEGER"},
 	}
 
 	DataTypeInt64 = DataType{
@@ -293,7 +297,7 @@ var (
 		APIParameterType:   APIParameterTypeInt64,
 		JSONType:           JSONTypeNumber,
 		GoType:             GoTypeInt64,
-		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "BIGINT", base.DXDatabaseTypeSQLServer: "BIGINT", base.DXDatabaseTypeMariaDB: "BIGINT", base.DXDatabaseTypeOracle: "NUMBER(19)"},
+		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "BIGINT", base.DXDatabaseTypeSQLServer: "BIGINT", base.DXDatabaseTypeMariaDB: "BIGINT", base.DXDatabaseTypeOracle: "NUMBER(19)", base.DXDatabaseTypeSQLite: "INTEGER"},
 	}
 
 	DataTypeInt64P = DataType{
@@ -301,7 +305,7 @@ var (
 		APIParameterType:   APIParameterTypeInt64P,
 		JSONType:           JSONTypeNumber,
 		GoType:             GoTypeInt64,
-		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "BIGINT", base.DXDatabaseTypeSQLServer: "BIGINT", base.DXDatabaseTypeMariaDB: "BIGINT", base.DXDatabaseTypeOracle: "NUMBER(19)"},
+		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "BIGINT", base.DXDatabaseTypeSQLServer: "BIGINT", base.DXDatabaseTypeMariaDB: "BIGINT", base.DXDatabaseTypeOracle: "NUMBER(19)", base.DXDatabaseTypeSQLite: "INTEGER"},
 	}
 
 	DataTypeInt64ZP = DataType{
@@ -309,7 +313,7 @@ var (
 		APIParameterType:   APIParameterTypeInt64ZP,
 		JSONType:           JSONTypeNumber,
 		GoType:             GoTypeInt64,
-		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "BIGINT", base.DXDatabaseTypeSQLServer: "BIGINT", base.DXDatabaseTypeMariaDB: "BIGINT", base.DXDatabaseTypeOracle: "NUMBER(19)"},
+		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "BIGINT", base.DXDatabaseTypeSQLServer: "BIGINT", base.DXDatabaseTypeMariaDB: "BIGINT", base.DXDatabaseTypeOracle: "NUMBER(19)", base.DXDatabaseTypeSQLite: "INTEGER"},
 	}
 
 	DataTypeNullableInt64 = DataType{
@@ -317,7 +321,7 @@ var (
 		APIParameterType:   APIParameterTypeNullableInt64,
 		JSONType:           JSONTypeNumber,
 		GoType:             GoTypeInt64Pointer,
-		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "BIGINT", base.DXDatabaseTypeSQLServer: "BIGINT", base.DXDatabaseTypeMariaDB: "BIGINT", base.DXDatabaseTypeOracle: "NUMBER(19)"},
+		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "BIGINT", base.DXDatabaseTypeSQLServer: "BIGINT", base.DXDatabaseTypeMariaDB: "BIGINT", base.DXDatabaseTypeOracle: "NUMBER(19)", base.DXDatabaseTypeSQLite: "INTEGER"},
 	}
 
 	DataTypeFloat32 = DataType{
@@ -325,7 +329,7 @@ var (
 		APIParameterType:   APIParameterTypeFloat32,
 		JSONType:           JSONTypeNumber,
 		GoType:             GoTypeFloat32,
-		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "REAL", base.DXDatabaseTypeSQLServer: "REAL", base.DXDatabaseTypeMariaDB: "FLOAT", base.DXDatabaseTypeOracle: "BINARY_FLOAT"},
+		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "REAL", base.DXDatabaseTypeSQLServer: "REAL", base.DXDatabaseTypeMariaDB: "FLOAT", base.DXDatabaseTypeOracle: "BINARY_FLOAT", base.DXDatabaseTypeSQLite: "REAL"},
 	}
 
 	DataTypeFloat32P = DataType{
@@ -333,7 +337,7 @@ var (
 		APIParameterType:   APIParameterTypeFloat32P,
 		JSONType:           JSONTypeNumber,
 		GoType:             GoTypeFloat32,
-		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "REAL", base.DXDatabaseTypeSQLServer: "REAL", base.DXDatabaseTypeMariaDB: "FLOAT", base.DXDatabaseTypeOracle: "BINARY_FLOAT"},
+		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "REAL", base.DXDatabaseTypeSQLServer: "REAL", base.DXDatabaseTypeMariaDB: "FLOAT", base.DXDatabaseTypeOracle: "BINARY_FLOAT", base.DXDatabaseTypeSQLite: "REAL"},
 	}
 
 	DataTypeFloat32ZP = DataType{
@@ -341,7 +345,7 @@ var (
 		APIParameterType:   APIParameterTypeFloat32ZP,
 		JSONType:           JSONTypeNumber,
 		GoType:             GoTypeFloat32,
-		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "REAL", base.DXDatabaseTypeSQLServer: "REAL", base.DXDatabaseTypeMariaDB: "FLOAT", base.DXDatabaseTypeOracle: "BINARY_FLOAT"},
+		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "REAL", base.DXDatabaseTypeSQLServer: "REAL", base.DXDatabaseTypeMariaDB: "FLOAT", base.DXDatabaseTypeOracle: "BINARY_FLOAT", base.DXDatabaseTypeSQLite: "REAL"},
 	}
 
 	DataTypeFloat64 = DataType{
@@ -349,7 +353,7 @@ var (
 		APIParameterType:   APIParameterTypeFloat64,
 		JSONType:           JSONTypeNumber,
 		GoType:             GoTypeFloat64,
-		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "DOUBLE PRECISION", base.DXDatabaseTypeSQLServer: "FLOAT", base.DXDatabaseTypeMariaDB: "DOUBLE", base.DXDatabaseTypeOracle: "BINARY_DOUBLE"},
+		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "DOUBLE PRECISION", base.DXDatabaseTypeSQLServer: "FLOAT", base.DXDatabaseTypeMariaDB: "DOUBLE", base.DXDatabaseTypeOracle: "BINARY_DOUBLE", base.DXDatabaseTypeSQLite: "REAL"},
 	}
 
 	DataTypeFloat64P = DataType{
@@ -357,7 +361,7 @@ var (
 		APIParameterType:   APIParameterTypeFloat64P,
 		JSONType:           JSONTypeNumber,
 		GoType:             GoTypeFloat64,
-		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "DOUBLE PRECISION", base.DXDatabaseTypeSQLServer: "FLOAT", base.DXDatabaseTypeMariaDB: "DOUBLE", base.DXDatabaseTypeOracle: "BINARY_DOUBLE"},
+		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "DOUBLE PRECISION", base.DXDatabaseTypeSQLServer: "FLOAT", base.DXDatabaseTypeMariaDB: "DOUBLE", base.DXDatabaseTypeOracle: "BINARY_DOUBLE", base.DXDatabaseTypeSQLite: "REAL"},
 	}
 
 	DataTypeFloat64ZP = DataType{
@@ -365,7 +369,7 @@ var (
 		APIParameterType:   APIParameterTypeFloat64ZP,
 		JSONType:           JSONTypeNumber,
 		GoType:             GoTypeFloat64,
-		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "DOUBLE PRECISION", base.DXDatabaseTypeSQLServer: "FLOAT", base.DXDatabaseTypeMariaDB: "DOUBLE", base.DXDatabaseTypeOracle: "BINARY_DOUBLE"},
+		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "DOUBLE PRECISION", base.DXDatabaseTypeSQLServer: "FLOAT", base.DXDatabaseTypeMariaDB: "DOUBLE", base.DXDatabaseTypeOracle: "BINARY_DOUBLE", base.DXDatabaseTypeSQLite: "REAL"},
 	}
 
 	DataTypeBool = DataType{
@@ -373,7 +377,7 @@ var (
 		APIParameterType:   APIParameterTypeBoolean,
 		JSONType:           JSONTypeBoolean,
 		GoType:             GoTypeBool,
-		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "BOOLEAN", base.DXDatabaseTypeSQLServer: "BIT", base.DXDatabaseTypeMariaDB: "BOOLEAN", base.DXDatabaseTypeOracle: "NUMBER(1)"},
+		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "BOOLEAN", base.DXDatabaseTypeSQLServer: "BIT", base.DXDatabaseTypeMariaDB: "BOOLEAN", base.DXDatabaseTypeOracle: "NUMBER(1)", base.DXDatabaseTypeSQLite: "BOOLEAN"},
 	}
 
 	DataTypeISO8601 = DataType{
@@ -381,7 +385,7 @@ var (
 		APIParameterType:   APIParameterTypeISO8601,
 		JSONType:           JSONTypeString,
 		GoType:             GoTypeTime,
-		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "TIMESTAMP WITH TIME ZONE", base.DXDatabaseTypeSQLServer: "DATETIMEOFFSET", base.DXDatabaseTypeMariaDB: "DATETIME", base.DXDatabaseTypeOracle: "TIMESTAMP WITH TIME ZONE"},
+		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "TIMESTAMP WITH TIME ZONE", base.DXDatabaseTypeSQLServer: "DATETIMEOFFSET", base.DXDatabaseTypeMariaDB: "DATETIME", base.DXDatabaseTypeOracle: "TIMESTAMP WITH TIME ZONE", base.DXDatabaseTypeSQLite: "TIMESTAMP"},
 	}
 
 	DataTypeDate = DataType{
@@ -389,7 +393,7 @@ var (
 		APIParameterType:   APIParameterTypeDate,
 		JSONType:           JSONTypeString,
 		GoType:             GoTypeTime,
-		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "DATE", base.DXDatabaseTypeSQLServer: "DATE", base.DXDatabaseTypeMariaDB: "DATE", base.DXDatabaseTypeOracle: "DATE"},
+		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "DATE", base.DXDatabaseTypeSQLServer: "DATE", base.DXDatabaseTypeMariaDB: "DATE", base.DXDatabaseTypeOracle: "DATE", base.DXDatabaseTypeSQLite: "DATE"},
 	}
 
 	DataTypeTime = DataType{
@@ -397,7 +401,7 @@ var (
 		APIParameterType:   APIParameterTypeTime,
 		JSONType:           JSONTypeString,
 		GoType:             GoTypeTime,
-		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "TIME", base.DXDatabaseTypeSQLServer: "TIME", base.DXDatabaseTypeMariaDB: "TIME", base.DXDatabaseTypeOracle: "DATE"},
+		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "TIME", base.DXDatabaseTypeSQLServer: "TIME", base.DXDatabaseTypeMariaDB: "TIME", base.DXDatabaseTypeOracle: "DATE", base.DXDatabaseTypeSQLite: "TEXT"},
 	}
 
 	DataTypeJSON = DataType{
@@ -405,7 +409,7 @@ var (
 		APIParameterType:   APIParameterTypeJSON,
 		JSONType:           JSONTypeObject,
 		GoType:             GoTypeMapStringInterface,
-		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "JSONB", base.DXDatabaseTypeSQLServer: "NVARCHAR(MAX)", base.DXDatabaseTypeMariaDB: "JSON", base.DXDatabaseTypeOracle: "CLOB"},
+		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "JSONB", base.DXDatabaseTypeSQLServer: "NVARCHAR(MAX)", base.DXDatabaseTypeMariaDB: "JSON", base.DXDatabaseTypeOracle: "CLOB", base.DXDatabaseTypeSQLite: "TEXT"},
 	}
 
 	DataTypeJSONPassthrough = DataType{
@@ -413,7 +417,7 @@ var (
 		APIParameterType:   APIParameterTypeJSONPassthrough,
 		JSONType:           JSONTypeObject,
 		GoType:             GoTypeMapStringInterface,
-		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "JSONB", base.DXDatabaseTypeSQLServer: "NVARCHAR(MAX)", base.DXDatabaseTypeMariaDB: "JSON", base.DXDatabaseTypeOracle: "CLOB"},
+		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "JSONB", base.DXDatabaseTypeSQLServer: "NVARCHAR(MAX)", base.DXDatabaseTypeMariaDB: "JSON", base.DXDatabaseTypeOracle: "CLOB", base.DXDatabaseTypeSQLite: "TEXT"},
 	}
 
 	DataTypeArray = DataType{
@@ -421,7 +425,7 @@ var (
 		APIParameterType:   APIParameterTypeArray,
 		JSONType:           JSONTypeArray,
 		GoType:             GoTypeSliceInterface,
-		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "JSONB", base.DXDatabaseTypeSQLServer: "NVARCHAR(MAX)", base.DXDatabaseTypeMariaDB: "JSON", base.DXDatabaseTypeOracle: "CLOB"},
+		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "JSONB", base.DXDatabaseTypeSQLServer: "NVARCHAR(MAX)", base.DXDatabaseTypeMariaDB: "JSON", base.DXDatabaseTypeOracle: "CLOB", base.DXDatabaseTypeSQLite: "TEXT"},
 	}
 
 	DataTypeArrayString = DataType{
@@ -429,7 +433,7 @@ var (
 		APIParameterType:   APIParameterTypeArrayString,
 		JSONType:           JSONTypeArray,
 		GoType:             GoTypeSliceString,
-		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "TEXT[]", base.DXDatabaseTypeSQLServer: "NVARCHAR(MAX)", base.DXDatabaseTypeMariaDB: "JSON", base.DXDatabaseTypeOracle: "CLOB"},
+		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "TEXT[]", base.DXDatabaseTypeSQLServer: "NVARCHAR(MAX)", base.DXDatabaseTypeMariaDB: "JSON", base.DXDatabaseTypeOracle: "CLOB", base.DXDatabaseTypeSQLite: "TEXT"},
 	}
 
 	DataTypeArrayInt64 = DataType{
@@ -437,7 +441,7 @@ var (
 		APIParameterType:   APIParameterTypeArrayInt64,
 		JSONType:           JSONTypeArray,
 		GoType:             GoTypeSliceInt64,
-		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "BIGINT[]", base.DXDatabaseTypeSQLServer: "NVARCHAR(MAX)", base.DXDatabaseTypeMariaDB: "JSON", base.DXDatabaseTypeOracle: "CLOB"},
+		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "BIGINT[]", base.DXDatabaseTypeSQLServer: "NVARCHAR(MAX)", base.DXDatabaseTypeMariaDB: "JSON", base.DXDatabaseTypeOracle: "CLOB", base.DXDatabaseTypeSQLite: "TEXT"},
 	}
 
 	DataTypeArrayJSONTemplate = DataType{
@@ -445,7 +449,7 @@ var (
 		APIParameterType:   APIParameterTypeArrayJSONTemplate,
 		JSONType:           JSONTypeArray,
 		GoType:             GoTypeSliceMapStringInterface,
-		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "JSONB", base.DXDatabaseTypeSQLServer: "NVARCHAR(MAX)", base.DXDatabaseTypeMariaDB: "JSON", base.DXDatabaseTypeOracle: "CLOB"},
+		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "JSONB", base.DXDatabaseTypeSQLServer: "NVARCHAR(MAX)", base.DXDatabaseTypeMariaDB: "JSON", base.DXDatabaseTypeOracle: "CLOB", base.DXDatabaseTypeSQLite: "TEXT"},
 	}
 
 	DataTypeMapStringString = DataType{
@@ -453,7 +457,7 @@ var (
 		APIParameterType:   APIParameterTypeMapStringString,
 		JSONType:           JSONTypeObject,
 		GoType:             GoTypeMapStringString,
-		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "JSONB", base.DXDatabaseTypeSQLServer: "NVARCHAR(MAX)", base.DXDatabaseTypeMariaDB: "JSON", base.DXDatabaseTypeOracle: "CLOB"},
+		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "JSONB", base.DXDatabaseTypeSQLServer: "NVARCHAR(MAX)", base.DXDatabaseTypeMariaDB: "JSON", base.DXDatabaseTypeOracle: "CLOB", base.DXDatabaseTypeSQLite: "TEXT"},
 	}
 
 	DataTypeSerial = DataType{
@@ -461,7 +465,7 @@ var (
 		APIParameterType:   "serial",
 		JSONType:           JSONTypeNumber,
 		GoType:             GoTypeInt32,
-		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "SERIAL", base.DXDatabaseTypeSQLServer: "INT IDENTITY(1,1)", base.DXDatabaseTypeMariaDB: "INT AUTO_INCREMENT", base.DXDatabaseTypeOracle: "NUMBER GENERATED BY DEFAULT AS IDENTITY"},
+		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "SERIAL", base.DXDatabaseTypeSQLServer: "INT IDENTITY(1,1)", base.DXDatabaseTypeMariaDB: "INT AUTO_INCREMENT", base.DXDatabaseTypeOracle: "NUMBER GENERATED BY DEFAULT AS IDENTITY", base.DXDatabaseTypeSQLite: "INTEGER"},
 	}
 
 	// DataTypeBigSerial is a 64-bit auto-increment primary key (the BIGINT counterpart
@@ -471,7 +475,7 @@ var (
 		APIParameterType:   "bigserial",
 		JSONType:           JSONTypeNumber,
 		GoType:             GoTypeInt64,
-		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "BIGSERIAL", base.DXDatabaseTypeSQLServer: "BIGINT IDENTITY(1,1)", base.DXDatabaseTypeMariaDB: "BIGINT AUTO_INCREMENT", base.DXDatabaseTypeOracle: "NUMBER(19) GENERATED BY DEFAULT AS IDENTITY"},
+		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "BIGSERIAL", base.DXDatabaseTypeSQLServer: "BIGINT IDENTITY(1,1)", base.DXDatabaseTypeMariaDB: "BIGINT AUTO_INCREMENT", base.DXDatabaseTypeOracle: "NUMBER(19) GENERATED BY DEFAULT AS IDENTITY", base.DXDatabaseTypeSQLite: "INTEGER"},
 	}
 
 	DataTypeInt = DataType{
@@ -479,7 +483,7 @@ var (
 		APIParameterType:   "int",
 		JSONType:           JSONTypeNumber,
 		GoType:             GoTypeInt32,
-		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "INT", base.DXDatabaseTypeSQLServer: "INT", base.DXDatabaseTypeMariaDB: "INT", base.DXDatabaseTypeOracle: "NUMBER(10)"},
+		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "INT", base.DXDatabaseTypeSQLServer: "INT", base.DXDatabaseTypeMariaDB: "INT", base.DXDatabaseTypeOracle: "NUMBER(10)", base.DXDatabaseTypeSQLite: "INTEGER"},
 	}
 
 	DataTypeString1 = DataType{
@@ -487,7 +491,7 @@ var (
 		APIParameterType:   "string1",
 		JSONType:           JSONTypeString,
 		GoType:             GoTypeString,
-		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "VARCHAR(1)", base.DXDatabaseTypeSQLServer: "VARCHAR(1)", base.DXDatabaseTypeMariaDB: "VARCHAR(1)", base.DXDatabaseTypeOracle: "VARCHAR2(1)"},
+		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "VARCHAR(1)", base.DXDatabaseTypeSQLServer: "VARCHAR(1)", base.DXDatabaseTypeMariaDB: "VARCHAR(1)", base.DXDatabaseTypeOracle: "VARCHAR2(1)", base.DXDatabaseTypeSQLite: "VARCHAR(1)"},
 	}
 
 	DataTypeString5 = DataType{
@@ -495,7 +499,7 @@ var (
 		APIParameterType:   "string5",
 		JSONType:           JSONTypeString,
 		GoType:             GoTypeString,
-		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "VARCHAR(5)", base.DXDatabaseTypeSQLServer: "VARCHAR(5)", base.DXDatabaseTypeMariaDB: "VARCHAR(5)", base.DXDatabaseTypeOracle: "VARCHAR2(5)"},
+		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "VARCHAR(5)", base.DXDatabaseTypeSQLServer: "VARCHAR(5)", base.DXDatabaseTypeMariaDB: "VARCHAR(5)", base.DXDatabaseTypeOracle: "VARCHAR2(5)", base.DXDatabaseTypeSQLite: "VARCHAR(5)"},
 	}
 
 	DataTypeString10 = DataType{
@@ -503,7 +507,7 @@ var (
 		APIParameterType:   "string10",
 		JSONType:           JSONTypeString,
 		GoType:             GoTypeString,
-		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "VARCHAR(10)", base.DXDatabaseTypeSQLServer: "VARCHAR(10)", base.DXDatabaseTypeMariaDB: "VARCHAR(10)", base.DXDatabaseTypeOracle: "VARCHAR2(10)"},
+		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "VARCHAR(10)", base.DXDatabaseTypeSQLServer: "VARCHAR(10)", base.DXDatabaseTypeMariaDB: "VARCHAR(10)", base.DXDatabaseTypeOracle: "VARCHAR2(10)", base.DXDatabaseTypeSQLite: "VARCHAR(10)"},
 	}
 
 	DataTypeString20 = DataType{
@@ -511,7 +515,7 @@ var (
 		APIParameterType:   "string20",
 		JSONType:           JSONTypeString,
 		GoType:             GoTypeString,
-		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "VARCHAR(20)", base.DXDatabaseTypeSQLServer: "VARCHAR(20)", base.DXDatabaseTypeMariaDB: "VARCHAR(20)", base.DXDatabaseTypeOracle: "VARCHAR2(20)"},
+		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "VARCHAR(20)", base.DXDatabaseTypeSQLServer: "VARCHAR(20)", base.DXDatabaseTypeMariaDB: "VARCHAR(20)", base.DXDatabaseTypeOracle: "VARCHAR2(20)", base.DXDatabaseTypeSQLite: "VARCHAR(20)"},
 	}
 
 	DataTypeString30 = DataType{
@@ -519,7 +523,7 @@ var (
 		APIParameterType:   "string30",
 		JSONType:           JSONTypeString,
 		GoType:             GoTypeString,
-		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "VARCHAR(30)", base.DXDatabaseTypeSQLServer: "VARCHAR(30)", base.DXDatabaseTypeMariaDB: "VARCHAR(30)", base.DXDatabaseTypeOracle: "VARCHAR2(30)"},
+		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "VARCHAR(30)", base.DXDatabaseTypeSQLServer: "VARCHAR(30)", base.DXDatabaseTypeMariaDB: "VARCHAR(30)", base.DXDatabaseTypeOracle: "VARCHAR2(30)", base.DXDatabaseTypeSQLite: "VARCHAR(30)"},
 	}
 
 	DataTypeString50 = DataType{
@@ -527,7 +531,7 @@ var (
 		APIParameterType:   "string50",
 		JSONType:           JSONTypeString,
 		GoType:             GoTypeString,
-		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "VARCHAR(50)", base.DXDatabaseTypeSQLServer: "VARCHAR(50)", base.DXDatabaseTypeMariaDB: "VARCHAR(50)", base.DXDatabaseTypeOracle: "VARCHAR2(50)"},
+		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "VARCHAR(50)", base.DXDatabaseTypeSQLServer: "VARCHAR(50)", base.DXDatabaseTypeMariaDB: "VARCHAR(50)", base.DXDatabaseTypeOracle: "VARCHAR2(50)", base.DXDatabaseTypeSQLite: "VARCHAR(50)"},
 	}
 
 	DataTypeString100 = DataType{
@@ -535,7 +539,7 @@ var (
 		APIParameterType:   "string100",
 		JSONType:           JSONTypeString,
 		GoType:             GoTypeString,
-		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "VARCHAR(100)", base.DXDatabaseTypeSQLServer: "VARCHAR(100)", base.DXDatabaseTypeMariaDB: "VARCHAR(100)", base.DXDatabaseTypeOracle: "VARCHAR2(100)"},
+		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "VARCHAR(100)", base.DXDatabaseTypeSQLServer: "VARCHAR(100)", base.DXDatabaseTypeMariaDB: "VARCHAR(100)", base.DXDatabaseTypeOracle: "VARCHAR2(100)", base.DXDatabaseTypeSQLite: "VARCHAR(100)"},
 	}
 
 	DataTypeString255 = DataType{
@@ -543,7 +547,7 @@ var (
 		APIParameterType:   "string255",
 		JSONType:           JSONTypeString,
 		GoType:             GoTypeString,
-		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: VARCHAR255, base.DXDatabaseTypeSQLServer: VARCHAR255, base.DXDatabaseTypeMariaDB: VARCHAR255, base.DXDatabaseTypeOracle: "VARCHAR2(255)"},
+		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: VARCHAR255, base.DXDatabaseTypeSQLServer: VARCHAR255, base.DXDatabaseTypeMariaDB: VARCHAR255, base.DXDatabaseTypeOracle: "VARCHAR2(255)", base.DXDatabaseTypeSQLite: VARCHAR255},
 	}
 
 	DataTypeString256 = DataType{
@@ -551,7 +555,7 @@ var (
 		APIParameterType:   "string256",
 		JSONType:           JSONTypeString,
 		GoType:             GoTypeString,
-		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "VARCHAR(256)", base.DXDatabaseTypeSQLServer: "VARCHAR(256)", base.DXDatabaseTypeMariaDB: "VARCHAR(256)", base.DXDatabaseTypeOracle: "VARCHAR2(256)"},
+		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "VARCHAR(256)", base.DXDatabaseTypeSQLServer: "VARCHAR(256)", base.DXDatabaseTypeMariaDB: "VARCHAR(256)", base.DXDatabaseTypeOracle: "VARCHAR2(256)", base.DXDatabaseTypeSQLite: "VARCHAR(256)"},
 	}
 
 	DataTypeString500 = DataType{
@@ -559,7 +563,7 @@ var (
 		APIParameterType:   "string500",
 		JSONType:           JSONTypeString,
 		GoType:             GoTypeString,
-		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "VARCHAR(500)", base.DXDatabaseTypeSQLServer: "VARCHAR(500)", base.DXDatabaseTypeMariaDB: "VARCHAR(500)", base.DXDatabaseTypeOracle: "VARCHAR2(500)"},
+		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "VARCHAR(500)", base.DXDatabaseTypeSQLServer: "VARCHAR(500)", base.DXDatabaseTypeMariaDB: "VARCHAR(500)", base.DXDatabaseTypeOracle: "VARCHAR2(500)", base.DXDatabaseTypeSQLite: "VARCHAR(500)"},
 	}
 
 	// DataTypeString512 is a 512-char string — index-safe as a key on all four engines
@@ -569,7 +573,7 @@ var (
 		APIParameterType:   "string512",
 		JSONType:           JSONTypeString,
 		GoType:             GoTypeString,
-		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "VARCHAR(512)", base.DXDatabaseTypeSQLServer: "VARCHAR(512)", base.DXDatabaseTypeMariaDB: "VARCHAR(512)", base.DXDatabaseTypeOracle: "VARCHAR2(512)"},
+		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "VARCHAR(512)", base.DXDatabaseTypeSQLServer: "VARCHAR(512)", base.DXDatabaseTypeMariaDB: "VARCHAR(512)", base.DXDatabaseTypeOracle: "VARCHAR2(512)", base.DXDatabaseTypeSQLite: "VARCHAR(512)"},
 	}
 
 	DataTypeString1024 = DataType{
@@ -577,7 +581,7 @@ var (
 		APIParameterType:   "string1024",
 		JSONType:           JSONTypeString,
 		GoType:             GoTypeString,
-		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: VARCHAR1024, base.DXDatabaseTypeSQLServer: VARCHAR1024, base.DXDatabaseTypeMariaDB: VARCHAR1024, base.DXDatabaseTypeOracle: "VARCHAR2(1024)"},
+		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: VARCHAR1024, base.DXDatabaseTypeSQLServer: VARCHAR1024, base.DXDatabaseTypeMariaDB: VARCHAR1024, base.DXDatabaseTypeOracle: "VARCHAR2(1024)", base.DXDatabaseTypeSQLite: VARCHAR1024},
 	}
 
 	DataTypeString2048 = DataType{
@@ -585,7 +589,7 @@ var (
 		APIParameterType:   "string2048",
 		JSONType:           JSONTypeString,
 		GoType:             GoTypeString,
-		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "VARCHAR(2048)", base.DXDatabaseTypeSQLServer: "VARCHAR(2048)", base.DXDatabaseTypeMariaDB: "VARCHAR(2048)", base.DXDatabaseTypeOracle: "VARCHAR2(2048)"},
+		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "VARCHAR(2048)", base.DXDatabaseTypeSQLServer: "VARCHAR(2048)", base.DXDatabaseTypeMariaDB: "VARCHAR(2048)", base.DXDatabaseTypeOracle: "VARCHAR2(2048)", base.DXDatabaseTypeSQLite: "VARCHAR(2048)"},
 	}
 
 	DataTypeString8096 = DataType{
@@ -593,7 +597,7 @@ var (
 		APIParameterType:   "string8096",
 		JSONType:           JSONTypeString,
 		GoType:             GoTypeString,
-		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "VARCHAR(8096)", base.DXDatabaseTypeSQLServer: "VARCHAR(MAX)", base.DXDatabaseTypeMariaDB: "TEXT", base.DXDatabaseTypeOracle: "CLOB"},
+		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "VARCHAR(8096)", base.DXDatabaseTypeSQLServer: "VARCHAR(MAX)", base.DXDatabaseTypeMariaDB: "TEXT", base.DXDatabaseTypeOracle: "CLOB", base.DXDatabaseTypeSQLite: "TEXT"},
 	}
 
 	DataTypeString32768 = DataType{
@@ -601,7 +605,7 @@ var (
 		APIParameterType:   "string32768",
 		JSONType:           JSONTypeString,
 		GoType:             GoTypeString,
-		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "VARCHAR(32768)", base.DXDatabaseTypeSQLServer: "VARCHAR(MAX)", base.DXDatabaseTypeMariaDB: "TEXT", base.DXDatabaseTypeOracle: "CLOB"},
+		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "VARCHAR(32768)", base.DXDatabaseTypeSQLServer: "VARCHAR(MAX)", base.DXDatabaseTypeMariaDB: "TEXT", base.DXDatabaseTypeOracle: "CLOB", base.DXDatabaseTypeSQLite: "TEXT"},
 	}
 
 	// Deprecated: use DataTypeMoney (NUMERIC(23,4)) for monetary values; this legacy
@@ -612,7 +616,7 @@ var (
 		APIParameterType:   "decimal",
 		JSONType:           JSONTypeString,
 		GoType:             GoTypeString,
-		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "NUMERIC(30,4)", base.DXDatabaseTypeSQLServer: "DECIMAL(30,4)", base.DXDatabaseTypeMariaDB: "DECIMAL(30,4)", base.DXDatabaseTypeOracle: "NUMBER(30,4)"},
+		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "NUMERIC(30,4)", base.DXDatabaseTypeSQLServer: "DECIMAL(30,4)", base.DXDatabaseTypeMariaDB: "DECIMAL(30,4)", base.DXDatabaseTypeOracle: "NUMBER(30,4)", base.DXDatabaseTypeSQLite: "NUMERIC(30,4)"},
 	}
 
 	// DataTypeMoney — fixed-point monetary amount, NUMERIC(23,4) on every engine (19
@@ -625,7 +629,7 @@ var (
 		APIParameterType:   APIParameterTypeMoney,
 		JSONType:           JSONTypeString,
 		GoType:             GoTypeMoney,
-		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "NUMERIC(23,4)", base.DXDatabaseTypeSQLServer: "DECIMAL(23,4)", base.DXDatabaseTypeMariaDB: "DECIMAL(23,4)", base.DXDatabaseTypeOracle: "NUMBER(23,4)"},
+		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "NUMERIC(23,4)", base.DXDatabaseTypeSQLServer: "DECIMAL(23,4)", base.DXDatabaseTypeMariaDB: "DECIMAL(23,4)", base.DXDatabaseTypeOracle: "NUMBER(23,4)", base.DXDatabaseTypeSQLite: "NUMERIC(23,4)"},
 	}
 
 	DataTypeGeometryPoint = DataType{
@@ -633,7 +637,7 @@ var (
 		APIParameterType:   "geometry_point",
 		JSONType:           JSONTypeObject,
 		GoType:             GoTypeMapStringInterface,
-		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "geometry(Point, 4326)", base.DXDatabaseTypeSQLServer: "GEOGRAPHY", base.DXDatabaseTypeMariaDB: "POINT SRID 4326", base.DXDatabaseTypeOracle: "SDO_GEOMETRY"},
+		TypeByDatabaseType: map[base.DXDatabaseType]string{base.DXDatabaseTypePostgreSQL: "geometry(Point, 4326)", base.DXDatabaseTypeSQLServer: "GEOGRAPHY", base.DXDatabaseTypeMariaDB: "POINT SRID 4326", base.DXDatabaseTypeOracle: "SDO_GEOMETRY", base.DXDatabaseTypeSQLite: "TEXT"},
 	}
 )
 
@@ -111,6 +111,11 @@ func TsIsContain[T comparable](arr []T, v T) bool {
 	return false
 }
 
+// Ptr returns a pointer to a copy of v, for optional fields in struct literals.
+func Ptr[T any](v T) *T {
+	return &v
+}
+
 // Int64sIsContain checks if a slice of int64s contains a specific value.
 func Int64sIsContain(arr []int64, v int64) bool {
 	return TsIsContain[int64](arr, v)